@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -14,9 +15,50 @@ type KeycloakConfig struct {
 	Timeout      time.Duration
 }
 
+// AuthConfig selects how bearer tokens are verified.
+// Mode is "oidc" (keys discovered from the Keycloak realm), "jwks" (a local JSON Web Key Set at JWKSPath)
+// or "static" (a single HMAC secret or PEM public key, given inline as StaticKey or read from StaticKeyPath).
+// Issuer, when set, must match the iss claim of tokens verified offline.
+// PermissionsPath points to a JSON file mapping realm and client roles to permissions; empty uses the defaults.
+type AuthConfig struct {
+	Mode            string
+	JWKSPath        string
+	StaticKey       string
+	StaticKeyPath   string
+	Issuer          string
+	PermissionsPath string
+}
+
+// StorageConfig selects the repository adapter backing the API.
+// Driver is "memory" (LRU caches, lost on restart), "file" (durable single-file store at Path)
+// or "sql" (database/sql with the SQLDriver driver name and DSN).
+type StorageConfig struct {
+	Driver        string
+	Path          string
+	SnapshotEvery int
+	SQLDriver     string
+	DSN           string
+}
+
+// FavouritesConfig controls how favourites react to changes of their assets.
+// With Tombstones, deleting an asset leaves a note for every user who had favourited it.
+type FavouritesConfig struct {
+	Tombstones bool
+}
+
+// RenderConfig controls the rendering of chart assets as images.
+// CacheSize is how many rendered images are kept for repeated requests; 0 or less keeps none.
+type RenderConfig struct {
+	CacheSize int
+}
+
 type Config struct {
-	Keycloak KeycloakConfig
-	Server   struct {
+	Keycloak   KeycloakConfig
+	Auth       AuthConfig
+	Storage    StorageConfig
+	Favourites FavouritesConfig
+	Render     RenderConfig
+	Server     struct {
 		Port string
 	}
 }
@@ -32,6 +74,27 @@ func Load() *Config {
 	cfg.Keycloak.ClientSecret = getEnv("KEYCLOAK_CLIENT_SECRET", "your-client-secret")
 	cfg.Keycloak.Timeout = 10 * time.Second
 
+	// Token verification configuration
+	cfg.Auth.Mode = getEnv("AUTH_MODE", "oidc")
+	cfg.Auth.JWKSPath = getEnv("AUTH_JWKS_FILE", "")
+	cfg.Auth.StaticKey = getEnv("AUTH_STATIC_KEY", "")
+	cfg.Auth.StaticKeyPath = getEnv("AUTH_STATIC_KEY_FILE", "")
+	cfg.Auth.Issuer = getEnv("AUTH_ISSUER", "")
+	cfg.Auth.PermissionsPath = getEnv("AUTH_PERMISSIONS_FILE", "")
+
+	// Storage configuration
+	cfg.Storage.Driver = getEnv("STORAGE_DRIVER", "memory")
+	cfg.Storage.Path = getEnv("STORAGE_PATH", "data/preferred_assets.db")
+	cfg.Storage.SnapshotEvery = getEnvInt("STORAGE_SNAPSHOT_EVERY", 1000)
+	cfg.Storage.SQLDriver = getEnv("STORAGE_SQL_DRIVER", "sqlite")
+	cfg.Storage.DSN = getEnv("STORAGE_DSN", "data/preferred_assets.sqlite")
+
+	// Favourites configuration
+	cfg.Favourites.Tombstones = getEnvBool("FAVOURITE_TOMBSTONES", true)
+
+	// Chart rendering configuration
+	cfg.Render.CacheSize = getEnvInt("RENDER_CACHE_SIZE", 256)
+
 	// Server configuration
 	cfg.Server.Port = getEnv("SERVER_PORT", "8081")
 
@@ -44,3 +107,21 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
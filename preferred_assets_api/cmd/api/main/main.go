@@ -25,6 +25,11 @@ import (
 // @in header
 // @name Authorization
 // @description Enter "Bearer" followed by a space and your JWT token. Example: "Bearer eyJhbGciOiJSUzI1NiIsInR5cCIgOiAiSldUIiw..."
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description An API key issued through POST /api-keys, for machine clients. Example: "pak_Xk3v9QfA0cW2b7nLr1YhJ5tUe8sMq4zD6gKpVxN2aBo"
 func main() {
 	// Check if we're running in Docker
 	if os.Getenv("DOCKER_ENV") == "true" {
@@ -32,6 +37,8 @@ func main() {
 	}
 
 	a := app.New()
+	defer a.Close()
+
 	if err := a.Run(); err != nil {
 		log.Fatal(err)
 	}
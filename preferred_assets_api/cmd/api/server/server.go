@@ -1,17 +1,23 @@
 package server
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"time"
 
 	_ "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/docs"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/cmd/api/config"
 	httpTransport "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/handlers"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/matching"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/render"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/filestore"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/inmemory"
+	sqlrepo "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/sql"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/search"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
 	application "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
@@ -19,51 +25,162 @@ import (
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/cache"
 	"github.com/go-chi/chi/v5"
 	httpSwagger "github.com/swaggo/http-swagger"
+	_ "modernc.org/sqlite"
 )
 
 type App struct {
-	UserHandler      *httpTransport.UserHandler
-	FavouriteHandler *httpTransport.FavouriteHandler
-	AssetHandler     *httpTransport.AssetHandler
-	Keycloak         *auth.KeycloakClient
-	Config           *config.Config
+	UserHandler       *httpTransport.UserHandler
+	FavouriteHandler  *httpTransport.FavouriteHandler
+	AssetHandler      *httpTransport.AssetHandler
+	CollectionHandler *httpTransport.CollectionHandler
+	MeHandler         *httpTransport.MeHandler
+	APIKeyHandler     *httpTransport.APIKeyHandler
+	Verifier          auth.TokenVerifier
+	Permissions       auth.RolePermissions
+	Config            *config.Config
+	ResolveUserID     middleware.UserIDResolver
+	ProvisionUser     middleware.UserProvisioner
+	AuthenticateKey   middleware.APIKeyAuthenticator
+	closer            io.Closer
 }
 
 func New() *App {
 	cfg := config.Load()
 
-	// Initialize Keycloak client
-	keycloakClient, _ := auth.NewKeycloakClient(&cfg.Keycloak)
+	// Initialize the token verifier
+	verifier, err := auth.NewTokenVerifier(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize %s token verification: %v", cfg.Auth.Mode, err)
+	}
+
+	//Initialization for Permissions
+	permissions, err := auth.LoadRolePermissions(cfg.Auth.PermissionsPath)
+	if err != nil {
+		log.Fatalf("failed to load permissions: %v", err)
+	}
+
+	//Initialization for Repositories
+	repos, err := newRepositories(&cfg.Storage)
+	if err != nil {
+		log.Fatalf("failed to initialize %s storage: %v", cfg.Storage.Driver, err)
+	}
 
 	//Initialization for Favourite resources
-	favouritesCache := cache.InitLRUCacheWithEvict[string, map[string]time.Time](100)
-	favouriteExistsCache := cache.InitLRUCacheWithEvict[string, bool](100)
-	var favouriteRepo ports.FavouriteRepository = inmemory.NewFavouriteRepository(favouritesCache, favouriteExistsCache)
-	favouriteService := application.NewFavouriteService(favouriteRepo)
+	favouriteService := application.NewFavouriteService(repos.favourites, repos.collections, repos.users, repos.assets)
 	favouriteHandler := httpTransport.NewFavouriteHandler(*favouriteService)
 
 	//Initialization for User resources
-	userCache := cache.InitLRUCacheWithEvict[string, *entities.UserEntity](5)
-	assetCache := cache.InitLRUCacheWithEvict[string, entities.AssetEntity](50)
-	var userRepo ports.UserRepository = inmemory.NewUserRepository(userCache, favouriteRepo)
-	var assetRepo ports.AssetRepository = inmemory.NewAssetRepository(assetCache)
-	userService := application.NewUserService(userRepo, assetRepo)
-	userHandler := httpTransport.NewUserHandler(*userService)
+	userService := application.NewUserService(repos.users, repos.assets)
+	userHandler := httpTransport.NewUserHandler(*userService, render.FavouritesReportWriter{})
 
 	//Initialization for Asset resources
-	assetRepo = inmemory.NewAssetRepository(assetCache)
-	assetService := application.NewAssetService(assetRepo)
+	assetService := application.NewAssetService(repos.assets, repos.favourites, repos.collections, search.NewAssetIndex(), matching.NewAudienceMatcher(),
+		render.NewChartRenderer(cfg.Render.CacheSize), cfg.Favourites.Tombstones)
+	if err := assetService.RebuildIndexes(); err != nil {
+		log.Fatalf("failed to build the asset search index and audience matcher: %v", err)
+	}
 	assetHandler := httpTransport.NewAssetHandler(assetService)
 
+	//Initialization for Collection resources
+	collectionService := application.NewCollectionService(repos.collections, repos.favourites, repos.assets, repos.users)
+	collectionHandler := httpTransport.NewCollectionHandler(collectionService)
+
+	//Initialization for the signed-in user's resources
+	meHandler := httpTransport.NewMeHandler(*userService, *favouriteService)
+
+	//Initialization for API key resources
+	apiKeyService := application.NewAPIKeyService(repos.apiKeys, repos.users)
+	apiKeyHandler := httpTransport.NewAPIKeyHandler(apiKeyService)
+
 	return &App{
-		UserHandler:      userHandler,
-		FavouriteHandler: favouriteHandler,
-		AssetHandler:     assetHandler,
-		Keycloak:         keycloakClient,
-		Config:           cfg,
+		UserHandler:       userHandler,
+		FavouriteHandler:  favouriteHandler,
+		AssetHandler:      assetHandler,
+		CollectionHandler: collectionHandler,
+		MeHandler:         meHandler,
+		APIKeyHandler:     apiKeyHandler,
+		Verifier:          verifier,
+		Permissions:       permissions,
+		Config:            cfg,
+		ResolveUserID:     userService.ResolveUserID,
+		ProvisionUser:     userService.ProvisionUser,
+		AuthenticateKey:   apiKeyService.Authenticate,
+		closer:            repos.closer,
 	}
 }
 
+type repositories struct {
+	users       ports.UserRepository
+	assets      ports.AssetRepository
+	favourites  ports.FavouriteRepository
+	collections ports.CollectionRepository
+	apiKeys     ports.APIKeyRepository
+	closer      io.Closer
+}
+
+// newRepositories builds the repository adapters selected by the storage configuration
+func newRepositories(cfg *config.StorageConfig) (*repositories, error) {
+	switch cfg.Driver {
+	case "file":
+		store, err := filestore.Open(cfg.Path, cfg.SnapshotEvery)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Using file storage at %s", cfg.Path)
+		return &repositories{
+			users:       filestore.NewUserRepository(store),
+			assets:      filestore.NewAssetRepository(store),
+			favourites:  filestore.NewFavouriteRepository(store),
+			collections: filestore.NewCollectionRepository(store),
+			apiKeys:     filestore.NewAPIKeyRepository(store),
+			closer:      store,
+		}, nil
+
+	case "sql":
+		db, err := sqlrepo.Open(cfg.SQLDriver, cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlrepo.Migrate(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+		log.Printf("Using %s database %s", cfg.SQLDriver, cfg.DSN)
+		favouriteRepo := sqlrepo.NewFavouriteRepository(db)
+		return &repositories{
+			users:       sqlrepo.NewUserRepository(db, favouriteRepo),
+			assets:      sqlrepo.NewAssetRepository(db),
+			favourites:  favouriteRepo,
+			collections: sqlrepo.NewCollectionRepository(db),
+			apiKeys:     sqlrepo.NewAPIKeyRepository(db),
+			closer:      db,
+		}, nil
+
+	case "memory", "":
+		favouriteRepo := inmemory.NewFavouriteRepository()
+		assetCache := cache.InitLRUCacheWithEvict[string, entities.AssetEntity](50)
+		collectionRepo := inmemory.NewCollectionRepository()
+		return &repositories{
+			users:       inmemory.NewUserRepository(favouriteRepo, collectionRepo),
+			assets:      inmemory.NewAssetRepository(assetCache),
+			favourites:  favouriteRepo,
+			collections: collectionRepo,
+			apiKeys:     inmemory.NewAPIKeyRepository(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// Close releases the resources held by the storage backend
+func (application *App) Close() error {
+	if application.closer == nil {
+		return nil
+	}
+	return application.closer.Close()
+}
+
 // Run configures the routes and starts the server
 func (application *App) Run() error {
 
@@ -75,33 +192,113 @@ func (application *App) Run() error {
 	// API routes
 	router.Route("/api/v1", func(apiRouter chi.Router) {
 		// Authenticate all API routes
-		apiRouter.Use(middleware.AuthMiddleware(application.Keycloak))
+		apiRouter.Use(middleware.AuthMiddleware(application.Verifier, application.AuthenticateKey))
+		apiRouter.Use(middleware.GrantPermissions(application.Permissions))
+		apiRouter.Use(middleware.ResolveCaller(application.ResolveUserID))
+
+		// User-scoped resources are only accessible to their owner and to user administrators
+		selfOrAdmin := func(owner middleware.OwnerExtractor) func(http.Handler) http.Handler {
+			return middleware.RequireSelfOrPermission(owner, auth.PermissionUsersAdmin)
+		}
+		userParam := middleware.OwnerFromURLParam("id")
+		userIdParam := middleware.OwnerFromURLParam("userId")
+
+		//Group API Keys: keys restricted to users cannot manage keys, so they cannot issue themselves wider ones
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAPIKeysAdmin), middleware.RejectRestrictedAPIKeys()).With(middleware.ValidateBody[dto.APIKeyRequest]()).
+			Post("/api-keys", application.APIKeyHandler.Create)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAPIKeysAdmin), middleware.RejectRestrictedAPIKeys()).
+			Get("/api-keys", application.APIKeyHandler.List)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAPIKeysAdmin), middleware.RejectRestrictedAPIKeys()).
+			Delete("/api-keys/{id}", application.APIKeyHandler.Revoke)
 
-		//Group Users
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
+		//Group Users: keys restricted to users only reach those users, even with administrator roles
+		apiRouter.With(middleware.RequirePermission(auth.PermissionUsersAdmin), middleware.RejectRestrictedAPIKeys()).
 			Get("/users", application.UserHandler.List)
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionUsersAdmin), middleware.RequireAPIKeyUser(userParam)).
 			Get("/users/{id}", application.UserHandler.Get)
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).With(middleware.ValidateBody[dto.CreateUserRequest]()).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionUsersAdmin), middleware.RejectRestrictedAPIKeys()).With(middleware.ValidateBody[dto.CreateUserRequest]()).
 			Post("/users", application.UserHandler.Create)
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionUsersAdmin), middleware.RequireAPIKeyUser(userParam)).
 			Put("/users/{id}", application.UserHandler.Update)
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionUsersAdmin), middleware.RequireAPIKeyUser(userParam)).
 			Delete("/users/{id}", application.UserHandler.Delete)
-		apiRouter.With(middleware.RequireAnyRole("Users")).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesRead), selfOrAdmin(userParam)).
 			Get("/users/{id}/favourites", application.UserHandler.GetFavourites)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesRead), selfOrAdmin(userParam)).
+			Get("/users/{id}/favourites/export", application.UserHandler.ExportFavourites)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesRead), selfOrAdmin(userParam)).
+			Get("/users/{id}/favourites/removed", application.FavouriteHandler.ListRemoved)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).
+			Delete("/users/{id}/favourites/removed/{assetId}", application.FavouriteHandler.DismissRemoved)
+
+		//Group Collections
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).With(middleware.ValidateBody[dto.CollectionRequest]()).
+			Post("/users/{id}/collections", application.CollectionHandler.Create)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesRead), selfOrAdmin(userParam)).
+			Get("/users/{id}/collections", application.CollectionHandler.List)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesRead), selfOrAdmin(userParam)).
+			Get("/users/{id}/collections/{cid}", application.CollectionHandler.Get)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).With(middleware.ValidateBody[dto.CollectionRequest]()).
+			Patch("/users/{id}/collections/{cid}", application.CollectionHandler.Rename)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).
+			Delete("/users/{id}/collections/{cid}", application.CollectionHandler.Delete)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).
+			Put("/users/{id}/collections/{cid}/items/{assetId}", application.CollectionHandler.AddItem)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).
+			Delete("/users/{id}/collections/{cid}/items/{assetId}", application.CollectionHandler.RemoveItem)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).With(middleware.ValidateBody[dto.CollectionOrderRequest]()).
+			Put("/users/{id}/collections/{cid}/order", application.CollectionHandler.Reorder)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userParam)).With(middleware.ValidateBody[dto.FavouriteCollectionsRequest]()).
+			Post("/users/{id}/favourites/{assetId}/collections", application.CollectionHandler.AddToCollections)
 
 		//Group Favourites
-		apiRouter.With(middleware.RequireAnyRole("Users")).With(middleware.ValidateBody[dto.FavouriteRequest]()).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite)).With(middleware.ValidateBody[dto.FavouriteRequest]()).
+			With(selfOrAdmin(middleware.OwnerFromBody(func(req dto.FavouriteRequest) string { return req.UserId }))).
 			Post("/favourites", application.FavouriteHandler.Create)
-		apiRouter.With(middleware.RequireAnyRole("Users")).
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite)).With(middleware.ValidateBody[dto.FavouriteBatchRequest]()).
+			With(selfOrAdmin(middleware.OwnerFromBody(func(req dto.FavouriteBatchRequest) string { return req.UserId }))).
+			Post("/favourites:batch", application.FavouriteHandler.Batch)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userIdParam)).
 			Delete("/favourites/{userId}/assets/{assetId}", application.FavouriteHandler.Delete)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), selfOrAdmin(userIdParam)).With(middleware.ValidateBody[dto.FavouriteUpdateRequest]()).
+			Patch("/favourites/{userId}/assets/{assetId}", application.FavouriteHandler.Update)
+
+		//Group Me: the user is taken from the token and created on first use
+		apiRouter.Group(func(meRouter chi.Router) {
+			meRouter.Use(middleware.RequirePermission(auth.PermissionFavouritesRead), middleware.ProvisionCaller(application.ProvisionUser))
+			meRouter.Get("/me/favourites", application.MeHandler.ListFavourites)
+			meRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), middleware.ValidateBody[dto.MyFavouriteRequest]()).
+				Post("/me/favourites", application.MeHandler.AddFavourite)
+			meRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite)).
+				Delete("/me/favourites/{assetId}", application.MeHandler.RemoveFavourite)
+			meRouter.With(middleware.RequirePermission(auth.PermissionFavouritesWrite), middleware.ValidateBody[dto.FavouriteUpdateRequest]()).
+				Patch("/me/favourites/{assetId}", application.MeHandler.UpdateFavourite)
+		})
+		apiRouter.Get("/me/permissions", application.MeHandler.Permissions)
 
-			//Group Assets
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
+		//Group Assets
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsWrite)).With(middleware.ValidateBody[dto.AssetRequest]()).
 			Post("/assets", application.AssetHandler.Create)
-		apiRouter.With(middleware.RequireAnyRole("Administrators")).
-			Delete("/assets/{id}", application.AssetHandler.Delete)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsWrite)).
+			Post("/assets:import", application.AssetHandler.Import)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsRead)).
+			Get("/assets", application.AssetHandler.List)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsRead), selfOrAdmin(middleware.OwnerFromQuery("favourites_of"))).
+			Get("/assets/search", application.AssetHandler.Search)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsRead)).
+			Get("/assets/{assetId}", application.AssetHandler.Get)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsRead)).
+			Get("/assets/{assetId}/render", application.AssetHandler.Render)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsWrite)).With(middleware.ValidateBody[dto.AssetRequest]()).
+			Put("/assets/{assetId}", application.AssetHandler.Update)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsWrite)).
+			Patch("/assets/{assetId}", application.AssetHandler.Patch)
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsWrite)).
+			Delete("/assets/{assetId}", application.AssetHandler.Delete)
+
+		//Group Audiences
+		apiRouter.With(middleware.RequirePermission(auth.PermissionAssetsRead)).With(middleware.ValidateBody[dto.AudienceMatchRequest]()).
+			Post("/audiences:match", application.AssetHandler.MatchAudiences)
 	})
 
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
@@ -23,123 +23,58 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/assets": {
-            "post": {
+        "/api-keys": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
-                    }
-                ],
-                "description": "Creates a new asset of specified type (audience, chart, or insight)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Assets"
-                ],
-                "summary": "Create a new asset",
-                "parameters": [
-                    {
-                        "description": "Asset creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.AssetRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/dto.AssetCreationResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input data",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "405": {
-                        "description": "Method not allowed",
-                        "schema": {
-                            "type": "string"
-                        }
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/assets/{assetId}": {
-            "delete": {
-                "security": [
                     {
-                        "BearerAuth": []
+                        "APIKeyAuth": []
                     }
                 ],
-                "description": "Permanently removes an asset from the system",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves every API key, revoked ones included, oldest first. The keys themselves are not returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assets"
-                ],
-                "summary": "Delete an asset",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Asset ID",
-                        "name": "assetId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "API Keys"
                 ],
+                "summary": "List API keys",
                 "responses": {
-                    "204": {
-                        "description": "Asset deleted successfully"
-                    },
-                    "400": {
-                        "description": "Invalid asset ID",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.APIKeyResponse"
+                            }
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/favourites": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
                     }
                 ],
-                "description": "Adds an asset to a user's favourites list",
+                "description": "Issues a key for a machine client, to send in the X-API-Key header. The key acts with the given roles\nand, when user_ids is set, can only reach those users' resources. The key is only shown in this response.\nA caller using an API key can only grant roles its own key holds; keys restricted to users cannot manage keys.",
                 "consumes": [
                     "application/json"
                 ],
@@ -147,112 +82,117 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Favourites"
+                    "API Keys"
                 ],
-                "summary": "Add a favourite",
+                "summary": "Issue an API key",
                 "parameters": [
                     {
-                        "description": "Favourite creation request",
+                        "description": "API key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.FavouriteRequest"
+                            "$ref": "#/definitions/dto.APIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Favourite added successfully"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.APIKeyCreatedResponse"
+                        }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid input data or unknown user",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Role not held by the calling API key",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/favourites/{userId}/{assetId}": {
+        "/api-keys/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
                     }
                 ],
-                "description": "Removes an asset from a user's favourites list",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Revokes the key so it is rejected from now on. Revoked keys stay listed.",
                 "tags": [
-                    "Favourites"
+                    "API Keys"
                 ],
-                "summary": "Remove a favourite",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Asset ID",
-                        "name": "assetId",
+                        "description": "API key ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Favourite removed successfully"
+                    "204": {
+                        "description": "API key revoked"
                     },
                     "400": {
-                        "description": "Invalid user ID or asset ID",
+                        "description": "Invalid API key ID",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
-                        "description": "User or favourite not found",
+                        "description": "API key not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/assets": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all users in the system",
+                "description": "Retrieves a page of assets matching the given filters. Audience filters restrict the result to audiences; ranges are inclusive.",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,29 +200,131 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
+                ],
+                "summary": "List assets",
+                "parameters": [
+                    {
+                        "enum": [
+                            "chart",
+                            "insight",
+                            "audience"
+                        ],
+                        "type": "string",
+                        "description": "Asset type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring of the title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest creation time (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest creation time (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest update time (RFC 3339)",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest update time (RFC 3339)",
+                        "name": "updated_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Audience gender",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Audience birth country",
+                        "name": "birth_country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Audience age group",
+                        "name": "age_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum audience hours on social media",
+                        "name": "hours_social_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum audience hours on social media",
+                        "name": "hours_social_max",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "updated_at",
+                            "-updated_at",
+                            "title",
+                            "-title"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of assets to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
-                        "description": "List of users retrieved successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.UserResponse"
-                            }
+                            "$ref": "#/definitions/dto.AssetsPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter, sort, limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -293,7 +335,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new user account with the provided information",
+                "description": "Creates a new asset of specified type (audience, chart, or insight)",
                 "consumes": [
                     "application/json"
                 ],
@@ -301,56 +343,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create a new asset",
                 "parameters": [
                     {
-                        "description": "User creation request",
+                        "description": "Asset creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
+                            "$ref": "#/definitions/dto.AssetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User created successfully",
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.AssetCreationResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid input data",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Asset ID already taken",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/assets/search": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves user details for the specified user ID",
+                "description": "Ranks assets by relevance (BM25) of their title, description, insight text, audience summary and chart axes titles, categories and series names to the query. Words are matched by their English stem, so \"socializing\" also finds \"social\".",
                 "consumes": [
                     "application/json"
                 ],
@@ -358,52 +406,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Search assets",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Search terms",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only search the favourites of this user ID",
+                        "name": "favourites_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User found successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.AssetSearchResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Missing query or invalid limit",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "405": {
+                        "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
-                    "405": {
-                        "description": "Method not allowed",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/assets/{assetId}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates user information for the specified user ID",
+                "description": "Retrieves a single asset of any type",
                 "consumes": [
                     "application/json"
                 ],
@@ -411,56 +473,145 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
                 ],
-                "summary": "Update a user",
+                "summary": "Get asset by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssetCreationResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the asset, for use in If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid asset ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Asset not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces every field of an asset. The asset type cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assets"
+                ],
+                "summary": "Replace an asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User update request",
+                        "description": "Asset replacement",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.AssetRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being replaced",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated successfully",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.AssetCreationResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the asset"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid input data",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Asset not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Asset type cannot be changed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Asset was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
@@ -471,7 +622,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Permanently removes a user from the system",
+                "description": "Permanently removes an asset from the system",
                 "consumes": [
                     "application/json"
                 ],
@@ -479,154 +630,2357 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete an asset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Asset deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid asset ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Asset was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies a JSON merge patch (RFC 7386) to an asset, e.g. {\"description\": \"New description\"}. The asset type cannot be changed.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assets"
+                ],
+                "summary": "Patch an asset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "JSON merge patch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being patched",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssetCreationResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the asset"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid input data",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Asset not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Asset type cannot be changed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Asset was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Patch beyond the body size limit",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported media type",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
         },
-        "/users/{id}/favourites": {
+        "/assets/{assetId}/render": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves all favourite assets for the specified user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Draws a chart asset as an SVG or PNG image, as its own kind of chart unless another kind is asked for.\nNamed series are drawn in a legend; charts that hold a data matrix plot every other column against the first, or a single column against the row index.\nPie charts draw the first series as slices. Rendered images are cached until the chart is updated.",
                 "produces": [
-                    "application/json"
+                    "image/svg+xml",
+                    "image/png"
                 ],
                 "tags": [
-                    "Users"
+                    "Assets"
                 ],
-                "summary": "Get user favourites",
+                "summary": "Render a chart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Asset ID",
+                        "name": "assetId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "svg",
+                            "png"
+                        ],
+                        "type": "string",
+                        "description": "Image format (default svg)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "line",
+                            "bar",
+                            "pie",
+                            "scatter"
+                        ],
+                        "type": "string",
+                        "description": "Chart kind (default: the chart's own kind)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1200,
+                        "minimum": 100,
+                        "type": "integer",
+                        "description": "Width in pixels (default 640)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1200,
+                        "minimum": 100,
+                        "type": "integer",
+                        "description": "Height in pixels (default 400)",
+                        "name": "height",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User favourites retrieved successfully",
+                        "description": "Chart image",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.FavouriteResponse"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID",
+                        "description": "Invalid asset ID, format, kind or size",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "404": {
-                        "description": "Favourites not found",
+                        "description": "Asset not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "405": {
                         "description": "Method not allowed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Asset is not a chart",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/middleware.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AssetCreationResponse": {
-            "type": "object",
-            "properties": {
-                "age_group": {
-                    "description": "AgeGroup of the audience segment (only for audience assets)\nexample: 18-24",
-                    "type": "string"
-                },
-                "axes_titles": {
-                    "description": "AxesTitles contains the titles for chart axes (only for chart assets)\nexample: {\"x\": \"Time\", \"y\": \"Revenue\"}",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/assets:import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "birth_country": {
-                    "description": "BirthCountry of the audience segment (only for audience assets)\nexample: US",
-                    "type": "string"
-                },
-                "created_at": {
-                    "description": "CreatedAt timestamp when the asset was created\nexample: 2023-10-05T14:30:00Z",
-                    "type": "string"
-                },
-                "data": {
-                    "description": "Data contains the chart or insight data (only for chart and insight assets)\nexample: [{\"x\": \"2023-01\", \"y\": 1000}, {\"x\": \"2023-02\", \"y\": 1500}]"
-                },
-                "description": {
-                    "description": "Description provides more details about the asset\nexample: \"Audience segment of young adults active on social media\"",
-                    "type": "string"
-                },
-                "gender": {
-                    "description": "Gender of the audience segment (only for audience assets)\nexample: female",
-                    "type": "string"
-                },
-                "hours_social": {
-                    "description": "HoursSocial represents hours spent on social media per week (only for audience assets)\nexample: 15",
-                    "type": "integer"
-                },
-                "id": {
-                    "description": "ID is the unique identifier for the asset\nexample: 550e8400-e29b-41d4-a716-446655440000",
-                    "type": "string"
-                },
-                "purchases_last_month": {
-                    "description": "PurchasesLastMo represents number of purchases in the last month (only for audience assets)\nexample: 3",
-                    "type": "integer"
-                },
+                ],
+                "description": "Imports assets from NDJSON (application/x-ndjson, one asset request per line, any type) or CSV (text/csv, audiences only).\nA CSV body starts with a header naming its columns: id, title, description, gender, birth_country, age_group, hours_social, purchases_last_month and criteria, which holds JSON encoded audience criteria.\nEvery line is validated and imported on its own; rejected lines are reported with their line number and do not stop the import.\nThe body may hold up to 32 MiB; the import stops at the limit. Only the first 100 rejected lines are listed, errors_truncated tells when more were rejected.\nIn insert mode an asset whose id is taken is rejected; in upsert mode it replaces the stored asset, which must be of the same type.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assets"
+                ],
+                "summary": "Import assets",
+                "parameters": [
+                    {
+                        "enum": [
+                            "insert",
+                            "upsert"
+                        ],
+                        "type": "string",
+                        "description": "What to do with an asset whose id is taken (default insert)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "One asset per line",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AssetImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mode or CSV header",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "CSV header beyond the body size limit",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported media type",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/audiences:match": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Evaluates the criteria of every audience against one respondent profile, or each of a batch of up to 1000, and returns the IDs of the matching audiences.\nAudiences with single attributes instead of criteria match respondents with all of those attributes, reading hours on social media and purchases as minimums.\nA criterion on an attribute the profile omits does not hold.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audiences"
+                ],
+                "summary": "Match respondents to audiences",
+                "parameters": [
+                    {
+                        "description": "A profile or a batch of profiles",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AudienceMatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching audiences of every profile",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AudienceMatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid profile",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/favourites": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an asset to a user's favourites list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "Add a favourite",
+                "parameters": [
+                    {
+                        "description": "Favourite creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Favourite added successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Asset already favourited",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "User or asset does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/favourites/{userId}/assets/{assetId}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the user's private note, custom title and tags of a favourite. The asset itself is not changed.\nOmitted fields are left unchanged; an empty string clears the note or custom title and an empty list clears the tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "Annotate a favourite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Favourite overrides",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourite updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Favourite not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/favourites/{userId}/{assetId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an asset from a user's favourites list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "Remove a favourite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourite removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid user ID or asset ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User or favourite not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/favourites:batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies up to 500 add and remove operations to a user's favourites in order, in one step, and reports each one:\nadded, already_present, removed, not_present or asset_missing.\nWith all_or_nothing a batch adding an asset that does not exist changes nothing, applied is false and the other operations are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "Add and remove favourites in bulk",
+                "parameters": [
+                    {
+                        "description": "Favourite operations",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Outcome of every operation",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "User does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/favourites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a page of the signed-in user's favourite assets, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Get my favourites",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of favourites to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourites retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouritesPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Token has no subject",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an asset to the signed-in user's favourites list",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Add to my favourites",
+                "parameters": [
+                    {
+                        "description": "Favourite creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MyFavouriteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Favourite added successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Token has no subject",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Asset already favourited",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Asset does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/favourites/{assetId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an asset from the signed-in user's favourites list",
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Remove from my favourites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourite removed successfully"
+                    },
+                    "400": {
+                        "description": "Invalid asset ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Token has no subject",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Favourite not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the signed-in user's private note, custom title and tags of a favourite. The asset itself is not changed.\nOmitted fields are left unchanged; an empty string clears the note or custom title and an empty list clears the tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Annotate one of my favourites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Favourite overrides",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourite updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Token has no subject",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Favourite not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the caller's roles and the permissions they grant, e.g. to hide actions the caller cannot use.\nUnlike the other /me endpoints it needs no permission and works for API keys too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Me"
+                ],
+                "summary": "Get my permissions",
+                "responses": {
+                    "200": {
+                        "description": "Permissions retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionsResponse"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all users in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "List of users retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.UserResponse"
+                            }
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new user account with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Subject already linked to another user",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves user details for the specified user ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User found successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the user, for use in If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates user information for the specified user ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Subject already linked to another user",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently removes a user from the system, together with their favourites, removed favourites and collections\nPermanently removes a user from the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/collections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the user's collections, oldest first, without their assets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "List collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CollectionResponse"
+                            }
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an empty, named collection of favourites for the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Collection name already in use",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "User does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/collections/{cid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a collection with its favourites and their assets, in the collection order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Get a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a collection; the favourites in it are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Delete a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Collection deleted successfully"
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the name of a collection",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Rename a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New collection name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Collection name already in use",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The collection kept changing concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/collections/{cid}/items/{assetId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appends one of the user's favourites to the end of the collection; a favourite already in it keeps its position",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Add a favourite to a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Favourite added to the collection"
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The collection kept changing concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Asset is not one of the user's favourites",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes a favourite out of the collection; the favourite itself is kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Remove a favourite from a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Favourite removed from the collection"
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The collection kept changing concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/collections/{cid}/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the item order of a collection; the request must list every item exactly once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Reorder a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New item order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The collection kept changing concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/favourites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a page of favourite assets for the specified user, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user favourites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of favourites to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User favourites retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouritesPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Favourites not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/favourites/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Streams all favourites of the user, newest first, with their full assets: chart kind, axes, categories, series and data, insight text and audience criteria, summary and attributes.\njson is an array of favourites, ndjson one favourite per line and csv one row per favourite with a header.\nhtml is a self-contained, printable report drawing charts as inline SVG.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Export user favourites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Export format (default json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favourites of the user",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.FavouriteResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or format",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/favourites/removed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the tombstones left in the user's favourites by deleted assets, most recently removed first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "List removed favourites",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Removed favourites retrieved successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.RemovedFavouriteResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/favourites/removed/{assetId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the tombstone left in the user's favourites by a deleted asset",
+                "tags": [
+                    "Favourites"
+                ],
+                "summary": "Dismiss a removed favourite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Removed favourite dismissed"
+                    },
+                    "400": {
+                        "description": "Invalid user ID or asset ID",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/favourites/{assetId}/collections": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appends one of the user's favourites to each of the given collections. Nothing changes unless every collection exists.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Add a favourite to collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "assetId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collections to add the favourite to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FavouriteCollectionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Favourite added to the collections"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "405": {
+                        "description": "Method not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The collection kept changing concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Asset is not one of the user's favourites",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/middleware.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.APIKeyCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Timestamp when the key was issued\nexample: \"2025-10-30T15:04:05Z\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The ID of the key\nexample: \"0b6f4a2e-6c1d-4f57-9a43-3c1e7d2f8b90\"",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "The key, to send in the X-API-Key header\nexample: \"pak_Xk3v9QfA0cW2b7nLr1YhJ5tUe8sMq4zD6gKpVxN2aBo\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "The name of the client\nexample: \"dashboard backend\"",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "The first characters of the key\nexample: \"pak_Xk3v9QfA\"",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "Timestamp when the key was revoked; omitted while it is active\nexample: \"2025-11-02T09:00:00Z\"",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "The roles the key acts with\nexample: [\"Users\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_ids": {
+                    "description": "The users the key is restricted to, if any\nexample: [\"user_123\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "roles",
+                "user_ids"
+            ],
+            "properties": {
+                "name": {
+                    "description": "A name to recognise the client by\nrequired: true\nexample: \"dashboard backend\"",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "roles": {
+                    "description": "The roles the key acts with\nrequired: true\nexample: [\"Users\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_ids": {
+                    "description": "The users whose resources the key may reach; omit for any user the roles allow.\nA key restricted to a single user acts as that user.\nexample: [\"user_123\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Timestamp when the key was issued\nexample: \"2025-10-30T15:04:05Z\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "The ID of the key\nexample: \"0b6f4a2e-6c1d-4f57-9a43-3c1e7d2f8b90\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "The name of the client\nexample: \"dashboard backend\"",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "The first characters of the key\nexample: \"pak_Xk3v9QfA\"",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "Timestamp when the key was revoked; omitted while it is active\nexample: \"2025-11-02T09:00:00Z\"",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "The roles the key acts with\nexample: [\"Users\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_ids": {
+                    "description": "The users the key is restricted to, if any\nexample: [\"user_123\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AssetCreationResponse": {
+            "type": "object",
+            "properties": {
+                "axes_titles": {
+                    "description": "AxesTitles contains the titles for chart axes (only for chart assets)\nexample: {\"x\": \"Time\", \"y\": \"Revenue\"}",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "axes_units": {
+                    "description": "AxesUnits contains the units of the chart axes, in the order of the axes titles (only for chart assets)\nexample: [\"\", \"EUR\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "categories": {
+                    "description": "Categories label the x axis, or the slices of a pie chart (only for chart assets)\nexample: [\"2023-01\", \"2023-02\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "description": "CreatedAt timestamp when the asset was created\nexample: 2023-10-05T14:30:00Z",
+                    "type": "string"
+                },
+                "criteria": {
+                    "description": "Criteria respondents of the audience meet (only for audience assets)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AudienceCriterion"
+                        }
+                    ]
+                },
+                "data": {
+                    "description": "Data contains the chart or insight data (only for chart and insight assets)\nexample: [{\"x\": \"2023-01\", \"y\": 1000}, {\"x\": \"2023-02\", \"y\": 1500}]"
+                },
+                "description": {
+                    "description": "Description provides more details about the asset\nexample: \"Audience segment of young adults active on social media\"",
+                    "type": "string"
+                },
+                "hours_social": {
+                    "description": "HoursSocial is the average hours a day the audience spends on social media, recorded for audiences defined by\nsingle attributes (only for audience assets)\nexample: 4",
+                    "type": "number"
+                },
+                "id": {
+                    "description": "ID is the unique identifier for the asset\nexample: 550e8400-e29b-41d4-a716-446655440000",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind is how the chart is drawn: line, bar, pie or scatter (only for chart assets)\nexample: line",
+                    "type": "string"
+                },
+                "series": {
+                    "description": "Series are the named values of the chart (only for chart assets with series)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChartSeries"
+                    }
+                },
+                "summary": {
+                    "description": "Summary describes the audience in words (only for audience assets)\nexample: Gender is male, aged 24 to 35 and spending more than 3 hours a day on social media",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Text of the insight (only for insight assets)\nexample: 40% of millennials spend more than 3 hours on social media daily",
+                    "type": "string"
+                },
                 "title": {
                     "description": "Title of the asset\nexample: \"Young Social Media Users\"",
                     "type": "string"
@@ -641,83 +2995,441 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.AssetRequest": {
+        "dto.AssetImportErrorResponse": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "What was wrong with the line\nexample: request validation failed",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Every invalid field of the line",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AssetImportFieldError"
+                    }
+                },
+                "id": {
+                    "description": "ID of the asset on the line, when it could be read\nexample: chart_042",
+                    "type": "string"
+                },
+                "line": {
+                    "description": "Number of the line in the request body, starting at 1\nexample: 42",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AssetImportFieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON name or CSV column of the field\nexample: title",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "example: is required",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AssetImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Number of assets created\nexample: 120",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Why each rejected line was rejected, in line order; only the first 100 are listed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AssetImportErrorResponse"
+                    }
+                },
+                "errors_truncated": {
+                    "description": "True when more lines were rejected than errors lists\nexample: false",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Number of lines rejected\nexample: 1",
+                    "type": "integer"
+                },
+                "incomplete": {
+                    "description": "True when the body could not be read past the last error; the lines after it were not imported\nexample: false",
+                    "type": "boolean"
+                },
+                "updated": {
+                    "description": "Number of existing assets replaced (upsert mode only)\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AssetRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "age_group": {
+                    "description": "Age group of the audience (optional, turned into criteria)\nexample: 25-34",
+                    "type": "string"
+                },
+                "axes_titles": {
+                    "description": "Titles for the chart axes, x first (for chart-type assets)\nexample: [\"Age Group\", \"Average Purchases\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "axes_units": {
+                    "description": "Units of the chart axes, in the order of the axes titles (for chart-type assets)\nexample: [\"years\", \"purchases\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "birth_country": {
+                    "description": "Birth country of the audience as an ISO 3166-1 alpha-2 code in any case or an English country name (optional, turned into criteria)\nexample: CA",
+                    "type": "string"
+                },
+                "categories": {
+                    "description": "Labels of the x axis of line and bar charts, or of the slices of a pie chart (for chart-type assets)\nexample: [\"18-24\", \"25-34\", \"35-44\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "description": "Timestamp when the asset was created\nexample: 2025-01-01T12:00:00Z",
+                    "type": "string"
+                },
+                "criteria": {
+                    "description": "Criteria respondents of the audience meet (for audience-type assets). The single attributes above replace the\nconditions on their fields at the top of the criteria.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AudienceCriterion"
+                        }
+                    ]
+                },
+                "data": {
+                    "description": "Data points for the chart when it has no series: the first column of every row is its x value and every\nother column one series (for chart-type assets)\nexample: [[18, 2.3], [25, 3.5], [34, 4.1]]",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number",
+                            "format": "float64"
+                        }
+                    }
+                },
+                "description": {
+                    "description": "Description of the asset\nexample: This chart shows demographic breakdown by age and gender.",
+                    "type": "string"
+                },
+                "gender": {
+                    "description": "Gender associated with the audience in any case (optional, turned into criteria)\nexample: female",
+                    "type": "string"
+                },
+                "hours_social": {
+                    "description": "Average hours spent on social media per day by the audience (optional, kept as it is rather than turned into criteria)\nexample: 4",
+                    "type": "number",
+                    "maximum": 24,
+                    "minimum": 0
+                },
+                "id": {
+                    "description": "Unique identifier of the asset\nexample: 123e4567-e89b-12d3-a456-426614174000",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "How the chart is drawn, line when omitted (for chart-type assets)\nenum: line,bar,pie,scatter\nexample: bar",
+                    "type": "string",
+                    "enum": [
+                        "line",
+                        "bar",
+                        "pie",
+                        "scatter"
+                    ]
+                },
+                "purchases_last_month": {
+                    "description": "Minimum number of purchases made in the last month (optional, turned into criteria)\nexample: 12",
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "Named series of values, one value per category; the alternative to data (for chart-type assets)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChartSeries"
+                    }
+                },
+                "text": {
+                    "description": "Text associated with the insight (optional)\n/ example: This insight highlights key trends in customer behavior.",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title of the asset\nrequired: true\nexample: Customer Demographics Overview",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the asset (audience, chart, or insight)\nrequired: true\nenum: audience,chart,insight\nexample: chart",
+                    "type": "string",
+                    "enum": [
+                        "audience",
+                        "chart",
+                        "insight"
+                    ]
+                },
+                "updated_at": {
+                    "description": "Timestamp when the asset was last updated\nexample: 2025-01-02T15:30:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AssetSearchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "The matching assets, most relevant first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AssetSearchResultResponse"
+                    }
+                }
+            }
+        },
+        "dto.AssetSearchResultResponse": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "description": "The matching asset",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AssetCreationResponse"
+                        }
+                    ]
+                },
+                "score": {
+                    "description": "BM25 relevance score; higher is more relevant\nexample: 2.47",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AssetsPageResponse": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "description": "The assets on this page, in the requested order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AssetCreationResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Opaque cursor to pass as the cursor query parameter for the next page; omitted on the last page\nexample: \"eyJzIjoiY3JlYXRlZF9hdCIsImkiOiJhc3NldF80NTYifQ\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AudienceCriterion": {
+            "type": "object",
+            "required": [
+                "op"
+            ],
+            "properties": {
+                "criteria": {
+                    "description": "Criteria of an and or or group",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AudienceCriterion"
+                    }
+                },
+                "field": {
+                    "description": "Respondent attribute of a condition\nenum: gender,birth_country,age,hours_social,purchases_last_month\nexample: age",
+                    "type": "string",
+                    "enum": [
+                        "gender",
+                        "birth_country",
+                        "age",
+                        "hours_social",
+                        "purchases_last_month"
+                    ]
+                },
+                "max": {
+                    "description": "Highest number matched by between\nexample: 35",
+                    "type": "number"
+                },
+                "min": {
+                    "description": "Lowest number matched by between\nexample: 24",
+                    "type": "number"
+                },
+                "op": {
+                    "description": "Operator combining the criteria, or comparing the field\nenum: and,or,in,not_in,eq,ne,lt,lte,gt,gte,between\nexample: between",
+                    "type": "string",
+                    "enum": [
+                        "and",
+                        "or",
+                        "in",
+                        "not_in",
+                        "eq",
+                        "ne",
+                        "lt",
+                        "lte",
+                        "gt",
+                        "gte",
+                        "between"
+                    ]
+                },
+                "value": {
+                    "description": "Number compared with by eq, ne, lt, lte, gt and gte\nexample: 3",
+                    "type": "number"
+                },
+                "values": {
+                    "description": "Genders (male, female, non-binary, other) or ISO 3166-1 alpha-2 birth countries for in and not_in\nexample: [\"GB\", \"IE\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AudienceMatchRequest": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "description": "A single profile; the alternative to profiles",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.RespondentProfile"
+                        }
+                    ]
+                },
+                "profiles": {
+                    "description": "A batch of up to 1000 profiles; the alternative to profile",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.RespondentProfile"
+                    }
+                }
+            }
+        },
+        "dto.AudienceMatchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "One result per profile, in the order of the request; a single profile has one result",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AudienceMatchResult"
+                    }
+                }
+            }
+        },
+        "dto.AudienceMatchResult": {
+            "type": "object",
+            "properties": {
+                "audience_ids": {
+                    "description": "IDs of the matching audience assets, in ascending order\nexample: [\"audience_001\", \"audience_007\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ChartSeries": {
+            "type": "object",
+            "required": [
+                "name",
+                "values"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Name of the series, shown in the legend\nexample: Average Purchases",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "The values, one per category or per x value\nexample: [2.3, 3.5, 4.1]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "x": {
+                    "description": "The x value of every value, for scatter charts and line charts without categories\nexample: [18, 25, 34]",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "dto.CollectionOrderRequest": {
+            "type": "object",
+            "required": [
+                "asset_ids"
+            ],
+            "properties": {
+                "asset_ids": {
+                    "description": "Every asset ID of the collection exactly once, in the new order\nrequired: true\nexample: [\"asset_456\", \"asset_123\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CollectionRequest": {
             "type": "object",
             "required": [
-                "id",
-                "title",
-                "type"
+                "name"
             ],
             "properties": {
-                "age_group": {
-                    "description": "Age group of the audience (optional)\nexample: 25-34",
-                    "type": "string"
-                },
-                "axes_titles": {
-                    "description": "Titles for the chart axes (for chart-type assets)\nexample: [\"Age Group\", \"Average Purchases\"]",
+                "name": {
+                    "description": "The name of the collection, unique per user\nrequired: true\nexample: \"Q3 campaign\"",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "asset_ids": {
+                    "description": "The asset IDs of the items, in display order\nexample: [\"asset_456\", \"asset_123\"]",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "birth_country": {
-                    "description": "Birth country of the audience (optional)\nexample: Canada",
-                    "type": "string"
-                },
                 "created_at": {
-                    "description": "Timestamp when the asset was created\nexample: 2025-01-01T12:00:00Z",
+                    "description": "Timestamp when the collection was created\nexample: \"2025-10-30T15:04:05Z\"",
                     "type": "string"
                 },
-                "data": {
-                    "description": "Data points for the chart (for chart-type assets)\nexample: [[18, 2.3], [25, 3.5], [34, 4.1]]",
+                "favourites": {
+                    "description": "The items with their assets, in display order; only returned when a single non-empty collection is read",
                     "type": "array",
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "number",
-                            "format": "float64"
-                        }
+                        "$ref": "#/definitions/dto.FavouriteResponse"
                     }
                 },
-                "description": {
-                    "description": "Description of the asset\nexample: This chart shows demographic breakdown by age and gender.",
-                    "type": "string"
-                },
-                "gender": {
-                    "description": "Gender associated with the audience (optional)\nexample: female",
-                    "type": "string"
-                },
-                "hours_social": {
-                    "description": "Average hours spent on social media per day (optional)\nexample: 4",
-                    "type": "integer"
-                },
                 "id": {
-                    "description": "Unique identifier of the asset\nexample: 123e4567-e89b-12d3-a456-426614174000",
+                    "description": "The ID of the collection\nexample: \"5f0c7a51-2a8f-4a3e-9f4e-0d8f2b1c7e11\"",
                     "type": "string"
                 },
-                "purchases_last_month": {
-                    "description": "Number of purchases made in the last month (optional)\nexample: 12",
-                    "type": "integer"
-                },
-                "text": {
-                    "description": "Text associated with the insight (optional)\n/ example: This insight highlights key trends in customer behavior.",
+                "name": {
+                    "description": "The name of the collection\nexample: \"Q3 campaign\"",
                     "type": "string"
                 },
-                "title": {
-                    "description": "Title of the asset\nrequired: true\nexample: Customer Demographics Overview",
+                "updated_at": {
+                    "description": "Timestamp when the collection was last changed\nexample: \"2025-10-30T15:04:05Z\"",
                     "type": "string"
                 },
-                "type": {
-                    "description": "Type of the asset (audience, chart, or insight)\nrequired: true\nenum: audience,chart,insight\nexample: chart",
-                    "type": "string",
-                    "enum": [
-                        "audience",
-                        "chart",
-                        "insight"
-                    ]
-                },
-                "updated_at": {
-                    "description": "Timestamp when the asset was last updated\nexample: 2025-01-02T15:30:00Z",
+                "user_id": {
+                    "description": "The ID of the user owning the collection\nexample: \"user_123\"",
                     "type": "string"
                 }
             }
@@ -743,6 +3455,106 @@ const docTemplate = `{
                     "description": "The user's password\nrequired: true\nexample: \"P@ssword123\"",
                     "type": "string",
                     "minLength": 8
+                },
+                "subject": {
+                    "description": "The identity provider subject (sub claim) that signs in as this user\nexample: \"bea950c6-ae5f-442d-babc-1ab6db7c6c7b\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FavouriteBatchRequest": {
+            "type": "object",
+            "required": [
+                "_id",
+                "operations"
+            ],
+            "properties": {
+                "_id": {
+                    "description": "The ID of the user\nrequired: true\nexample: \"user_123\"",
+                    "type": "string"
+                },
+                "all_or_nothing": {
+                    "description": "Apply nothing when an operation adds an asset that does not exist\nexample: true",
+                    "type": "boolean"
+                },
+                "operations": {
+                    "description": "The operations, applied in order\nrequired: true",
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.FavouriteOperationRequest"
+                    }
+                }
+            }
+        },
+        "dto.FavouriteBatchResponse": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Whether the batch was applied; false when an all-or-nothing batch was rejected\nexample: true",
+                    "type": "boolean"
+                },
+                "results": {
+                    "description": "The outcome of each operation, in request order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FavouriteOperationResponse"
+                    }
+                }
+            }
+        },
+        "dto.FavouriteCollectionsRequest": {
+            "type": "object",
+            "required": [
+                "collection_ids"
+            ],
+            "properties": {
+                "collection_ids": {
+                    "description": "The IDs of the collections to add the favourite to\nrequired: true\nexample: [\"5f0c7a51-2a8f-4a3e-9f4e-0d8f2b1c7e11\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.FavouriteOperationRequest": {
+            "type": "object",
+            "required": [
+                "asset_id",
+                "op"
+            ],
+            "properties": {
+                "asset_id": {
+                    "description": "The ID of the asset\nrequired: true\nexample: \"asset_456\"",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "What to do with the favourite\nrequired: true\nexample: \"add\"",
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "remove"
+                    ]
+                }
+            }
+        },
+        "dto.FavouriteOperationResponse": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "description": "The ID of the asset\nexample: \"asset_456\"",
+                    "type": "string"
+                },
+                "op": {
+                    "description": "What the operation asked for\nexample: \"add\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "What the operation did: added, already_present, removed, not_present, asset_missing or skipped\nexample: \"added\"",
+                    "type": "string"
                 }
             }
         },
@@ -781,12 +3593,162 @@ const docTemplate = `{
                     "description": "Timestamp when the favourite was created\nexample: \"2025-10-30T15:04:05Z\"",
                     "type": "string"
                 },
+                "custom_title": {
+                    "description": "The title the user sees instead of the asset's title\nexample: \"Q3 sales\"",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "The user's private note about the asset\nexample: \"Use in the Q3 deck\"",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "The user's tags, lowercased\nexample: [\"q3\",\"sales\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "description": "The ID of the user\nexample: \"user_123\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FavouriteUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "custom_title": {
+                    "description": "A title to show instead of the asset's title\nexample: \"Q3 sales\"",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "note": {
+                    "description": "A private note about the asset\nexample: \"Use in the Q3 deck\"",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "tags": {
+                    "description": "Tags for the favourite; matching ignores case\nexample: [\"q3\",\"sales\"]",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.FavouritesPageResponse": {
+            "type": "object",
+            "properties": {
+                "favourites": {
+                    "description": "The favourites on this page, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FavouriteResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Opaque cursor to pass as the cursor query parameter for the next page; omitted on the last page\nexample: \"eyJ0IjoiMjAyNS0xMC0zMFQxNTowNDowNVoiLCJhIjoiYXNzZXRfNDU2In0\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MyFavouriteRequest": {
+            "type": "object",
+            "required": [
+                "asset_id"
+            ],
+            "properties": {
+                "asset_id": {
+                    "description": "The ID of the asset\nrequired: true\nexample: \"asset_456\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "description": "The permissions the roles grant\nexample: [\"assets:read\",\"favourites:read\",\"favourites:write\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roles": {
+                    "description": "The caller's realm and client roles\nexample: [\"Users\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.RemovedFavouriteResponse": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "description": "The ID of the deleted asset\nexample: \"asset_456\"",
+                    "type": "string"
+                },
+                "asset_title": {
+                    "description": "The title of the asset when it was deleted\nexample: \"Sales Chart\"",
+                    "type": "string"
+                },
+                "asset_type": {
+                    "description": "The type of the deleted asset\nexample: \"chart\"",
+                    "type": "string"
+                },
+                "favourited_at": {
+                    "description": "Timestamp when the user had favourited the asset\nexample: \"2025-10-30T15:04:05Z\"",
+                    "type": "string"
+                },
+                "removed_at": {
+                    "description": "Timestamp when the asset was deleted\nexample: \"2025-11-02T09:00:00Z\"",
+                    "type": "string"
+                },
                 "user_id": {
                     "description": "The ID of the user\nexample: \"user_123\"",
                     "type": "string"
                 }
             }
         },
+        "dto.RespondentProfile": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Age in years\nexample: 29",
+                    "type": "integer",
+                    "maximum": 150,
+                    "minimum": 0
+                },
+                "birth_country": {
+                    "description": "ISO 3166-1 alpha-2 code of the country the respondent was born in\nexample: GB",
+                    "type": "string"
+                },
+                "gender": {
+                    "description": "Gender of the respondent\nenum: male,female,non-binary,other\nexample: male",
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female",
+                        "non-binary",
+                        "other"
+                    ]
+                },
+                "hours_social": {
+                    "description": "Hours spent on social media a day\nexample: 4.5",
+                    "type": "number",
+                    "maximum": 24,
+                    "minimum": 0
+                },
+                "purchases_last_month": {
+                    "description": "Number of purchases made last month\nexample: 2",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "dto.UpdateUserRequest": {
             "type": "object",
             "required": [
@@ -805,6 +3767,10 @@ const docTemplate = `{
                     "description": "The user's password\nexample: \"NewP@ssword123\"",
                     "type": "string",
                     "minLength": 8
+                },
+                "subject": {
+                    "description": "The identity provider subject (sub claim) that signs in as this user\nexample: \"bea950c6-ae5f-442d-babc-1ab6db7c6c7b\"",
+                    "type": "string"
                 }
             }
         },
@@ -822,11 +3788,67 @@ const docTemplate = `{
                 "name": {
                     "description": "The user's full name\nexample: \"Alice Johnson\"",
                     "type": "string"
+                },
+                "subject": {
+                    "description": "The identity provider subject linked to the user\nexample: \"bea950c6-ae5f-442d-babc-1ab6db7c6c7b\"",
+                    "type": "string"
+                }
+            }
+        },
+        "middleware.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "example: not_found",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "example: asset not found",
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/middleware.ValidationError"
+                    }
+                },
+                "instance": {
+                    "description": "example: /api/v1/assets/asset_456",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "example: 404",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "example: Not Found",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "example: about:blank",
+                    "type": "string"
+                }
+            }
+        },
+        "middleware.ValidationError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
                 }
             }
         }
     },
     "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "An API key issued through POST /api-keys, for machine clients. Example: \"pak_Xk3v9QfA0cW2b7nLr1YhJ5tUe8sMq4zD6gKpVxN2aBo\"",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Enter \"Bearer\" followed by a space and your JWT token. Example: \"Bearer eyJhbGciOiJSUzI1NiIsInR5cCIgOiAiSldUIiw...\"",
             "type": "apiKey",
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+var _ ports.APIKeyHandler = (*APIKeyHandler)(nil)
+
+type APIKeyHandler struct {
+	service ports.APIKeyService
+}
+
+func NewAPIKeyHandler(s ports.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: s}
+}
+
+// Create issues an API key
+// @Summary Issue an API key
+// @Description Issues a key for a machine client, to send in the X-API-Key header. The key acts with the given roles
+// @Description and, when user_ids is set, can only reach those users' resources. The key is only shown in this response.
+// @Description A caller using an API key can only grant roles its own key holds; keys restricted to users cannot manage keys.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body dto.APIKeyRequest true "API key request"
+// @Success 201 {object} dto.APIKeyCreatedResponse
+// @Failure 400 {object} middleware.Problem "Invalid input data or unknown user"
+// @Failure 403 {object} middleware.Problem "Role not held by the calling API key"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.APIKeyRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	if _, viaKey := middleware.GetAPIKeyIDFromContext(r.Context()); viaKey {
+		// A key can only issue keys with roles it holds itself
+		callerRoles, _ := middleware.GetRolesFromContext(r.Context())
+		for _, role := range req.Roles {
+			if !slices.Contains(callerRoles, role) {
+				middleware.WriteProblem(w, r, http.StatusForbidden, "An API key cannot grant role "+role+" it does not hold")
+				return
+			}
+		}
+	}
+
+	key, plain, err := h.service.CreateAPIKey(req.Name, req.Roles, req.UserIds)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, dto.APIKeyCreatedResponse{APIKeyResponse: mapping.APIKeyToResponse(key), Key: plain})
+}
+
+// List retrieves the API keys
+// @Summary List API keys
+// @Description Retrieves every API key, revoked ones included, oldest first. The keys themselves are not returned.
+// @Tags API Keys
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	keys, err := h.service.ListAPIKeys()
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.APIKeysToResponse(keys))
+}
+
+// Revoke revokes an API key
+// @Summary Revoke an API key
+// @Description Revokes the key so it is rejected from now on. Revoked keys stay listed.
+// @Tags API Keys
+// @Param id path string true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 400 {object} middleware.Problem "Invalid API key ID"
+// @Failure 404 {object} middleware.Problem "API key not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing api key id")
+		return
+	}
+
+	err := h.service.RevokeAPIKey(id)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyService is a mock implementation of ports.APIKeyService
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(name string, roles []string, userIDs []string) (domain.APIKey, string, error) {
+	args := m.Called(name, roles, userIDs)
+	return args.Get(0).(domain.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) ListAPIKeys() ([]domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(plain string) (domain.APIKey, error) {
+	args := m.Called(plain)
+	return args.Get(0).(domain.APIKey), args.Error(1)
+}
+
+// newAPIKeyRouter mounts the API key routes the way the server does
+func newAPIKeyRouter(h *APIKeyHandler) chi.Router {
+	router := chi.NewRouter()
+	router.With(middleware.ValidateBody[dto.APIKeyRequest]()).Post("/api-keys", h.Create)
+	router.Get("/api-keys", h.List)
+	router.Delete("/api-keys/{id}", h.Revoke)
+	return router
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	now := time.Now().UTC()
+	key := domain.APIKey{ID: "k1", Name: "reporting", Prefix: "pak_abcdefgh", Roles: []string{"Users"}, UserIDs: []string{"u1"}, CreatedAt: now}
+	revoked := domain.APIKey{ID: "k2", Name: "old", Prefix: "pak_ijklmnop", Roles: []string{"Administrators"}, CreatedAt: now, RevokedAt: now}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		callerKeyRoles []string
+		setupMock      func(*MockAPIKeyService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Happy Path - Create shows the key",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting","roles":["Users"],"user_ids":["u1"]}`,
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("CreateAPIKey", "reporting", []string{"Users"}, []string{"u1"}).Return(key, "pak_abcdefgh-secret", nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"key":"pak_abcdefgh-secret"`,
+		},
+		{
+			name:   "Unhappy Path - Create with an unknown role",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting","roles":["Root"]}`,
+			setupMock:      func(m *MockAPIKeyService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unhappy Path - Create without roles",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting"}`,
+			setupMock:      func(m *MockAPIKeyService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Happy Path - Create through a key with the roles",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting","roles":["Users"],"user_ids":["u1"]}`,
+			callerKeyRoles: []string{"Administrators", "Users"},
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("CreateAPIKey", "reporting", []string{"Users"}, []string{"u1"}).Return(key, "pak_abcdefgh-secret", nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Unhappy Path - Create through a key without the roles",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting","roles":["Administrators","Users"]}`,
+			callerKeyRoles: []string{"Users"},
+			setupMock:      func(m *MockAPIKeyService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Unhappy Path - Create for an unknown user",
+			method: http.MethodPost, url: "/api-keys", body: `{"name":"reporting","roles":["Users"],"user_ids":["missing"]}`,
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("CreateAPIKey", "reporting", []string{"Users"}, []string{"missing"}).Return(domain.APIKey{}, "", domain.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Happy Path - List hides the keys",
+			method: http.MethodGet, url: "/api-keys",
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("ListAPIKeys").Return([]domain.APIKey{key, revoked}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"prefix":"pak_ijklmnop"`,
+		},
+		{
+			name:   "Unhappy Path - List fails",
+			method: http.MethodGet, url: "/api-keys",
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("ListAPIKeys").Return([]domain.APIKey(nil), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "Happy Path - Revoke",
+			method: http.MethodDelete, url: "/api-keys/k1",
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("RevokeAPIKey", "k1").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Unhappy Path - Revoke unknown key",
+			method: http.MethodDelete, url: "/api-keys/missing",
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("RevokeAPIKey", "missing").Return(domain.ErrAPIKeyNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAPIKeyService)
+			tt.setupMock(mockService)
+			router := newAPIKeyRouter(NewAPIKeyHandler(mockService))
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.callerKeyRoles != nil {
+				ctx := context.WithValue(req.Context(), middleware.APIKeyIDKey, "caller")
+				req = req.WithContext(context.WithValue(ctx, middleware.UserRolesKey, tt.callerKeyRoles))
+			}
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			if tt.method == http.MethodGet {
+				assert.NotContains(t, rr.Body.String(), `"key"`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
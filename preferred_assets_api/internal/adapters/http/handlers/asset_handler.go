@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
 	"github.com/go-chi/chi/v5"
 )
@@ -30,32 +35,33 @@ func NewAssetHandler(s ports.AssetService) *AssetHandler {
 // @Produce json
 // @Param request body dto.AssetRequest true "Asset creation request"
 // @Success 201 {object} dto.AssetCreationResponse
-// @Failure 400 {string} string "Invalid input data"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Asset ID already taken"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /assets [post]
 func (h *AssetHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	req, ok := middleware.GetValidatedBody[dto.AssetRequest](r)
 	if !ok {
-		http.Error(w, "missing validated body", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
 		return
 	}
 
 	asset, err := mapping.AssetReqToDomain(req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	createdAsset, err := h.service.CreateAsset(asset)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
@@ -64,17 +70,363 @@ func (h *AssetHandler) Create(w http.ResponseWriter, r *http.Request) {
 	jsonBytes, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		log.Printf("JSON marshaling error: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
 	log.Printf("Assets JSON response: %s", string(jsonBytes))
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, createdAsset.GetVersion())
 	w.WriteHeader(http.StatusCreated)
 	w.Write(jsonBytes)
 }
 
+// List retrieves a filtered page of the asset catalogue
+// @Summary List assets
+// @Description Retrieves a page of assets matching the given filters. Audience filters restrict the result to audiences; ranges are inclusive.
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param type query string false "Asset type" Enums(chart, insight, audience)
+// @Param title query string false "Case-insensitive substring of the title"
+// @Param created_from query string false "Earliest creation time (RFC 3339)"
+// @Param created_to query string false "Latest creation time (RFC 3339)"
+// @Param updated_from query string false "Earliest update time (RFC 3339)"
+// @Param updated_to query string false "Latest update time (RFC 3339)"
+// @Param gender query string false "Audience gender"
+// @Param birth_country query string false "Audience birth country"
+// @Param age_group query string false "Audience age group"
+// @Param hours_social_min query number false "Minimum audience hours on social media"
+// @Param hours_social_max query number false "Maximum audience hours on social media"
+// @Param sort query string false "Sort field, prefixed with - for descending order" Enums(created_at, -created_at, updated_at, -updated_at, title, -title)
+// @Param limit query int false "Maximum number of assets to return (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} dto.AssetsPageResponse
+// @Failure 400 {object} middleware.Problem "Invalid filter, sort, limit or cursor"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets [get]
+func (h *AssetHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query, err := parseAssetQuery(r)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListAssets(query)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	response := mapping.AssetPageToResponse(page)
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		log.Printf("JSON marshaling error: %v", err)
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonBytes)
+}
+
+// Search runs a full-text search over the asset catalogue
+// @Summary Search assets
+// @Description Ranks assets by relevance (BM25) of their title, description, insight text, audience summary and chart axes titles, categories and series names to the query. Words are matched by their English stem, so "socializing" also finds "social".
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param q query string true "Search terms"
+// @Param favourites_of query string false "Only search the favourites of this user ID"
+// @Param limit query int false "Maximum number of results to return (default 20, max 100)"
+// @Success 200 {object} dto.AssetSearchResponse
+// @Failure 400 {object} middleware.Problem "Missing query or invalid limit"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets/search [get]
+func (h *AssetHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing search query")
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.SearchAssets(query, r.URL.Query().Get("favourites_of"), limit)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	response := mapping.AssetSearchResultsToResponse(results)
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		log.Printf("JSON marshaling error: %v", err)
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonBytes)
+}
+
+// Get retrieves an asset by ID
+// @Summary Get asset by ID
+// @Description Retrieves a single asset of any type
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Success 200 {object} dto.AssetCreationResponse
+// @Header 200 {string} ETag "Version of the asset, for use in If-Match"
+// @Failure 400 {object} middleware.Problem "Invalid asset ID"
+// @Failure 404 {object} middleware.Problem "Asset not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets/{assetId} [get]
+func (h *AssetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	assetID := chi.URLParam(r, "assetId")
+	if assetID == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	asset, err := h.service.GetAsset(assetID)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeAsset(w, r, http.StatusOK, asset)
+}
+
+// Update replaces an asset by ID
+// @Summary Replace an asset
+// @Description Replaces every field of an asset. The asset type cannot be changed.
+// @Tags Assets
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param request body dto.AssetRequest true "Asset replacement"
+// @Param If-Match header string false "ETag of the version being replaced"
+// @Success 200 {object} dto.AssetCreationResponse
+// @Header 200 {string} ETag "New version of the asset"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "Asset not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Asset type cannot be changed"
+// @Failure 412 {object} middleware.Problem "Asset was modified since it was read"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets/{assetId} [put]
+func (h *AssetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	assetID := chi.URLParam(r, "assetId")
+	if assetID == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.AssetRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	expectedVersion, ok := h.precondition(w, r, assetID)
+	if !ok {
+		return
+	}
+
+	h.replace(w, r, assetID, req, expectedVersion)
+}
+
+// Patch partially updates an asset by ID
+// @Summary Patch an asset
+// @Description Applies a JSON merge patch (RFC 7386) to an asset, e.g. {"description": "New description"}. The asset type cannot be changed.
+// @Tags Assets
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param request body object true "JSON merge patch"
+// @Param If-Match header string false "ETag of the version being patched"
+// @Success 200 {object} dto.AssetCreationResponse
+// @Header 200 {string} ETag "New version of the asset"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "Asset not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Asset type cannot be changed"
+// @Failure 412 {object} middleware.Problem "Asset was modified since it was read"
+// @Failure 413 {object} middleware.Problem "Patch beyond the body size limit"
+// @Failure 415 {object} middleware.Problem "Unsupported media type"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets/{assetId} [patch]
+func (h *AssetHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			middleware.WriteProblem(w, r, http.StatusUnsupportedMediaType, "unsupported media type")
+			return
+		}
+	}
+
+	assetID := chi.URLParam(r, "assetId")
+	if assetID == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			middleware.WriteProblem(w, r, http.StatusRequestEntityTooLarge, bodyReadError(err).Error())
+			return
+		}
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	existing, err := h.service.GetAsset(assetID)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+	if !ifMatch(r, existing.GetVersion()) {
+		middleware.WriteError(w, r, domain.ErrVersionConflict)
+		return
+	}
+
+	current, err := mapping.AssetDomainToRequest(existing)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+	original, err := json.Marshal(current)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	patched, err := applyMergePatch(original, patch)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	var req dto.AssetRequest
+	if err := json.Unmarshal(patched, &req); err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	// The patched asset is held to the same rules as one sent whole to PUT
+	if fields := middleware.ValidateStruct(req); fields != nil {
+		middleware.WriteValidationProblem(w, r, fields)
+		return
+	}
+
+	// The patch was computed against the version just read, so the write is conditioned on it
+	h.replace(w, r, assetID, req, existing.GetVersion())
+}
+
+// precondition resolves the version a write to the asset is conditioned on.
+// Without an If-Match header the write is unconditional, otherwise the header is checked against the current version.
+func (h *AssetHandler) precondition(w http.ResponseWriter, r *http.Request, assetID string) (int64, bool) {
+	if !hasIfMatch(r) {
+		return ports.AnyVersion, true
+	}
+
+	asset, err := h.service.GetAsset(assetID)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return 0, false
+	}
+	if !ifMatch(r, asset.GetVersion()) {
+		middleware.WriteError(w, r, domain.ErrVersionConflict)
+		return 0, false
+	}
+	return asset.GetVersion(), true
+}
+
+// replace stores req as the new state of the asset and writes the result
+func (h *AssetHandler) replace(w http.ResponseWriter, r *http.Request, assetID string, req dto.AssetRequest, expectedVersion int64) {
+	if req.ID != assetID {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "asset id cannot be changed")
+		return
+	}
+
+	asset, err := mapping.AssetReqToDomain(req)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatedAsset, err := h.service.UpdateAsset(asset, expectedVersion)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeAsset(w, r, http.StatusOK, updatedAsset)
+}
+
+func writeAsset(w http.ResponseWriter, r *http.Request, status int, asset domain.Asset) {
+	response := mapping.AssetDomainToCreationResponse(asset)
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("JSON marshaling error: %v", err)
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setETag(w, asset.GetVersion())
+	w.WriteHeader(status)
+	w.Write(jsonBytes)
+}
+
 // Delete removes an asset by ID
 // @Summary Delete an asset
 // @Description Permanently removes an asset from the system
@@ -82,27 +434,34 @@ func (h *AssetHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param assetId path string true "Asset ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 204 "Asset deleted successfully"
-// @Failure 400 {string} string "Invalid asset ID"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} middleware.Problem "Invalid asset ID"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 412 {object} middleware.Problem "Asset was modified since it was read"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /assets/{assetId} [delete]
 func (h *AssetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	assetID := chi.URLParam(r, "assetId")
 	if assetID == "" {
-		http.Error(w, "missing asset id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	expectedVersion, ok := h.precondition(w, r, assetID)
+	if !ok {
 		return
 	}
 
-	err := h.service.DeleteAsset(assetID)
+	err := h.service.DeleteAsset(assetID, expectedVersion)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,11 +32,61 @@ func (m *MockAssetService) CreateAsset(asset domain.Asset) (domain.Asset, error)
 	return args.Get(0).(domain.Asset), args.Error(1)
 }
 
-func (m *MockAssetService) DeleteAsset(assetID string) error {
+func (m *MockAssetService) GetAsset(assetID string) (domain.Asset, error) {
 	args := m.Called(assetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(domain.Asset), args.Error(1)
+}
+
+func (m *MockAssetService) ListAssets(query domain.AssetQuery) (domain.AssetPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(domain.AssetPage), args.Error(1)
+}
+
+func (m *MockAssetService) SearchAssets(query string, favouritesOf string, limit int) ([]domain.AssetSearchResult, error) {
+	args := m.Called(query, favouritesOf, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AssetSearchResult), args.Error(1)
+}
+
+func (m *MockAssetService) UpdateAsset(asset domain.Asset, expectedVersion int64) (domain.Asset, error) {
+	args := m.Called(asset, expectedVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(domain.Asset), args.Error(1)
+}
+
+func (m *MockAssetService) DeleteAsset(assetID string, expectedVersion int64) error {
+	args := m.Called(assetID, expectedVersion)
 	return args.Error(0)
 }
 
+func (m *MockAssetService) ImportAsset(asset domain.Asset, mode domain.AssetImportMode) (domain.AssetImportOutcome, error) {
+	args := m.Called(asset, mode)
+	return args.Get(0).(domain.AssetImportOutcome), args.Error(1)
+}
+
+func (m *MockAssetService) RenderChart(id string, options domain.ChartRenderOptions) ([]byte, error) {
+	args := m.Called(id, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockAssetService) MatchAudiences(profiles []domain.RespondentProfile) ([][]string, error) {
+	args := m.Called(profiles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]string), args.Error(1)
+}
+
 func TestAssetHandler_Create(t *testing.T) {
 	// Save the original Body getter and restore after test
 	originalBodyGetter := middleware.Body
@@ -160,7 +211,7 @@ func TestAssetHandler_Create(t *testing.T) {
 				m.On("CreateAsset", mock.AnythingOfType("*domain.Audience")).Return(asset, errors.New("service error"))
 			},
 			expectedStatus:      http.StatusInternalServerError,
-			expectedBody:        "service error\n",
+			expectedBody:        "internal server error\n",
 			validateBodySucceed: true,
 		},
 	}
@@ -200,7 +251,7 @@ func TestAssetHandler_Create(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
 			} else if tt.expectedStatus == http.StatusCreated {
 				// Validate JSON response structure for success cases
 				var response dto.AssetCreationResponse
@@ -230,7 +281,7 @@ func TestAssetHandler_Delete(t *testing.T) {
 			method:  http.MethodDelete,
 			assetID: "asset-123",
 			setupMock: func(m *MockAssetService) {
-				m.On("DeleteAsset", "asset-123").Return(nil)
+				m.On("DeleteAsset", "asset-123", ports.AnyVersion).Return(nil)
 			},
 			expectedStatus: http.StatusNoContent,
 			expectedBody:   "",
@@ -256,10 +307,10 @@ func TestAssetHandler_Delete(t *testing.T) {
 			method:  http.MethodDelete,
 			assetID: "asset-999",
 			setupMock: func(m *MockAssetService) {
-				m.On("DeleteAsset", "asset-999").Return(errors.New("delete failed"))
+				m.On("DeleteAsset", "asset-999", ports.AnyVersion).Return(errors.New("delete failed"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "delete failed\n",
+			expectedBody:   "internal server error\n",
 		},
 	}
 
@@ -286,13 +337,612 @@ func TestAssetHandler_Delete(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func withAssetID(req *http.Request, assetID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("assetId", assetID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func newTestInsight() *domain.Insight {
+	return &domain.Insight{
+		AssetBase: domain.AssetBase{
+			ID:          "ins-1",
+			Type:        domain.AssetTypeInsight,
+			Title:       "Social media",
+			Description: "Old description",
+			Version:     3,
+		},
+		Text: "40% of millennials",
+	}
+}
+
+func TestAssetHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+	}{
+		{
+			name: "Happy Path - Returns asset",
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Unhappy Path - Asset not found",
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(nil, domain.ErrAssetNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := withAssetID(httptest.NewRequest(http.MethodGet, "/assets/ins-1", nil), "ins-1")
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Get(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response dto.AssetCreationResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "insight", response.Type)
+				assert.Equal(t, "40% of millennials", *response.Text)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssetHandler_List(t *testing.T) {
+	insight := domain.AssetTypeInsight
+	hoursMin := 1.5
+
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Happy Path - Defaults to created_at ascending",
+			url:  "/assets",
+			setupMock: func(m *MockAssetService) {
+				m.On("ListAssets", domain.AssetQuery{SortBy: domain.AssetSortCreatedAt, Limit: defaultPageLimit}).
+					Return(domain.AssetPage{Assets: []domain.Asset{newTestInsight()}, NextCursor: "next"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Happy Path - Parses filters and descending sort",
+			url: "/assets?type=insight&title=millennials&created_from=2025-01-01T00:00:00Z" +
+				"&hours_social_min=1.5&sort=-title&limit=5&cursor=abc",
+			setupMock: func(m *MockAssetService) {
+				m.On("ListAssets", domain.AssetQuery{
+					Type:           &insight,
+					Title:          "millennials",
+					CreatedFrom:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					HoursSocialMin: &hoursMin,
+					SortBy:         domain.AssetSortTitle,
+					Descending:     true,
+					Limit:          5,
+					Cursor:         "abc",
+				}).Return(domain.AssetPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unhappy Path - Unknown type",
+			url:            "/assets?type=video",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid asset type",
+		},
+		{
+			name:           "Unhappy Path - Malformed date",
+			url:            "/assets?updated_to=yesterday",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid updated_to",
+		},
+		{
+			name:           "Unhappy Path - Malformed hours range",
+			url:            "/assets?hours_social_max=lots",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid hours_social_max",
+		},
+		{
+			name:           "Unhappy Path - Unknown sort field",
+			url:            "/assets?sort=-description",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid sort",
+		},
+		{
+			name: "Unhappy Path - Invalid cursor",
+			url:  "/assets?cursor=bogus",
+			setupMock: func(m *MockAssetService) {
+				m.On("ListAssets", mock.AnythingOfType("domain.AssetQuery")).
+					Return(domain.AssetPage{}, domain.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid cursor",
+		},
+		{
+			name: "Unhappy Path - Service error",
+			url:  "/assets",
+			setupMock: func(m *MockAssetService) {
+				m.On("ListAssets", mock.AnythingOfType("domain.AssetQuery")).
+					Return(domain.AssetPage{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.List(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Happy Path - Returns the page and next cursor", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockAssetService)
+		mockService.On("ListAssets", mock.AnythingOfType("domain.AssetQuery")).
+			Return(domain.AssetPage{Assets: []domain.Asset{newTestInsight()}, NextCursor: "next"}, nil)
+		handler := NewAssetHandler(mockService)
+		rr := httptest.NewRecorder()
+
+		// Act
+		handler.List(rr, httptest.NewRequest(http.MethodGet, "/assets", nil))
+
+		// Assert
+		var response dto.AssetsPageResponse
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Len(t, response.Assets, 1)
+		assert.Equal(t, "insight", response.Assets[0].Type)
+		assert.Equal(t, "next", response.NextCursor)
+	})
+}
+
+func TestAssetHandler_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Happy Path - Returns ranked results",
+			url:  "/assets/search?q=millennials+social+media",
+			setupMock: func(m *MockAssetService) {
+				m.On("SearchAssets", "millennials social media", "", defaultPageLimit).
+					Return([]domain.AssetSearchResult{{Asset: newTestInsight(), Score: 1.5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"score": 1.5`,
+		},
+		{
+			name: "Happy Path - Restricts to a user's favourites",
+			url:  "/assets/search?q=social&favourites_of=user_123&limit=5",
+			setupMock: func(m *MockAssetService) {
+				m.On("SearchAssets", "social", "user_123", 5).Return([]domain.AssetSearchResult{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"results": []`,
+		},
+		{
+			name:           "Unhappy Path - Missing query",
+			url:            "/assets/search?q=+",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "missing search query",
+		},
+		{
+			name:           "Unhappy Path - Invalid limit",
+			url:            "/assets/search?q=social&limit=0",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid limit",
+		},
+		{
+			name: "Unhappy Path - Service error",
+			url:  "/assets/search?q=social",
+			setupMock: func(m *MockAssetService) {
+				m.On("SearchAssets", "social", "", defaultPageLimit).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Search(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssetHandler_Update(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	tests := []struct {
+		name           string
+		requestBody    dto.AssetRequest
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Happy Path - Replaces asset",
+			requestBody: dto.AssetRequest{ID: "ins-1", Type: "insight", Title: "New title", Text: stringPtr("New text")},
+			setupMock: func(m *MockAssetService) {
+				m.On("UpdateAsset", mock.MatchedBy(func(a domain.Asset) bool {
+					return a.GetTitle() == "New title" && a.(*domain.Insight).Text == "New text"
+				}), ports.AnyVersion).Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unhappy Path - Body id differs from path",
+			requestBody:    dto.AssetRequest{ID: "other", Type: "insight", Title: "New title"},
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "asset id cannot be changed\n",
+		},
+		{
+			name:        "Unhappy Path - Type change rejected",
+			requestBody: dto.AssetRequest{ID: "ins-1", Type: "chart", Title: "New title", Data: [][]float64{{1}}},
+			setupMock: func(m *MockAssetService) {
+				m.On("UpdateAsset", mock.AnythingOfType("*domain.Chart"), ports.AnyVersion).Return(nil, domain.ErrAssetTypeImmutable)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "Unhappy Path - Domain validation fails",
+			requestBody: dto.AssetRequest{ID: "ins-1", Type: "insight", Title: "New title"},
+			setupMock: func(m *MockAssetService) {
+				m.On("UpdateAsset", mock.AnythingOfType("*domain.Insight"), ports.AnyVersion).Return(nil, domain.ErrInvalidAsset)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			middleware.Body = MockBodyGetter{MockedBody: tt.requestBody, ShouldSucceed: true}
+			req := withAssetID(httptest.NewRequest(http.MethodPut, "/assets/ins-1", nil), "ins-1")
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Update(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assertBody(t, tt.expectedBody, rr)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssetHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		patch          string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+	}{
+		{
+			name:        "Happy Path - Edits only the description",
+			contentType: "application/merge-patch+json",
+			patch:       `{"description": "New description"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+				m.On("UpdateAsset", mock.MatchedBy(func(a domain.Asset) bool {
+					insight := a.(*domain.Insight)
+					return insight.Description == "New description" &&
+						insight.Title == "Social media" &&
+						insight.Text == "40% of millennials"
+				}), int64(3)).Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "Happy Path - Null removes a member",
+			contentType: "application/json",
+			patch:       `{"description": null}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+				m.On("UpdateAsset", mock.MatchedBy(func(a domain.Asset) bool {
+					return a.GetDescription() == ""
+				}), int64(3)).Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "Unhappy Path - Id change rejected",
+			contentType: "application/merge-patch+json",
+			patch:       `{"id": "other"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Unhappy Path - Patched asset fails request validation",
+			contentType: "application/merge-patch+json",
+			patch:       `{"title": null}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Unhappy Path - Invalid JSON",
+			contentType: "application/merge-patch+json",
+			patch:       `{"description":`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Unhappy Path - Asset not found",
+			contentType: "application/merge-patch+json",
+			patch:       `{"description": "New description"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(nil, domain.ErrAssetNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unhappy Path - Patch beyond the body size limit",
+			contentType:    "application/merge-patch+json",
+			patch:          `{"description": "` + strings.Repeat("x", maxImportBodySize) + `"}`,
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Unhappy Path - Unsupported media type",
+			contentType:    "text/plain",
+			patch:          `description`,
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := withAssetID(httptest.NewRequest(http.MethodPatch, "/assets/ins-1", bytes.NewBufferString(tt.patch)), "ins-1")
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Patch(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssetHandler_PatchLegacyAudienceFields(t *testing.T) {
+	// Arrange
+	stored := &domain.Audience{
+		AssetBase: domain.AssetBase{ID: "aud-1", Type: domain.AssetTypeAudience, Title: "Young women", Version: 2},
+		Criteria: &domain.AudienceCriterion{Operator: domain.AudienceAnd, Criteria: []domain.AudienceCriterion{
+			{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
+			{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"GR"}},
+		}},
+	}
+	mockService := new(MockAssetService)
+	mockService.On("GetAsset", "aud-1").Return(stored, nil)
+	mockService.On("UpdateAsset", mock.MatchedBy(func(a domain.Asset) bool {
+		audience := a.(*domain.Audience)
+		children := audience.Criteria.Criteria
+		return len(children) == 2 &&
+			children[0].Values[0] == "female" &&
+			children[1].Values[0] == "CA" &&
+			audience.AverageHoursSocial == 4.5
+	}), int64(2)).Return(stored, nil)
+	handler := NewAssetHandler(mockService)
+	req := withAssetID(httptest.NewRequest(http.MethodPatch, "/assets/aud-1",
+		bytes.NewBufferString(`{"birth_country": "Canada", "hours_social": 4.5}`)), "aud-1")
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.Patch(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestAssetHandler_IfMatch(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+	middleware.Body = MockBodyGetter{
+		MockedBody:    dto.AssetRequest{ID: "ins-1", Type: "insight", Title: "New title", Text: stringPtr("New text")},
+		ShouldSucceed: true,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		ifMatch        string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+	}{
+		{
+			name:    "Happy Path - PUT with current ETag",
+			method:  http.MethodPut,
+			ifMatch: `"3"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+				m.On("UpdateAsset", mock.AnythingOfType("*domain.Insight"), int64(3)).Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "Unhappy Path - PUT with stale ETag",
+			method:  http.MethodPut,
+			ifMatch: `"2"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Unhappy Path - PUT loses the race after the check",
+			method:  http.MethodPut,
+			ifMatch: `"3"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+				m.On("UpdateAsset", mock.AnythingOfType("*domain.Insight"), int64(3)).Return(nil, ports.ErrVersionConflict)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Unhappy Path - PATCH with stale ETag",
+			method:  http.MethodPatch,
+			ifMatch: `"2"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Happy Path - DELETE with current ETag",
+			method:  http.MethodDelete,
+			ifMatch: `"3"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+				m.On("DeleteAsset", "ins-1", int64(3)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:    "Unhappy Path - DELETE with stale ETag",
+			method:  http.MethodDelete,
+			ifMatch: `"2"`,
+			setupMock: func(m *MockAssetService) {
+				m.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := withAssetID(httptest.NewRequest(tt.method, "/assets/ins-1", bytes.NewBufferString(`{"title": "New title"}`)), "ins-1")
+			req.Header.Set("If-Match", tt.ifMatch)
+			rr := httptest.NewRecorder()
+
+			// Act
+			switch tt.method {
+			case http.MethodPut:
+				handler.Update(rr, req)
+			case http.MethodPatch:
+				handler.Patch(rr, req)
+			case http.MethodDelete:
+				handler.Delete(rr, req)
 			}
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 			mockService.AssertExpectations(t)
 		})
 	}
 }
 
+func TestAssetHandler_Get_SetsETag(t *testing.T) {
+	// Arrange
+	mockService := new(MockAssetService)
+	mockService.On("GetAsset", "ins-1").Return(newTestInsight(), nil)
+	handler := NewAssetHandler(mockService)
+	req := withAssetID(httptest.NewRequest(http.MethodGet, "/assets/ins-1", nil), "ins-1")
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.Get(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+}
+
 func TestNewAssetHandler(t *testing.T) {
 	t.Run("should create new asset handler", func(t *testing.T) {
 		// Arrange
@@ -406,3 +1056,73 @@ func TestAssetHandler_InterfaceImplementation(t *testing.T) {
 func stringPointer(s string) *string {
 	return &s
 }
+
+func TestAssetHandler_MatchAudiences(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	age := 29
+	tests := []struct {
+		name           string
+		requestBody    dto.AudienceMatchRequest
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Happy Path - Matches a single profile",
+			requestBody: dto.AudienceMatchRequest{Profile: &dto.RespondentProfile{Gender: "female", Age: &age}},
+			setupMock: func(m *MockAssetService) {
+				m.On("MatchAudiences", mock.MatchedBy(func(profiles []domain.RespondentProfile) bool {
+					return len(profiles) == 1 && profiles[0].Gender == domain.GenderFemale && *profiles[0].Age == 29
+				})).Return([][]string{{"aud-1", "aud-2"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[{"audience_ids":["aud-1","aud-2"]}]}`,
+		},
+		{
+			name:        "Happy Path - Matches a batch in order",
+			requestBody: dto.AudienceMatchRequest{Profiles: []dto.RespondentProfile{{BirthCountry: "GB"}, {Gender: "male"}}},
+			setupMock: func(m *MockAssetService) {
+				m.On("MatchAudiences", mock.MatchedBy(func(profiles []domain.RespondentProfile) bool {
+					return len(profiles) == 2 && profiles[0].BirthCountry == "GB" && profiles[1].Gender == domain.GenderMale
+				})).Return([][]string{{"aud-3"}, {}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"audience_ids":[]}`,
+		},
+		{
+			name:        "Unhappy Path - Invalid profile",
+			requestBody: dto.AudienceMatchRequest{Profile: &dto.RespondentProfile{BirthCountry: "Canada"}},
+			setupMock: func(m *MockAssetService) {
+				m.On("MatchAudiences", mock.Anything).Return(nil, domain.ErrInvalidProfile)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			middleware.Body = MockBodyGetter{MockedBody: tt.requestBody, ShouldSucceed: true}
+			req := httptest.NewRequest(http.MethodPost, "/audiences:match", nil)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.MatchAudiences(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	csvContentType    = "text/csv"
+
+	// maxImportLineSize caps one line of an NDJSON import
+	maxImportLineSize = 1 << 20
+	// maxImportBodySize caps a whole import body, and the body of an asset patch
+	maxImportBodySize = 32 << 20
+	// maxImportErrors caps the rejected lines listed in an import report; the rest are only counted
+	maxImportErrors = 100
+)
+
+// audienceCSVColumns are the columns a CSV import may have, in any order; CSV imports hold audiences only.
+// The criteria column holds JSON encoded audience criteria.
+var audienceCSVColumns = []string{"id", "title", "description", "gender", "birth_country", "age_group", "hours_social", "purchases_last_month", "criteria"}
+
+// Import creates assets in bulk from a stream of lines
+// @Summary Import assets
+// @Description Imports assets from NDJSON (application/x-ndjson, one asset request per line, any type) or CSV (text/csv, audiences only).
+// @Description A CSV body starts with a header naming its columns: id, title, description, gender, birth_country, age_group, hours_social, purchases_last_month and criteria, which holds JSON encoded audience criteria.
+// @Description Every line is validated and imported on its own; rejected lines are reported with their line number and do not stop the import.
+// @Description The body may hold up to 32 MiB; the import stops at the limit. Only the first 100 rejected lines are listed, errors_truncated tells when more were rejected.
+// @Description In insert mode an asset whose id is taken is rejected; in upsert mode it replaces the stored asset, which must be of the same type.
+// @Tags Assets
+// @Accept application/x-ndjson
+// @Accept text/csv
+// @Produce json
+// @Param mode query string false "What to do with an asset whose id is taken (default insert)" Enums(insert, upsert)
+// @Param request body string true "One asset per line"
+// @Success 200 {object} dto.AssetImportResponse "Import report"
+// @Failure 400 {object} middleware.Problem "Invalid mode or CSV header"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 413 {object} middleware.Problem "CSV header beyond the body size limit"
+// @Failure 415 {object} middleware.Problem "Unsupported media type"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets:import [post]
+func (h *AssetHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	mode := domain.AssetImportInsert
+	if value := r.URL.Query().Get("mode"); value != "" {
+		var ok bool
+		if mode, ok = domain.ParseAssetImportMode(value); !ok {
+			middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid mode: must be insert or upsert")
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBodySize)
+
+	var reader assetImportReader
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case err != nil:
+		middleware.WriteProblem(w, r, http.StatusUnsupportedMediaType, "unsupported media type")
+		return
+	case mediaType == ndjsonContentType:
+		reader = newNDJSONImportReader(r.Body)
+	case mediaType == csvContentType:
+		if reader, err = newAudienceCSVImportReader(r.Body); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				middleware.WriteProblem(w, r, http.StatusRequestEntityTooLarge, err.Error())
+				return
+			}
+			middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		middleware.WriteProblem(w, r, http.StatusUnsupportedMediaType, "unsupported media type")
+		return
+	}
+
+	report := dto.AssetImportResponse{Errors: []dto.AssetImportErrorResponse{}}
+	for {
+		line, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			report.Incomplete = true
+			addImportError(&report, dto.AssetImportErrorResponse{Line: line.number, Detail: err.Error()})
+			break
+		}
+
+		outcome, failure, err := h.importLine(line, mode)
+		if err != nil {
+			middleware.WriteError(w, r, err)
+			return
+		}
+		switch {
+		case failure != nil:
+			addImportError(&report, *failure)
+		case outcome == domain.AssetImportCreated:
+			report.Created++
+		default:
+			report.Updated++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// addImportError counts a rejected line and lists it while the report has room for it
+func addImportError(report *dto.AssetImportResponse, failure dto.AssetImportErrorResponse) {
+	report.Failed++
+	if len(report.Errors) == maxImportErrors {
+		report.ErrorsTruncated = true
+		return
+	}
+	report.Errors = append(report.Errors, failure)
+}
+
+// bodyReadError tells why the import body could not be read any further
+func bodyReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("body is larger than %d bytes: %w", tooLarge.Limit, err)
+	}
+	return fmt.Errorf("reading the body failed: %w", err)
+}
+
+// importLine validates and stores the asset of one line. What is wrong with the line is returned as its failure;
+// the error is only set when the import cannot go on.
+func (h *AssetHandler) importLine(line assetImportLine, mode domain.AssetImportMode) (domain.AssetImportOutcome, *dto.AssetImportErrorResponse, error) {
+	failure := &dto.AssetImportErrorResponse{Line: line.number, ID: line.request.ID}
+	if line.err != nil {
+		failure.Detail = line.err.Error()
+		return "", failure, nil
+	}
+
+	if fields := append(line.fields, middleware.ValidateStruct(line.request)...); len(fields) > 0 {
+		failure.Detail = "request validation failed"
+		for _, field := range fields {
+			failure.Fields = append(failure.Fields, dto.AssetImportFieldError{Field: field.Field, Message: field.Message})
+		}
+		return "", failure, nil
+	}
+
+	asset, err := mapping.AssetReqToDomain(line.request)
+	if err != nil {
+		failure.Detail = err.Error()
+		return "", failure, nil
+	}
+
+	outcome, err := h.service.ImportAsset(asset, mode)
+	if err != nil {
+		if _, ok := domain.KindOf(err); !ok {
+			return "", nil, err
+		}
+		failure.Detail = err.Error()
+		return "", failure, nil
+	}
+	return outcome, nil, nil
+}
+
+// assetImportLine is the asset request read from one line of an import body
+type assetImportLine struct {
+	number  int
+	request dto.AssetRequest
+	// err tells why the line could not be read, fields which of its values could not be parsed
+	err    error
+	fields []middleware.ValidationError
+}
+
+// assetImportReader reads an import body one line at a time. next returns io.EOF after the last line,
+// and any other error when the body cannot be read any further, with the number of the offending line.
+type assetImportReader interface {
+	next() (assetImportLine, error)
+}
+
+// ndjsonImportReader reads one asset request per line; blank lines are skipped
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONImportReader(body io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+func (r *ndjsonImportReader) next() (assetImportLine, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := bytes.TrimSpace(r.scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		line := assetImportLine{number: r.line}
+		if err := json.Unmarshal(text, &line.request); err != nil {
+			line.request = dto.AssetRequest{}
+			line.err = errors.New("invalid JSON")
+		}
+		return line, nil
+	}
+
+	failed := assetImportLine{number: r.line + 1}
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return failed, fmt.Errorf("line is longer than %d bytes", maxImportLineSize)
+		}
+		return failed, bodyReadError(err)
+	}
+	return failed, io.EOF
+}
+
+// audienceCSVImportReader reads one audience per CSV record, with the columns named by the header
+type audienceCSVImportReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newAudienceCSVImportReader(body io.Reader) (*audienceCSVImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	var parseErr *csv.ParseError
+	switch {
+	case err == io.EOF:
+		return nil, errors.New("missing CSV header")
+	case errors.As(err, &parseErr):
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	case err != nil:
+		return nil, bodyReadError(err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(audienceCSVColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column %q: must be one of %s", name, strings.Join(audienceCSVColumns, ", "))
+		}
+		if slices.Contains(columns[:i], name) {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[i] = name
+	}
+	return &audienceCSVImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *audienceCSVImportReader) next() (assetImportLine, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return assetImportLine{}, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+		// The reader moves on to the next record, so only this line is lost
+		return assetImportLine{
+			number: parseErr.StartLine,
+			err:    fmt.Errorf("expected %d fields, got %d", len(r.columns), len(record)),
+		}, nil
+	}
+	if parseErr != nil {
+		return assetImportLine{number: parseErr.StartLine}, fmt.Errorf("invalid CSV: %v", parseErr.Err)
+	}
+	if err != nil {
+		return assetImportLine{}, bodyReadError(err)
+	}
+
+	line := assetImportLine{}
+	line.number, _ = r.reader.FieldPos(0)
+	line.request, line.fields = audienceRequestFromCSV(r.columns, record)
+	return line, nil
+}
+
+// audienceRequestFromCSV maps a CSV record to an audience request; empty cells leave their field unset
+func audienceRequestFromCSV(columns []string, record []string) (dto.AssetRequest, []middleware.ValidationError) {
+	req := dto.AssetRequest{Type: domain.AssetTypeAudience.String()}
+	var fields []middleware.ValidationError
+	for i, column := range columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case "id":
+			req.ID = value
+		case "title":
+			req.Title = value
+		case "description":
+			req.Description = value
+		case "gender":
+			req.Gender = &value
+		case "birth_country":
+			req.BirthCountry = &value
+		case "age_group":
+			req.AgeGroup = &value
+		case "hours_social":
+			hours, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				fields = append(fields, middleware.ValidationError{Field: column, Message: "must be a number"})
+				continue
+			}
+			req.HoursSocial = &hours
+		case "purchases_last_month":
+			purchases, err := strconv.Atoi(value)
+			if err != nil {
+				fields = append(fields, middleware.ValidationError{Field: column, Message: "must be an integer"})
+				continue
+			}
+			req.PurchasesLastMo = &purchases
+		case "criteria":
+			var criteria dto.AudienceCriterion
+			if err := json.Unmarshal([]byte(value), &criteria); err != nil {
+				fields = append(fields, middleware.ValidationError{Field: column, Message: "must be JSON audience criteria"})
+				continue
+			}
+			req.Criteria = &criteria
+		}
+	}
+	return req, fields
+}
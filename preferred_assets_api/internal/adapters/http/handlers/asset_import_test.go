@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// importedAsset matches the asset of the given id and type passed to ImportAsset
+func importedAsset(id string, assetType domain.AssetType) any {
+	return mock.MatchedBy(func(asset domain.Asset) bool {
+		return asset.GetID() == id && asset.GetType() == assetType
+	})
+}
+
+func TestAssetHandler_Import(t *testing.T) {
+	invalidLines := strings.Repeat("{\n", maxImportErrors+1)
+	listedErrors := make([]dto.AssetImportErrorResponse, maxImportErrors)
+	for i := range listedErrors {
+		listedErrors[i] = dto.AssetImportErrorResponse{Line: i + 1, Detail: "invalid JSON"}
+	}
+
+	tests := []struct {
+		name           string
+		contentType    string
+		query          string
+		body           string
+		setupMock      func(*MockAssetService)
+		expectedStatus int
+		expectedReport dto.AssetImportResponse
+	}{
+		{
+			name:        "Happy Path - NDJSON of every asset type",
+			contentType: "application/x-ndjson",
+			body: `{"id":"c1","type":"chart","title":"Chart","axes_titles":["x","y"],"data":[[1,2]]}` + "\n" +
+				"\n" +
+				`{"id":"i1","type":"insight","title":"Insight","text":"text"}` + "\n" +
+				`{"id":"a1","type":"audience","title":"Audience","gender":"female"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", importedAsset("c1", domain.AssetTypeChart), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+				m.On("ImportAsset", importedAsset("i1", domain.AssetTypeInsight), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+				m.On("ImportAsset", importedAsset("a1", domain.AssetTypeAudience), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Created: 3, Errors: []dto.AssetImportErrorResponse{}},
+		},
+		{
+			name:        "Happy Path - Rejected lines are reported and skipped",
+			contentType: "application/x-ndjson",
+			body: `{"id":"c1","type":"chart","title":"Chart","data":[[1,2]]}` + "\n" +
+				`{"id":"c2",` + "\n" +
+				`{"id":"c3","type":"table"}` + "\n" +
+				`{"id":"c4","type":"chart","title":"Chart"}` + "\n" +
+				`{"id":"c5","type":"chart","title":"Chart","data":[[1,2]]}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", importedAsset("c1", domain.AssetTypeChart), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+				m.On("ImportAsset", importedAsset("c4", domain.AssetTypeChart), domain.AssetImportInsert).
+					Return(domain.AssetImportOutcome(""), fmt.Errorf("%w: data cannot be empty", domain.ErrInvalidAsset))
+				m.On("ImportAsset", importedAsset("c5", domain.AssetTypeChart), domain.AssetImportInsert).Return(domain.AssetImportOutcome(""), domain.ErrAssetExists)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Created: 1, Failed: 4, Errors: []dto.AssetImportErrorResponse{
+				{Line: 2, Detail: "invalid JSON"},
+				{Line: 3, ID: "c3", Detail: "request validation failed", Fields: []dto.AssetImportFieldError{
+					{Field: "type", Message: "must be one of: audience, chart, insight"},
+					{Field: "title", Message: "is required"},
+				}},
+				{Line: 4, ID: "c4", Detail: "invalid asset: data cannot be empty"},
+				{Line: 5, ID: "c5", Detail: "asset already exists"},
+			}},
+		},
+		{
+			name:        "Happy Path - Upsert replaces existing assets",
+			contentType: "application/x-ndjson; charset=utf-8",
+			query:       "?mode=upsert",
+			body:        `{"id":"i1","type":"insight","title":"Insight","text":"text"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", importedAsset("i1", domain.AssetTypeInsight), domain.AssetImportUpsert).Return(domain.AssetImportUpdated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Updated: 1, Errors: []dto.AssetImportErrorResponse{}},
+		},
+		{
+			name:        "Happy Path - CSV of audiences",
+			contentType: "text/csv",
+			body: "id,title,gender,hours_social,purchases_last_month\n" +
+				"a1,Gen Z,Female,4.5,12\n" +
+				"a2,Boomers,male,many,\n" +
+				"a3,Too many,male,1,2,3\n" +
+				"a4,Millennials,,,\n",
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", mock.MatchedBy(func(asset domain.Asset) bool {
+					audience, ok := asset.(*domain.Audience)
+					return ok && audience.ID == "a1" &&
+						audience.Summary() == "Gender is female and with at least 12 purchases last month, averaging 4.5 hours a day on social media"
+				}), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+				m.On("ImportAsset", importedAsset("a4", domain.AssetTypeAudience), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Created: 2, Failed: 2, Errors: []dto.AssetImportErrorResponse{
+				{Line: 3, ID: "a2", Detail: "request validation failed", Fields: []dto.AssetImportFieldError{{Field: "hours_social", Message: "must be a number"}}},
+				{Line: 4, Detail: "expected 5 fields, got 6"},
+			}},
+		},
+		{
+			name:        "Happy Path - CSV of audiences with criteria",
+			contentType: "text/csv",
+			body: "id,title,criteria\n" +
+				`a1,Young men,"{""op"":""and"",""criteria"":[{""op"":""in"",""field"":""gender"",""values"":[""male""]},{""op"":""between"",""field"":""age"",""min"":24,""max"":35}]}"` + "\n" +
+				"a2,Broken,{not json\n",
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", mock.MatchedBy(func(asset domain.Asset) bool {
+					audience, ok := asset.(*domain.Audience)
+					return ok && audience.ID == "a1" && audience.Criteria != nil && len(audience.Criteria.Criteria) == 2 &&
+						*audience.Criteria.Criteria[1].Max == 35
+				}), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Created: 1, Failed: 1, Errors: []dto.AssetImportErrorResponse{
+				{Line: 3, ID: "a2", Detail: "request validation failed", Fields: []dto.AssetImportFieldError{{Field: "criteria", Message: "must be JSON audience criteria"}}},
+			}},
+		},
+		{
+			name:        "Happy Path - Unreadable CSV stops the import",
+			contentType: "text/csv",
+			body:        "id,title\na1,Gen Z\na2,\"Boomers\n",
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", importedAsset("a1", domain.AssetTypeAudience), domain.AssetImportInsert).Return(domain.AssetImportCreated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Created: 1, Failed: 1, Incomplete: true, Errors: []dto.AssetImportErrorResponse{
+				{Line: 3, Detail: `invalid CSV: extraneous or missing " in quoted-field`},
+			}},
+		},
+		{
+			name:           "Happy Path - Only the first rejected lines are listed",
+			contentType:    "application/x-ndjson",
+			body:           invalidLines,
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Failed: maxImportErrors + 1, Errors: listedErrors, ErrorsTruncated: true},
+		},
+		{
+			name:           "Happy Path - Body beyond the size limit stops the import",
+			contentType:    "application/x-ndjson",
+			body:           strings.Repeat("\n", maxImportBodySize+1),
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusOK,
+			expectedReport: dto.AssetImportResponse{Failed: 1, Incomplete: true, Errors: []dto.AssetImportErrorResponse{
+				{Line: maxImportBodySize + 1, Detail: fmt.Sprintf("body is larger than %d bytes: http: request body too large", maxImportBodySize)},
+			}},
+		},
+		{
+			name:           "Unhappy Path - CSV header beyond the size limit",
+			contentType:    "text/csv",
+			body:           "id," + strings.Repeat("x", maxImportBodySize),
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Unhappy Path - Unknown CSV column",
+			contentType:    "text/csv",
+			body:           "id,title,colour\na1,Gen Z,blue\n",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Invalid mode",
+			contentType:    "application/x-ndjson",
+			query:          "?mode=merge",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Unsupported media type",
+			contentType:    "application/json",
+			body:           `[]`,
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "Unhappy Path - Storage failure stops the import",
+			contentType: "application/x-ndjson",
+			body:        `{"id":"i1","type":"insight","title":"Insight","text":"text"}`,
+			setupMock: func(m *MockAssetService) {
+				m.On("ImportAsset", importedAsset("i1", domain.AssetTypeInsight), domain.AssetImportInsert).
+					Return(domain.AssetImportOutcome(""), errors.New("disk full"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := httptest.NewRequest(http.MethodPost, "/assets:import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Import(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var report dto.AssetImportResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+				assert.Equal(t, tt.expectedReport, report)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
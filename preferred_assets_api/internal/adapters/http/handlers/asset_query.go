@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// parseAssetQuery reads the catalogue filters, sort order and pagination from the query string.
+// sort names a field, optionally prefixed with "-" for descending order, and defaults to created_at.
+func parseAssetQuery(r *http.Request) (domain.AssetQuery, error) {
+	values := r.URL.Query()
+	query := domain.AssetQuery{
+		Title:        values.Get("title"),
+		Gender:       values.Get("gender"),
+		BirthCountry: values.Get("birth_country"),
+		AgeGroup:     values.Get("age_group"),
+		Cursor:       values.Get("cursor"),
+		SortBy:       domain.AssetSortCreatedAt,
+	}
+
+	if raw := values.Get("type"); raw != "" {
+		assetType, err := domain.ParseAssetType(raw)
+		if err != nil {
+			return domain.AssetQuery{}, err
+		}
+		query.Type = &assetType
+	}
+
+	times := []struct {
+		name   string
+		target *time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+		{"updated_from", &query.UpdatedFrom},
+		{"updated_to", &query.UpdatedTo},
+	}
+	for _, param := range times {
+		raw := values.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return domain.AssetQuery{}, fmt.Errorf("invalid %s: expected an RFC 3339 timestamp", param.name)
+		}
+		*param.target = t
+	}
+
+	floats := []struct {
+		name   string
+		target **float64
+	}{
+		{"hours_social_min", &query.HoursSocialMin},
+		{"hours_social_max", &query.HoursSocialMax},
+	}
+	for _, param := range floats {
+		raw := values.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return domain.AssetQuery{}, fmt.Errorf("invalid %s", param.name)
+		}
+		*param.target = &f
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		field, ok := domain.ParseAssetSortField(strings.TrimPrefix(raw, "-"))
+		if !ok {
+			return domain.AssetQuery{}, fmt.Errorf("invalid sort: %s", raw)
+		}
+		query.SortBy = field
+		query.Descending = strings.HasPrefix(raw, "-")
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		return domain.AssetQuery{}, err
+	}
+	query.Limit = limit
+
+	return query, nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// chartContentTypes maps the image formats of rendered charts to their media types
+var chartContentTypes = map[domain.ChartFormat]string{
+	domain.ChartSVG: "image/svg+xml",
+	domain.ChartPNG: "image/png",
+}
+
+// Render draws a chart asset as an image
+// @Summary Render a chart
+// @Description Draws a chart asset as an SVG or PNG image, as its own kind of chart unless another kind is asked for.
+// @Description Named series are drawn in a legend; charts that hold a data matrix plot every other column against the first, or a single column against the row index.
+// @Description Pie charts draw the first series as slices. Rendered images are cached until the chart is updated.
+// @Tags Assets
+// @Produce image/svg+xml
+// @Produce image/png
+// @Param assetId path string true "Asset ID"
+// @Param format query string false "Image format (default svg)" Enums(svg, png)
+// @Param kind query string false "Chart kind (default: the chart's own kind)" Enums(line, bar, pie, scatter)
+// @Param width query int false "Width in pixels (default 640)" minimum(100) maximum(1200)
+// @Param height query int false "Height in pixels (default 400)" minimum(100) maximum(1200)
+// @Success 200 {file} binary "Chart image"
+// @Failure 400 {object} middleware.Problem "Invalid asset ID, format, kind or size"
+// @Failure 404 {object} middleware.Problem "Asset not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 422 {object} middleware.Problem "Asset is not a chart"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /assets/{assetId}/render [get]
+func (h *AssetHandler) Render(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	assetID := chi.URLParam(r, "assetId")
+	if assetID == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	options, err := parseChartRenderOptions(r)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	image, err := h.service.RenderChart(assetID, options)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", chartContentTypes[options.Format])
+	w.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+}
+
+// parseChartRenderOptions reads the render options of the query, defaulting to the chart's own kind in SVG
+func parseChartRenderOptions(r *http.Request) (domain.ChartRenderOptions, error) {
+	query := r.URL.Query()
+	options := domain.DefaultChartRenderOptions()
+
+	if value := query.Get("format"); value != "" {
+		var ok bool
+		if options.Format, ok = domain.ParseChartFormat(value); !ok {
+			return options, errors.New("invalid format: must be svg or png")
+		}
+	}
+	if value := query.Get("kind"); value != "" {
+		var ok bool
+		if options.Kind, ok = domain.ParseChartKind(value); !ok {
+			return options, errors.New("invalid kind: must be line, bar, pie or scatter")
+		}
+	}
+
+	for _, size := range []struct {
+		name  string
+		value *int
+	}{{"width", &options.Width}, {"height", &options.Height}} {
+		value := query.Get(size.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < domain.MinChartRenderSize || parsed > domain.MaxChartRenderSize {
+			return options, fmt.Errorf("invalid %s: must be an integer between %d and %d", size.name, domain.MinChartRenderSize, domain.MaxChartRenderSize)
+		}
+		*size.value = parsed
+	}
+	return options, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetHandler_Render(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		setupMock           func(*MockAssetService)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name: "Happy Path - SVG of the chart's own kind by default",
+			setupMock: func(m *MockAssetService) {
+				m.On("RenderChart", "chart-1", domain.ChartRenderOptions{Format: domain.ChartSVG, Width: 640, Height: 400}).
+					Return([]byte("<svg/>"), nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			expectedBody:        "<svg/>",
+		},
+		{
+			name:  "Happy Path - PNG bar chart of a given size",
+			query: "?format=png&kind=bar&width=300&height=200",
+			setupMock: func(m *MockAssetService) {
+				m.On("RenderChart", "chart-1", domain.ChartRenderOptions{Format: domain.ChartPNG, Kind: domain.ChartBar, Width: 300, Height: 200}).
+					Return([]byte("png"), nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        "png",
+		},
+		{
+			name:           "Unhappy Path - Invalid format",
+			query:          "?format=gif",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Invalid kind",
+			query:          "?kind=radar",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Width out of range",
+			query:          "?width=5000",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Height not a number",
+			query:          "?height=tall",
+			setupMock:      func(m *MockAssetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unhappy Path - Asset not found",
+			setupMock: func(m *MockAssetService) {
+				m.On("RenderChart", "chart-1", domain.ChartRenderOptions{Format: domain.ChartSVG, Width: 640, Height: 400}).
+					Return(nil, domain.ErrAssetNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Unhappy Path - Asset is not a chart",
+			setupMock: func(m *MockAssetService) {
+				m.On("RenderChart", "chart-1", domain.ChartRenderOptions{Format: domain.ChartSVG, Width: 640, Height: 400}).
+					Return(nil, domain.ErrNotAChart)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Unhappy Path - Rendering failure",
+			setupMock: func(m *MockAssetService) {
+				m.On("RenderChart", "chart-1", domain.ChartRenderOptions{Format: domain.ChartSVG, Width: 640, Height: 400}).
+					Return(nil, errors.New("encoding failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAssetService)
+			tt.setupMock(mockService)
+			handler := NewAssetHandler(mockService)
+			req := withAssetID(httptest.NewRequest(http.MethodGet, "/assets/chart-1/render"+tt.query, nil), "chart-1")
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Render(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedContentType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
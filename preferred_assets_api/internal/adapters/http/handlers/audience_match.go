@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+)
+
+// MatchAudiences finds the audiences respondent profiles belong to
+// @Summary Match respondents to audiences
+// @Description Evaluates the criteria of every audience against one respondent profile, or each of a batch of up to 1000, and returns the IDs of the matching audiences.
+// @Description Audiences with single attributes instead of criteria match respondents with all of those attributes, reading hours on social media and purchases as minimums.
+// @Description A criterion on an attribute the profile omits does not hold.
+// @Tags Audiences
+// @Accept json
+// @Produce json
+// @Param request body dto.AudienceMatchRequest true "A profile or a batch of profiles"
+// @Success 200 {object} dto.AudienceMatchResponse "Matching audiences of every profile"
+// @Failure 400 {object} middleware.Problem "Invalid profile"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /audiences:match [post]
+func (h *AssetHandler) MatchAudiences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.AudienceMatchRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	matches, err := h.service.MatchAudiences(mapping.AudienceMatchReqToDomain(req))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.AudienceMatchesToResponse(matches))
+}
@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+var _ ports.CollectionHandler = (*CollectionHandler)(nil)
+
+type CollectionHandler struct {
+	service ports.CollectionService
+}
+
+func NewCollectionHandler(s ports.CollectionService) *CollectionHandler {
+	return &CollectionHandler{service: s}
+}
+
+// Create creates a collection for a user
+// @Summary Create a collection
+// @Description Creates an empty, named collection of favourites for the user
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.CollectionRequest true "Collection creation request"
+// @Success 201 {object} dto.CollectionResponse
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Collection name already in use"
+// @Failure 422 {object} middleware.Problem "User does not exist"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections [post]
+func (h *CollectionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.CollectionRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	collection, err := h.service.CreateCollection(chi.URLParam(r, "id"), req.Name)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, mapping.CollectionToResponse(collection))
+}
+
+// List retrieves the collections of a user
+// @Summary List collections
+// @Description Retrieves the user's collections, oldest first, without their assets
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} dto.CollectionResponse
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections [get]
+func (h *CollectionHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	collections, err := h.service.GetCollections(chi.URLParam(r, "id"))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.CollectionsToResponse(collections))
+}
+
+// Get retrieves a collection with its favourites
+// @Summary Get a collection
+// @Description Retrieves a collection with its favourites and their assets, in the collection order
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Success 200 {object} dto.CollectionResponse
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid} [get]
+func (h *CollectionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	collection, err := h.service.GetCollection(chi.URLParam(r, "id"), chi.URLParam(r, "cid"))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.CollectionToResponse(collection))
+}
+
+// Rename renames a collection
+// @Summary Rename a collection
+// @Description Changes the name of a collection
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Param request body dto.CollectionRequest true "New collection name"
+// @Success 200 {object} dto.CollectionResponse
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Collection name already in use"
+// @Failure 412 {object} middleware.Problem "The collection kept changing concurrently"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid} [patch]
+func (h *CollectionHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.CollectionRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	collection, err := h.service.RenameCollection(chi.URLParam(r, "id"), chi.URLParam(r, "cid"), req.Name)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.CollectionToResponse(collection))
+}
+
+// Delete removes a collection
+// @Summary Delete a collection
+// @Description Removes a collection; the favourites in it are kept
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Success 204 "Collection deleted successfully"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid} [delete]
+func (h *CollectionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := h.service.DeleteCollection(chi.URLParam(r, "id"), chi.URLParam(r, "cid")); err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddItem adds a favourite to a collection
+// @Summary Add a favourite to a collection
+// @Description Appends one of the user's favourites to the end of the collection; a favourite already in it keeps its position
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Param assetId path string true "Asset ID"
+// @Success 204 "Favourite added to the collection"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 422 {object} middleware.Problem "Asset is not one of the user's favourites"
+// @Failure 412 {object} middleware.Problem "The collection kept changing concurrently"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid}/items/{assetId} [put]
+func (h *CollectionHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	err := h.service.AddFavourite(chi.URLParam(r, "id"), chi.URLParam(r, "assetId"), []string{chi.URLParam(r, "cid")})
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveItem removes a favourite from a collection
+// @Summary Remove a favourite from a collection
+// @Description Takes a favourite out of the collection; the favourite itself is kept
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Param assetId path string true "Asset ID"
+// @Success 204 "Favourite removed from the collection"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 412 {object} middleware.Problem "The collection kept changing concurrently"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid}/items/{assetId} [delete]
+func (h *CollectionHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	err := h.service.RemoveFavourite(chi.URLParam(r, "id"), chi.URLParam(r, "cid"), chi.URLParam(r, "assetId"))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reorder changes the order of the items in a collection
+// @Summary Reorder a collection
+// @Description Replaces the item order of a collection; the request must list every item exactly once
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param cid path string true "Collection ID"
+// @Param request body dto.CollectionOrderRequest true "New item order"
+// @Success 200 {object} dto.CollectionResponse
+// @Failure 400 {object} middleware.Problem "Invalid order"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 412 {object} middleware.Problem "The collection kept changing concurrently"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/collections/{cid}/order [put]
+func (h *CollectionHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.CollectionOrderRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	collection, err := h.service.ReorderCollection(chi.URLParam(r, "id"), chi.URLParam(r, "cid"), req.AssetIds)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.CollectionToResponse(collection))
+}
+
+// AddToCollections files a favourite into several collections at once
+// @Summary Add a favourite to collections
+// @Description Appends one of the user's favourites to each of the given collections. Nothing changes unless every collection exists.
+// @Tags Collections
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param assetId path string true "Asset ID"
+// @Param request body dto.FavouriteCollectionsRequest true "Collections to add the favourite to"
+// @Success 204 "Favourite added to the collections"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "Collection not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 422 {object} middleware.Problem "Asset is not one of the user's favourites"
+// @Failure 412 {object} middleware.Problem "The collection kept changing concurrently"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/favourites/{assetId}/collections [post]
+func (h *CollectionHandler) AddToCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.FavouriteCollectionsRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	err := h.service.AddFavourite(chi.URLParam(r, "id"), chi.URLParam(r, "assetId"), req.CollectionIds)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, response any) {
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("JSON marshaling error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonBytes)
+}
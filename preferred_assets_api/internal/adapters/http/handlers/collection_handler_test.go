@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCollectionService is a mock implementation of ports.CollectionService
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) CreateCollection(userID string, name string) (domain.Collection, error) {
+	args := m.Called(userID, name)
+	return args.Get(0).(domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) GetCollections(userID string) ([]domain.Collection, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) GetCollection(userID string, collectionID string) (domain.Collection, error) {
+	args := m.Called(userID, collectionID)
+	return args.Get(0).(domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) RenameCollection(userID string, collectionID string, name string) (domain.Collection, error) {
+	args := m.Called(userID, collectionID, name)
+	return args.Get(0).(domain.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) DeleteCollection(userID string, collectionID string) error {
+	args := m.Called(userID, collectionID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) AddFavourite(userID string, assetID string, collectionIDs []string) error {
+	args := m.Called(userID, assetID, collectionIDs)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) RemoveFavourite(userID string, collectionID string, assetID string) error {
+	args := m.Called(userID, collectionID, assetID)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) ReorderCollection(userID string, collectionID string, assetIDs []string) (domain.Collection, error) {
+	args := m.Called(userID, collectionID, assetIDs)
+	return args.Get(0).(domain.Collection), args.Error(1)
+}
+
+// newCollectionRouter mounts the collection routes the way the server does
+func newCollectionRouter(h *CollectionHandler) chi.Router {
+	router := chi.NewRouter()
+	router.With(middleware.ValidateBody[dto.CollectionRequest]()).Post("/users/{id}/collections", h.Create)
+	router.Get("/users/{id}/collections", h.List)
+	router.Get("/users/{id}/collections/{cid}", h.Get)
+	router.With(middleware.ValidateBody[dto.CollectionRequest]()).Patch("/users/{id}/collections/{cid}", h.Rename)
+	router.Delete("/users/{id}/collections/{cid}", h.Delete)
+	router.Put("/users/{id}/collections/{cid}/items/{assetId}", h.AddItem)
+	router.Delete("/users/{id}/collections/{cid}/items/{assetId}", h.RemoveItem)
+	router.With(middleware.ValidateBody[dto.CollectionOrderRequest]()).Put("/users/{id}/collections/{cid}/order", h.Reorder)
+	router.With(middleware.ValidateBody[dto.FavouriteCollectionsRequest]()).Post("/users/{id}/favourites/{assetId}/collections", h.AddToCollections)
+	return router
+}
+
+func TestCollectionHandler(t *testing.T) {
+	collection := domain.Collection{ID: "c1", UserID: "u1", Name: "Campaign", AssetIDs: []string{"a1"}}
+	withItems := collection
+	withItems.Favourites = []domain.Favourite{{UserID: "u1", AssetID: "a1", AssetType: domain.AssetTypeInsight, Insight: newTestInsight()}}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		setupMock      func(*MockCollectionService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Happy Path - Create",
+			method: http.MethodPost, url: "/users/u1/collections", body: `{"name":"Campaign"}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("CreateCollection", "u1", "Campaign").Return(collection, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"name":"Campaign"`,
+		},
+		{
+			name:   "Unhappy Path - Create without a name",
+			method: http.MethodPost, url: "/users/u1/collections", body: `{}`,
+			setupMock:      func(m *MockCollectionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unhappy Path - Create with a taken name",
+			method: http.MethodPost, url: "/users/u1/collections", body: `{"name":"Campaign"}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("CreateCollection", "u1", "Campaign").Return(domain.Collection{}, domain.ErrCollectionNameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Happy Path - List",
+			method: http.MethodGet, url: "/users/u1/collections",
+			setupMock: func(m *MockCollectionService) {
+				m.On("GetCollections", "u1").Return([]domain.Collection{collection}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"asset_ids":["a1"]`,
+		},
+		{
+			name:   "Happy Path - Get returns enhanced favourites",
+			method: http.MethodGet, url: "/users/u1/collections/c1",
+			setupMock: func(m *MockCollectionService) {
+				m.On("GetCollection", "u1", "c1").Return(withItems, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"favourites":[{"user_id":"u1","asset_id":"a1"`,
+		},
+		{
+			name:   "Unhappy Path - Get unknown collection",
+			method: http.MethodGet, url: "/users/u1/collections/missing",
+			setupMock: func(m *MockCollectionService) {
+				m.On("GetCollection", "u1", "missing").Return(domain.Collection{}, domain.ErrCollectionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Happy Path - Rename",
+			method: http.MethodPatch, url: "/users/u1/collections/c1", body: `{"name":"Renamed"}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("RenameCollection", "u1", "c1", "Renamed").Return(collection, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Happy Path - Delete",
+			method: http.MethodDelete, url: "/users/u1/collections/c1",
+			setupMock: func(m *MockCollectionService) {
+				m.On("DeleteCollection", "u1", "c1").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Happy Path - Add item",
+			method: http.MethodPut, url: "/users/u1/collections/c1/items/a2",
+			setupMock: func(m *MockCollectionService) {
+				m.On("AddFavourite", "u1", "a2", []string{"c1"}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Unhappy Path - Add an asset that is not a favourite",
+			method: http.MethodPut, url: "/users/u1/collections/c1/items/a9",
+			setupMock: func(m *MockCollectionService) {
+				m.On("AddFavourite", "u1", "a9", []string{"c1"}).Return(domain.ErrNotAFavourite)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Happy Path - Remove item",
+			method: http.MethodDelete, url: "/users/u1/collections/c1/items/a1",
+			setupMock: func(m *MockCollectionService) {
+				m.On("RemoveFavourite", "u1", "c1", "a1").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Happy Path - Reorder",
+			method: http.MethodPut, url: "/users/u1/collections/c1/order", body: `{"asset_ids":["a1"]}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("ReorderCollection", "u1", "c1", []string{"a1"}).Return(collection, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Unhappy Path - Reorder with a different set of items",
+			method: http.MethodPut, url: "/users/u1/collections/c1/order", body: `{"asset_ids":["a2"]}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("ReorderCollection", "u1", "c1", []string{"a2"}).Return(domain.Collection{}, domain.ErrInvalidCollectionOrder)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Happy Path - Add a favourite to several collections",
+			method: http.MethodPost, url: "/users/u1/favourites/a1/collections", body: `{"collection_ids":["c1","c2"]}`,
+			setupMock: func(m *MockCollectionService) {
+				m.On("AddFavourite", "u1", "a1", []string{"c1", "c2"}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "Unhappy Path - Add a favourite to no collection",
+			method: http.MethodPost, url: "/users/u1/favourites/a1/collections", body: `{"collection_ids":[]}`,
+			setupMock:      func(m *MockCollectionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unhappy Path - Service error",
+			method: http.MethodGet, url: "/users/u1/collections",
+			setupMock: func(m *MockCollectionService) {
+				m.On("GetCollections", "u1").Return([]domain.Collection(nil), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockCollectionService)
+			tt.setupMock(mockService)
+			router := newCollectionRouter(NewCollectionHandler(mockService))
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCollectionHandler_MethodNotAllowed(t *testing.T) {
+	// Arrange
+	handler := NewCollectionHandler(new(MockCollectionService))
+	rr := httptest.NewRecorder()
+
+	// Act
+	handler.Create(rr, httptest.NewRequest(http.MethodGet, "/users/u1/collections", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestCollectionHandler_ResponseShape(t *testing.T) {
+	// Arrange
+	mockService := new(MockCollectionService)
+	mockService.On("GetCollections", "u1").Return([]domain.Collection{{ID: "c1", UserID: "u1", Name: "Empty", AssetIDs: []string{}}}, nil)
+	router := newCollectionRouter(NewCollectionHandler(mockService))
+	rr := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/u1/collections", nil))
+
+	// Assert
+	var response []dto.CollectionResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, []string{}, response[0].AssetIds)
+	assert.Nil(t, response[0].Favourites)
+}
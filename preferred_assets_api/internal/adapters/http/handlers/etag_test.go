@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{name: "no header", header: "", expected: true},
+		{name: "current version", header: `"3"`, expected: true},
+		{name: "stale version", header: `"2"`, expected: false},
+		{name: "any version", header: "*", expected: true},
+		{name: "list containing current version", header: `"1", "3"`, expected: true},
+		{name: "weak tag never matches", header: `W/"3"`, expected: false},
+		{name: "unquoted tag", header: `3`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+
+			// Act & Assert
+			assert.Equal(t, tt.expected, ifMatch(req, 3))
+		})
+	}
+}
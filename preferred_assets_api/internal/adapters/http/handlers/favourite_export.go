@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+// exportPageSize is how many favourites an export reads at a time
+const exportPageSize = maxPageLimit
+
+// favouritesCSVHeader names the columns of a CSV export. Columns that do not apply to a favourite's asset type are
+// empty; lists are JSON encoded.
+var favouritesCSVHeader = []string{
+	"user_id", "asset_id", "asset_type", "favourited_at", "custom_title", "note", "tags",
+	"title", "description", "text",
+	"axes_titles", "data", "chart_kind", "axes_units", "categories", "series", "criteria", "summary",
+}
+
+// favouritesExporter writes the favourites of an export in one format
+type favouritesExporter interface {
+	add(f domain.Favourite) error
+	close() error
+}
+
+// ExportFavourites downloads all favourites of a user
+// @Summary Export user favourites
+// @Description Streams all favourites of the user, newest first, with their full assets: chart kind, axes, categories, series and data, insight text and audience criteria, summary and attributes.
+// @Description json is an array of favourites, ndjson one favourite per line and csv one row per favourite with a header.
+// @Description html is a self-contained, printable report drawing charts as inline SVG.
+// @Tags Users
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Produce text/html
+// @Param id path string true "User ID"
+// @Param format query string false "Export format (default json)" Enums(json, ndjson, csv, html)
+// @Success 200 {array} dto.FavouriteResponse "Favourites of the user"
+// @Failure 400 {object} middleware.Problem "Invalid user ID or format"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/favourites/export [get]
+func (h *UserHandler) ExportFavourites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	contentType, disposition := "", "attachment"
+	switch format {
+	case "json":
+		contentType = "application/json"
+	case "ndjson":
+		contentType = ndjsonContentType
+	case "csv":
+		contentType = csvContentType + "; charset=utf-8"
+	case "html":
+		contentType, disposition = "text/html; charset=utf-8", "inline"
+	default:
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid format: must be json, ndjson, csv or html")
+		return
+	}
+
+	// Read the first page before writing anything, so that failing to read it is still reported as a problem
+	page, err := h.service.GetFavouritesPageByUser(id, exportPageSize, "")
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": "favourites-" + id + "." + format}))
+	w.WriteHeader(http.StatusOK)
+
+	exporter, err := newFavouritesExporter(format, w, id, h.reports)
+	for err == nil {
+		for _, favourite := range page.Favourites {
+			if err = exporter.add(favourite); err != nil {
+				break
+			}
+		}
+		if err != nil || page.NextCursor == "" {
+			break
+		}
+		page, err = h.service.GetFavouritesPageByUser(id, exportPageSize, page.NextCursor)
+	}
+	if err == nil {
+		err = exporter.close()
+	}
+	if err != nil {
+		// The status is already sent, so the client can only tell from the truncated body
+		log.Printf("Exporting the favourites of user %s failed: %v", id, err)
+	}
+}
+
+func newFavouritesExporter(format string, w io.Writer, userID string, reports ports.FavouritesReportWriter) (favouritesExporter, error) {
+	switch format {
+	case "ndjson":
+		return &ndjsonFavouritesExporter{encoder: json.NewEncoder(w)}, nil
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(favouritesCSVHeader); err != nil {
+			return nil, err
+		}
+		return &csvFavouritesExporter{writer: writer}, nil
+	case "html":
+		report, err := reports.NewReport(w, userID, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		return htmlFavouritesExporter{report: report}, nil
+	default:
+		return &jsonFavouritesExporter{w: w}, nil
+	}
+}
+
+// jsonFavouritesExporter writes a JSON array of favourites, one element at a time
+type jsonFavouritesExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonFavouritesExporter) add(f domain.Favourite) error {
+	element, err := json.Marshal(mapping.FavouriteToResponse(&f))
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+	_, err = io.WriteString(e.w, separator+string(element))
+	return err
+}
+
+func (e *jsonFavouritesExporter) close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+type ndjsonFavouritesExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonFavouritesExporter) add(f domain.Favourite) error {
+	return e.encoder.Encode(mapping.FavouriteToResponse(&f))
+}
+
+func (e *ndjsonFavouritesExporter) close() error {
+	return nil
+}
+
+type csvFavouritesExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvFavouritesExporter) add(f domain.Favourite) error {
+	return e.writer.Write(favouriteCSVRecord(f))
+}
+
+func (e *csvFavouritesExporter) close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type htmlFavouritesExporter struct {
+	report ports.FavouritesReport
+}
+
+func (e htmlFavouritesExporter) add(f domain.Favourite) error {
+	return e.report.Add(f)
+}
+
+func (e htmlFavouritesExporter) close() error {
+	return e.report.Close()
+}
+
+// favouriteCSVRecord flattens a favourite and its asset into the columns of favouritesCSVHeader
+func favouriteCSVRecord(f domain.Favourite) []string {
+	record := make(map[string]string, len(favouritesCSVHeader))
+	record["user_id"] = f.UserID
+	record["asset_id"] = f.AssetID
+	record["asset_type"] = f.AssetType.String()
+	record["favourited_at"] = f.CreatedAt.Format(time.RFC3339)
+	record["custom_title"] = f.CustomTitle
+	record["note"] = f.Note
+	if len(f.Tags) > 0 {
+		record["tags"] = jsonCell(f.Tags)
+	}
+
+	var asset domain.Asset
+	switch {
+	case f.Chart != nil:
+		asset = f.Chart
+		record["chart_kind"] = string(f.Chart.DrawnAs())
+		record["axes_titles"] = jsonCell(f.Chart.AxesTitles)
+		if len(f.Chart.AxesUnits) > 0 {
+			record["axes_units"] = jsonCell(f.Chart.AxesUnits)
+		}
+		if len(f.Chart.Categories) > 0 {
+			record["categories"] = jsonCell(f.Chart.Categories)
+		}
+		if len(f.Chart.Series) > 0 {
+			record["series"] = jsonCell(mapping.ChartSeriesToDTO(f.Chart.Series))
+		}
+		if len(f.Chart.Data) > 0 {
+			record["data"] = jsonCell(f.Chart.Data)
+		}
+	case f.Insight != nil:
+		asset = f.Insight
+		record["text"] = f.Insight.Text
+	case f.Audience != nil:
+		asset = f.Audience
+		if f.Audience.Criteria != nil {
+			record["criteria"] = jsonCell(mapping.AudienceCriterionToDTO(f.Audience.Criteria))
+		}
+		record["summary"] = f.Audience.Summary()
+	}
+	if asset != nil {
+		record["asset_type"] = asset.GetType().String()
+		record["title"] = asset.GetTitle()
+		record["description"] = asset.GetDescription()
+	}
+
+	row := make([]string, len(favouritesCSVHeader))
+	for i, column := range favouritesCSVHeader {
+		row[i] = record[column]
+	}
+	return row
+}
+
+// jsonCell encodes a list for a CSV cell
+func jsonCell(v any) string {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReportWriter writes a line for the start of a report, each favourite and the end of the report
+type fakeReportWriter struct{}
+
+func (fakeReportWriter) NewReport(w io.Writer, userID string, _ time.Time) (ports.FavouritesReport, error) {
+	_, err := fmt.Fprintf(w, "report %s\n", userID)
+	return fakeReport{w: w}, err
+}
+
+type fakeReport struct {
+	w io.Writer
+}
+
+func (r fakeReport) Add(f domain.Favourite) error {
+	_, err := fmt.Fprintln(r.w, f.AssetID)
+	return err
+}
+
+func (r fakeReport) Close() error {
+	_, err := fmt.Fprintln(r.w, "end")
+	return err
+}
+
+// exportedFavourites are two pages of favourites, one of each asset type
+func exportedFavourites() (domain.FavouritePage, domain.FavouritePage) {
+	favouritedAt := time.Date(2025, 10, 30, 15, 4, 5, 0, time.UTC)
+	chart := domain.Favourite{
+		UserID: "u1", AssetID: "c1", AssetType: domain.AssetTypeChart, CreatedAt: favouritedAt, Tags: []string{"q3"},
+		Chart: &domain.Chart{
+			AssetBase:  domain.AssetBase{ID: "c1", Type: domain.AssetTypeChart, Title: "Purchases <by> age"},
+			AxesTitles: []string{"Age", "Purchases"},
+			Data:       [][]float64{{18, 2.3}, {25, 3.5}},
+		},
+	}
+	insight := domain.Favourite{
+		UserID: "u1", AssetID: "i1", AssetType: domain.AssetTypeInsight, CreatedAt: favouritedAt, Note: "For the deck",
+		Insight: &domain.Insight{AssetBase: domain.AssetBase{ID: "i1", Type: domain.AssetTypeInsight, Title: "Social"}, Text: "40% of millennials"},
+	}
+	hours := 4.5
+	audience := domain.Favourite{
+		UserID: "u1", AssetID: "a1", AssetType: domain.AssetTypeAudience, CreatedAt: favouritedAt, CustomTitle: "Gen Z",
+		Audience: &domain.Audience{AssetBase: domain.AssetBase{ID: "a1", Type: domain.AssetTypeAudience, Title: "Young"},
+			Criteria: &domain.AudienceCriterion{Operator: domain.AudienceGte, Field: domain.AudienceFieldHoursSocial, Value: &hours}},
+	}
+	return domain.FavouritePage{Favourites: []domain.Favourite{chart, insight}, NextCursor: "next"},
+		domain.FavouritePage{Favourites: []domain.Favourite{audience}}
+}
+
+func TestUserHandler_ExportFavourites(t *testing.T) {
+	first, second := exportedFavourites()
+
+	tests := []struct {
+		name                string
+		format              string
+		setupMock           func(*MockUserService)
+		expectedStatus      int
+		expectedContentType string
+		assertBody          func(t *testing.T, body string)
+	}{
+		{
+			name:   "Happy Path - JSON array of every page",
+			format: "json",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(first, nil)
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "next").Return(second, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			assertBody: func(t *testing.T, body string) {
+				var favourites []dto.FavouriteResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &favourites))
+				require.Len(t, favourites, 3)
+				assert.Equal(t, []any{[]any{18.0, 2.3}, []any{25.0, 3.5}}, favourites[0].Asset.(map[string]any)["data"])
+				assert.Equal(t, "40% of millennials", favourites[1].Asset.(map[string]any)["text"])
+				assert.Equal(t, "hours_social", favourites[2].Asset.(map[string]any)["criteria"].(map[string]any)["field"])
+			},
+		},
+		{
+			name:   "Happy Path - Empty JSON array",
+			format: "",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(domain.FavouritePage{}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			assertBody: func(t *testing.T, body string) {
+				assert.Equal(t, "[]\n", body)
+			},
+		},
+		{
+			name:   "Happy Path - NDJSON",
+			format: "ndjson",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(first, nil)
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "next").Return(second, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			assertBody: func(t *testing.T, body string) {
+				lines := strings.Split(strings.TrimSpace(body), "\n")
+				require.Len(t, lines, 3)
+				var favourite dto.FavouriteResponse
+				require.NoError(t, json.Unmarshal([]byte(lines[2]), &favourite))
+				assert.Equal(t, "a1", favourite.AssetID)
+				assert.Equal(t, "Gen Z", favourite.CustomTitle)
+			},
+		},
+		{
+			name:   "Happy Path - CSV",
+			format: "csv",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(first, nil)
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "next").Return(second, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			assertBody: func(t *testing.T, body string) {
+				records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 4)
+				assert.Equal(t, favouritesCSVHeader, records[0])
+				column := func(row int, name string) string {
+					for i, header := range favouritesCSVHeader {
+						if header == name {
+							return records[row][i]
+						}
+					}
+					return ""
+				}
+				assert.Equal(t, "chart", column(1, "asset_type"))
+				assert.Equal(t, "2025-10-30T15:04:05Z", column(1, "favourited_at"))
+				assert.Equal(t, `["q3"]`, column(1, "tags"))
+				assert.Equal(t, `["Age","Purchases"]`, column(1, "axes_titles"))
+				assert.Equal(t, `[[18,2.3],[25,3.5]]`, column(1, "data"))
+				assert.Equal(t, "line", column(1, "chart_kind"))
+				assert.Empty(t, column(1, "series"))
+				assert.Equal(t, "40% of millennials", column(2, "text"))
+				assert.Equal(t, "For the deck", column(2, "note"))
+				assert.Equal(t, `{"op":"gte","field":"hours_social","value":4.5}`, column(3, "criteria"))
+				assert.Equal(t, "Spending at least 4.5 hours a day on social media", column(3, "summary"))
+				assert.Empty(t, column(3, "data"))
+			},
+		},
+		{
+			name:   "Happy Path - HTML report",
+			format: "html",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(first, nil)
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "next").Return(second, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/html; charset=utf-8",
+			assertBody: func(t *testing.T, body string) {
+				assert.Equal(t, "report u1\nc1\ni1\na1\nend\n", body)
+			},
+		},
+		{
+			name:           "Unhappy Path - Unknown format",
+			format:         "xlsx",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unhappy Path - First page fails",
+			format: "csv",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "u1", exportPageSize, "").Return(domain.FavouritePage{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+			router := chi.NewRouter()
+			router.Get("/users/{id}/favourites/export", NewUserHandler(mockService, fakeReportWriter{}).ExportFavourites)
+			req := httptest.NewRequest(http.MethodGet, "/users/u1/favourites/export?format="+tt.format, nil)
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.assertBody != nil {
+				assert.Equal(t, tt.expectedContentType, rr.Header().Get("Content-Type"))
+				assert.Contains(t, rr.Header().Get("Content-Disposition"), "favourites-u1.")
+				tt.assertBody(t, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -28,20 +28,22 @@ func NewFavouriteHandler(s ports.FavouriteService) *FavouriteHandler {
 // @Produce json
 // @Param request body dto.FavouriteRequest true "Favourite creation request"
 // @Success 201 "Favourite added successfully"
-// @Failure 400 {string} string "Invalid input data"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Asset already favourited"
+// @Failure 422 {object} middleware.Problem "User or asset does not exist"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /favourites [post]
 func (f *FavouriteHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	req, ok := middleware.GetValidatedBody[dto.FavouriteRequest](r)
 	if !ok {
-		http.Error(w, "missing validated body", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
 		return
 	}
 
@@ -49,13 +51,50 @@ func (f *FavouriteHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	err := f.service.CreateFavourite(favourite)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+// Batch adds and removes many favourites of a user at once
+// @Summary Add and remove favourites in bulk
+// @Description Applies up to 500 add and remove operations to a user's favourites in order, in one step, and reports each one:
+// @Description added, already_present, removed, not_present or asset_missing.
+// @Description With all_or_nothing a batch adding an asset that does not exist changes nothing, applied is false and the other operations are skipped.
+// @Tags Favourites
+// @Accept json
+// @Produce json
+// @Param request body dto.FavouriteBatchRequest true "Favourite operations"
+// @Success 200 {object} dto.FavouriteBatchResponse "Outcome of every operation"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 422 {object} middleware.Problem "User does not exist"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /favourites:batch [post]
+func (f *FavouriteHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.FavouriteBatchRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	result, err := f.service.ApplyFavouriteBatch(req.UserId, mapping.FavouriteBatchReqToDomain(req), req.AllOrNothing)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.FavouriteBatchResultToResponse(result))
+}
+
 // Delete removes a favourite for a user
 // @Summary Remove a favourite
 // @Description Removes an asset from a user's favourites list
@@ -65,32 +104,153 @@ func (f *FavouriteHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Param userId path string true "User ID"
 // @Param assetId path string true "Asset ID"
 // @Success 200 "Favourite removed successfully"
-// @Failure 400 {string} string "Invalid user ID or asset ID"
-// @Failure 404 {string} string "User or favourite not found"
-// @Failure 405 {string} string "Method not allowed"
+// @Failure 400 {object} middleware.Problem "Invalid user ID or asset ID"
+// @Failure 404 {object} middleware.Problem "User or favourite not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
 // @Security BearerAuth
 // @Router /favourites/{userId}/{assetId} [delete]
 func (f *FavouriteHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	usrId := chi.URLParam(r, "userId")
 	if usrId == "" {
-		http.Error(w, "missing user id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
 		return
 	}
 
 	assetId := chi.URLParam(r, "assetId")
 	if assetId == "" {
-		http.Error(w, "missing asset id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
 		return
 	}
 
 	err := f.service.DeleteFavourite(usrId, assetId)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		middleware.WriteError(w, r, err)
+		return
+	}
+}
+
+// Update changes the user's private overrides of a favourite
+// @Summary Annotate a favourite
+// @Description Sets the user's private note, custom title and tags of a favourite. The asset itself is not changed.
+// @Description Omitted fields are left unchanged; an empty string clears the note or custom title and an empty list clears the tags.
+// @Tags Favourites
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param assetId path string true "Asset ID"
+// @Param request body dto.FavouriteUpdateRequest true "Favourite overrides"
+// @Success 200 {object} dto.FavouriteResponse "Favourite updated successfully"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "Favourite not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /favourites/{userId}/assets/{assetId} [patch]
+func (f *FavouriteHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId := chi.URLParam(r, "userId")
+	if usrId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
+		return
+	}
+
+	assetId := chi.URLParam(r, "assetId")
+	if assetId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.FavouriteUpdateRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	favourite, err := f.service.UpdateFavourite(usrId, assetId, mapping.FavouriteUpdateReqToDomain(req))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.FavouriteToResponse(&favourite))
+}
+
+// ListRemoved lists the user's favourites whose assets were deleted
+// @Summary List removed favourites
+// @Description Lists the tombstones left in the user's favourites by deleted assets, most recently removed first
+// @Tags Favourites
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} dto.RemovedFavouriteResponse "Removed favourites retrieved successfully"
+// @Failure 400 {object} middleware.Problem "Invalid user ID"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/favourites/removed [get]
+func (f *FavouriteHandler) ListRemoved(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId := chi.URLParam(r, "id")
+	if usrId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
+		return
+	}
+
+	tombstones, err := f.service.GetRemovedFavourites(usrId)
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.RemovedFavouritesToResponse(tombstones))
+}
+
+// DismissRemoved dismisses the tombstone of a deleted favourite asset
+// @Summary Dismiss a removed favourite
+// @Description Deletes the tombstone left in the user's favourites by a deleted asset
+// @Tags Favourites
+// @Param id path string true "User ID"
+// @Param assetId path string true "Asset ID"
+// @Success 204 "Removed favourite dismissed"
+// @Failure 400 {object} middleware.Problem "Invalid user ID or asset ID"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/favourites/removed/{assetId} [delete]
+func (f *FavouriteHandler) DismissRemoved(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
+
+	usrId := chi.URLParam(r, "id")
+	if usrId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
+		return
+	}
+
+	assetId := chi.URLParam(r, "assetId")
+	if assetId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	if err := f.service.DismissRemovedFavourite(usrId, assetId); err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
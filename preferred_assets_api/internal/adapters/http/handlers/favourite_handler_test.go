@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -35,6 +36,26 @@ func (m *MockFavouriteService) DeleteFavourite(userID string, assetID string) er
 	return args.Error(0)
 }
 
+func (m *MockFavouriteService) ApplyFavouriteBatch(userID string, operations []domain.FavouriteOperation, allOrNothing bool) (domain.FavouriteBatchResult, error) {
+	args := m.Called(userID, operations, allOrNothing)
+	return args.Get(0).(domain.FavouriteBatchResult), args.Error(1)
+}
+
+func (m *MockFavouriteService) UpdateFavourite(userID string, assetID string, patch domain.FavouritePatch) (domain.Favourite, error) {
+	args := m.Called(userID, assetID, patch)
+	return args.Get(0).(domain.Favourite), args.Error(1)
+}
+
+func (m *MockFavouriteService) GetRemovedFavourites(userID string) ([]domain.FavouriteTombstone, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.FavouriteTombstone), args.Error(1)
+}
+
+func (m *MockFavouriteService) DismissRemovedFavourite(userID string, assetID string) error {
+	args := m.Called(userID, assetID)
+	return args.Error(0)
+}
+
 func TestFavouriteHandler_Create(t *testing.T) {
 	// Save the original Body getter and restore after test
 	originalBodyGetter := middleware.Body
@@ -100,7 +121,36 @@ func TestFavouriteHandler_Create(t *testing.T) {
 				m.On("CreateFavourite", expectedFavourite).Return(errors.New("database error"))
 			},
 			expectedStatus:      http.StatusInternalServerError,
-			expectedBody:        "database error\n",
+			expectedBody:        "internal server error\n",
+			validateBodySucceed: true,
+		},
+		{
+			name:   "Unhappy Path - Asset already favourited",
+			method: http.MethodPost,
+			requestBody: dto.FavouriteRequest{
+				UserId:  "user-123",
+				AssetId: "asset-456",
+			},
+			setupMock: func(m *MockFavouriteService) {
+				m.On("CreateFavourite", domain.Favourite{UserID: "user-123", AssetID: "asset-456"}).Return(domain.ErrFavouriteExists)
+			},
+			expectedStatus:      http.StatusConflict,
+			expectedBody:        "asset already favourited\n",
+			validateBodySucceed: true,
+		},
+		{
+			name:   "Unhappy Path - Unknown asset",
+			method: http.MethodPost,
+			requestBody: dto.FavouriteRequest{
+				UserId:  "user-123",
+				AssetId: "missing",
+			},
+			setupMock: func(m *MockFavouriteService) {
+				m.On("CreateFavourite", domain.Favourite{UserID: "user-123", AssetID: "missing"}).
+					Return(fmt.Errorf("%w: missing", domain.ErrUnknownAsset))
+			},
+			expectedStatus:      http.StatusUnprocessableEntity,
+			expectedBody:        "asset does not exist: missing\n",
 			validateBodySucceed: true,
 		},
 		{
@@ -163,7 +213,7 @@ func TestFavouriteHandler_Create(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
 			}
 			mockService.AssertExpectations(t)
 		})
@@ -224,7 +274,7 @@ func TestFavouriteHandler_Delete(t *testing.T) {
 			userID:  "user-999",
 			assetID: "asset-999",
 			setupMock: func(m *MockFavouriteService) {
-				m.On("DeleteFavourite", "user-999", "asset-999").Return(errors.New("not found"))
+				m.On("DeleteFavourite", "user-999", "asset-999").Return(domain.ErrUserNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "user not found\n",
@@ -237,8 +287,8 @@ func TestFavouriteHandler_Delete(t *testing.T) {
 			setupMock: func(m *MockFavouriteService) {
 				m.On("DeleteFavourite", "user-123", "asset-456").Return(errors.New("database error"))
 			},
-			expectedStatus: http.StatusNotFound, // Your handler returns 404 for all errors
-			expectedBody:   "user not found\n",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "internal server error\n",
 		},
 		{
 			name:           "Unhappy Path - Both user ID and asset ID missing",
@@ -277,7 +327,192 @@ func TestFavouriteHandler_Delete(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFavouriteHandler_Update(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	note := "Use in the Q3 deck"
+	request := dto.FavouriteUpdateRequest{Note: &note, Tags: []string{"q3"}}
+	patch := domain.FavouritePatch{Note: &note, Tags: []string{"q3"}}
+	updated := domain.Favourite{UserID: "user-123", AssetID: "ins-1", AssetType: domain.AssetTypeInsight, Insight: newTestInsight(), Note: note, Tags: []string{"q3"}}
+
+	tests := []struct {
+		name           string
+		method         string
+		requestBody    *dto.FavouriteUpdateRequest
+		setupMock      func(*MockFavouriteService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "Happy Path - Returns the overrides with the asset",
+			method:      http.MethodPatch,
+			requestBody: &request,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("UpdateFavourite", "user-123", "ins-1", patch).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"note":"Use in the Q3 deck","tags":["q3"],"asset":{"id":"ins-1"`,
+		},
+		{
+			name:           "Unhappy Path - Wrong HTTP method",
+			method:         http.MethodPut,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "method not allowed",
+		},
+		{
+			name:           "Unhappy Path - Missing validated body",
+			method:         http.MethodPatch,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "missing validated body",
+		},
+		{
+			name:        "Unhappy Path - Favourite not found",
+			method:      http.MethodPatch,
+			requestBody: &request,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("UpdateFavourite", "user-123", "ins-1", patch).Return(domain.Favourite{}, domain.ErrFavouriteNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "Unhappy Path - Invalid overrides",
+			method:      http.MethodPatch,
+			requestBody: &request,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("UpdateFavourite", "user-123", "ins-1", patch).Return(domain.Favourite{}, domain.ErrInvalidFavourite)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockFavouriteService)
+			tt.setupMock(mockService)
+			handler := NewFavouriteHandler(mockService)
+
+			if tt.requestBody != nil {
+				middleware.Body = MockBodyGetter{MockedBody: *tt.requestBody, ShouldSucceed: true}
+			} else {
+				middleware.Body = MockBodyGetter{MockedBody: nil, ShouldSucceed: false}
+			}
+
+			req := httptest.NewRequest(tt.method, "/favourites/user-123/assets/ins-1", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userId", "user-123")
+			rctx.URLParams.Add("assetId", "ins-1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Update(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFavouriteHandler_RemovedFavourites(t *testing.T) {
+	removedAt := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	tombstone := domain.FavouriteTombstone{UserID: "user-123", AssetID: "chart-1", AssetType: domain.AssetTypeChart, AssetTitle: "Sales", RemovedAt: removedAt}
+
+	tests := []struct {
+		name           string
+		method         string
+		assetID        string
+		call           func(*FavouriteHandler, http.ResponseWriter, *http.Request)
+		setupMock      func(*MockFavouriteService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Happy Path - Lists removed favourites",
+			method: http.MethodGet,
+			call:   (*FavouriteHandler).ListRemoved,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("GetRemovedFavourites", "user-123").Return([]domain.FavouriteTombstone{tombstone}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"asset_type":"chart","asset_title":"Sales","favourited_at":"0001-01-01T00:00:00Z","removed_at":"2025-11-02T09:00:00Z"`,
+		},
+		{
+			name:   "Unhappy Path - Listing fails",
+			method: http.MethodGet,
+			call:   (*FavouriteHandler).ListRemoved,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("GetRemovedFavourites", "user-123").Return([]domain.FavouriteTombstone(nil), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "Happy Path - Dismisses a removed favourite",
+			method:  http.MethodDelete,
+			assetID: "chart-1",
+			call:    (*FavouriteHandler).DismissRemoved,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("DismissRemovedFavourite", "user-123", "chart-1").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Unhappy Path - Dismiss without asset ID",
+			method:         http.MethodDelete,
+			call:           (*FavouriteHandler).DismissRemoved,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "missing asset id",
+		},
+		{
+			name:           "Unhappy Path - Wrong HTTP method",
+			method:         http.MethodPost,
+			call:           (*FavouriteHandler).ListRemoved,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockFavouriteService)
+			tt.setupMock(mockService)
+			handler := NewFavouriteHandler(mockService)
+
+			req := httptest.NewRequest(tt.method, "/users/user-123/favourites/removed", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "user-123")
+			if tt.assetID != "" {
+				rctx.URLParams.Add("assetId", tt.assetID)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+
+			// Act
+			tt.call(handler, rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedBody)
 			}
 			mockService.AssertExpectations(t)
 		})
@@ -361,7 +596,7 @@ func TestFavouriteHandler_Create_Detailed(t *testing.T) {
 		}
 
 		mockService.On("CreateFavourite", mock.AnythingOfType("domain.Favourite")).
-			Return(errors.New("duplicate favourite"))
+			Return(fmt.Errorf("%w: at most 20 tags are allowed", domain.ErrInvalidFavourite))
 
 		middleware.Body = MockBodyGetter{
 			MockedBody:    requestBody,
@@ -376,8 +611,8 @@ func TestFavouriteHandler_Create_Detailed(t *testing.T) {
 		handler.Create(rr, req)
 
 		// Assert
-		assert.Equal(t, http.StatusInternalServerError, rr.Code)
-		assert.Equal(t, "duplicate favourite\n", rr.Body.String())
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assertBody(t, "invalid favourite: at most 20 tags are allowed\n", rr)
 		mockService.AssertExpectations(t)
 	})
 }
@@ -446,3 +681,104 @@ func stringPtr(s string) *string {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestFavouriteHandler_Batch(t *testing.T) {
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	operations := []domain.FavouriteOperation{
+		{Action: domain.FavouriteActionAdd, AssetID: "a1"},
+		{Action: domain.FavouriteActionRemove, AssetID: "a2"},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockFavouriteService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Happy Path - Reports every operation",
+			body: `{"_id":"u1","operations":[{"op":"add","asset_id":"a1"},{"op":"remove","asset_id":"a2"}]}`,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("ApplyFavouriteBatch", "u1", operations, false).Return(domain.FavouriteBatchResult{
+					Applied: true,
+					Results: []domain.FavouriteOperationResult{
+						{FavouriteOperation: operations[0], Outcome: domain.FavouriteAdded},
+						{FavouriteOperation: operations[1], Outcome: domain.FavouriteNotPresent},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"applied":true,"results":[{"op":"add","asset_id":"a1","status":"added"},{"op":"remove","asset_id":"a2","status":"not_present"}]}`,
+		},
+		{
+			name: "Happy Path - All-or-nothing batch rejected",
+			body: `{"_id":"u1","all_or_nothing":true,"operations":[{"op":"add","asset_id":"a1"},{"op":"remove","asset_id":"a2"}]}`,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("ApplyFavouriteBatch", "u1", operations, true).Return(domain.FavouriteBatchResult{
+					Results: []domain.FavouriteOperationResult{
+						{FavouriteOperation: operations[0], Outcome: domain.FavouriteAssetMissing},
+						{FavouriteOperation: operations[1], Outcome: domain.FavouriteSkipped},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"applied":false,"results":[{"op":"add","asset_id":"a1","status":"asset_missing"},{"op":"remove","asset_id":"a2","status":"skipped"}]}`,
+		},
+		{
+			name:           "Unhappy Path - Unknown operation",
+			body:           `{"_id":"u1","operations":[{"op":"toggle","asset_id":"a1"}]}`,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - No operations",
+			body:           `{"_id":"u1","operations":[]}`,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Too many operations",
+			body:           `{"_id":"u1","operations":[` + strings.TrimSuffix(strings.Repeat(`{"op":"add","asset_id":"a1"},`, domain.MaxFavouriteBatchSize+1), ",") + `]}`,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unhappy Path - Unknown user",
+			body: `{"_id":"missing","operations":[{"op":"add","asset_id":"a1"},{"op":"remove","asset_id":"a2"}]}`,
+			setupMock: func(m *MockFavouriteService) {
+				m.On("ApplyFavouriteBatch", "missing", operations, false).
+					Return(domain.FavouriteBatchResult{}, fmt.Errorf("%w: missing", domain.ErrUnknownUser))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			middleware.Body = middleware.DefaultBodyGetter{}
+			mockService := new(MockFavouriteService)
+			tt.setupMock(mockService)
+			router := chi.NewRouter()
+			router.With(middleware.ValidateBody[dto.FavouriteBatchRequest]()).Post("/favourites:batch", NewFavouriteHandler(mockService).Batch)
+			req := httptest.NewRequest(http.MethodPost, "/favourites:batch", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
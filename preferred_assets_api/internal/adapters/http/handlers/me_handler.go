@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/go-chi/chi/v5"
+)
+
+var _ ports.MeHandler = (*MeHandler)(nil)
+
+// MeHandler serves the signed-in user's own resources. The user is taken from the verified token,
+// never from the request, so it must be mounted behind middleware.ProvisionCaller.
+type MeHandler struct {
+	users      ports.UserService
+	favourites ports.FavouriteService
+}
+
+func NewMeHandler(users ports.UserService, favourites ports.FavouriteService) *MeHandler {
+	return &MeHandler{users: users, favourites: favourites}
+}
+
+// ListFavourites retrieves the signed-in user's favourites
+// @Summary Get my favourites
+// @Description Retrieves a page of the signed-in user's favourite assets, newest first
+// @Tags Me
+// @Produce json
+// @Param limit query int false "Maximum number of favourites to return (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} dto.FavouritesPageResponse "Favourites retrieved successfully"
+// @Failure 400 {object} middleware.Problem "Invalid limit or cursor"
+// @Failure 401 {object} middleware.Problem "Token has no subject"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /me/favourites [get]
+func (h *MeHandler) ListFavourites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId, ok := middleware.GetCallerIDFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "missing signed-in user")
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.users.GetFavouritesPageByUser(usrId, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.FavouritePageToResponse(page))
+}
+
+// AddFavourite adds a favourite for the signed-in user
+// @Summary Add to my favourites
+// @Description Adds an asset to the signed-in user's favourites list
+// @Tags Me
+// @Accept json
+// @Param request body dto.MyFavouriteRequest true "Favourite creation request"
+// @Success 201 "Favourite added successfully"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 401 {object} middleware.Problem "Token has no subject"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Asset already favourited"
+// @Failure 422 {object} middleware.Problem "Asset does not exist"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /me/favourites [post]
+func (h *MeHandler) AddFavourite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId, ok := middleware.GetCallerIDFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "missing signed-in user")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.MyFavouriteRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	if err := h.favourites.CreateFavourite(mapping.MyFavouriteReqToDomain(usrId, req)); err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// RemoveFavourite removes a favourite of the signed-in user
+// @Summary Remove from my favourites
+// @Description Removes an asset from the signed-in user's favourites list
+// @Tags Me
+// @Param assetId path string true "Asset ID"
+// @Success 200 "Favourite removed successfully"
+// @Failure 400 {object} middleware.Problem "Invalid asset ID"
+// @Failure 401 {object} middleware.Problem "Token has no subject"
+// @Failure 404 {object} middleware.Problem "Favourite not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Security BearerAuth
+// @Router /me/favourites/{assetId} [delete]
+func (h *MeHandler) RemoveFavourite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId, ok := middleware.GetCallerIDFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "missing signed-in user")
+		return
+	}
+
+	assetId := chi.URLParam(r, "assetId")
+	if assetId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	if err := h.favourites.DeleteFavourite(usrId, assetId); err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+}
+
+// UpdateFavourite changes the signed-in user's private overrides of a favourite
+// @Summary Annotate one of my favourites
+// @Description Sets the signed-in user's private note, custom title and tags of a favourite. The asset itself is not changed.
+// @Description Omitted fields are left unchanged; an empty string clears the note or custom title and an empty list clears the tags.
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param assetId path string true "Asset ID"
+// @Param request body dto.FavouriteUpdateRequest true "Favourite overrides"
+// @Success 200 {object} dto.FavouriteResponse "Favourite updated successfully"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 401 {object} middleware.Problem "Token has no subject"
+// @Failure 404 {object} middleware.Problem "Favourite not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /me/favourites/{assetId} [patch]
+func (h *MeHandler) UpdateFavourite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	usrId, ok := middleware.GetCallerIDFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "missing signed-in user")
+		return
+	}
+
+	assetId := chi.URLParam(r, "assetId")
+	if assetId == "" {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing asset id")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[dto.FavouriteUpdateRequest](r)
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
+		return
+	}
+
+	favourite, err := h.favourites.UpdateFavourite(usrId, assetId, mapping.FavouriteUpdateReqToDomain(req))
+	if err != nil {
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mapping.FavouriteToResponse(&favourite))
+}
+
+// Permissions retrieves what the signed-in caller may do
+// @Summary Get my permissions
+// @Description Retrieves the caller's roles and the permissions they grant, e.g. to hide actions the caller cannot use.
+// @Description Unlike the other /me endpoints it needs no permission and works for API keys too.
+// @Tags Me
+// @Produce json
+// @Success 200 {object} dto.PermissionsResponse "Permissions retrieved successfully"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /me/permissions [get]
+func (h *MeHandler) Permissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	resp := dto.PermissionsResponse{Roles: []string{}, Permissions: []string{}}
+	if roles, ok := middleware.GetRolesFromContext(r.Context()); ok && roles != nil {
+		resp.Roles = roles
+	}
+	if permissions, ok := middleware.GetPermissionsFromContext(r.Context()); ok && permissions != nil {
+		resp.Permissions = permissions
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// meRequest builds a request as ProvisionCaller leaves it, with the signed-in user's id in the context
+func meRequest(method string, target string, callerID string, assetID string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	ctx := req.Context()
+	if callerID != "" {
+		ctx = context.WithValue(ctx, middleware.CallerIDKey, callerID)
+	}
+	if assetID != "" {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("assetId", assetID)
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestMeHandler_ListFavourites(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		callerID       string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "Happy Path - Lists the signed-in user's favourites",
+			target:   "/me/favourites",
+			callerID: "user-123",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "user-123", 20, "").Return(domain.FavouritePage{
+					Favourites: []domain.Favourite{{UserID: "user-123", AssetID: "ins-1", AssetType: domain.AssetTypeInsight, Insight: newTestInsight()}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"user_id":"user-123"`,
+		},
+		{
+			name:           "Unhappy Path - No signed-in user",
+			target:         "/me/favourites",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:     "Unhappy Path - Invalid cursor",
+			target:   "/me/favourites?cursor=bad",
+			callerID: "user-123",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "user-123", 20, "bad").Return(domain.FavouritePage{}, domain.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			users := new(MockUserService)
+			tt.setupMock(users)
+			handler := NewMeHandler(users, new(MockFavouriteService))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListFavourites(w, meRequest(http.MethodGet, tt.target, tt.callerID, ""))
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeHandler_AddFavourite(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	tests := []struct {
+		name           string
+		callerID       string
+		requestBody    *dto.MyFavouriteRequest
+		setupMock      func(*MockFavouriteService)
+		expectedStatus int
+	}{
+		{
+			name:        "Happy Path - Favourites for the signed-in user",
+			callerID:    "user-123",
+			requestBody: &dto.MyFavouriteRequest{AssetId: "ins-1"},
+			setupMock: func(m *MockFavouriteService) {
+				m.On("CreateFavourite", domain.Favourite{UserID: "user-123", AssetID: "ins-1"}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Unhappy Path - No signed-in user",
+			requestBody:    &dto.MyFavouriteRequest{AssetId: "ins-1"},
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Unhappy Path - Missing validated body",
+			callerID:       "user-123",
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Unhappy Path - Service error",
+			callerID:    "user-123",
+			requestBody: &dto.MyFavouriteRequest{AssetId: "ins-1"},
+			setupMock: func(m *MockFavouriteService) {
+				m.On("CreateFavourite", domain.Favourite{UserID: "user-123", AssetID: "ins-1"}).Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			favourites := new(MockFavouriteService)
+			tt.setupMock(favourites)
+			handler := NewMeHandler(new(MockUserService), favourites)
+			if tt.requestBody != nil {
+				middleware.Body = MockBodyGetter{MockedBody: *tt.requestBody, ShouldSucceed: true}
+			} else {
+				middleware.Body = MockBodyGetter{MockedBody: nil, ShouldSucceed: false}
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.AddFavourite(w, meRequest(http.MethodPost, "/me/favourites", tt.callerID, ""))
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			favourites.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeHandler_RemoveAndUpdateFavourite(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+
+	note := "Use in the Q3 deck"
+	updated := domain.Favourite{UserID: "user-123", AssetID: "ins-1", AssetType: domain.AssetTypeInsight, Insight: newTestInsight(), Note: note}
+
+	tests := []struct {
+		name           string
+		method         string
+		callerID       string
+		setupMock      func(*MockFavouriteService)
+		expectedStatus int
+	}{
+		{
+			name:     "Happy Path - Removes the signed-in user's favourite",
+			method:   http.MethodDelete,
+			callerID: "user-123",
+			setupMock: func(m *MockFavouriteService) {
+				m.On("DeleteFavourite", "user-123", "ins-1").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Unhappy Path - Remove a favourite that does not exist",
+			method:   http.MethodDelete,
+			callerID: "user-123",
+			setupMock: func(m *MockFavouriteService) {
+				m.On("DeleteFavourite", "user-123", "ins-1").Return(domain.ErrFavouriteNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "Happy Path - Annotates the signed-in user's favourite",
+			method:   http.MethodPatch,
+			callerID: "user-123",
+			setupMock: func(m *MockFavouriteService) {
+				m.On("UpdateFavourite", "user-123", "ins-1", domain.FavouritePatch{Note: &note}).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Unhappy Path - Annotate a favourite that does not exist",
+			method:   http.MethodPatch,
+			callerID: "user-123",
+			setupMock: func(m *MockFavouriteService) {
+				m.On("UpdateFavourite", "user-123", "ins-1", domain.FavouritePatch{Note: &note}).Return(domain.Favourite{}, domain.ErrFavouriteNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unhappy Path - No signed-in user",
+			method:         http.MethodPatch,
+			setupMock:      func(m *MockFavouriteService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			favourites := new(MockFavouriteService)
+			tt.setupMock(favourites)
+			handler := NewMeHandler(new(MockUserService), favourites)
+			middleware.Body = MockBodyGetter{MockedBody: dto.FavouriteUpdateRequest{Note: &note}, ShouldSucceed: true}
+			req := meRequest(tt.method, "/me/favourites/ins-1", tt.callerID, "ins-1")
+			w := httptest.NewRecorder()
+
+			// Act
+			if tt.method == http.MethodDelete {
+				handler.RemoveFavourite(w, req)
+			} else {
+				handler.UpdateFavourite(w, req)
+			}
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			favourites.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMeHandler_Permissions(t *testing.T) {
+	tests := []struct {
+		name         string
+		roles        []string
+		permissions  []string
+		expectedBody string
+	}{
+		{
+			name:         "Happy Path - Returns roles and permissions",
+			roles:        []string{"Users"},
+			permissions:  []string{"assets:read", "favourites:read"},
+			expectedBody: `{"roles":["Users"],"permissions":["assets:read","favourites:read"]}`,
+		},
+		{
+			name:         "Happy Path - Caller without roles gets empty lists",
+			expectedBody: `{"roles":[],"permissions":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewMeHandler(new(MockUserService), new(MockFavouriteService))
+			req := httptest.NewRequest(http.MethodGet, "/me/permissions", nil)
+			ctx := req.Context()
+			if tt.roles != nil {
+				ctx = context.WithValue(ctx, middleware.UserRolesKey, tt.roles)
+				ctx = context.WithValue(ctx, middleware.PermissionsKey, tt.permissions)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.Permissions(w, req.WithContext(ctx))
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
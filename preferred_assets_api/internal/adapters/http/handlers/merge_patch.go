@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var errInvalidPatch = errors.New("invalid merge patch")
+
+// applyMergePatch applies an RFC 7386 JSON merge patch to the original document.
+// Object members in the patch replace those in the original, null members remove them,
+// and any non-object patch replaces the original entirely.
+func applyMergePatch(original, patch []byte) ([]byte, error) {
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, errInvalidPatch
+	}
+
+	var originalValue any
+	if err := json.Unmarshal(original, &originalValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeValue(originalValue, patchValue))
+}
+
+func mergeValue(original, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	originalObject, ok := original.(map[string]any)
+	if !ok {
+		originalObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(originalObject, key)
+			continue
+		}
+		originalObject[key] = mergeValue(originalObject[key], value)
+	}
+	return originalObject
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/mapping"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
 	"github.com/go-chi/chi/v5"
 )
@@ -16,10 +17,11 @@ var _ ports.UserHandler = (*UserHandler)(nil)
 
 type UserHandler struct {
 	service ports.UserService
+	reports ports.FavouritesReportWriter
 }
 
-func NewUserHandler(s ports.UserService) *UserHandler {
-	return &UserHandler{service: s}
+func NewUserHandler(s ports.UserService, reports ports.FavouritesReportWriter) *UserHandler {
+	return &UserHandler{service: s, reports: reports}
 }
 
 // Create creates a new user
@@ -30,32 +32,33 @@ func NewUserHandler(s ports.UserService) *UserHandler {
 // @Produce json
 // @Param request body dto.CreateUserRequest true "User creation request"
 // @Success 201 {object} dto.UserResponse "User created successfully"
-// @Failure 400 {string} string "Invalid input data"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Subject already linked to another user"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /users [post]
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	req, ok := middleware.GetValidatedBody[dto.CreateUserRequest](r)
 	if !ok {
-		http.Error(w, "missing validated body", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
 		return
 	}
 
 	usr, err := mapping.UserReqToDomain(req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
 	err = h.service.CreateUser(usr)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
@@ -70,30 +73,32 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} dto.UserResponse "User found successfully"
-// @Failure 400 {string} string "Invalid user ID"
-// @Failure 404 {string} string "User not found"
-// @Failure 405 {string} string "Method not allowed"
+// @Header 200 {string} ETag "Version of the user, for use in If-Match"
+// @Failure 400 {object} middleware.Problem "Invalid user ID"
+// @Failure 404 {object} middleware.Problem "User not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
 // @Security BearerAuth
 // @Router /users/{id} [get]
 func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "missing user id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
 		return
 	}
 
 	u, err := h.service.GetUserByID(id)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
 	usr := mapping.DomainToUserRes(*u)
+	setETag(w, u.Version)
 	if err := json.NewEncoder(w).Encode(usr); err != nil {
 		log.Fatal(err)
 	}
@@ -106,19 +111,19 @@ func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} dto.UserResponse "List of users retrieved successfully"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /users [get]
 func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	users, err := h.service.GetAllUsers()
 	if err != nil {
-		http.Error(w, "error fetching users", http.StatusInternalServerError)
+		middleware.WriteProblem(w, r, http.StatusInternalServerError, "error fetching users")
 		return
 	}
 
@@ -129,33 +134,49 @@ func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 // Delete removes a user by ID
-// @Summary Delete a user
+// @Description Permanently removes a user from the system, together with their favourites, removed favourites and collections
 // @Description Permanently removes a user from the system
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 "User deleted successfully"
-// @Failure 400 {string} string "Invalid user ID"
-// @Failure 404 {string} string "User not found"
-// @Failure 405 {string} string "Method not allowed"
+// @Failure 400 {object} middleware.Problem "Invalid user ID"
+// @Failure 404 {object} middleware.Problem "User not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 412 {object} middleware.Problem "User was modified since it was read"
 // @Security BearerAuth
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "missing user id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
 		return
 	}
 
-	err := h.service.DeleteUser(id)
+	expectedVersion := ports.AnyVersion
+	if hasIfMatch(r) {
+		u, err := h.service.GetUserByID(id)
+		if err != nil {
+			middleware.WriteError(w, r, err)
+			return
+		}
+		if !ifMatch(r, u.Version) {
+			middleware.WriteError(w, r, domain.ErrVersionConflict)
+			return
+		}
+		expectedVersion = u.Version
+	}
+
+	err := h.service.DeleteUser(id, expectedVersion)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		middleware.WriteError(w, r, err)
 		return
 	}
 }
@@ -168,93 +189,101 @@ func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "User ID"
 // @Param request body dto.UpdateUserRequest true "User update request"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Success 200 {string} string "User updated successfully"
-// @Failure 400 {string} string "Invalid input data"
-// @Failure 404 {string} string "User not found"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} middleware.Problem "Invalid input data"
+// @Failure 404 {object} middleware.Problem "User not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 409 {object} middleware.Problem "Subject already linked to another user"
+// @Failure 412 {object} middleware.Problem "User was modified since it was read"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "missing user id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
 		return
 	}
 
 	req, ok := middleware.GetValidatedBody[dto.UpdateUserRequest](r)
 	if !ok {
-		http.Error(w, "missing validated body", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing validated body")
 		return
 	}
 
 	existingUser, err := h.service.GetUserByID(id)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusNotFound)
+		middleware.WriteError(w, r, err)
+		return
+	}
+
+	if !ifMatch(r, existingUser.Version) {
+		middleware.WriteError(w, r, domain.ErrVersionConflict)
 		return
 	}
 
+	// The update is conditioned on the version just read, so a concurrent write is never silently overwritten
+	readVersion := existingUser.Version
 	updatedUser := mapping.UpdateReqToDomain(existingUser, req)
-	if err := h.service.UpdateUser(*updatedUser); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	version, err := h.service.UpdateUser(*updatedUser, readVersion)
+	if err != nil {
+		middleware.WriteError(w, r, err)
 		return
 	}
 
+	setETag(w, version)
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(map[string]string{"status": "updated"}); err != nil {
-		http.Error(w, "Failed JSON serialization", http.StatusInternalServerError)
+		log.Printf("JSON serialization error: %v", err)
 	}
 }
 
 // GetFavourites retrieves user favourites
 // @Summary Get user favourites
-// @Description Retrieves all favourite assets for the specified user
+// @Description Retrieves a page of favourite assets for the specified user, newest first
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {array} dto.FavouriteResponse "User favourites retrieved successfully"
-// @Failure 400 {string} string "Invalid user ID"
-// @Failure 404 {string} string "Favourites not found"
-// @Failure 405 {string} string "Method not allowed"
-// @Failure 500 {string} string "Internal server error"
+// @Param limit query int false "Maximum number of favourites to return (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} dto.FavouritesPageResponse "User favourites retrieved successfully"
+// @Failure 400 {object} middleware.Problem "Invalid user ID, limit or cursor"
+// @Failure 404 {object} middleware.Problem "Favourites not found"
+// @Failure 405 {object} middleware.Problem "Method not allowed"
+// @Failure 500 {object} middleware.Problem "Internal server error"
 // @Security BearerAuth
 // @Router /users/{id}/favourites [get]
 func (h *UserHandler) GetFavourites(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		middleware.WriteProblem(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, "missing user id", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "missing user id")
 		return
 	}
 
-	favourites, err := h.service.GetFavouritesByUser(id)
+	limit, err := parseLimit(r)
 	if err != nil {
-		http.Error(w, "favourites not found", http.StatusNotFound)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response := mapping.FavouritesToResponse(favourites)
-
-	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	page, err := h.service.GetFavouritesPageByUser(id, limit, r.URL.Query().Get("cursor"))
 	if err != nil {
-		log.Printf("JSON marshaling error: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		middleware.WriteError(w, r, err)
 		return
 	}
 
-	log.Printf("Favourites JSON response: %s", string(jsonBytes))
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonBytes)
+	writeJSON(w, http.StatusOK, mapping.FavouritePageToResponse(page))
 }
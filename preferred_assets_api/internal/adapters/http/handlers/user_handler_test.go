@@ -15,6 +15,7 @@ import (
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -46,16 +47,26 @@ func (m *MockUserService) GetAllUsers() ([]domain.User, error) {
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
-func (m *MockUserService) DeleteUser(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
+func (m *MockUserService) ResolveUserID(subject string) (string, error) {
+	args := m.Called(subject)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUser(user domain.User) error {
-	args := m.Called(user)
+func (m *MockUserService) ProvisionUser(subject string, username string, email string) (string, error) {
+	args := m.Called(subject, username, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(id string, expectedVersion int64) error {
+	args := m.Called(id, expectedVersion)
 	return args.Error(0)
 }
 
+func (m *MockUserService) UpdateUser(user domain.User, expectedVersion int64) (int64, error) {
+	args := m.Called(user, expectedVersion)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockUserService) GetFavouritesByUser(id string) ([]domain.Favourite, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -64,6 +75,11 @@ func (m *MockUserService) GetFavouritesByUser(id string) ([]domain.Favourite, er
 	return args.Get(0).([]domain.Favourite), args.Error(1)
 }
 
+func (m *MockUserService) GetFavouritesPageByUser(id string, limit int, cursor string) (domain.FavouritePage, error) {
+	args := m.Called(id, limit, cursor)
+	return args.Get(0).(domain.FavouritePage), args.Error(1)
+}
+
 type MockBodyGetter struct {
 	MockedBody    any
 	ShouldSucceed bool
@@ -82,6 +98,23 @@ func (m MockBodyGetter) GetValidatedBody(r *http.Request) (any, bool) {
 	}
 	return m.MockedBody, m.ShouldSucceed
 }
+
+// assertBody compares a success body as is, and an error body by the detail of its problem
+func assertBody(t *testing.T, expected string, rr *httptest.ResponseRecorder) {
+	t.Helper()
+	if rr.Code < http.StatusBadRequest {
+		assert.Equal(t, expected, rr.Body.String())
+		return
+	}
+
+	assert.Equal(t, middleware.ProblemContentType, rr.Header().Get("Content-Type"))
+	var problem middleware.Problem
+	if assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem)) {
+		assert.Equal(t, rr.Code, problem.Status)
+		assert.Equal(t, strings.TrimSuffix(expected, "\n"), problem.Detail)
+	}
+}
+
 func TestUserHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -133,7 +166,7 @@ func TestUserHandler_Create(t *testing.T) {
 				m.On("CreateUser", mock.AnythingOfType("domain.User")).Return(errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "database error\n",
+			expectedBody:   "internal server error\n",
 		},
 	}
 
@@ -142,7 +175,7 @@ func TestUserHandler_Create(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			var body []byte
 			if tt.requestBody != nil {
@@ -167,7 +200,7 @@ func TestUserHandler_Create(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
 			}
 			mockService.AssertExpectations(t)
 		})
@@ -223,7 +256,7 @@ func TestUserHandler_Get(t *testing.T) {
 			method: http.MethodGet,
 			userID: "user-999",
 			setupMock: func(m *MockUserService) {
-				m.On("GetUserByID", "user-999").Return(nil, errors.New("not found"))
+				m.On("GetUserByID", "user-999").Return(nil, domain.ErrUserNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "user not found\n",
@@ -235,7 +268,7 @@ func TestUserHandler_Get(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			req := httptest.NewRequest(tt.method, "/users/"+tt.userID, nil)
 			rr := httptest.NewRecorder()
@@ -257,7 +290,7 @@ func TestUserHandler_Get(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedBody, response)
 			} else {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody.(string), rr)
 			}
 
 			mockService.AssertExpectations(t)
@@ -319,7 +352,7 @@ func TestUserHandler_List(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			req := httptest.NewRequest(tt.method, "/users", nil)
 			rr := httptest.NewRecorder()
@@ -356,7 +389,7 @@ func TestUserHandler_Delete(t *testing.T) {
 			method: http.MethodDelete,
 			userID: "user-123",
 			setupMock: func(m *MockUserService) {
-				m.On("DeleteUser", "user-123").Return(nil)
+				m.On("DeleteUser", "user-123", ports.AnyVersion).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   "",
@@ -382,7 +415,7 @@ func TestUserHandler_Delete(t *testing.T) {
 			method: http.MethodDelete,
 			userID: "user-999",
 			setupMock: func(m *MockUserService) {
-				m.On("DeleteUser", "user-999").Return(errors.New("not found"))
+				m.On("DeleteUser", "user-999", ports.AnyVersion).Return(domain.ErrUserNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "user not found\n",
@@ -394,7 +427,7 @@ func TestUserHandler_Delete(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			req := httptest.NewRequest(tt.method, "/users/"+tt.userID, nil)
 			rr := httptest.NewRecorder()
@@ -410,7 +443,7 @@ func TestUserHandler_Delete(t *testing.T) {
 			// Assert
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, rr.Body.String())
+				assertBody(t, tt.expectedBody, rr)
 			}
 			mockService.AssertExpectations(t)
 		})
@@ -448,7 +481,7 @@ func TestUserHandler_Update(t *testing.T) {
 					Email: "john@example.com",
 				}
 				m.On("GetUserByID", "user-123").Return(existingUser, nil)
-				m.On("UpdateUser", mock.AnythingOfType("domain.User")).Return(nil)
+				m.On("UpdateUser", mock.AnythingOfType("domain.User"), mock.AnythingOfType("int64")).Return(int64(2), nil)
 			},
 			expectedStatus:      http.StatusOK,
 			expectedBody:        `{"status":"updated"}` + "\n",
@@ -468,7 +501,7 @@ func TestUserHandler_Update(t *testing.T) {
 					Email: "john@example.com",
 				}
 				m.On("GetUserByID", "user-123").Return(existingUser, nil)
-				m.On("UpdateUser", mock.AnythingOfType("domain.User")).Return(nil)
+				m.On("UpdateUser", mock.AnythingOfType("domain.User"), mock.AnythingOfType("int64")).Return(int64(2), nil)
 			},
 			expectedStatus:      http.StatusOK,
 			expectedBody:        `{"status":"updated"}` + "\n",
@@ -509,7 +542,7 @@ func TestUserHandler_Update(t *testing.T) {
 				Name: "Updated Name",
 			},
 			setupMock: func(m *MockUserService) {
-				m.On("GetUserByID", "user-999").Return(nil, errors.New("not found"))
+				m.On("GetUserByID", "user-999").Return(nil, domain.ErrUserNotFound)
 			},
 			expectedStatus:      http.StatusNotFound,
 			expectedBody:        "user not found\n",
@@ -529,10 +562,10 @@ func TestUserHandler_Update(t *testing.T) {
 					Email: "john@example.com",
 				}
 				m.On("GetUserByID", "user-123").Return(existingUser, nil)
-				m.On("UpdateUser", mock.AnythingOfType("domain.User")).Return(errors.New("update failed"))
+				m.On("UpdateUser", mock.AnythingOfType("domain.User"), mock.AnythingOfType("int64")).Return(int64(0), errors.New("update failed"))
 			},
 			expectedStatus:      http.StatusInternalServerError,
-			expectedBody:        "update failed\n",
+			expectedBody:        "internal server error\n",
 			validateBodySucceed: true,
 		},
 		{
@@ -556,7 +589,7 @@ func TestUserHandler_Update(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			var body []byte
 			if tt.requestBody != nil {
@@ -595,16 +628,69 @@ func TestUserHandler_Update(t *testing.T) {
 				if strings.HasPrefix(tt.expectedBody, "{") {
 					assert.JSONEq(t, tt.expectedBody, rr.Body.String())
 				} else {
-					assert.Equal(t, tt.expectedBody, rr.Body.String())
+					assertBody(t, tt.expectedBody, rr)
 				}
 			}
 			mockService.AssertExpectations(t)
 		})
 	}
 }
+func TestUserHandler_Update_IfMatch(t *testing.T) {
+	// Save the original Body getter and restore after test
+	originalBodyGetter := middleware.Body
+	defer func() {
+		middleware.Body = originalBodyGetter
+	}()
+	middleware.Body = MockBodyGetter{MockedBody: dto.UpdateUserRequest{Name: "John Updated"}, ShouldSucceed: true}
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		updateErr      error
+		expectedStatus int
+		expectedETag   string
+	}{
+		{name: "Happy Path - Current ETag", ifMatch: `"4"`, expectedStatus: http.StatusOK, expectedETag: `"9"`},
+		{name: "Happy Path - No If-Match still conditions on the read version", expectedStatus: http.StatusOK, expectedETag: `"9"`},
+		{name: "Unhappy Path - Stale ETag", ifMatch: `"3"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "Unhappy Path - Concurrent write", ifMatch: `"4"`, updateErr: ports.ErrVersionConflict, expectedStatus: http.StatusPreconditionFailed},
+	}
+
+	// The ETag is the version the service stored the user at, whatever the adapter's numbering
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockUserService)
+			mockService.On("GetUserByID", "user-123").Return(&domain.User{Id: "user-123", Name: "John Doe", Version: 4}, nil)
+			if tt.expectedStatus != http.StatusPreconditionFailed || tt.updateErr != nil {
+				mockService.On("UpdateUser", mock.AnythingOfType("domain.User"), int64(4)).Return(int64(9), tt.updateErr)
+			}
+			handler := NewUserHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/user-123", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "user-123")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.Update(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedETag, rr.Header().Get("ETag"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUserHandler_GetFavourites(t *testing.T) {
 	// Create sample time for consistent testing
 	sampleTime := time.Date(2023, 10, 15, 14, 30, 0, 0, time.UTC)
+	purchases := 5.0
 
 	tests := []struct {
 		name           string
@@ -634,11 +720,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 								CreatedAt:   sampleTime,
 								UpdatedAt:   sampleTime,
 							},
-							Gender:          "All",
-							BirthCountry:    "Various",
-							AgeGroup:        "25-40",
-							HoursSocial:     15,
-							PurchasesLastMo: 5,
+							Criteria: &domain.AudienceCriterion{Operator: domain.AudienceGte, Field: domain.AudienceFieldPurchases, Value: &purchases},
 						},
 					},
 					{
@@ -677,7 +759,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 						},
 					},
 				}
-				m.On("GetFavouritesByUser", "user-123").Return(favourites, nil)
+				m.On("GetFavouritesPageByUser", "user-123", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  3,
@@ -707,7 +789,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 						},
 					},
 				}
-				m.On("GetFavouritesByUser", "user-456").Return(favourites, nil)
+				m.On("GetFavouritesPageByUser", "user-456", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
@@ -732,15 +814,11 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 								CreatedAt:   sampleTime,
 								UpdatedAt:   sampleTime,
 							},
-							Gender:          "All",
-							BirthCountry:    "US",
-							AgeGroup:        "18-25",
-							HoursSocial:     20,
-							PurchasesLastMo: 2,
+							Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"US"}},
 						},
 					},
 				}
-				m.On("GetFavouritesByUser", "user-789").Return(favourites, nil)
+				m.On("GetFavouritesPageByUser", "user-789", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
@@ -769,7 +847,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 						},
 					},
 				}
-				m.On("GetFavouritesByUser", "user-999").Return(favourites, nil)
+				m.On("GetFavouritesPageByUser", "user-999", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
@@ -788,7 +866,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 						Chart:     nil, // nil pointer to test resilience
 					},
 				}
-				m.On("GetFavouritesByUser", "user-555").Return(favourites, nil)
+				m.On("GetFavouritesPageByUser", "user-555", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
@@ -814,7 +892,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 			method: http.MethodGet,
 			userID: "user-999",
 			setupMock: func(m *MockUserService) {
-				m.On("GetFavouritesByUser", "user-999").Return(nil, errors.New("not found"))
+				m.On("GetFavouritesPageByUser", "user-999", 20, "").Return(domain.FavouritePage{}, domain.ErrUserNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedCount:  0,
@@ -824,7 +902,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 			method: http.MethodGet,
 			userID: "user-123",
 			setupMock: func(m *MockUserService) {
-				m.On("GetFavouritesByUser", "user-123").Return([]domain.Favourite{}, nil)
+				m.On("GetFavouritesPageByUser", "user-123", 20, "").Return(domain.FavouritePage{Favourites: []domain.Favourite{}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  0,
@@ -836,7 +914,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 			// Arrange
 			mockService := new(MockUserService)
 			tt.setupMock(mockService)
-			handler := NewUserHandler(mockService)
+			handler := NewUserHandler(mockService, nil)
 
 			req := httptest.NewRequest(tt.method, "/users/"+tt.userID+"/favourites", nil)
 			rr := httptest.NewRecorder()
@@ -853,9 +931,10 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.expectedStatus == http.StatusOK {
-				var response []dto.FavouriteResponse
-				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				var page dto.FavouritesPageResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &page)
 				assert.NoError(t, err)
+				response := page.Favourites
 				assert.Len(t, response, tt.expectedCount)
 
 				// Additional assertions for specific test cases
@@ -877,7 +956,7 @@ func TestUserHandler_GetFavourites(t *testing.T) {
 func TestUserHandler_GetFavourites_HappyPath_SuccessfullyGetsUserFavouritesWithMixedAssetTypes(t *testing.T) {
 	// Arrange
 	mockService := new(MockUserService)
-	handler := NewUserHandler(mockService)
+	handler := NewUserHandler(mockService, nil)
 
 	// Create test favourites with proper assets
 	favourites := []domain.Favourite{
@@ -894,9 +973,7 @@ func TestUserHandler_GetFavourites_HappyPath_SuccessfullyGetsUserFavouritesWithM
 					CreatedAt:   time.Now(),
 					UpdatedAt:   time.Now(),
 				},
-				Gender:       "F",
-				BirthCountry: "US",
-				AgeGroup:     "25-34",
+				Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
 			},
 		},
 		{
@@ -918,7 +995,7 @@ func TestUserHandler_GetFavourites_HappyPath_SuccessfullyGetsUserFavouritesWithM
 		},
 	}
 
-	mockService.On("GetFavouritesByUser", "user-123").Return(favourites, nil)
+	mockService.On("GetFavouritesPageByUser", "user-123", 20, "").Return(domain.FavouritePage{Favourites: favourites}, nil)
 
 	req := httptest.NewRequest("GET", "/users/user-123/favourites", nil)
 	rctx := chi.NewRouteContext()
@@ -938,9 +1015,10 @@ func TestUserHandler_GetFavourites_HappyPath_SuccessfullyGetsUserFavouritesWithM
 	assert.NotEmpty(t, rr.Body.String(), "Response body should not be empty")
 
 	// Parse and verify the JSON response
-	var response []dto.FavouriteResponse
-	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	var page dto.FavouritesPageResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &page)
 	assert.NoError(t, err)
+	response := page.Favourites
 	assert.Len(t, response, 2, "Should return 2 favourites")
 
 	// Verify first favourite (Audience)
@@ -955,3 +1033,81 @@ func TestUserHandler_GetFavourites_HappyPath_SuccessfullyGetsUserFavouritesWithM
 
 	mockService.AssertExpectations(t)
 }
+
+func TestUserHandler_GetFavourites_Pagination(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setupMock          func(*MockUserService)
+		expectedStatus     int
+		expectedNextCursor string
+	}{
+		{
+			name:  "Happy Path - Passes limit and cursor through and returns next cursor",
+			query: "?limit=2&cursor=abc",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "user-123", 2, "abc").Return(domain.FavouritePage{
+					Favourites: []domain.Favourite{{UserID: "user-123", AssetID: "a1"}, {UserID: "user-123", AssetID: "a2"}},
+					NextCursor: "next-token",
+				}, nil)
+			},
+			expectedStatus:     http.StatusOK,
+			expectedNextCursor: "next-token",
+		},
+		{
+			name:  "Happy Path - Limit above maximum is capped",
+			query: "?limit=1000",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "user-123", 100, "").Return(domain.FavouritePage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unhappy Path - Non numeric limit",
+			query:          "?limit=ten",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unhappy Path - Zero limit",
+			query:          "?limit=0",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Unhappy Path - Invalid cursor",
+			query: "?cursor=garbage",
+			setupMock: func(m *MockUserService) {
+				m.On("GetFavouritesPageByUser", "user-123", 20, "garbage").Return(domain.FavouritePage{}, domain.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+			handler := NewUserHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/user-123/favourites"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "user-123")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.GetFavourites(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var page dto.FavouritesPageResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+				assert.Equal(t, tt.expectedNextCursor, page.NextCursor)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
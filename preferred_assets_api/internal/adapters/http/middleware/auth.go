@@ -2,9 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
 )
 
@@ -14,36 +17,70 @@ type cxtKey string
 const (
 	UserClaimsKey cxtKey = "user_claims"
 	UserRolesKey  cxtKey = "user_roles"
+	// APIKeyIDKey holds the id of the API key the caller authenticated with, if any
+	APIKeyIDKey cxtKey = "api_key_id"
+	// APIKeyUsersKey holds the users an API key is restricted to, if any
+	APIKeyUsersKey cxtKey = "api_key_users"
 )
 
-// AuthMiddleware verifies JWT tokens
-func AuthMiddleware(keycloak *auth.KeycloakClient) func(http.Handler) http.Handler {
+// APIKeyHeader carries the key of machine clients authenticating without a token
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator returns the API key matching a clear key, or domain.ErrInvalidAPIKey
+type APIKeyAuthenticator func(plain string) (domain.APIKey, error)
+
+// AuthMiddleware verifies JWT tokens, or the API key sent in the X-API-Key header.
+// API keys put their roles in the context like tokens do; a key restricted to a single user acts as that user.
+func AuthMiddleware(verifier auth.TokenVerifier, apiKeys APIKeyAuthenticator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if plain := r.Header.Get(APIKeyHeader); plain != "" {
+				key, err := apiKeys(plain)
+				if errors.Is(err, domain.ErrInvalidAPIKey) {
+					WriteProblem(w, r, http.StatusUnauthorized, "Invalid or revoked API key")
+					return
+				}
+				if err != nil {
+					WriteError(w, r, fmt.Errorf("failed to verify API key: %w", err))
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserRolesKey, key.Roles)
+				ctx = context.WithValue(ctx, APIKeyIDKey, key.ID)
+				if len(key.UserIDs) > 0 {
+					ctx = context.WithValue(ctx, APIKeyUsersKey, key.UserIDs)
+				}
+				if len(key.UserIDs) == 1 {
+					ctx = context.WithValue(ctx, CallerIDKey, key.UserIDs[0])
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, `{"error": "Authorization header required"}`, http.StatusUnauthorized)
+				WriteProblem(w, r, http.StatusUnauthorized, "Authorization header required")
 				return
 			}
 
 			// Extract token from "Bearer <token>"
 			parts := strings.Split(authHeader, " ")
 			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-				http.Error(w, `{"error": "Invalid authorization header format"}`, http.StatusUnauthorized)
+				WriteProblem(w, r, http.StatusUnauthorized, "Invalid authorization header format")
 				return
 			}
 
 			token := parts[1]
 
 			// Verify token
-			claims, err := keycloak.VerifyToken(token)
+			claims, err := verifier.VerifyToken(token)
 			if err != nil {
-				http.Error(w, `{"error": "Invalid or expired token"}`, http.StatusUnauthorized)
+				WriteProblem(w, r, http.StatusUnauthorized, "Invalid or expired token")
 				return
 			}
 
 			// Get user roles
-			roles := keycloak.GetUserRoles(claims)
+			roles := verifier.GetUserRoles(claims)
 
 			// Add claims and roles to context
 			ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
@@ -60,7 +97,7 @@ func RoleMiddleware(requiredRole string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			roles, ok := r.Context().Value(UserRolesKey).([]string)
 			if !ok {
-				http.Error(w, `{"error": "No roles found in context"}`, http.StatusForbidden)
+				WriteProblem(w, r, http.StatusForbidden, "No roles found in context")
 				return
 			}
 
@@ -74,7 +111,7 @@ func RoleMiddleware(requiredRole string) func(http.Handler) http.Handler {
 			}
 
 			if !hasRole {
-				http.Error(w, `{"error": "Insufficient permissions"}`, http.StatusForbidden)
+				WriteProblem(w, r, http.StatusForbidden, "Insufficient permissions")
 				return
 			}
 
@@ -89,26 +126,13 @@ func RequireAnyRole(requiredRoles ...string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			roles, ok := r.Context().Value(UserRolesKey).([]string)
 			if !ok {
-				http.Error(w, `{"error": "No roles found in context"}`, http.StatusForbidden)
+				WriteProblem(w, r, http.StatusForbidden, "No roles found in context")
 				return
 			}
 
 			// Check if user has at least one of the required roles
-			hasRequiredRole := false
-			for _, userRole := range roles {
-				for _, requiredRole := range requiredRoles {
-					if userRole == requiredRole {
-						hasRequiredRole = true
-						break
-					}
-				}
-				if hasRequiredRole {
-					break
-				}
-			}
-
-			if !hasRequiredRole {
-				http.Error(w, `{"error": "Insufficient permissions"}`, http.StatusForbidden)
+			if !hasAnyRole(roles, requiredRoles) {
+				WriteProblem(w, r, http.StatusForbidden, "Insufficient permissions")
 				return
 			}
 
@@ -128,3 +152,15 @@ func GetRolesFromContext(ctx context.Context) ([]string, bool) {
 	roles, ok := ctx.Value(UserRolesKey).([]string)
 	return roles, ok
 }
+
+// GetAPIKeyIDFromContext retrieves the id of the caller's API key, if they authenticated with one
+func GetAPIKeyIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(APIKeyIDKey).(string)
+	return id, ok && id != ""
+}
+
+// GetAPIKeyUsersFromContext retrieves the users the caller's API key is restricted to, if it is
+func GetAPIKeyUsersFromContext(ctx context.Context) ([]string, bool) {
+	users, ok := ctx.Value(APIKeyUsersKey).([]string)
+	return users, ok && len(users) > 0
+}
@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+	"github.com/golang-jwt/jwt"
+)
+
+func noAPIKeys(plain string) (domain.APIKey, error) {
+	return domain.APIKey{}, domain.ErrInvalidAPIKey
+}
+
+func TestAuthMiddleware_StaticKey(t *testing.T) {
+	secret := []byte("a-test-secret-of-reasonable-length")
+	verifier, err := auth.NewStaticKeyVerifier(secret, "", "preferred-assets-api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims := auth.CustomClaims{AuthorizedParty: "preferred-assets-api", StandardClaims: jwt.StandardClaims{Subject: "alice-sub", ExpiresAt: time.Now().Add(time.Hour).Unix()}}
+	claims.RealmAccess.Roles = []string{"Users"}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "valid token", authorization: "Bearer " + token, wantStatus: http.StatusOK},
+		{name: "missing header", wantStatus: http.StatusUnauthorized},
+		{name: "malformed header", authorization: token, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer " + token + "x", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var gotRoles []string
+			handler := middleware.AuthMiddleware(verifier, noAPIKeys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRoles, _ = middleware.GetRolesFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/assets", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && (len(gotRoles) != 1 || gotRoles[0] != "Users") {
+				t.Errorf("expected roles [Users], got %v", gotRoles)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	keys := map[string]domain.APIKey{
+		"pak_admin":  {ID: "k1", Roles: []string{"Administrators"}},
+		"pak_single": {ID: "k2", Roles: []string{"Users"}, UserIDs: []string{"u1"}},
+		"pak_multi":  {ID: "k3", Roles: []string{"Users"}, UserIDs: []string{"u1", "u2"}},
+	}
+	apiKeys := func(plain string) (domain.APIKey, error) {
+		if plain == "pak_broken" {
+			return domain.APIKey{}, errors.New("db down")
+		}
+		key, ok := keys[plain]
+		if !ok {
+			return domain.APIKey{}, domain.ErrInvalidAPIKey
+		}
+		return key, nil
+	}
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		wantStatus int
+		wantRoles  []string
+		wantCaller string
+		wantUsers  []string
+		wantKeyID  string
+	}{
+		{name: "unrestricted key", apiKey: "pak_admin", wantStatus: http.StatusOK, wantRoles: []string{"Administrators"}, wantKeyID: "k1"},
+		{name: "key for a single user acts as the user", apiKey: "pak_single", wantStatus: http.StatusOK, wantRoles: []string{"Users"}, wantCaller: "u1", wantUsers: []string{"u1"}, wantKeyID: "k2"},
+		{name: "key for several users", apiKey: "pak_multi", wantStatus: http.StatusOK, wantRoles: []string{"Users"}, wantUsers: []string{"u1", "u2"}, wantKeyID: "k3"},
+		{name: "unknown or revoked key", apiKey: "pak_unknown", wantStatus: http.StatusUnauthorized},
+		{name: "lookup fails", apiKey: "pak_broken", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var gotRoles, gotUsers []string
+			var gotCaller, gotKeyID string
+			handler := middleware.AuthMiddleware(nil, apiKeys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotKeyID, _ = middleware.GetAPIKeyIDFromContext(r.Context())
+				gotRoles, _ = middleware.GetRolesFromContext(r.Context())
+				gotUsers, _ = middleware.GetAPIKeyUsersFromContext(r.Context())
+				gotCaller, _ = middleware.GetCallerIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/assets", nil)
+			req.Header.Set(middleware.APIKeyHeader, tt.apiKey)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if !slices.Equal(gotRoles, tt.wantRoles) {
+				t.Errorf("expected roles %v, got %v", tt.wantRoles, gotRoles)
+			}
+			if !slices.Equal(gotUsers, tt.wantUsers) {
+				t.Errorf("expected users %v, got %v", tt.wantUsers, gotUsers)
+			}
+			if gotCaller != tt.wantCaller {
+				t.Errorf("expected caller '%s', got '%s'", tt.wantCaller, gotCaller)
+			}
+			if gotKeyID != tt.wantKeyID {
+				t.Errorf("expected key '%s', got '%s'", tt.wantKeyID, gotKeyID)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// CallerIDKey holds the id of the domain user the authenticated caller signs in as
+const CallerIDKey cxtKey = "caller_id"
+
+// UserIDResolver maps the subject of an authenticated caller to the id of their domain user.
+// It returns domain.ErrUserNotFound when the caller is not linked to any user.
+type UserIDResolver func(subject string) (string, error)
+
+// UserProvisioner returns the id of the domain user of an authenticated caller, creating it on first use
+type UserProvisioner func(subject string, username string, email string) (string, error)
+
+// OwnerExtractor returns the id of the user owning the resource a request addresses,
+// or false if the request is not scoped to a single user
+type OwnerExtractor func(r *http.Request) (string, bool)
+
+// ResolveCaller looks up the domain user of the authenticated caller and stores its id in the context.
+// It must run after AuthMiddleware. Callers that are not linked to a user continue without an id.
+func ResolveCaller(resolve UserIDResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaimsFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := resolve(claims.Subject)
+			if errors.Is(err, domain.ErrUserNotFound) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				WriteError(w, r, fmt.Errorf("failed to resolve user: %w", err))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), CallerIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ProvisionCaller makes sure the authenticated caller has a domain user, creating one the first time
+// their subject calls the API, and stores its id in the context. It must run after AuthMiddleware.
+func ProvisionCaller(provision UserProvisioner) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetCallerIDFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := GetClaimsFromContext(r.Context())
+			if !ok || claims.Subject == "" {
+				WriteProblem(w, r, http.StatusUnauthorized, "Token has no subject")
+				return
+			}
+
+			userID, err := provision(claims.Subject, claims.PreferredName, claims.Email)
+			if err != nil {
+				WriteError(w, r, fmt.Errorf("failed to provision user: %w", err))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), CallerIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireSelfOrPermission lets a request through if it is not scoped to a user, if the caller owns
+// the addressed resource, or if the caller has been granted the permission. It must run after GrantPermissions.
+// Callers using an API key restricted to some users can only reach those users, whatever their permissions.
+func RequireSelfOrPermission(owner OwnerExtractor, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID, scoped := owner(r)
+			if !scoped {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if keyUsers, restricted := GetAPIKeyUsersFromContext(r.Context()); restricted {
+				if !slices.Contains(keyUsers, ownerID) {
+					writeOtherUserProblem(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if callerID, ok := GetCallerIDFromContext(r.Context()); ok && callerID == ownerID {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if permissions, ok := GetPermissionsFromContext(r.Context()); ok && slices.Contains(permissions, permission) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writeOtherUserProblem(w, r)
+		})
+	}
+}
+
+// RequireAPIKeyUser refuses callers using an API key restricted to some users when the request addresses
+// another user, whatever their permissions. Other callers are let through; this does not check ownership.
+func RequireAPIKeyUser(owner OwnerExtractor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyUsers, restricted := GetAPIKeyUsersFromContext(r.Context())
+			if !restricted {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ownerID, scoped := owner(r); !scoped || !slices.Contains(keyUsers, ownerID) {
+				writeOtherUserProblem(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RejectRestrictedAPIKeys refuses callers using an API key restricted to some users. It guards the routes that reach
+// every user, or could widen the key, such as listing users and issuing API keys.
+func RejectRestrictedAPIKeys() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, restricted := GetAPIKeyUsersFromContext(r.Context()); restricted {
+				WriteProblem(w, r, http.StatusForbidden, "Not allowed with an API key restricted to users")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeOtherUserProblem(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, http.StatusForbidden, "Access to another user's resources is not allowed")
+}
+
+// OwnerFromURLParam reads the owner from a chi URL parameter
+func OwnerFromURLParam(name string) OwnerExtractor {
+	return func(r *http.Request) (string, bool) {
+		return chi.URLParam(r, name), true
+	}
+}
+
+// OwnerFromQuery reads the owner from a query parameter; requests without it are not scoped to a user
+func OwnerFromQuery(name string) OwnerExtractor {
+	return func(r *http.Request) (string, bool) {
+		ownerID := r.URL.Query().Get(name)
+		return ownerID, ownerID != ""
+	}
+}
+
+// OwnerFromBody reads the owner from the validated body of type T, so it must run after ValidateBody[T]
+func OwnerFromBody[T any](owner func(T) string) OwnerExtractor {
+	return func(r *http.Request) (string, bool) {
+		body, ok := GetValidatedBody[T](r)
+		if !ok {
+			return "", true
+		}
+		return owner(body), true
+	}
+}
+
+// GetCallerIDFromContext retrieves the caller's user id from context
+func GetCallerIDFromContext(ctx context.Context) (string, bool) {
+	callerID, ok := ctx.Value(CallerIDKey).(string)
+	return callerID, ok && callerID != ""
+}
+
+// hasAnyRole reports whether roles contains at least one of the required roles
+func hasAnyRole(roles []string, required []string) bool {
+	for _, role := range roles {
+		for _, requiredRole := range required {
+			if role == requiredRole {
+				return true
+			}
+		}
+	}
+	return false
+}
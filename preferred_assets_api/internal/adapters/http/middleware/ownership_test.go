@@ -0,0 +1,360 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt"
+)
+
+// signedIn builds a request context as AuthMiddleware, GrantPermissions and ResolveCaller would leave it
+func signedIn(r *http.Request, callerID string, roles ...string) *http.Request {
+	ctx := context.WithValue(r.Context(), middleware.UserRolesKey, roles)
+	ctx = context.WithValue(ctx, middleware.PermissionsKey, auth.DefaultRolePermissions().PermissionsFor(roles))
+	if callerID != "" {
+		ctx = context.WithValue(ctx, middleware.CallerIDKey, callerID)
+	}
+	return r.WithContext(ctx)
+}
+
+func withURLParam(r *http.Request, name string, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(name, value)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRequireSelfOrPermission_URLParam(t *testing.T) {
+	tests := []struct {
+		name       string
+		callerID   string
+		roles      []string
+		ownerID    string
+		wantStatus int
+	}{
+		{name: "owner", callerID: "u1", roles: []string{"Users"}, ownerID: "u1", wantStatus: http.StatusOK},
+		{name: "another user", callerID: "u2", roles: []string{"Users"}, ownerID: "u1", wantStatus: http.StatusForbidden},
+		{name: "administrator", callerID: "admin", roles: []string{"Administrators", "Users"}, ownerID: "u1", wantStatus: http.StatusOK},
+		{name: "administrator without user", roles: []string{"Administrators"}, ownerID: "u1", wantStatus: http.StatusOK},
+		{name: "caller not linked to a user", roles: []string{"Users"}, ownerID: "u1", wantStatus: http.StatusForbidden},
+		{name: "empty owner", roles: []string{"Users"}, ownerID: "", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.RequireSelfOrPermission(middleware.OwnerFromURLParam("id"), auth.PermissionUsersAdmin)(okHandler())
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tt.ownerID+"/favourites", nil)
+			req = signedIn(withURLParam(req, "id", tt.ownerID), tt.callerID, tt.roles...)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSelfOrPermission_APIKeyUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyUsers   []string
+		roles      []string
+		ownerID    string
+		wantStatus int
+	}{
+		{name: "user the key is restricted to", keyUsers: []string{"u1", "u2"}, roles: []string{"Users"}, ownerID: "u2", wantStatus: http.StatusOK},
+		{name: "user outside the key's users", keyUsers: []string{"u1", "u2"}, roles: []string{"Users"}, ownerID: "u3", wantStatus: http.StatusForbidden},
+		{name: "administrator key restricted to users", keyUsers: []string{"u1"}, roles: []string{"Administrators"}, ownerID: "u3", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.RequireSelfOrPermission(middleware.OwnerFromURLParam("id"), auth.PermissionUsersAdmin)(okHandler())
+			req := signedIn(withURLParam(httptest.NewRequest(http.MethodGet, "/users/"+tt.ownerID+"/favourites", nil), "id", tt.ownerID), "", tt.roles...)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.APIKeyUsersKey, tt.keyUsers))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireAPIKeyUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyUsers   []string
+		ownerID    string
+		wantStatus int
+	}{
+		{name: "administrator token", ownerID: "u3", wantStatus: http.StatusOK},
+		{name: "unrestricted key", keyUsers: []string{}, ownerID: "u3", wantStatus: http.StatusOK},
+		{name: "user the key is restricted to", keyUsers: []string{"u1", "u2"}, ownerID: "u2", wantStatus: http.StatusOK},
+		{name: "user outside the key's users", keyUsers: []string{"u1", "u2"}, ownerID: "u3", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.RequireAPIKeyUser(middleware.OwnerFromURLParam("id"))(okHandler())
+			req := signedIn(withURLParam(httptest.NewRequest(http.MethodDelete, "/users/"+tt.ownerID, nil), "id", tt.ownerID), "", "Administrators")
+			if tt.keyUsers != nil {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.APIKeyUsersKey, tt.keyUsers))
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRejectRestrictedAPIKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyUsers   []string
+		wantStatus int
+	}{
+		{name: "administrator token", wantStatus: http.StatusOK},
+		{name: "unrestricted key", keyUsers: []string{}, wantStatus: http.StatusOK},
+		{name: "key restricted to users", keyUsers: []string{"u1"}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.RejectRestrictedAPIKeys()(okHandler())
+			req := signedIn(httptest.NewRequest(http.MethodGet, "/users", nil), "", "Administrators")
+			if tt.keyUsers != nil {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.APIKeyUsersKey, tt.keyUsers))
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSelfOrPermission_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "unscoped", target: "/assets/search?q=social", wantStatus: http.StatusOK},
+		{name: "own favourites", target: "/assets/search?q=social&favourites_of=u1", wantStatus: http.StatusOK},
+		{name: "another user's favourites", target: "/assets/search?q=social&favourites_of=u2", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.RequireSelfOrPermission(middleware.OwnerFromQuery("favourites_of"), auth.PermissionUsersAdmin)(okHandler())
+			req := signedIn(httptest.NewRequest(http.MethodGet, tt.target, nil), "u1", "Users")
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSelfOrPermission_Body(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		wantStatus int
+	}{
+		{name: "own favourite", userID: "u1", wantStatus: http.StatusOK},
+		{name: "favourite for another user", userID: "u2", wantStatus: http.StatusForbidden},
+	}
+
+	original := middleware.Body
+	defer func() { middleware.Body = original }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			middleware.Body = MockBodyGetter{
+				MockedBody:    dto.FavouriteRequest{UserId: tt.userID, AssetId: "a1"},
+				ShouldSucceed: true,
+			}
+			owner := middleware.OwnerFromBody(func(req dto.FavouriteRequest) string { return req.UserId })
+			handler := middleware.RequireSelfOrPermission(owner, auth.PermissionUsersAdmin)(okHandler())
+			req := signedIn(httptest.NewRequest(http.MethodPost, "/favourites", nil), "u1", "Users")
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestResolveCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolve    middleware.UserIDResolver
+		wantStatus int
+		wantCaller string
+	}{
+		{
+			name:       "linked caller",
+			resolve:    func(subject string) (string, error) { return "u1", nil },
+			wantStatus: http.StatusOK,
+			wantCaller: "u1",
+		},
+		{
+			name:       "caller not linked",
+			resolve:    func(subject string) (string, error) { return "", domain.ErrUserNotFound },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "lookup fails",
+			resolve:    func(subject string) (string, error) { return "", errors.New("db down") },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var gotCaller string
+			handler := middleware.ResolveCaller(tt.resolve)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotCaller, _ = middleware.GetCallerIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+			claims := &auth.CustomClaims{PreferredName: "alice", StandardClaims: jwt.StandardClaims{Subject: "alice-sub"}}
+			req := httptest.NewRequest(http.MethodGet, "/users/u1/favourites", nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserClaimsKey, claims))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if gotCaller != tt.wantCaller {
+				t.Errorf("expected caller '%s', got '%s'", tt.wantCaller, gotCaller)
+			}
+		})
+	}
+}
+
+func TestProvisionCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *auth.CustomClaims
+		callerID   string
+		provision  middleware.UserProvisioner
+		wantStatus int
+		wantCaller string
+	}{
+		{
+			name:   "first call provisions the user",
+			claims: &auth.CustomClaims{PreferredName: "alice", Email: "alice@example.com", StandardClaims: jwt.StandardClaims{Subject: "alice-sub"}},
+			provision: func(subject, username, email string) (string, error) {
+				if subject != "alice-sub" || username != "alice" || email != "alice@example.com" {
+					return "", errors.New("unexpected claims")
+				}
+				return "u1", nil
+			},
+			wantStatus: http.StatusOK,
+			wantCaller: "u1",
+		},
+		{
+			name:       "already resolved caller",
+			claims:     &auth.CustomClaims{StandardClaims: jwt.StandardClaims{Subject: "alice-sub"}},
+			callerID:   "u1",
+			provision:  func(subject, username, email string) (string, error) { return "", errors.New("must not be called") },
+			wantStatus: http.StatusOK,
+			wantCaller: "u1",
+		},
+		{
+			name:       "token without subject",
+			claims:     &auth.CustomClaims{PreferredName: "alice"},
+			provision:  func(subject, username, email string) (string, error) { return "u1", nil },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "provisioning fails",
+			claims:     &auth.CustomClaims{StandardClaims: jwt.StandardClaims{Subject: "alice-sub"}},
+			provision:  func(subject, username, email string) (string, error) { return "", errors.New("db down") },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var gotCaller string
+			handler := middleware.ProvisionCaller(tt.provision)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotCaller, _ = middleware.GetCallerIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/me/favourites", nil)
+			ctx := context.WithValue(req.Context(), middleware.UserClaimsKey, tt.claims)
+			if tt.callerID != "" {
+				ctx = context.WithValue(ctx, middleware.CallerIDKey, tt.callerID)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req.WithContext(ctx))
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if gotCaller != tt.wantCaller {
+				t.Errorf("expected caller '%s', got '%s'", tt.wantCaller, gotCaller)
+			}
+		})
+	}
+}
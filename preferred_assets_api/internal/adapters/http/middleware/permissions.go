@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+)
+
+// PermissionsKey holds the permissions granted to the authenticated caller by their roles
+const PermissionsKey cxtKey = "user_permissions"
+
+// GrantPermissions stores the permissions the caller's roles grant in the context. It must run after AuthMiddleware.
+func GrantPermissions(mapping auth.RolePermissions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			roles, _ := GetRolesFromContext(r.Context())
+			ctx := context.WithValue(r.Context(), PermissionsKey, mapping.PermissionsFor(roles))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePermission checks that the caller has been granted the permission
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			permissions, ok := GetPermissionsFromContext(r.Context())
+			if !ok {
+				WriteProblem(w, r, http.StatusForbidden, "No permissions found in context")
+				return
+			}
+
+			if !slices.Contains(permissions, permission) {
+				WriteProblem(w, r, http.StatusForbidden, "Insufficient permissions")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetPermissionsFromContext retrieves the caller's permissions from context
+func GetPermissionsFromContext(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value(PermissionsKey).([]string)
+	return permissions, ok
+}
@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+)
+
+func TestRequirePermission(t *testing.T) {
+	mapping := auth.RolePermissions{
+		"Users":  {auth.PermissionAssetsRead},
+		"editor": {auth.PermissionAssetsRead, auth.PermissionAssetsWrite},
+	}
+
+	tests := []struct {
+		name       string
+		roles      []string
+		permission string
+		wantStatus int
+	}{
+		{name: "granted by a role", roles: []string{"Users"}, permission: auth.PermissionAssetsRead, wantStatus: http.StatusOK},
+		{name: "granted by a client role", roles: []string{"Users", "editor"}, permission: auth.PermissionAssetsWrite, wantStatus: http.StatusOK},
+		{name: "not granted", roles: []string{"Users"}, permission: auth.PermissionAssetsWrite, wantStatus: http.StatusForbidden},
+		{name: "unmapped role", roles: []string{"Guests"}, permission: auth.PermissionAssetsRead, wantStatus: http.StatusForbidden},
+		{name: "no roles", permission: auth.PermissionAssetsRead, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := middleware.GrantPermissions(mapping)(middleware.RequirePermission(tt.permission)(okHandler()))
+			req := httptest.NewRequest(http.MethodGet, "/assets", nil)
+			if tt.roles != nil {
+				req = req.WithContext(context.WithValue(req.Context(), middleware.UserRolesKey, tt.roles))
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequirePermission_WithoutGrant(t *testing.T) {
+	// Arrange
+	handler := middleware.RequirePermission(auth.PermissionAssetsRead)(okHandler())
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets", nil))
+
+	// Assert
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
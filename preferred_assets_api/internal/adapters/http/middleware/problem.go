@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// ProblemContentType is the media type of error responses
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is the domain error kind, when the problem comes from one,
+// and Errors lists the invalid fields of a rejected request body.
+// swagger:model Problem
+type Problem struct {
+	// example: about:blank
+	Type string `json:"type"`
+	// example: Not Found
+	Title string `json:"title"`
+	// example: 404
+	Status int `json:"status"`
+	// example: asset not found
+	Detail string `json:"detail,omitempty"`
+	// example: /api/v1/assets/asset_456
+	Instance string `json:"instance,omitempty"`
+	// example: not_found
+	Code   string            `json:"code,omitempty"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// kindStatuses maps each domain error kind to its HTTP status
+var kindStatuses = map[domain.ErrorKind]int{
+	domain.KindNotFound:           http.StatusNotFound,
+	domain.KindConflict:           http.StatusConflict,
+	domain.KindValidation:         http.StatusBadRequest,
+	domain.KindUnprocessable:      http.StatusUnprocessableEntity,
+	domain.KindPreconditionFailed: http.StatusPreconditionFailed,
+	domain.KindUnauthorized:       http.StatusUnauthorized,
+	domain.KindForbidden:          http.StatusForbidden,
+}
+
+// StatusOf returns the HTTP status for err by its domain error kind; errors of no kind are internal errors
+func StatusOf(err error) int {
+	if kind, ok := domain.KindOf(err); ok {
+		if status, ok := kindStatuses[kind]; ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError writes err as a problem with the status of its domain error kind.
+// Errors of no kind are logged and reported without detail, so internals do not leak to clients.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status := StatusOf(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("[http] %s %s failed: %v", r.Method, r.URL.Path, err)
+		WriteProblem(w, r, status, "internal server error")
+		return
+	}
+
+	kind, _ := domain.KindOf(err)
+	writeProblem(w, r, Problem{Status: status, Detail: err.Error(), Code: string(kind)})
+}
+
+// WriteProblem writes a problem with the given status and detail
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblem(w, r, Problem{Status: status, Detail: detail})
+}
+
+// WriteValidationProblem writes a 400 problem listing the invalid fields
+func WriteValidationProblem(w http.ResponseWriter, r *http.Request, fields []ValidationError) {
+	writeProblem(w, r, Problem{
+		Status: http.StatusBadRequest,
+		Detail: "request validation failed",
+		Code:   string(domain.KindValidation),
+		Errors: fields,
+	})
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("[http] failed to write problem: %v", err)
+	}
+}
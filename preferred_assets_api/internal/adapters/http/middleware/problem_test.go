@@ -0,0 +1,114 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/http/middleware"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) middleware.Problem {
+	t.Helper()
+	require.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+	var problem middleware.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return problem
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+		wantCode   string
+	}{
+		{name: "not found", err: domain.ErrAssetNotFound, wantStatus: http.StatusNotFound, wantDetail: "asset not found", wantCode: "not_found"},
+		{name: "conflict", err: domain.ErrCollectionNameTaken, wantStatus: http.StatusConflict, wantDetail: domain.ErrCollectionNameTaken.Error(), wantCode: "conflict"},
+		{
+			name:       "wrapped validation error",
+			err:        fmt.Errorf("%w: at most 20 tags are allowed", domain.ErrInvalidFavourite),
+			wantStatus: http.StatusBadRequest,
+			wantDetail: "invalid favourite: at most 20 tags are allowed",
+			wantCode:   "validation",
+		},
+		{name: "unprocessable", err: domain.ErrNotAFavourite, wantStatus: http.StatusUnprocessableEntity, wantDetail: domain.ErrNotAFavourite.Error(), wantCode: "unprocessable"},
+		{name: "version conflict", err: domain.ErrVersionConflict, wantStatus: http.StatusPreconditionFailed, wantDetail: "version conflict", wantCode: "precondition_failed"},
+		{name: "internal error hides its detail", err: errors.New("disk full"), wantStatus: http.StatusInternalServerError, wantDetail: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/assets/a1", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			middleware.WriteError(w, req, tt.err)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+			problem := decodeProblem(t, w)
+			assert.Equal(t, "about:blank", problem.Type)
+			assert.Equal(t, http.StatusText(tt.wantStatus), problem.Title)
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, tt.wantDetail, problem.Detail)
+			assert.Equal(t, tt.wantCode, problem.Code)
+			assert.Equal(t, "/api/v1/assets/a1", problem.Instance)
+		})
+	}
+}
+
+func TestValidateBody_Problem(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantDetail string
+		wantErrors []middleware.ValidationError
+	}{
+		{name: "invalid JSON", body: `{"name":`, wantDetail: "invalid JSON"},
+		{
+			name:       "every invalid field",
+			body:       `{"name":"","roles":["Users","Root"]}`,
+			wantDetail: "request validation failed",
+			wantErrors: []middleware.ValidationError{
+				{Field: "name", Message: "is required"},
+				{Field: "roles[1]", Message: "must be one of: Administrators, Users"},
+			},
+		},
+		{
+			name:       "empty list",
+			body:       `{"name":"reporting","roles":[]}`,
+			wantDetail: "request validation failed",
+			wantErrors: []middleware.ValidationError{{Field: "roles", Message: "must have at least 1 items"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			middleware.Body = middleware.DefaultBodyGetter{}
+			handler := middleware.ValidateBody[dto.APIKeyRequest]()(okHandler())
+			req := httptest.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			problem := decodeProblem(t, w)
+			assert.Equal(t, tt.wantDetail, problem.Detail)
+			assert.Equal(t, tt.wantErrors, problem.Errors)
+		})
+	}
+}
@@ -45,13 +45,13 @@ func ValidateBody[T any]() func(http.Handler) http.Handler {
 
 			// Decode JSON request
 			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-				http.Error(w, "invalid JSON", http.StatusBadRequest)
+				WriteProblem(w, r, http.StatusBadRequest, "invalid JSON")
 				return
 			}
 
-			// Validate struct fields using tags
-			if err := validate.Struct(body); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
+			// Validate struct fields using tags, reporting every invalid field
+			if fields := ValidateStruct(body); fields != nil {
+				WriteValidationProblem(w, r, fields)
 				return
 			}
 
@@ -62,6 +62,15 @@ func ValidateBody[T any]() func(http.Handler) http.Handler {
 	}
 }
 
+// ValidateStruct checks a DTO against its validation tags outside of ValidateBody, e.g. each line of a bulk request.
+// It returns every invalid field, or nil if the DTO is valid.
+func ValidateStruct(v any) []ValidationError {
+	if err := validate.Struct(v); err != nil {
+		return validationErrors(err)
+	}
+	return nil
+}
+
 // GetValidatedBody retrieves the validated DTO using the current BodyGetter
 func GetValidatedBody[T any](r *http.Request) (T, bool) {
 	val, ok := Body.GetValidatedBody(r)
@@ -1,10 +1,79 @@
 package middleware
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError describes why a field of a request body was rejected.
+// Field is the JSON path of the field, e.g. "roles[0]".
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
-type ValidationErrorsResponse struct {
-	Errors []ValidationError `json:"errors"`
+func init() {
+	// Report fields by their JSON names, as clients know them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// validationErrors turns the error of validate.Struct into one ValidationError per invalid field
+func validationErrors(err error) []ValidationError {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []ValidationError{{Message: err.Error()}}
+	}
+
+	result := make([]ValidationError, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		// The namespace starts with the name of the validated struct, which means nothing to clients
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		result = append(result, ValidationError{Field: field, Message: validationMessage(fe)})
+	}
+	return result
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map {
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map {
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+		}
+		return fmt.Sprintf("must satisfy %s", fe.Tag())
+	}
 }
@@ -0,0 +1,79 @@
+package matching
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.AudienceMatcher = (*AudienceMatcher)(nil)
+
+// compiledAudience is the id of an audience with the predicate of its definition
+type compiledAudience struct {
+	id      string
+	matches predicate
+}
+
+// AudienceMatcher holds the compiled definition of every audience in memory. It is safe for concurrent use.
+type AudienceMatcher struct {
+	mu        sync.RWMutex
+	audiences []compiledAudience // in ascending order of id
+}
+
+func NewAudienceMatcher() *AudienceMatcher {
+	return &AudienceMatcher{}
+}
+
+// Index implements ports.AudienceMatcher.
+func (m *AudienceMatcher) Index(asset domain.Asset) {
+	var definition *domain.AudienceCriterion
+	if audience, ok := asset.(*domain.Audience); ok {
+		definition = audience.Definition()
+	}
+	if definition == nil {
+		m.Remove(asset.GetID())
+		return
+	}
+	compiled := compiledAudience{id: asset.GetID(), matches: compile(*definition)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i, found := m.find(compiled.id); found {
+		m.audiences[i] = compiled
+	} else {
+		m.audiences = slices.Insert(m.audiences, i, compiled)
+	}
+}
+
+// Remove implements ports.AudienceMatcher.
+func (m *AudienceMatcher) Remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i, found := m.find(id); found {
+		m.audiences = slices.Delete(m.audiences, i, i+1)
+	}
+}
+
+// Match implements ports.AudienceMatcher.
+func (m *AudienceMatcher) Match(profile domain.RespondentProfile) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matches := []string{}
+	for _, audience := range m.audiences {
+		if audience.matches(&profile) {
+			matches = append(matches, audience.id)
+		}
+	}
+	return matches
+}
+
+// find returns the position of the audience with the id, or where it would be inserted
+func (m *AudienceMatcher) find(id string) (int, bool) {
+	return slices.BinarySearchFunc(m.audiences, id, func(a compiledAudience, id string) int {
+		return strings.Compare(a.id, id)
+	})
+}
@@ -0,0 +1,222 @@
+package matching
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+func number(f float64) *float64 {
+	return &f
+}
+
+func integer(i int) *int {
+	return &i
+}
+
+func criterion(op domain.AudienceOperator, field domain.AudienceField, values ...string) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: op, Field: field, Values: values}
+}
+
+func comparison(op domain.AudienceOperator, field domain.AudienceField, value float64) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: op, Field: field, Value: number(value)}
+}
+
+func group(op domain.AudienceOperator, criteria ...domain.AudienceCriterion) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: op, Criteria: criteria}
+}
+
+func audience(id string, criteria domain.AudienceCriterion) *domain.Audience {
+	return &domain.Audience{
+		AssetBase: domain.AssetBase{ID: id, Type: domain.AssetTypeAudience, Title: id},
+		Criteria:  &criteria,
+	}
+}
+
+func TestCompile(t *testing.T) {
+	// Males 24 to 35 spending more than 3 hours a day on social media
+	youngMales := group(domain.AudienceAnd,
+		criterion(domain.AudienceIn, domain.AudienceFieldGender, "male"),
+		domain.AudienceCriterion{Operator: domain.AudienceBetween, Field: domain.AudienceFieldAge, Min: number(24), Max: number(35)},
+		comparison(domain.AudienceGt, domain.AudienceFieldHoursSocial, 3))
+	// Born in GB or IE, or buyers outside the US
+	buyers := group(domain.AudienceOr,
+		criterion(domain.AudienceIn, domain.AudienceFieldBirthCountry, "GB", "IE"),
+		group(domain.AudienceAnd,
+			criterion(domain.AudienceNotIn, domain.AudienceFieldBirthCountry, "US"),
+			comparison(domain.AudienceGte, domain.AudienceFieldPurchases, 1)))
+
+	tests := []struct {
+		name     string
+		criteria domain.AudienceCriterion
+		profile  domain.RespondentProfile
+		want     bool
+	}{
+		{
+			name:     "and group holds",
+			criteria: youngMales,
+			profile:  domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(24), HoursSocial: number(3.5)},
+			want:     true,
+		},
+		{
+			name:     "between includes its max",
+			criteria: youngMales,
+			profile:  domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(35), HoursSocial: number(4)},
+			want:     true,
+		},
+		{
+			name:     "and group fails on one condition",
+			criteria: youngMales,
+			profile:  domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(36), HoursSocial: number(4)},
+		},
+		{
+			name:     "gt excludes its value",
+			criteria: youngMales,
+			profile:  domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(30), HoursSocial: number(3)},
+		},
+		{
+			name:     "unknown attribute fails its condition",
+			criteria: youngMales,
+			profile:  domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(30)},
+		},
+		{
+			name:     "or group holds on its first criterion",
+			criteria: buyers,
+			profile:  domain.RespondentProfile{BirthCountry: "IE"},
+			want:     true,
+		},
+		{
+			name:     "or group holds on a nested group",
+			criteria: buyers,
+			profile:  domain.RespondentProfile{BirthCountry: "CA", PurchasesLastMonth: integer(2)},
+			want:     true,
+		},
+		{
+			name:     "not_in excludes its values",
+			criteria: buyers,
+			profile:  domain.RespondentProfile{BirthCountry: "US", PurchasesLastMonth: integer(2)},
+		},
+		{
+			name:     "not_in fails on an unknown attribute",
+			criteria: criterion(domain.AudienceNotIn, domain.AudienceFieldGender, "male"),
+			profile:  domain.RespondentProfile{Age: integer(30)},
+		},
+		{
+			name:     "ne fails on an unknown attribute",
+			criteria: comparison(domain.AudienceNe, domain.AudienceFieldAge, 30),
+			profile:  domain.RespondentProfile{Gender: domain.GenderFemale},
+		},
+		{
+			name:     "ne holds on another value",
+			criteria: comparison(domain.AudienceNe, domain.AudienceFieldPurchases, 0),
+			profile:  domain.RespondentProfile{PurchasesLastMonth: integer(3)},
+			want:     true,
+		},
+		{
+			name:     "nested groups of the same operator are merged",
+			criteria: group(domain.AudienceAnd, group(domain.AudienceAnd, comparison(domain.AudienceLte, domain.AudienceFieldAge, 20)), comparison(domain.AudienceLt, domain.AudienceFieldHoursSocial, 1)),
+			profile:  domain.RespondentProfile{Age: integer(20), HoursSocial: number(0.5)},
+			want:     true,
+		},
+		{
+			name:     "empty group never holds",
+			criteria: group(domain.AudienceOr),
+			profile:  domain.RespondentProfile{Gender: domain.GenderOther},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			matches := compile(tt.criteria)
+
+			// Act
+			got := matches(&tt.profile)
+
+			// Assert
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAudienceMatcher_Match(t *testing.T) {
+	// Arrange
+	matcher := NewAudienceMatcher()
+	legacy, err := domain.AudienceAttributes{Gender: "Female", AgeGroup: "25-34"}.Criteria()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	matcher.Index(audience("aud-3", criterion(domain.AudienceIn, domain.AudienceFieldGender, "female", "non-binary")))
+	matcher.Index(audience("aud-1", comparison(domain.AudienceGte, domain.AudienceFieldHoursSocial, 2)))
+	matcher.Index(audience("aud-2", *legacy))
+	matcher.Index(&domain.Insight{AssetBase: domain.AssetBase{ID: "ins-1", Type: domain.AssetTypeInsight, Title: "Insight"}, Text: "Text"})
+
+	tests := []struct {
+		name    string
+		profile domain.RespondentProfile
+		want    []string
+	}{
+		{
+			name:    "every audience, in ascending order of id",
+			profile: domain.RespondentProfile{Gender: domain.GenderFemale, Age: integer(30), HoursSocial: number(2)},
+			want:    []string{"aud-1", "aud-2", "aud-3"},
+		},
+		{
+			name:    "single attributes match whatever the hours",
+			profile: domain.RespondentProfile{Gender: domain.GenderFemale, Age: integer(30), HoursSocial: number(0.5)},
+			want:    []string{"aud-2", "aud-3"},
+		},
+		{
+			name:    "single attributes need every attribute",
+			profile: domain.RespondentProfile{Gender: domain.GenderFemale, Age: integer(40), HoursSocial: number(1)},
+			want:    []string{"aud-3"},
+		},
+		{
+			name:    "no audience",
+			profile: domain.RespondentProfile{Gender: domain.GenderMale},
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := matcher.Match(tt.profile)
+
+			// Assert
+			if !slices.Equal(got, tt.want) || got == nil {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAudienceMatcher_IndexReplacesAndRemoveDrops(t *testing.T) {
+	// Arrange
+	matcher := NewAudienceMatcher()
+	profile := domain.RespondentProfile{Gender: domain.GenderMale, Age: integer(40)}
+	matcher.Index(audience("aud-1", criterion(domain.AudienceIn, domain.AudienceFieldGender, "male")))
+	matcher.Index(audience("aud-2", comparison(domain.AudienceGt, domain.AudienceFieldAge, 30)))
+
+	// Act
+	matcher.Index(audience("aud-1", criterion(domain.AudienceIn, domain.AudienceFieldGender, "female")))
+	replaced := matcher.Match(profile)
+	matcher.Remove("aud-2")
+	removed := matcher.Match(profile)
+	matcher.Index(&domain.Audience{AssetBase: domain.AssetBase{ID: "aud-1", Type: domain.AssetTypeAudience, Title: "Undefined"}})
+	matcher.Remove("missing")
+
+	// Assert
+	if !slices.Equal(replaced, []string{"aud-2"}) {
+		t.Errorf("expected the replaced audience not to match, got %v", replaced)
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected the removed audience not to match, got %v", removed)
+	}
+	if got := matcher.Match(domain.RespondentProfile{Gender: domain.GenderFemale}); len(got) != 0 {
+		t.Errorf("expected an audience without a definition to leave the matcher, got %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+package matching
+
+import "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+// predicate reports whether a respondent profile meets the criteria it was compiled from
+type predicate func(p *domain.RespondentProfile) bool
+
+// never is the predicate of criteria no profile can meet
+func never(*domain.RespondentProfile) bool { return false }
+
+// compile turns audience criteria into a predicate. Nested groups combining with the same operator as their
+// parent are merged into it, values are gathered into sets and every comparison is bound to its field and bounds,
+// so evaluating a predicate reads nothing but the profile. Criteria that fail validation never hold.
+func compile(c domain.AudienceCriterion) predicate {
+	switch c.Operator {
+	case domain.AudienceAnd, domain.AudienceOr:
+		return compileGroup(c)
+	case domain.AudienceIn, domain.AudienceNotIn:
+		return compileList(c)
+	default:
+		return compileComparison(c)
+	}
+}
+
+func compileGroup(c domain.AudienceCriterion) predicate {
+	criteria := flatten(c.Operator, c.Criteria)
+	children := make([]predicate, len(criteria))
+	for i, criterion := range criteria {
+		children[i] = compile(criterion)
+	}
+
+	switch {
+	case len(children) == 0:
+		return never
+	case len(children) == 1:
+		return children[0]
+	case c.Operator == domain.AudienceAnd:
+		return func(p *domain.RespondentProfile) bool {
+			for _, child := range children {
+				if !child(p) {
+					return false
+				}
+			}
+			return true
+		}
+	default:
+		return func(p *domain.RespondentProfile) bool {
+			for _, child := range children {
+				if child(p) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// flatten lists the criteria of a group, replacing the groups among them that combine with the same operator
+// by their own criteria
+func flatten(operator domain.AudienceOperator, criteria []domain.AudienceCriterion) []domain.AudienceCriterion {
+	flat := make([]domain.AudienceCriterion, 0, len(criteria))
+	for _, criterion := range criteria {
+		if criterion.Operator == operator {
+			flat = append(flat, flatten(operator, criterion.Criteria)...)
+		} else {
+			flat = append(flat, criterion)
+		}
+	}
+	return flat
+}
+
+func compileList(c domain.AudienceCriterion) predicate {
+	var read func(p *domain.RespondentProfile) string
+	switch c.Field {
+	case domain.AudienceFieldGender:
+		read = func(p *domain.RespondentProfile) string { return string(p.Gender) }
+	case domain.AudienceFieldBirthCountry:
+		read = func(p *domain.RespondentProfile) string { return p.BirthCountry }
+	default:
+		return never
+	}
+
+	values := make(map[string]bool, len(c.Values))
+	for _, value := range c.Values {
+		values[value] = true
+	}
+	in := c.Operator == domain.AudienceIn
+	return func(p *domain.RespondentProfile) bool {
+		value := read(p)
+		return value != "" && values[value] == in
+	}
+}
+
+func compileComparison(c domain.AudienceCriterion) predicate {
+	var read func(p *domain.RespondentProfile) (float64, bool)
+	switch c.Field {
+	case domain.AudienceFieldAge:
+		read = func(p *domain.RespondentProfile) (float64, bool) {
+			if p.Age == nil {
+				return 0, false
+			}
+			return float64(*p.Age), true
+		}
+	case domain.AudienceFieldHoursSocial:
+		read = func(p *domain.RespondentProfile) (float64, bool) {
+			if p.HoursSocial == nil {
+				return 0, false
+			}
+			return *p.HoursSocial, true
+		}
+	case domain.AudienceFieldPurchases:
+		read = func(p *domain.RespondentProfile) (float64, bool) {
+			if p.PurchasesLastMonth == nil {
+				return 0, false
+			}
+			return float64(*p.PurchasesLastMonth), true
+		}
+	default:
+		return never
+	}
+
+	if c.Operator == domain.AudienceBetween {
+		if c.Min == nil || c.Max == nil {
+			return never
+		}
+		min, max := *c.Min, *c.Max
+		return func(p *domain.RespondentProfile) bool {
+			value, ok := read(p)
+			return ok && value >= min && value <= max
+		}
+	}
+
+	if c.Value == nil {
+		return never
+	}
+	bound := *c.Value
+	var compare func(value float64) bool
+	switch c.Operator {
+	case domain.AudienceEq:
+		compare = func(value float64) bool { return value == bound }
+	case domain.AudienceNe:
+		compare = func(value float64) bool { return value != bound }
+	case domain.AudienceLt:
+		compare = func(value float64) bool { return value < bound }
+	case domain.AudienceLte:
+		compare = func(value float64) bool { return value <= bound }
+	case domain.AudienceGt:
+		compare = func(value float64) bool { return value > bound }
+	case domain.AudienceGte:
+		compare = func(value float64) bool { return value >= bound }
+	default:
+		return never
+	}
+	return func(p *domain.RespondentProfile) bool {
+		value, ok := read(p)
+		return ok && compare(value)
+	}
+}
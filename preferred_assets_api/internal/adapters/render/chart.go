@@ -0,0 +1,358 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+const (
+	// The plot area is inset from the edges of the image to leave room for the axes labels
+	marginLeft   = 64
+	marginRight  = 24
+	marginTop    = 24
+	marginBottom = 56
+	ticks        = 5
+
+	// categoryLabelWidth is the room left for each category label, skipping labels that would overlap
+	categoryLabelWidth = 40
+
+	// Legends mark every series or slice with a square of its colour
+	legendSwatch     = 10
+	legendLineHeight = 20
+	pieLegendWidth   = 200
+)
+
+// palette colours the series of a chart in turn
+var palette = []color.RGBA{
+	{0x4e, 0x79, 0xa7, 0xff}, {0xf2, 0x8e, 0x2b, 0xff}, {0xe1, 0x57, 0x59, 0xff}, {0x76, 0xb7, 0xb2, 0xff},
+	{0x59, 0xa1, 0x4f, 0xff}, {0xed, 0xc9, 0x48, 0xff}, {0xb0, 0x7a, 0xa1, 0xff}, {0xff, 0x9d, 0xa7, 0xff},
+}
+
+var (
+	backgroundColour = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColour       = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	axisColour       = color.RGBA{0x44, 0x44, 0x44, 0xff}
+	titleColour      = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	mutedColour      = color.RGBA{0x66, 0x66, 0x66, 0xff}
+)
+
+type point struct {
+	x, y float64
+}
+
+// plot is what a chart draws: series of points, with the names of the chart's series, and the labels of the
+// categories. The points of a series placed by category have the index of their category as x value.
+type plot struct {
+	series     [][]point
+	names      []string
+	categories []string
+}
+
+// plotOf returns the plot of a chart, from its series or else from its data matrix
+func plotOf(chart *domain.Chart) plot {
+	if len(chart.Series) == 0 {
+		return plot{series: chartSeries(chart.Data)}
+	}
+
+	p := plot{categories: chart.Categories}
+	for _, series := range chart.Series {
+		points := make([]point, len(series.Values))
+		for i, value := range series.Values {
+			points[i] = point{x: float64(i), y: value}
+			if i < len(series.X) {
+				points[i].x = series.X[i]
+			}
+		}
+		p.series = append(p.series, points)
+		p.names = append(p.names, series.Name)
+	}
+	return p
+}
+
+// label returns the category at x, or x itself when there is no such category
+func (p plot) label(x float64) string {
+	if i := int(x); float64(i) == x && i >= 0 && i < len(p.categories) {
+		return p.categories[i]
+	}
+	return number(x)
+}
+
+// textAnchor aligns a text to its position
+type textAnchor string
+
+const (
+	anchorStart  textAnchor = "start"
+	anchorMiddle textAnchor = "middle"
+	anchorEnd    textAnchor = "end"
+)
+
+// canvas is what a chart is drawn on, in pixels from the top left corner of the image
+type canvas interface {
+	rect(x, y, width, height float64, fill color.RGBA)
+	line(x1, y1, x2, y2, width float64, stroke color.RGBA)
+	polyline(points []point, width float64, stroke color.RGBA)
+	circle(cx, cy, r float64, fill color.RGBA)
+	// wedge fills the slice of a circle between two angles, in radians clockwise from the top
+	wedge(cx, cy, r, start, end float64, fill color.RGBA)
+	// text is vertically centred on y and aligned to x by anchor; vertical text is turned to read upwards
+	text(x, y float64, s string, anchor textAnchor, vertical bool, fill color.RGBA)
+	// textWidth is about how wide text draws s
+	textWidth(s string) float64
+}
+
+// chartSize replaces non-positive sizes with the default chart render size
+func chartSize(width, height int) (int, int) {
+	if width <= 0 {
+		width = domain.DefaultChartRenderWidth
+	}
+	if height <= 0 {
+		height = domain.DefaultChartRenderHeight
+	}
+	return width, height
+}
+
+// chartSeries splits the data of a chart into series. With two or more columns every row is a point: the first
+// column is its x value and each other column the y value of one series. A single column is one series plotted
+// against the row index.
+func chartSeries(data [][]float64) [][]point {
+	if len(data) == 0 || len(data[0]) == 0 {
+		return nil
+	}
+
+	if len(data[0]) == 1 {
+		line := make([]point, len(data))
+		for i, row := range data {
+			line[i] = point{x: float64(i), y: row[0]}
+		}
+		return [][]point{line}
+	}
+
+	series := make([][]point, len(data[0])-1)
+	for _, row := range data {
+		for s := range series {
+			if s+1 < len(row) {
+				series[s] = append(series[s], point{x: row[0], y: row[s+1]})
+			}
+		}
+	}
+	return series
+}
+
+// bounds returns the range of the x and y values of the series, widened when all values are equal
+func bounds(series [][]point) (minX, maxX, minY, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, line := range series {
+		for _, p := range line {
+			minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+			minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+		}
+	}
+	if minX == maxX {
+		minX, maxX = minX-1, maxX+1
+	}
+	if minY == maxY {
+		minY, maxY = minY-1, maxY+1
+	}
+	return minX, maxX, minY, maxY
+}
+
+// drawChart draws the chart as the given kind of chart, or as its own kind when kind is empty. Line and scatter
+// charts place points by their x value, or at their category; bar charts draw one group of bars per category, or per
+// row of a chart without categories, with bars growing from zero. Named series are listed in a legend at the top.
+func drawChart(c canvas, chart *domain.Chart, kind domain.ChartKind, width, height int) {
+	c.rect(0, 0, float64(width), float64(height), backgroundColour)
+	if kind == "" {
+		kind = chart.DrawnAs()
+	}
+
+	p := plotOf(chart)
+	if len(p.series) == 0 {
+		c.text(float64(width)/2, float64(height)/2, "No data", anchorMiddle, false, mutedColour)
+		return
+	}
+	if kind == domain.ChartPie {
+		drawPie(c, p, width, height)
+		return
+	}
+
+	left, top := float64(marginLeft), float64(marginTop)
+	right, bottom := float64(width-marginRight), float64(height-marginBottom)
+
+	minX, maxX, minY, maxY := bounds(p.series)
+	if kind == domain.ChartBar {
+		minY, maxY = math.Min(minY, 0), math.Max(maxY, 0)
+	}
+	toX := func(x float64) float64 { return left + (x-minX)/(maxX-minX)*(right-left) }
+	toY := func(y float64) float64 { return bottom - (y-minY)/(maxY-minY)*(bottom-top) }
+
+	// Grid and y tick labels
+	for i := 0; i < ticks; i++ {
+		fraction := float64(i) / float64(ticks-1)
+		y := bottom - fraction*(bottom-top)
+		c.line(left, y, right, y, 1, gridColour)
+		c.text(left-6, y, number(minY+fraction*(maxY-minY)), anchorEnd, false, axisColour)
+	}
+
+	// x tick labels; bar charts label their groups as they draw them
+	switch {
+	case kind == domain.ChartBar:
+	case len(p.categories) > 0:
+		labelEvery := categoryLabelStep(len(p.categories), right-left)
+		for i := 0; i < len(p.categories); i += labelEvery {
+			c.text(toX(float64(i)), bottom+14, p.categories[i], anchorMiddle, false, axisColour)
+		}
+	default:
+		for i := 0; i < ticks; i++ {
+			fraction := float64(i) / float64(ticks-1)
+			c.text(left+fraction*(right-left), bottom+14, number(minX+fraction*(maxX-minX)), anchorMiddle, false, axisColour)
+		}
+	}
+
+	// Axes titles
+	if title := axisTitle(chart, 0); title != "" {
+		c.text((left+right)/2, float64(height)-16, title, anchorMiddle, false, titleColour)
+	}
+	if title := axisTitle(chart, 1); title != "" {
+		c.text(12, (top+bottom)/2, title, anchorMiddle, true, titleColour)
+	}
+
+	// Series
+	switch kind {
+	case domain.ChartBar:
+		drawBars(c, p, left, right, bottom, toY)
+	default:
+		for s, line := range p.series {
+			colour := palette[s%len(palette)]
+			points := make([]point, len(line))
+			for i, pt := range line {
+				points[i] = point{x: toX(pt.x), y: toY(pt.y)}
+			}
+			radius := 4.0
+			if kind == domain.ChartLine {
+				c.polyline(points, 2, colour)
+				radius = 3
+			}
+			for _, pt := range points {
+				c.circle(pt.x, pt.y, radius, colour)
+			}
+		}
+	}
+
+	c.line(left, top, left, bottom, 1, axisColour)
+	c.line(left, bottom, right, bottom, 1, axisColour)
+
+	if len(p.names) > 0 {
+		drawLegend(c, p.names, left, right)
+	}
+}
+
+// drawBars draws one group of bars per position with one bar per series, labelled with the position's category,
+// or the x value of the first series
+func drawBars(c canvas, p plot, left, right, bottom float64, toY func(float64) float64) {
+	positions := 0
+	for _, bars := range p.series {
+		positions = max(positions, len(bars))
+	}
+	slot := (right - left) / float64(positions)
+	barWidth := slot * 0.8 / float64(len(p.series))
+	labelEvery := categoryLabelStep(positions, right-left)
+	zero := toY(0)
+
+	for i := 0; i < positions; i++ {
+		groupLeft := left + float64(i)*slot + slot*0.1
+		for s, bars := range p.series {
+			if i >= len(bars) {
+				continue
+			}
+			y := toY(bars[i].y)
+			c.rect(groupLeft+float64(s)*barWidth, math.Min(y, zero), barWidth, math.Abs(zero-y), palette[s%len(palette)])
+		}
+		if i%labelEvery == 0 && i < len(p.series[0]) {
+			c.text(left+(float64(i)+0.5)*slot, bottom+14, p.label(p.series[0][i].x), anchorMiddle, false, axisColour)
+		}
+	}
+}
+
+// drawPie draws the first series as the slices of a circle, starting at the top and going clockwise, with a legend
+// of the slices beside it. Values that are not positive get no slice.
+func drawPie(c canvas, p plot, width, height int) {
+	slices := p.series[0]
+	total := 0.0
+	for _, slice := range slices {
+		total += math.Max(slice.y, 0)
+	}
+	if total == 0 {
+		c.text(float64(width)/2, float64(height)/2, "No data", anchorMiddle, false, mutedColour)
+		return
+	}
+
+	legendLeft := float64(width) - math.Min(float64(width)/3, pieLegendWidth)
+	cx, cy := legendLeft/2, float64(height)/2
+	radius := math.Max(math.Min(cx, cy)-marginTop, 1)
+	legendTop := math.Max(cy-float64(len(slices)-1)*legendLineHeight/2, legendLineHeight)
+
+	angle := 0.0
+	for i, slice := range slices {
+		colour := palette[i%len(palette)]
+		if slice.y > 0 {
+			sweep := slice.y / total * 2 * math.Pi
+			c.wedge(cx, cy, radius, angle, angle+sweep, colour)
+			angle += sweep
+		}
+
+		y := legendTop + float64(i)*legendLineHeight
+		if y > float64(height)-legendLineHeight {
+			continue
+		}
+		share := math.Max(slice.y, 0) / total * 100
+		c.rect(legendLeft, y-legendSwatch/2, legendSwatch, legendSwatch, colour)
+		c.text(legendLeft+legendSwatch+6, y, p.label(slice.x)+" "+strconv.FormatFloat(share, 'f', 1, 64)+"%", anchorStart, false, axisColour)
+	}
+}
+
+// drawLegend lists the series names along the top of the chart, as many as fit
+func drawLegend(c canvas, names []string, left, right float64) {
+	x := left
+	for s, name := range names {
+		entryWidth := legendSwatch + 6 + c.textWidth(name)
+		if x+entryWidth > right {
+			return
+		}
+		c.rect(x, marginTop/2-legendSwatch/2, legendSwatch, legendSwatch, palette[s%len(palette)])
+		c.text(x+legendSwatch+6, marginTop/2, name, anchorStart, false, axisColour)
+		x += entryWidth + 16
+	}
+}
+
+// categoryLabelStep returns every how many of the positions along an axis of the given length a label fits
+func categoryLabelStep(positions int, length float64) int {
+	return max(1, int(math.Ceil(float64(positions)*categoryLabelWidth/length)))
+}
+
+// axisTitle returns the title of the x (0) or y (1) axis of the chart, followed by its unit
+func axisTitle(chart *domain.Chart, axis int) string {
+	title, unit := "", ""
+	if axis < len(chart.AxesTitles) {
+		title = chart.AxesTitles[axis]
+	}
+	if axis < len(chart.AxesUnits) {
+		unit = chart.AxesUnits[axis]
+	}
+	switch {
+	case unit == "":
+		return title
+	case title == "":
+		return unit
+	default:
+		return title + " (" + unit + ")"
+	}
+}
+
+// number formats an axis tick value with up to four significant digits
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
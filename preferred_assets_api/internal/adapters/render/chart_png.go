@@ -0,0 +1,177 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"unicode/utf8"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+const (
+	// supersampling draws a PNG at this many times its size and scales it down, to smooth the edges of its shapes
+	supersampling = 2
+	// glyphScale is the size of a font pixel on the supersampled image, so text is drawn about 10 pixels high
+	glyphScale = 3
+)
+
+// ChartPNG draws the chart as a PNG image of the given kind, or of its own kind when kind is empty.
+// Non-positive sizes fall back to the default chart render size.
+func ChartPNG(chart *domain.Chart, kind domain.ChartKind, width, height int) ([]byte, error) {
+	width, height = chartSize(width, height)
+
+	img := image.NewRGBA(image.Rect(0, 0, width*supersampling, height*supersampling))
+	drawChart(rasterCanvas{img: img}, chart, kind, width, height)
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, downsample(img, width, height)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// rasterCanvas paints the shapes drawn on it onto a supersampled image. Every shape is opaque, so pixels are
+// simply overwritten; the edges are smoothed when the image is scaled down.
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+func (c rasterCanvas) rect(x, y, width, height float64, fill color.RGBA) {
+	c.fill(image.Rect(scaled(x), scaled(y), scaled(x+width), scaled(y+height)), fill)
+}
+
+func (c rasterCanvas) line(x1, y1, x2, y2, width float64, stroke color.RGBA) {
+	c.segment(x1*supersampling, y1*supersampling, x2*supersampling, y2*supersampling, width*supersampling/2, stroke)
+}
+
+func (c rasterCanvas) polyline(points []point, width float64, stroke color.RGBA) {
+	for i := 1; i < len(points); i++ {
+		c.line(points[i-1].x, points[i-1].y, points[i].x, points[i].y, width, stroke)
+	}
+}
+
+func (c rasterCanvas) circle(cx, cy, r float64, fill color.RGBA) {
+	// A segment of no length is a disc
+	x, y := cx*supersampling, cy*supersampling
+	c.segment(x, y, x, y, r*supersampling, fill)
+}
+
+func (c rasterCanvas) wedge(cx, cy, r, start, end float64, fill color.RGBA) {
+	x, y, radius := cx*supersampling, cy*supersampling, r*supersampling
+	area := image.Rect(
+		int(math.Floor(x-radius)), int(math.Floor(y-radius)), int(math.Ceil(x+radius))+1, int(math.Ceil(y+radius))+1,
+	).Intersect(c.img.Bounds())
+
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			dx, dy := float64(px)+0.5-x, float64(py)+0.5-y
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+			// Clockwise from the top, as y grows downwards
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			if angle >= start && angle < end {
+				c.img.SetRGBA(px, py, fill)
+			}
+		}
+	}
+}
+
+func (c rasterCanvas) text(x, y float64, s string, anchor textAnchor, vertical bool, fill color.RGBA) {
+	// Offsets along the text and across it, from its anchor on the supersampled image
+	length := float64((utf8.RuneCountInString(s)*glyphAdvance - 1) * glyphScale)
+	along := 0.0
+	switch anchor {
+	case anchorMiddle:
+		along = -length / 2
+	case anchorEnd:
+		along = -length
+	}
+	across := -float64(glyphHeight*glyphScale) / 2
+
+	originX, originY := x*supersampling, y*supersampling
+	for i, r := range []rune(s) {
+		g := glyphFor(r)
+		for row, bits := range g {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				a := along + float64((i*glyphAdvance+col)*glyphScale)
+				b := across + float64(row*glyphScale)
+				// Vertical text is turned a quarter anticlockwise: along the text is up, across it is right
+				px, py := originX+a, originY+b
+				if vertical {
+					px, py = originX+b, originY-a-glyphScale
+				}
+				left, top := int(math.Round(px)), int(math.Round(py))
+				c.fill(image.Rect(left, top, left+glyphScale, top+glyphScale), fill)
+			}
+		}
+	}
+}
+
+func (c rasterCanvas) textWidth(s string) float64 {
+	return float64((utf8.RuneCountInString(s)*glyphAdvance-1)*glyphScale) / supersampling
+}
+
+func (c rasterCanvas) fill(r image.Rectangle, colour color.RGBA) {
+	draw.Draw(c.img, r, &image.Uniform{C: colour}, image.Point{}, draw.Src)
+}
+
+// segment paints every pixel whose centre lies within radius of the segment from (x1, y1) to (x2, y2),
+// in supersampled coordinates. The ends are rounded.
+func (c rasterCanvas) segment(x1, y1, x2, y2, radius float64, colour color.RGBA) {
+	area := image.Rect(
+		int(math.Floor(math.Min(x1, x2)-radius)), int(math.Floor(math.Min(y1, y2)-radius)),
+		int(math.Ceil(math.Max(x1, x2)+radius))+1, int(math.Ceil(math.Max(y1, y2)+radius))+1,
+	).Intersect(c.img.Bounds())
+
+	dx, dy := x2-x1, y2-y1
+	length2 := dx*dx + dy*dy
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			// Nearest point of the segment to the pixel centre
+			t := 0.0
+			if length2 > 0 {
+				t = math.Max(0, math.Min(1, ((cx-x1)*dx+(cy-y1)*dy)/length2))
+			}
+			ex, ey := cx-(x1+t*dx), cy-(y1+t*dy)
+			if ex*ex+ey*ey <= radius*radius {
+				c.img.SetRGBA(px, py, colour)
+			}
+		}
+	}
+}
+
+// scaled converts an image coordinate to the nearest supersampled pixel
+func scaled(v float64) int {
+	return int(math.Round(v * supersampling))
+}
+
+// downsample scales a supersampled image down to its final size, averaging each block of pixels
+func downsample(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	const samples = supersampling * supersampling
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b, a int
+			for sy := 0; sy < supersampling; sy++ {
+				for sx := 0; sx < supersampling; sx++ {
+					c := src.RGBAAt(x*supersampling+sx, y*supersampling+sy)
+					r, g, b, a = r+int(c.R), g+int(c.G), b+int(c.B), a+int(c.A)
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / samples), G: uint8(g / samples), B: uint8(b / samples), A: uint8(a / samples)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartPNG(t *testing.T) {
+	chart := &domain.Chart{
+		AxesTitles: []string{"Month", "Units"},
+		Data:       [][]float64{{1, 10, 2}, {2, 12, -3}, {3, 9, 1}},
+	}
+
+	for _, kind := range []domain.ChartKind{domain.ChartLine, domain.ChartBar, domain.ChartScatter} {
+		t.Run(string(kind), func(t *testing.T) {
+			// Act
+			encoded, err := ChartPNG(chart, kind, 300, 200)
+
+			// Assert
+			require.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(encoded))
+			require.NoError(t, err)
+			assert.Equal(t, 300, img.Bounds().Dx())
+			assert.Equal(t, 200, img.Bounds().Dy())
+
+			// The first series is drawn in the first colour of the palette
+			found := false
+			for y := 0; y < 200 && !found; y++ {
+				for x := 0; x < 300 && !found; x++ {
+					r, g, b, _ := img.At(x, y).RGBA()
+					found = uint8(r>>8) == palette[0].R && uint8(g>>8) == palette[0].G && uint8(b>>8) == palette[0].B
+				}
+			}
+			assert.True(t, found, "no pixel of the first series")
+		})
+	}
+}
+
+func TestChartPNG_DefaultSize(t *testing.T) {
+	// Act
+	encoded, err := ChartPNG(&domain.Chart{}, domain.ChartLine, 0, 0)
+
+	// Assert
+	require.NoError(t, err)
+	config, err := png.DecodeConfig(bytes.NewReader(encoded))
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultChartRenderWidth, config.Width)
+	assert.Equal(t, domain.DefaultChartRenderHeight, config.Height)
+}
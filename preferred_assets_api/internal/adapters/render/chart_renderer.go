@@ -0,0 +1,63 @@
+package render
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/cache"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+var _ ports.ChartRenderer = (*ChartRenderer)(nil)
+
+// chartImageKey identifies a rendered image. Updating a chart changes its UpdatedAt, and deleting it and creating
+// it anew under the same id changes its CreatedAt, so either renders it again.
+type chartImageKey struct {
+	id        string
+	createdAt int64
+	updatedAt int64
+	options   domain.ChartRenderOptions
+}
+
+// ChartRenderer renders charts as SVG or PNG images, keeping the most recently rendered images in an LRU cache.
+// Without a cache every request renders its image anew.
+type ChartRenderer struct {
+	images *lru.Cache[chartImageKey, []byte]
+}
+
+// NewChartRenderer creates a renderer caching up to cacheSize images, or none when cacheSize is 0 or less
+func NewChartRenderer(cacheSize int) *ChartRenderer {
+	if cacheSize <= 0 {
+		return &ChartRenderer{}
+	}
+	return &ChartRenderer{images: cache.InitLRUCache[chartImageKey, []byte](cacheSize)}
+}
+
+// Render implements ports.ChartRenderer.
+func (r *ChartRenderer) Render(chart *domain.Chart, options domain.ChartRenderOptions) ([]byte, error) {
+	key := chartImageKey{
+		id:        chart.ID,
+		createdAt: chart.CreatedAt.UnixNano(),
+		updatedAt: chart.UpdatedAt.UnixNano(),
+		options:   options,
+	}
+	if r.images != nil {
+		if image, ok := r.images.Get(key); ok {
+			return image, nil
+		}
+	}
+
+	var image []byte
+	switch options.Format {
+	case domain.ChartPNG:
+		var err error
+		if image, err = ChartPNG(chart, options.Kind, options.Width, options.Height); err != nil {
+			return nil, err
+		}
+	default:
+		image = ChartSVG(chart, options.Kind, options.Width, options.Height)
+	}
+	if r.images != nil {
+		r.images.Add(key, image)
+	}
+	return image, nil
+}
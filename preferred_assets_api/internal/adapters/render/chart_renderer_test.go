@@ -0,0 +1,59 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartRenderer_Render(t *testing.T) {
+	// Arrange
+	renderer := NewChartRenderer(8)
+	chart := &domain.Chart{
+		AssetBase: domain.AssetBase{ID: "c1", Title: "Sales", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Data:      [][]float64{{1, 10}, {2, 12}},
+	}
+	svg := domain.ChartRenderOptions{Format: domain.ChartSVG, Kind: domain.ChartLine, Width: 300, Height: 200}
+
+	// Act
+	first, err := renderer.Render(chart, svg)
+	require.NoError(t, err)
+	chart.Title = "Changed without an update"
+	cached, err := renderer.Render(chart, svg)
+	require.NoError(t, err)
+	chart.UpdatedAt = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := renderer.Render(chart, svg)
+	require.NoError(t, err)
+	png, err := renderer.Render(chart, domain.ChartRenderOptions{Format: domain.ChartPNG, Kind: domain.ChartLine, Width: 300, Height: 200})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, first, cached, "an unchanged chart is served from the cache")
+	assert.Contains(t, string(updated), "Changed without an update", "an updated chart is rendered again")
+	assert.Equal(t, []byte("\x89PNG"), png[:4])
+}
+
+func TestChartRenderer_WithoutCache(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		// Arrange
+		renderer := NewChartRenderer(size)
+		chart := &domain.Chart{
+			AssetBase: domain.AssetBase{ID: "c1", Title: "Sales", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Data:      [][]float64{{1, 10}, {2, 12}},
+		}
+		svg := domain.ChartRenderOptions{Format: domain.ChartSVG, Kind: domain.ChartLine, Width: 300, Height: 200}
+
+		// Act
+		_, err := renderer.Render(chart, svg)
+		require.NoError(t, err)
+		chart.Title = "Changed without an update"
+		second, err := renderer.Render(chart, svg)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Contains(t, string(second), "Changed without an update", "size %d renders every request anew", size)
+	}
+}
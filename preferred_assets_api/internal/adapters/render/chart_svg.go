@@ -0,0 +1,94 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// ChartSVG draws the chart as an SVG image of the given kind, or of its own kind when kind is empty, escaping every
+// text it draws. Non-positive sizes fall back to the default chart render size.
+func ChartSVG(chart *domain.Chart, kind domain.ChartKind, width, height int) []byte {
+	width, height = chartSize(width, height)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" font-family="sans-serif" font-size="12">`,
+		width, height, width, height)
+	fmt.Fprintf(&b, `<title>%s</title>`, html.EscapeString(chart.Title))
+	drawChart(svgCanvas{b: &b}, chart, kind, width, height)
+	b.WriteString(`</svg>`)
+	return b.Bytes()
+}
+
+// svgCanvas writes the shapes drawn on it as SVG elements
+type svgCanvas struct {
+	b *bytes.Buffer
+}
+
+func (c svgCanvas) rect(x, y, width, height float64, fill color.RGBA) {
+	fmt.Fprintf(c.b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`, coord(x), coord(y), coord(width), coord(height), hex(fill))
+}
+
+func (c svgCanvas) line(x1, y1, x2, y2, width float64, stroke color.RGBA) {
+	fmt.Fprintf(c.b, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke-width="%s" stroke="%s"/>`,
+		coord(x1), coord(y1), coord(x2), coord(y2), coord(width), hex(stroke))
+}
+
+func (c svgCanvas) polyline(points []point, width float64, stroke color.RGBA) {
+	fmt.Fprintf(c.b, `<polyline fill="none" stroke-width="%s" stroke="%s" points="`, coord(width), hex(stroke))
+	for i, p := range points {
+		if i > 0 {
+			c.b.WriteByte(' ')
+		}
+		c.b.WriteString(coord(p.x) + "," + coord(p.y))
+	}
+	c.b.WriteString(`"/>`)
+}
+
+func (c svgCanvas) circle(cx, cy, r float64, fill color.RGBA) {
+	fmt.Fprintf(c.b, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`, coord(cx), coord(cy), coord(r), hex(fill))
+}
+
+func (c svgCanvas) wedge(cx, cy, r, start, end float64, fill color.RGBA) {
+	if end-start >= 2*math.Pi-1e-9 {
+		c.circle(cx, cy, r, fill)
+		return
+	}
+	largeArc := 0
+	if end-start > math.Pi {
+		largeArc = 1
+	}
+	fmt.Fprintf(c.b, `<path d="M%s %sL%s %sA%s %s 0 %d 1 %s %sZ" fill="%s"/>`,
+		coord(cx), coord(cy), coord(cx+r*math.Sin(start)), coord(cy-r*math.Cos(start)),
+		coord(r), coord(r), largeArc, coord(cx+r*math.Sin(end)), coord(cy-r*math.Cos(end)), hex(fill))
+}
+
+func (c svgCanvas) text(x, y float64, s string, anchor textAnchor, vertical bool, fill color.RGBA) {
+	position := fmt.Sprintf(`x="%s" y="%s"`, coord(x), coord(y))
+	if vertical {
+		position = fmt.Sprintf(`transform="translate(%s %s) rotate(-90)"`, coord(x), coord(y))
+	}
+	fmt.Fprintf(c.b, `<text %s text-anchor="%s" dominant-baseline="middle" fill="%s">%s</text>`,
+		position, anchor, hex(fill), html.EscapeString(s))
+}
+
+// textWidth assumes an average character of the 12 pixel sans-serif font is 7 pixels wide
+func (c svgCanvas) textWidth(s string) float64 {
+	return float64(utf8.RuneCountInString(s)) * 7
+}
+
+// coord formats an SVG coordinate to a tenth of a pixel
+func coord(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// hex formats an opaque colour for SVG
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,158 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requireWellFormed fails unless svg parses as XML
+func requireWellFormed(t *testing.T, svg []byte) {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestChartSeries(t *testing.T) {
+	tests := []struct {
+		name string
+		data [][]float64
+		want [][]point
+	}{
+		{name: "no data", data: nil, want: nil},
+		{name: "single column against the row index", data: [][]float64{{5}, {7}}, want: [][]point{{{0, 5}, {1, 7}}}},
+		{name: "x and one series", data: [][]float64{{18, 2.3}, {25, 3.5}}, want: [][]point{{{18, 2.3}, {25, 3.5}}}},
+		{name: "x and two series", data: [][]float64{{1, 2, 3}, {2, 4, 6}}, want: [][]point{{{1, 2}, {2, 4}}, {{1, 3}, {2, 6}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := chartSeries(tt.data)
+
+			// Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChartSVG(t *testing.T) {
+	tests := []struct {
+		name          string
+		chart         *domain.Chart
+		kind          domain.ChartKind
+		width, height int
+		wantLines     int
+		wantCircles   int
+		wantContains  []string
+	}{
+		{
+			name: "one line per series with its axes titles",
+			chart: &domain.Chart{
+				AssetBase:  domain.AssetBase{Title: "Sales & <returns>"},
+				AxesTitles: []string{"Month", "Units"},
+				Data:       [][]float64{{1, 10, 2}, {2, 12, 3}, {3, 9, 1}},
+			},
+			kind:  domain.ChartLine,
+			width: 300, height: 200,
+			wantLines:    2,
+			wantCircles:  6,
+			wantContains: []string{`width="300" height="200"`, "<title>Sales &amp; &lt;returns&gt;</title>", ">Month</text>", ">Units</text>"},
+		},
+		{
+			name:         "default size for a flat series",
+			chart:        &domain.Chart{Data: [][]float64{{4}, {4}}},
+			kind:         domain.ChartLine,
+			wantLines:    1,
+			wantCircles:  2,
+			wantContains: []string{`width="640" height="400"`},
+		},
+		{
+			name:        "scatter points are not joined",
+			chart:       &domain.Chart{Data: [][]float64{{1, 2}, {2, 4}, {3, 3}}},
+			kind:        domain.ChartScatter,
+			wantCircles: 3,
+		},
+		{
+			name:         "bars grow from zero",
+			chart:        &domain.Chart{Data: [][]float64{{1, 2}, {2, 4}}},
+			kind:         domain.ChartBar,
+			wantContains: []string{">0</text>", `fill="#4e79a7"`},
+		},
+		{
+			name: "bar groups labelled by category with a legend and units",
+			chart: &domain.Chart{
+				Kind:       domain.ChartBar,
+				AxesTitles: []string{"Month"},
+				AxesUnits:  []string{"", "%"},
+				Categories: []string{"Jan", "Feb"},
+				Series:     []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2}}, {Name: "US", Values: []float64{3, 4}}},
+			},
+			wantContains: []string{">Jan</text>", ">Feb</text>", ">UK</text>", ">US</text>", ">Month</text>", ">%</text>", `fill="#f28e2b"`},
+		},
+		{
+			name: "line through categories with units on its axes titles",
+			chart: &domain.Chart{
+				AxesTitles: []string{"Month", "Share"},
+				AxesUnits:  []string{"", "%"},
+				Categories: []string{"Jan", "Feb", "Mar"},
+				Series:     []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2, 3}}},
+			},
+			wantLines:    1,
+			wantCircles:  3,
+			wantContains: []string{">Mar</text>", ">Share (%)</text>"},
+		},
+		{
+			name: "pie slices with their shares",
+			chart: &domain.Chart{
+				Kind:       domain.ChartPie,
+				Categories: []string{"Yes", "No", "Unsure"},
+				Series:     []domain.ChartSeries{{Name: "Answers", Values: []float64{3, 1, 0}}},
+			},
+			wantContains: []string{"<path", ">Yes 75.0%</text>", ">No 25.0%</text>", ">Unsure 0.0%</text>"},
+		},
+		{
+			name: "a single pie slice is a circle",
+			chart: &domain.Chart{
+				Kind:       domain.ChartPie,
+				Categories: []string{"All"},
+				Series:     []domain.ChartSeries{{Name: "Answers", Values: []float64{5}}},
+			},
+			wantCircles:  1,
+			wantContains: []string{">All 100.0%</text>"},
+		},
+		{
+			name:         "no data",
+			chart:        &domain.Chart{},
+			kind:         domain.ChartLine,
+			wantContains: []string{"No data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			svg := ChartSVG(tt.chart, tt.kind, tt.width, tt.height)
+
+			// Assert
+			requireWellFormed(t, svg)
+			assert.Equal(t, tt.wantLines, strings.Count(string(svg), "<polyline"))
+			assert.Equal(t, tt.wantCircles, strings.Count(string(svg), "<circle"))
+			for _, want := range tt.wantContains {
+				assert.Contains(t, string(svg), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package render
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+// reportChartWidth and reportChartHeight size the charts of a favourites report to fit an A4 page
+const (
+	reportChartWidth  = 560
+	reportChartHeight = 320
+)
+
+var reportTemplates = template.Must(template.New("report").Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Favourites of {{.UserID}}</title>
+<style>
+body { font-family: sans-serif; color: #222; max-width: 800px; margin: 2em auto; padding: 0 1em; }
+header { border-bottom: 2px solid #4e79a7; margin-bottom: 1.5em; }
+article { border: 1px solid #ddd; border-radius: 6px; padding: 1em 1.25em; margin-bottom: 1.25em; page-break-inside: avoid; }
+h2 { margin: 0 0 0.25em; font-size: 1.2em; }
+.meta { color: #666; font-size: 0.85em; }
+.type { text-transform: uppercase; letter-spacing: 0.05em; font-weight: bold; color: #4e79a7; }
+.tag { display: inline-block; background: #eef2f7; border-radius: 3px; padding: 0 0.4em; margin-right: 0.3em; }
+.note { background: #fff8e1; border-left: 3px solid #edc948; padding: 0.5em 0.75em; }
+blockquote { margin: 0.75em 0; padding-left: 0.75em; border-left: 3px solid #76b7b2; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.25em 1em; }
+dt { color: #666; }
+dd { margin: 0; }
+svg { max-width: 100%; height: auto; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<header>
+<h1>Favourites</h1>
+<p class="meta">User {{.UserID}} &middot; generated {{.GeneratedAt.Format "2 Jan 2006 15:04 MST"}}</p>
+</header>
+{{end -}}
+
+{{- define "favourite" -}}
+<article>
+<p class="meta"><span class="type">{{.Type}}</span> &middot; favourited {{.FavouritedAt.Format "2 Jan 2006"}}</p>
+<h2>{{.Title}}</h2>
+{{- if and .AssetTitle (ne .AssetTitle .Title)}}
+<p class="meta">{{.AssetTitle}}</p>
+{{- end}}
+{{- if .Tags}}
+<p>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</p>
+{{- end}}
+{{- if .Note}}
+<p class="note">{{.Note}}</p>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Chart}}
+{{.Chart}}
+{{- end}}
+{{- if .Text}}
+<blockquote>{{.Text}}</blockquote>
+{{- end}}
+{{- if .Attributes}}
+<dl>
+{{- range .Attributes}}
+<dt>{{.Name}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+</article>
+{{end -}}
+
+{{- define "empty" -}}
+<p>No favourites yet.</p>
+{{end -}}
+
+{{- define "footer" -}}
+</body>
+</html>
+{{end -}}
+`))
+
+var _ ports.FavouritesReportWriter = FavouritesReportWriter{}
+
+// FavouritesReportWriter starts FavouritesReport reports
+type FavouritesReportWriter struct{}
+
+// NewReport implements ports.FavouritesReportWriter.
+func (FavouritesReportWriter) NewReport(w io.Writer, userID string, generatedAt time.Time) (ports.FavouritesReport, error) {
+	return NewFavouritesReport(w, userID, generatedAt)
+}
+
+// FavouritesReport writes a self-contained, printable HTML report of a user's favourites one favourite at a time,
+// so that long lists are streamed. Charts are drawn inline as SVG.
+type FavouritesReport struct {
+	w     io.Writer
+	count int
+}
+
+// NewFavouritesReport starts the report of the user's favourites
+func NewFavouritesReport(w io.Writer, userID string, generatedAt time.Time) (*FavouritesReport, error) {
+	data := struct {
+		UserID      string
+		GeneratedAt time.Time
+	}{userID, generatedAt}
+	if err := reportTemplates.ExecuteTemplate(w, "header", data); err != nil {
+		return nil, err
+	}
+	return &FavouritesReport{w: w}, nil
+}
+
+type reportAttribute struct {
+	Name  string
+	Value string
+}
+
+// reportFavourite is what the report shows of a favourite
+type reportFavourite struct {
+	Type         string
+	Title        string
+	AssetTitle   string
+	FavouritedAt time.Time
+	Tags         []string
+	Note         string
+	Description  string
+	Chart        template.HTML
+	Text         string
+	Attributes   []reportAttribute
+}
+
+// Add writes one favourite to the report
+func (r *FavouritesReport) Add(f domain.Favourite) error {
+	item := reportFavourite{
+		Type:         f.AssetType.String(),
+		Title:        f.CustomTitle,
+		FavouritedAt: f.CreatedAt,
+		Tags:         f.Tags,
+		Note:         f.Note,
+	}
+
+	var asset domain.Asset
+	switch {
+	case f.Chart != nil:
+		asset = f.Chart
+		// ChartSVG escapes every text it draws
+		item.Chart = template.HTML(ChartSVG(f.Chart, f.Chart.DrawnAs(), reportChartWidth, reportChartHeight))
+	case f.Insight != nil:
+		asset = f.Insight
+		item.Text = f.Insight.Text
+	case f.Audience != nil:
+		asset = f.Audience
+		item.Attributes = audienceAttributes(f.Audience)
+	}
+	if asset != nil {
+		item.Type = asset.GetType().String()
+		item.AssetTitle = asset.GetTitle()
+		item.Description = asset.GetDescription()
+	}
+	if item.Title == "" {
+		item.Title = item.AssetTitle
+	}
+	if item.Title == "" {
+		item.Title = f.AssetID
+	}
+
+	r.count++
+	return reportTemplates.ExecuteTemplate(r.w, "favourite", item)
+}
+
+// Close ends the report
+func (r *FavouritesReport) Close() error {
+	if r.count == 0 {
+		if err := reportTemplates.ExecuteTemplate(r.w, "empty", nil); err != nil {
+			return err
+		}
+	}
+	return reportTemplates.ExecuteTemplate(r.w, "footer", nil)
+}
+
+// audienceAttributes lists the criteria of an audience
+func audienceAttributes(a *domain.Audience) []reportAttribute {
+	if a.Criteria == nil {
+		return nil
+	}
+	return []reportAttribute{{Name: "Criteria", Value: a.Summary()}}
+}
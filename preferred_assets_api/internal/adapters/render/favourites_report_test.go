@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavouritesReport(t *testing.T) {
+	t.Run("shows each favourite with its asset", func(t *testing.T) {
+		// Arrange
+		var out bytes.Buffer
+		report, err := NewFavouritesReport(&out, "u1", time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		purchases := 3.0
+
+		// Act
+		require.NoError(t, report.Add(domain.Favourite{
+			AssetID: "c1", CustomTitle: "My chart", Tags: []string{"q3"}, Note: "<b>check</b>",
+			Chart: &domain.Chart{AssetBase: domain.AssetBase{Type: domain.AssetTypeChart, Title: "Sales"}, Data: [][]float64{{1, 2}, {2, 3}}},
+		}))
+		require.NoError(t, report.Add(domain.Favourite{
+			AssetID: "a1",
+			Audience: &domain.Audience{AssetBase: domain.AssetBase{Type: domain.AssetTypeAudience, Title: "Gen Z"},
+				Criteria: &domain.AudienceCriterion{Operator: domain.AudienceGte, Field: domain.AudienceFieldPurchases, Value: &purchases}},
+		}))
+		require.NoError(t, report.Close())
+
+		// Assert
+		html := out.String()
+		assert.Contains(t, html, "User u1 &middot; generated 2 Nov 2025 09:00 UTC")
+		assert.Contains(t, html, "<h2>My chart</h2>")
+		assert.Contains(t, html, `<p class="meta">Sales</p>`)
+		assert.Contains(t, html, `<span class="tag">q3</span>`)
+		assert.Contains(t, html, "&lt;b&gt;check&lt;/b&gt;")
+		assert.Contains(t, html, "<svg")
+		assert.Contains(t, html, "<h2>Gen Z</h2>")
+		assert.Contains(t, html, "<dt>Criteria</dt><dd>With at least 3 purchases last month</dd>")
+		assert.NotContains(t, html, "No favourites yet")
+	})
+
+	t.Run("says when there are no favourites", func(t *testing.T) {
+		// Arrange
+		var out bytes.Buffer
+		report, err := NewFavouritesReport(&out, "u1", time.Now())
+		require.NoError(t, err)
+
+		// Act
+		require.NoError(t, report.Close())
+
+		// Assert
+		assert.Contains(t, out.String(), "No favourites yet")
+		assert.True(t, strings.HasSuffix(strings.TrimSpace(out.String()), "</html>"))
+	})
+}
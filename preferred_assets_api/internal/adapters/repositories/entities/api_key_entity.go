@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// APIKeyEntity is a machine client's key. Only the SHA-256 hash of the key is stored.
+type APIKeyEntity struct {
+	Id        string    `db:"id"`
+	Name      string    `db:"name"`
+	Prefix    string    `db:"prefix"`
+	Hash      string    `db:"hash"`
+	Roles     string    `db:"roles"`    // JSON array
+	UserIds   string    `db:"user_ids"` // JSON array, empty for a key that may act on any user
+	CreatedAt time.Time `db:"created_at"`
+	RevokedAt time.Time `db:"revoked_at"` // zero while the key is active
+}
+
+// SortAPIKeys orders keys oldest first, ties broken by id
+func SortAPIKeys(keys []APIKeyEntity) {
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].Id < keys[j].Id
+	})
+}
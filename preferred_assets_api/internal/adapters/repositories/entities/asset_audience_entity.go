@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// AudienceEntity is the stored form of an audience. Gender, birth country, age group and purchases are only set on
+// audiences stored before criteria existed, which are turned into criteria when read; HoursSocial is the average
+// the audience records.
 type AudienceEntity struct {
 	AssetBaseEntity
 	Gender          string  `db:"gender"`
@@ -12,6 +15,18 @@ type AudienceEntity struct {
 	AgeGroup        string  `db:"age_group"`
 	HoursSocial     float64 `db:"hours_social"`
 	PurchasesLastMo int     `db:"purchases_last_mo"`
+	Criteria        string  `db:"criteria"` // JSON serialized AudienceCriterionEntity, empty for none
+}
+
+// AudienceCriterionEntity is the stored form of a node of the criteria of an audience
+type AudienceCriterionEntity struct {
+	Operator string                    `json:"op"`
+	Criteria []AudienceCriterionEntity `json:"criteria,omitempty"`
+	Field    string                    `json:"field,omitempty"`
+	Values   []string                  `json:"values,omitempty"`
+	Value    *float64                  `json:"value,omitempty"`
+	Min      *float64                  `json:"min,omitempty"`
+	Max      *float64                  `json:"max,omitempty"`
 }
 
 // Validate Data Consistency Validation
@@ -6,10 +6,21 @@ import (
 
 type ChartEntity struct {
 	AssetBaseEntity
+	Kind       string `db:"kind"`
 	AxesTitles string `db:"axes_titles"` // JSON serialized
+	AxesUnits  string `db:"axes_units"`  // JSON serialized
+	Categories string `db:"categories"`  // JSON serialized
+	Series     string `db:"series"`      // JSON serialized ChartSeriesEntity list
 	Data       string `db:"data"`        // JSON serialized
 }
 
+// ChartSeriesEntity is the stored form of one named series of a chart
+type ChartSeriesEntity struct {
+	Name   string    `json:"name"`
+	X      []float64 `json:"x,omitempty"`
+	Values []float64 `json:"values"`
+}
+
 // Validate Data Consistency Validation
 func (c *ChartEntity) Validate() error {
 	if err := c.AssetBaseEntity.Validate(); err != nil {
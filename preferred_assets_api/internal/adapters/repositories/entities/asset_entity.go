@@ -12,6 +12,7 @@ type AssetBaseEntity struct {
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
+	Version     int64     `db:"version"`
 }
 
 type AssetEntity interface {
@@ -21,6 +22,8 @@ type AssetEntity interface {
 	GetDescription() string
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
+	GetVersion() int64
+	SetVersion(version int64)
 	Validate() error
 }
 
@@ -30,6 +33,10 @@ func (a AssetBaseEntity) GetTitle() string        { return a.Title }
 func (a AssetBaseEntity) GetDescription() string  { return a.Description }
 func (a AssetBaseEntity) GetCreatedAt() time.Time { return a.CreatedAt }
 func (a AssetBaseEntity) GetUpdatedAt() time.Time { return a.UpdatedAt }
+func (a AssetBaseEntity) GetVersion() int64       { return a.Version }
+
+// SetVersion is called by repositories, which own the version counter
+func (a *AssetBaseEntity) SetVersion(version int64) { a.Version = version }
 
 // Validate Data Consistency Validation
 func (a AssetBaseEntity) Validate() error {
@@ -44,3 +51,20 @@ func (a AssetBaseEntity) Validate() error {
 	}
 	return nil
 }
+
+// CloneAsset returns a copy of the asset that shares no memory with it, so repositories never hand out what they store
+func CloneAsset(asset AssetEntity) AssetEntity {
+	switch a := asset.(type) {
+	case *ChartEntity:
+		clone := *a
+		return &clone
+	case *InsightEntity:
+		clone := *a
+		return &clone
+	case *AudienceEntity:
+		clone := *a
+		return &clone
+	default:
+		return asset
+	}
+}
@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// AssetSortField is the field assets are ordered by; ties are always broken by asset id
+type AssetSortField string
+
+const (
+	AssetSortCreatedAt AssetSortField = "created_at"
+	AssetSortUpdatedAt AssetSortField = "updated_at"
+	AssetSortTitle     AssetSortField = "title"
+)
+
+// AssetQuery filters, orders and paginates assets. Zero-valued fields do not constrain the result.
+// An audience filter restricts the result to the audiences it accepts. Date ranges are inclusive.
+type AssetQuery struct {
+	Type          *AssetType
+	TitleContains string // case-insensitive
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+	UpdatedFrom   time.Time
+	UpdatedTo     time.Time
+
+	// MatchesAudience filters on the definitions of audiences, which only the domain can read
+	MatchesAudience func(*AudienceEntity) bool
+
+	SortBy     AssetSortField // defaults to AssetSortCreatedAt
+	Descending bool
+	After      *AssetCursor
+	Limit      int // <= 0 returns every match
+}
+
+// AssetCursor marks the last asset of a page; the next page starts right after it in the query ordering
+type AssetCursor struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Title     string
+	ID        string
+}
+
+// AssetPage is one page of a query result. Next is nil on the last page.
+type AssetPage struct {
+	Assets []AssetEntity
+	Next   *AssetCursor
+}
+
+// HasAudienceFilters reports whether the query filters on audience-only fields
+func (q AssetQuery) HasAudienceFilters() bool {
+	return q.MatchesAudience != nil
+}
+
+// Matches reports whether the asset satisfies every filter of the query, ignoring pagination
+func (q AssetQuery) Matches(asset AssetEntity) bool {
+	if q.Type != nil && asset.GetType() != *q.Type {
+		return false
+	}
+	if q.TitleContains != "" && !strings.Contains(strings.ToLower(asset.GetTitle()), strings.ToLower(q.TitleContains)) {
+		return false
+	}
+	if !inTimeRange(asset.GetCreatedAt(), q.CreatedFrom, q.CreatedTo) ||
+		!inTimeRange(asset.GetUpdatedAt(), q.UpdatedFrom, q.UpdatedTo) {
+		return false
+	}
+
+	if !q.HasAudienceFilters() {
+		return true
+	}
+	audience, ok := asset.(*AudienceEntity)
+	return ok && q.MatchesAudience(audience)
+}
+
+func inTimeRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && t.After(to) {
+		return false
+	}
+	return true
+}
+
+// Less reports whether asset a sorts before asset b in the query ordering
+func (q AssetQuery) Less(a, b AssetEntity) bool {
+	cmp := compareAssets(q.SortBy, a, b)
+	if cmp == 0 {
+		cmp = strings.Compare(a.GetID(), b.GetID())
+	}
+	if q.Descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func compareAssets(field AssetSortField, a, b AssetEntity) int {
+	switch field {
+	case AssetSortUpdatedAt:
+		return a.GetUpdatedAt().Compare(b.GetUpdatedAt())
+	case AssetSortTitle:
+		return strings.Compare(a.GetTitle(), b.GetTitle())
+	default:
+		return a.GetCreatedAt().Compare(b.GetCreatedAt())
+	}
+}
+
+// CursorFor returns the cursor positioned at the given asset
+func CursorFor(asset AssetEntity) *AssetCursor {
+	return &AssetCursor{
+		CreatedAt: asset.GetCreatedAt(),
+		UpdatedAt: asset.GetUpdatedAt(),
+		Title:     asset.GetTitle(),
+		ID:        asset.GetID(),
+	}
+}
+
+// RunAssetQuery filters, sorts and paginates assets in memory, for adapters without a query engine
+func RunAssetQuery(assets []AssetEntity, q AssetQuery) AssetPage {
+	matches := make([]AssetEntity, 0, len(assets))
+	for _, asset := range assets {
+		if q.Matches(asset) {
+			matches = append(matches, asset)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return q.Less(matches[i], matches[j])
+	})
+	return PaginateAssets(matches, q)
+}
+
+// PaginateAssets returns the page of up to q.Limit assets following q.After.
+// assets must already be filtered and sorted with q.Less.
+func PaginateAssets(assets []AssetEntity, q AssetQuery) AssetPage {
+	start := 0
+	if q.After != nil {
+		position := &AssetBaseEntity{
+			ID:        q.After.ID,
+			Title:     q.After.Title,
+			CreatedAt: q.After.CreatedAt,
+			UpdatedAt: q.After.UpdatedAt,
+		}
+		start = sort.Search(len(assets), func(i int) bool {
+			return q.Less(position, assets[i])
+		})
+	}
+
+	end := start + q.Limit
+	if q.Limit <= 0 || end > len(assets) {
+		end = len(assets)
+	}
+
+	page := AssetPage{Assets: append([]AssetEntity{}, assets[start:end]...)}
+	if end < len(assets) && end > start {
+		page.Next = CursorFor(assets[end-1])
+	}
+	return page
+}
@@ -0,0 +1,107 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+)
+
+func queryTestAssets(base time.Time) []entities.AssetEntity {
+	return []entities.AssetEntity{
+		&entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{
+			ID: "ins-1", Type: entities.AssetTypeInsight, Title: "Millennial habits", CreatedAt: base, UpdatedAt: base.Add(3 * time.Hour),
+		}},
+		&entities.ChartEntity{AssetBaseEntity: entities.AssetBaseEntity{
+			ID: "chart-1", Type: entities.AssetTypeChart, Title: "Sales chart", CreatedAt: base.Add(time.Hour), UpdatedAt: base.Add(time.Hour),
+		}},
+		&entities.AudienceEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{
+				ID: "aud-1", Type: entities.AssetTypeAudience, Title: "Greek millennials", CreatedAt: base.Add(2 * time.Hour), UpdatedAt: base.Add(2 * time.Hour),
+			},
+			Gender: "female", BirthCountry: "GR", AgeGroup: "25-34", HoursSocial: 2.5,
+		},
+		&entities.AudienceEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{
+				ID: "aud-2", Type: entities.AssetTypeAudience, Title: "UK gamers", CreatedAt: base.Add(2 * time.Hour), UpdatedAt: base.Add(2 * time.Hour),
+			},
+			Gender: "male", BirthCountry: "UK", AgeGroup: "18-24", HoursSocial: 5,
+		},
+	}
+}
+
+func TestRunAssetQuery(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	audience := entities.AssetTypeAudience
+	greek := func(a *entities.AudienceEntity) bool { return a.BirthCountry == "GR" }
+	everyAudience := func(*entities.AudienceEntity) bool { return true }
+
+	tests := []struct {
+		name     string
+		query    entities.AssetQuery
+		wantIDs  []string
+		wantNext bool
+	}{
+		{"no filters sorts by creation then id", entities.AssetQuery{}, []string{"ins-1", "chart-1", "aud-1", "aud-2"}, false},
+		{"type", entities.AssetQuery{Type: &audience}, []string{"aud-1", "aud-2"}, false},
+		{"title is case-insensitive", entities.AssetQuery{TitleContains: "MILLENNIAL"}, []string{"ins-1", "aud-1"}, false},
+		{"inclusive created range", entities.AssetQuery{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(2 * time.Hour)}, []string{"chart-1", "aud-1", "aud-2"}, false},
+		{"updated range", entities.AssetQuery{UpdatedFrom: base.Add(3 * time.Hour)}, []string{"ins-1"}, false},
+		{"audience filter", entities.AssetQuery{MatchesAudience: greek}, []string{"aud-1"}, false},
+		{"audience filters exclude other types", entities.AssetQuery{MatchesAudience: everyAudience}, []string{"aud-1", "aud-2"}, false},
+		{"title descending", entities.AssetQuery{SortBy: entities.AssetSortTitle, Descending: true}, []string{"aud-2", "chart-1", "ins-1", "aud-1"}, false},
+		{"updated descending", entities.AssetQuery{SortBy: entities.AssetSortUpdatedAt, Descending: true}, []string{"ins-1", "aud-2", "aud-1", "chart-1"}, false},
+		{"limit", entities.AssetQuery{Limit: 2}, []string{"ins-1", "chart-1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			page := entities.RunAssetQuery(queryTestAssets(base), tt.query)
+
+			// Assert
+			if len(page.Assets) != len(tt.wantIDs) {
+				t.Fatalf("expected %v, got %d assets", tt.wantIDs, len(page.Assets))
+			}
+			for i, id := range tt.wantIDs {
+				if page.Assets[i].GetID() != id {
+					t.Errorf("position %d: expected %s, got %s", i, id, page.Assets[i].GetID())
+				}
+			}
+			if (page.Next != nil) != tt.wantNext {
+				t.Errorf("expected next cursor %v, got %+v", tt.wantNext, page.Next)
+			}
+		})
+	}
+}
+
+func TestRunAssetQuery_PagesThroughEveryMatch(t *testing.T) {
+	// Arrange
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := entities.AssetQuery{SortBy: entities.AssetSortUpdatedAt, Descending: true, Limit: 1}
+	var got []string
+
+	// Act
+	for {
+		page := entities.RunAssetQuery(queryTestAssets(base), query)
+		for _, asset := range page.Assets {
+			got = append(got, asset.GetID())
+		}
+		if page.Next == nil {
+			break
+		}
+		query.After = page.Next
+	}
+
+	// Assert
+	want := []string{"ins-1", "aud-2", "aud-1", "chart-1"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// CollectionEntity is a named folder of a user's favourites
+type CollectionEntity struct {
+	Id        string    `db:"id"`
+	UserId    string    `db:"user_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Version   int64     `db:"version"`
+	AssetIds  []string  `db:"-"` // items in display order; the SQL repository keeps them in collection_items
+}
+
+// Clone returns a copy of the collection that shares no memory with it
+func (c CollectionEntity) Clone() CollectionEntity {
+	c.AssetIds = append([]string{}, c.AssetIds...)
+	return c
+}
+
+// RemoveAsset drops the asset from the collection items, reporting whether it was there
+func (c *CollectionEntity) RemoveAsset(assetID string) bool {
+	for i, id := range c.AssetIds {
+		if id == assetID {
+			c.AssetIds = append(c.AssetIds[:i:i], c.AssetIds[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SortCollections orders collections oldest first, ties broken by id
+func SortCollections(collections []CollectionEntity) {
+	sort.Slice(collections, func(i, j int) bool {
+		if !collections[i].CreatedAt.Equal(collections[j].CreatedAt) {
+			return collections[i].CreatedAt.Before(collections[j].CreatedAt)
+		}
+		return collections[i].Id < collections[j].Id
+	})
+}
@@ -1,11 +1,75 @@
 package entities
 
 import (
+	"sort"
 	"time"
 )
 
 type FavouriteEntity struct {
-	UserId    string
-	AssetId   string
+	UserId      string    `db:"user_id"`
+	AssetId     string    `db:"asset_id"`
+	AssetType   AssetType `db:"asset_type"` // the type of the asset when it was favourited; asset types never change
+	CreatedAt   time.Time `db:"created_at"`
+	Note        string    `db:"note"`
+	CustomTitle string    `db:"custom_title"`
+	Tags        string    `db:"tags"` // JSON serialized
+}
+
+// FavouriteCursor marks the last favourite of a page; the next page starts right after it.
+// Favourites are ordered newest first, ties broken by ascending asset id.
+type FavouriteCursor struct {
 	CreatedAt time.Time
+	AssetId   string
+}
+
+// FavouritePage is one page of a user's favourites. Next is nil on the last page.
+type FavouritePage struct {
+	Favourites []FavouriteEntity
+	Next       *FavouriteCursor
+}
+
+// FavouriteChange is one change of a favourites batch: adding Favourite, or with Remove removing the user's
+// favourite of its asset
+type FavouriteChange struct {
+	Remove    bool
+	Favourite FavouriteEntity
+}
+
+// FavouriteLess reports whether a sorts before b in the favourites ordering
+func FavouriteLess(a, b FavouriteEntity) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.AssetId < b.AssetId
+}
+
+// SortFavourites sorts favourites newest first, ties broken by asset id
+func SortFavourites(favourites []FavouriteEntity) {
+	sort.Slice(favourites, func(i, j int) bool {
+		return FavouriteLess(favourites[i], favourites[j])
+	})
+}
+
+// PaginateFavourites returns the page of up to limit favourites following after.
+// favourites must already be sorted with SortFavourites; a nil after starts from the first favourite.
+func PaginateFavourites(favourites []FavouriteEntity, after *FavouriteCursor, limit int) FavouritePage {
+	start := 0
+	if after != nil {
+		position := FavouriteEntity{AssetId: after.AssetId, CreatedAt: after.CreatedAt}
+		start = sort.Search(len(favourites), func(i int) bool {
+			return FavouriteLess(position, favourites[i])
+		})
+	}
+
+	end := start + limit
+	if limit <= 0 || end > len(favourites) {
+		end = len(favourites)
+	}
+
+	page := FavouritePage{Favourites: append([]FavouriteEntity{}, favourites[start:end]...)}
+	if end < len(favourites) && end > start {
+		last := favourites[end-1]
+		page.Next = &FavouriteCursor{CreatedAt: last.CreatedAt, AssetId: last.AssetId}
+	}
+	return page
 }
@@ -0,0 +1,57 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+)
+
+func TestPaginateFavourites(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	favourites := []entities.FavouriteEntity{
+		{AssetId: "old", CreatedAt: base},
+		{AssetId: "b", CreatedAt: base.Add(time.Hour)},
+		{AssetId: "a", CreatedAt: base.Add(time.Hour)},
+		{AssetId: "new", CreatedAt: base.Add(2 * time.Hour)},
+	}
+	entities.SortFavourites(favourites)
+
+	tests := []struct {
+		name     string
+		after    *entities.FavouriteCursor
+		limit    int
+		wantIDs  []string
+		wantNext bool
+	}{
+		{"first page", nil, 2, []string{"new", "a"}, true},
+		{"middle page", &entities.FavouriteCursor{AssetId: "a", CreatedAt: base.Add(time.Hour)}, 2, []string{"b", "old"}, false},
+		{"no limit returns everything", nil, 0, []string{"new", "a", "b", "old"}, false},
+		{"cursor of a removed favourite", &entities.FavouriteCursor{AssetId: "removed", CreatedAt: base.Add(90 * time.Minute)}, 10, []string{"a", "b", "old"}, false},
+		{"past the end", &entities.FavouriteCursor{AssetId: "old", CreatedAt: base}, 2, []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			page := entities.PaginateFavourites(favourites, tt.after, tt.limit)
+
+			// Assert
+			ids := make([]string, 0, len(page.Favourites))
+			for _, f := range page.Favourites {
+				ids = append(ids, f.AssetId)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("expected %v, got %v", tt.wantIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("expected %v, got %v", tt.wantIDs, ids)
+				}
+			}
+			if (page.Next != nil) != tt.wantNext {
+				t.Errorf("expected next cursor presence %v, got %+v", tt.wantNext, page.Next)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// FavouriteTombstoneEntity records that an asset a user had favourited was deleted.
+// The asset's type and title are copied so the user can still recognise what was removed.
+type FavouriteTombstoneEntity struct {
+	UserId       string    `db:"user_id"`
+	AssetId      string    `db:"asset_id"`
+	AssetType    AssetType `db:"asset_type"`
+	AssetTitle   string    `db:"asset_title"`
+	FavouritedAt time.Time `db:"favourited_at"`
+	RemovedAt    time.Time `db:"removed_at"`
+}
+
+// SortFavouriteTombstones sorts tombstones most recently removed first, ties broken by asset id
+func SortFavouriteTombstones(tombstones []FavouriteTombstoneEntity) {
+	sort.Slice(tombstones, func(i, j int) bool {
+		a, b := tombstones[i], tombstones[j]
+		if !a.RemovedAt.Equal(b.RemovedAt) {
+			return a.RemovedAt.After(b.RemovedAt)
+		}
+		return a.AssetId < b.AssetId
+	})
+}
+
+// TombstoneFor returns a copy of the tombstone for the user whose favourite f was removed
+func (t FavouriteTombstoneEntity) TombstoneFor(f FavouriteEntity) FavouriteTombstoneEntity {
+	t.UserId = f.UserId
+	t.AssetId = f.AssetId
+	t.FavouritedAt = f.CreatedAt
+	return t
+}
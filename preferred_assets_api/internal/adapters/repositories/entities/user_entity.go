@@ -3,10 +3,12 @@ package entities
 import "time"
 
 type UserEntity struct {
-	Id        string
-	Name      string
-	Email     string
-	Password  string // hashed
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id        string    `db:"id"`
+	Name      string    `db:"name"`
+	Email     string    `db:"email"`
+	Password  string    `db:"password"` // hashed
+	Subject   string    `db:"subject"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Version   int64     `db:"version"` // bumped by the repository on every write
 }
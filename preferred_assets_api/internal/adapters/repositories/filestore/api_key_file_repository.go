@@ -0,0 +1,61 @@
+package filestore
+
+import (
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.APIKeyRepository = (*FileAPIKeyRepositoryImpl)(nil)
+
+type FileAPIKeyRepositoryImpl struct {
+	store *Store
+}
+
+func NewAPIKeyRepository(store *Store) *FileAPIKeyRepositoryImpl {
+	return &FileAPIKeyRepositoryImpl{store: store}
+}
+
+func (r *FileAPIKeyRepositoryImpl) Save(key entities.APIKeyEntity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.append(record{Op: opAPIKeyPut, APIKey: &key})
+}
+
+func (r *FileAPIKeyRepositoryImpl) GetByHash(hash string) (entities.APIKeyEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, key := range r.store.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return entities.APIKeyEntity{}, ports.ErrAPIKeyNotFound
+}
+
+func (r *FileAPIKeyRepositoryImpl) GetAll() ([]entities.APIKeyEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keys := make([]entities.APIKeyEntity, 0, len(r.store.apiKeys))
+	for _, key := range r.store.apiKeys {
+		keys = append(keys, key)
+	}
+	entities.SortAPIKeys(keys)
+	return keys, nil
+}
+
+func (r *FileAPIKeyRepositoryImpl) Revoke(id string, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok {
+		return ports.ErrAPIKeyNotFound
+	}
+	key.RevokedAt = at
+	return r.store.append(record{Op: opAPIKeyPut, APIKey: &key})
+}
@@ -0,0 +1,162 @@
+package filestore
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.AssetRepository = (*FileAssetRepositoryImpl)(nil)
+
+type FileAssetRepositoryImpl struct {
+	store *Store
+}
+
+func NewAssetRepository(store *Store) *FileAssetRepositoryImpl {
+	return &FileAssetRepositoryImpl{store: store}
+}
+
+func (r *FileAssetRepositoryImpl) Exists(id string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, exists := r.store.assets[id]
+	return exists, nil
+}
+
+func (r *FileAssetRepositoryImpl) Save(asset entities.AssetEntity) (entities.AssetEntity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.assets[asset.GetID()]; exists {
+		return nil, ports.ErrAssetExists
+	}
+
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(1)
+	rec, err := encodeAsset(asset)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.append(record{Op: opAssetPut, Asset: rec}); err != nil {
+		return nil, err
+	}
+
+	return entities.CloneAsset(r.store.assets[asset.GetID()]), nil
+}
+
+func (r *FileAssetRepositoryImpl) GetByID(id string) (entities.AssetEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	asset, ok := r.store.assets[id]
+	if !ok {
+		return nil, ports.ErrAssetNotFound
+	}
+	return entities.CloneAsset(asset), nil
+}
+
+func (r *FileAssetRepositoryImpl) GetByIDs(ids []string) ([]entities.AssetEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assets := make([]entities.AssetEntity, 0, len(ids))
+	for _, id := range ids {
+		if asset, ok := r.store.assets[id]; ok {
+			assets = append(assets, entities.CloneAsset(asset))
+		}
+	}
+	return assets, nil
+}
+
+func (r *FileAssetRepositoryImpl) GetAll() ([]entities.AssetEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assets := make([]entities.AssetEntity, 0, len(r.store.assets))
+	for _, asset := range r.store.assets {
+		assets = append(assets, entities.CloneAsset(asset))
+	}
+	return assets, nil
+}
+
+func (r *FileAssetRepositoryImpl) GetByType(assetType entities.AssetType) ([]entities.AssetEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assets := make([]entities.AssetEntity, 0)
+	for _, asset := range r.store.assets {
+		if asset.GetType() == assetType {
+			assets = append(assets, entities.CloneAsset(asset))
+		}
+	}
+	return assets, nil
+}
+
+func (r *FileAssetRepositoryImpl) Query(query entities.AssetQuery) (entities.AssetPage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assets := make([]entities.AssetEntity, 0, len(r.store.assets))
+	for _, asset := range r.store.assets {
+		assets = append(assets, entities.CloneAsset(asset))
+	}
+	return entities.RunAssetQuery(assets, query), nil
+}
+
+func (r *FileAssetRepositoryImpl) Update(asset entities.AssetEntity) (int64, error) {
+	return r.update(asset, nil)
+}
+
+func (r *FileAssetRepositoryImpl) Delete(id string) error {
+	return r.delete(id, nil)
+}
+
+func (r *FileAssetRepositoryImpl) CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error) {
+	return r.update(asset, &expectedVersion)
+}
+
+func (r *FileAssetRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	return r.delete(id, &expectedVersion)
+}
+
+// update appends the asset with the next version. A nil expectedVersion skips the version check.
+// It returns the version the asset was stored at.
+func (r *FileAssetRepositoryImpl) update(asset entities.AssetEntity, expectedVersion *int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.assets[asset.GetID()]
+	if !ok {
+		return 0, ports.ErrAssetNotFound
+	}
+	if expectedVersion != nil && stored.GetVersion() != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(stored.GetVersion() + 1)
+	rec, err := encodeAsset(asset)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.store.append(record{Op: opAssetPut, Asset: rec}); err != nil {
+		return 0, err
+	}
+	return asset.GetVersion(), nil
+}
+
+// delete appends the removal of the asset. A nil expectedVersion skips the version check.
+func (r *FileAssetRepositoryImpl) delete(id string, expectedVersion *int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.assets[id]
+	if !ok {
+		return ports.ErrAssetNotFound
+	}
+	if expectedVersion != nil && stored.GetVersion() != *expectedVersion {
+		return ports.ErrVersionConflict
+	}
+
+	return r.store.append(record{Op: opAssetDelete, ID: id})
+}
@@ -0,0 +1,123 @@
+package filestore
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.CollectionRepository = (*FileCollectionRepositoryImpl)(nil)
+
+type FileCollectionRepositoryImpl struct {
+	store *Store
+}
+
+func NewCollectionRepository(store *Store) *FileCollectionRepositoryImpl {
+	return &FileCollectionRepositoryImpl{store: store}
+}
+
+func (r *FileCollectionRepositoryImpl) Save(collection entities.CollectionEntity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	collection = collection.Clone()
+	collection.Version = 1
+	return r.store.append(record{Op: opCollectionPut, Collection: &collection})
+}
+
+func (r *FileCollectionRepositoryImpl) GetByID(id string) (entities.CollectionEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	collection, ok := r.store.collections[id]
+	if !ok {
+		return entities.CollectionEntity{}, ports.ErrCollectionNotFound
+	}
+	return collection.Clone(), nil
+}
+
+func (r *FileCollectionRepositoryImpl) GetByUserID(userID string) ([]entities.CollectionEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	collections := make([]entities.CollectionEntity, 0)
+	for _, collection := range r.store.collections {
+		if collection.UserId == userID {
+			collections = append(collections, collection.Clone())
+		}
+	}
+	entities.SortCollections(collections)
+	return collections, nil
+}
+
+func (r *FileCollectionRepositoryImpl) Update(collection entities.CollectionEntity) (int64, error) {
+	return r.update(collection, nil)
+}
+
+func (r *FileCollectionRepositoryImpl) CompareAndSwap(collection entities.CollectionEntity, expectedVersion int64) (int64, error) {
+	return r.update(collection, &expectedVersion)
+}
+
+// update appends the collection with the next version. A nil expectedVersion skips the version check.
+// It returns the version the collection was stored at.
+func (r *FileCollectionRepositoryImpl) update(collection entities.CollectionEntity, expectedVersion *int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.collections[collection.Id]
+	if !ok {
+		return 0, ports.ErrCollectionNotFound
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	collection = collection.Clone()
+	collection.Version = stored.Version + 1
+	if err := r.store.append(record{Op: opCollectionPut, Collection: &collection}); err != nil {
+		return 0, err
+	}
+	return collection.Version, nil
+}
+
+func (r *FileCollectionRepositoryImpl) Delete(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.collections[id]; !ok {
+		return ports.ErrCollectionNotFound
+	}
+	return r.store.append(record{Op: opCollectionDelete, ID: id})
+}
+
+func (r *FileCollectionRepositoryImpl) DeleteByUserID(userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, collection := range r.store.collections {
+		if collection.UserId == userID {
+			return r.store.append(record{Op: opUserUncollect, UserID: userID})
+		}
+	}
+	return nil
+}
+
+func (r *FileCollectionRepositoryImpl) RemoveAsset(userID, assetID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	changed := make([]entities.CollectionEntity, 0)
+	for _, collection := range r.store.collections {
+		collection = collection.Clone()
+		if collection.UserId == userID && collection.RemoveAsset(assetID) {
+			collection.Version++
+			changed = append(changed, collection)
+		}
+	}
+
+	for i := range changed {
+		if err := r.store.append(record{Op: opCollectionPut, Collection: &changed[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package filestore
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.FavouriteRepository = (*FileFavouriteRepositoryImpl)(nil)
+
+type FileFavouriteRepositoryImpl struct {
+	store *Store
+}
+
+func NewFavouriteRepository(store *Store) *FileFavouriteRepositoryImpl {
+	return &FileFavouriteRepositoryImpl{store: store}
+}
+
+func (r *FileFavouriteRepositoryImpl) Add(f entities.FavouriteEntity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.favourites[f.UserId][f.AssetId]; exists {
+		return ports.ErrFavouriteExists
+	}
+	return r.store.append(record{Op: opFavouritePut, Favourite: &f})
+}
+
+func (r *FileFavouriteRepositoryImpl) Delete(userID, assetID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.favourites[userID][assetID]; !ok {
+		return nil
+	}
+	return r.store.append(record{Op: opFavouriteDelete, UserID: userID, AssetID: assetID})
+}
+
+// ApplyChanges implements ports.FavouriteRepository.
+// The changes that take effect are written as a single record, so a crash keeps all or none of them.
+func (r *FileFavouriteRepositoryImpl) ApplyChanges(changes []entities.FavouriteChange) ([]bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Track the changes earlier in the batch, as they are only applied once the record is written
+	pending := make(map[[2]string]bool)
+	present := func(f entities.FavouriteEntity) bool {
+		if exists, ok := pending[[2]string{f.UserId, f.AssetId}]; ok {
+			return exists
+		}
+		_, exists := r.store.favourites[f.UserId][f.AssetId]
+		return exists
+	}
+
+	applied := make([]bool, len(changes))
+	effective := make([]entities.FavouriteChange, 0, len(changes))
+	for i, change := range changes {
+		if present(change.Favourite) != change.Remove {
+			continue
+		}
+		applied[i] = true
+		effective = append(effective, change)
+		pending[[2]string{change.Favourite.UserId, change.Favourite.AssetId}] = !change.Remove
+	}
+
+	if len(effective) == 0 {
+		return applied, nil
+	}
+	if err := r.store.append(record{Op: opFavouriteBatch, Changes: effective}); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (r *FileFavouriteRepositoryImpl) GetByUserID(userID string) ([]entities.FavouriteEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.userFavourites(userID), nil
+}
+
+func (r *FileFavouriteRepositoryImpl) GetPageByUserID(userID string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return entities.PaginateFavourites(r.store.userFavourites(userID), after, limit), nil
+}
+
+func (r *FileFavouriteRepositoryImpl) Exists(userID, assetID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, exists := r.store.favourites[userID][assetID]
+	return exists, nil
+}
+
+func (r *FileFavouriteRepositoryImpl) Get(userID, assetID string) (entities.FavouriteEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	f, exists := r.store.favourites[userID][assetID]
+	if !exists {
+		return entities.FavouriteEntity{}, ports.ErrFavouriteNotFound
+	}
+	return f, nil
+}
+
+func (r *FileFavouriteRepositoryImpl) Update(f entities.FavouriteEntity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, exists := r.store.favourites[f.UserId][f.AssetId]
+	if !exists {
+		return ports.ErrFavouriteNotFound
+	}
+	f.CreatedAt = existing.CreatedAt
+	return r.store.append(record{Op: opFavouritePut, Favourite: &f})
+}
+
+func (r *FileFavouriteRepositoryImpl) DeleteByAssetID(assetID string, tombstone *entities.FavouriteTombstoneEntity) ([]entities.FavouriteEntity, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	removed := r.store.assetFavourites(assetID)
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	if err := r.store.append(record{Op: opAssetUnfavourite, AssetID: assetID, Tombstone: tombstone}); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (r *FileFavouriteRepositoryImpl) DeleteByUserID(userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if len(r.store.favourites[userID]) == 0 && len(r.store.tombstones[userID]) == 0 {
+		return nil
+	}
+	return r.store.append(record{Op: opUserUnfavourite, UserID: userID})
+}
+
+func (r *FileFavouriteRepositoryImpl) GetTombstonesByUserID(userID string) ([]entities.FavouriteTombstoneEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tombstones := make([]entities.FavouriteTombstoneEntity, 0, len(r.store.tombstones[userID]))
+	for _, t := range r.store.tombstones[userID] {
+		tombstones = append(tombstones, t)
+	}
+	entities.SortFavouriteTombstones(tombstones)
+	return tombstones, nil
+}
+
+func (r *FileFavouriteRepositoryImpl) DeleteTombstone(userID, assetID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tombstones[userID][assetID]; !ok {
+		return nil
+	}
+	return r.store.append(record{Op: opTombstoneDelete, UserID: userID, AssetID: assetID})
+}
@@ -0,0 +1,601 @@
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+)
+
+// DefaultSnapshotEvery is the number of log records appended before the store compacts itself
+const DefaultSnapshotEvery = 1000
+
+var (
+	ErrStoreClosed = errors.New("file store is closed")
+	ErrStoreFailed = errors.New("file store failed and refuses writes")
+)
+
+// Log record operations
+const (
+	opSnapshot         = "snapshot"
+	opUserPut          = "user.put"
+	opUserDelete       = "user.delete"
+	opAssetPut         = "asset.put"
+	opAssetDelete      = "asset.delete"
+	opFavouritePut     = "favourite.put"
+	opFavouriteDelete  = "favourite.delete"
+	opFavouriteBatch   = "favourite.batch"
+	opAssetUnfavourite = "favourite.delete_asset"
+	opUserUnfavourite  = "favourite.delete_user"
+	opTombstoneDelete  = "tombstone.delete"
+	opCollectionPut    = "collection.put"
+	opCollectionDelete = "collection.delete"
+	opUserUncollect    = "collection.delete_user"
+	opAPIKeyPut        = "api_key.put"
+)
+
+// Store is an embedded, single-file database backing the file repositories.
+// Every mutation is appended to the file as a JSON line and fsynced before it is applied in memory.
+// Once the log grows past snapshotEvery records it is compacted into a single snapshot record,
+// written to a temporary file and atomically renamed over the original.
+type Store struct {
+	path          string
+	file          *os.File
+	snapshotEvery int
+	records       int
+	// failure is set when a failed append could not be rolled back, leaving the end of the file unknown
+	failure error
+
+	users       map[string]entities.UserEntity
+	assets      map[string]entities.AssetEntity
+	favourites  map[string]map[string]entities.FavouriteEntity
+	collections map[string]entities.CollectionEntity
+	tombstones  map[string]map[string]entities.FavouriteTombstoneEntity
+	apiKeys     map[string]entities.APIKeyEntity
+
+	// favouritedBy is the reverse index asset id -> ids of the users who favourited it
+	favouritedBy map[string]map[string]bool
+
+	mu sync.RWMutex
+}
+
+type record struct {
+	Op         string                             `json:"op"`
+	ID         string                             `json:"id,omitempty"`
+	UserID     string                             `json:"user_id,omitempty"`
+	AssetID    string                             `json:"asset_id,omitempty"`
+	User       *entities.UserEntity               `json:"user,omitempty"`
+	Asset      *assetRecord                       `json:"asset,omitempty"`
+	Favourite  *entities.FavouriteEntity          `json:"favourite,omitempty"`
+	Changes    []entities.FavouriteChange         `json:"changes,omitempty"`
+	Collection *entities.CollectionEntity         `json:"collection,omitempty"`
+	Tombstone  *entities.FavouriteTombstoneEntity `json:"tombstone,omitempty"`
+	APIKey     *entities.APIKeyEntity             `json:"api_key,omitempty"`
+	Snapshot   *snapshot                          `json:"snapshot,omitempty"`
+}
+
+// assetRecord wraps an asset entity with its type so it can be decoded into the right concrete entity
+type assetRecord struct {
+	Type entities.AssetType `json:"type"`
+	Data json.RawMessage    `json:"data"`
+}
+
+type snapshot struct {
+	Users       []entities.UserEntity               `json:"users"`
+	Assets      []assetRecord                       `json:"assets"`
+	Favourites  []entities.FavouriteEntity          `json:"favourites"`
+	Collections []entities.CollectionEntity         `json:"collections,omitempty"`
+	Tombstones  []entities.FavouriteTombstoneEntity `json:"tombstones,omitempty"`
+	APIKeys     []entities.APIKeyEntity             `json:"api_keys,omitempty"`
+}
+
+// Open opens the store file at path, creating it if needed, and replays its contents into memory.
+// snapshotEvery <= 0 falls back to DefaultSnapshotEvery.
+func Open(path string, snapshotEvery int) (*Store, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %w", err)
+	}
+
+	s := &Store{
+		path:          path,
+		file:          file,
+		snapshotEvery: snapshotEvery,
+		users:         make(map[string]entities.UserEntity),
+		assets:        make(map[string]entities.AssetEntity),
+		favourites:    make(map[string]map[string]entities.FavouriteEntity),
+		collections:   make(map[string]entities.CollectionEntity),
+		tombstones:    make(map[string]map[string]entities.FavouriteTombstoneEntity),
+		apiKeys:       make(map[string]entities.APIKeyEntity),
+		favouritedBy:  make(map[string]map[string]bool),
+	}
+
+	if err := s.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close flushes and closes the underlying file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Sync()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	s.file = nil
+	return err
+}
+
+// Compact rewrites the store file as a single snapshot of the current state
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compact()
+}
+
+// replay loads the file into memory. A torn final line, left behind by a crash mid-append, is truncated away.
+func (s *Store) replay() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				log.Printf("[filestore] Truncating incomplete record at offset %d in %s", offset, s.path)
+				return s.truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read store file: %w", err)
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("corrupt record at offset %d in %s: %w", offset, s.path, err)
+		}
+		if err := s.apply(rec); err != nil {
+			return fmt.Errorf("failed to apply record at offset %d: %w", offset, err)
+		}
+
+		offset += int64(len(line))
+		s.records++
+	}
+
+	_, err := s.file.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (s *Store) truncate(offset int64) error {
+	if err := s.file.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to truncate store file: %w", err)
+	}
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// append durably writes rec to the log and applies it in memory. Callers must hold s.mu.
+func (s *Store) append(rec record) error {
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+	if s.failure != nil {
+		return fmt.Errorf("%w: %v", ErrStoreFailed, s.failure)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+	line = append(line, '\n')
+
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to locate end of store file: %w", err)
+	}
+	if _, err := s.file.Write(line); err != nil {
+		return s.rollback(offset, fmt.Errorf("failed to append record: %w", err))
+	}
+	if err := s.file.Sync(); err != nil {
+		return s.rollback(offset, fmt.Errorf("failed to sync store file: %w", err))
+	}
+
+	if err := s.apply(rec); err != nil {
+		return err
+	}
+	s.records++
+
+	if s.records > s.snapshotEvery {
+		if err := s.compact(); err != nil {
+			// The record is already durable, so a failed compaction only delays the next attempt
+			log.Printf("[filestore] Compaction of %s failed: %v", s.path, err)
+		}
+	}
+	return nil
+}
+
+// rollback cuts a record whose append failed off the end of the file, so it is neither replayed nor followed by
+// later records. If the file cannot be restored the store is marked failed and refuses further writes.
+func (s *Store) rollback(offset int64, cause error) error {
+	err := s.truncate(offset)
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err != nil {
+		log.Printf("[filestore] Could not roll back failed append to %s, refusing further writes: %v", s.path, err)
+		s.failure = cause
+	}
+	return cause
+}
+
+// apply mutates the in-memory state for a single record
+func (s *Store) apply(rec record) error {
+	switch rec.Op {
+	case opSnapshot:
+		if rec.Snapshot == nil {
+			return errors.New("snapshot record without payload")
+		}
+		return s.restore(*rec.Snapshot)
+	case opUserPut:
+		if rec.User == nil {
+			return errors.New("user record without payload")
+		}
+		s.putUser(*rec.User)
+	case opUserDelete:
+		delete(s.users, rec.ID)
+		s.deleteUserFavourites(rec.ID)
+		s.deleteUserCollections(rec.ID)
+	case opAssetPut:
+		if rec.Asset == nil {
+			return errors.New("asset record without payload")
+		}
+		asset, err := decodeAsset(*rec.Asset)
+		if err != nil {
+			return err
+		}
+		s.assets[asset.GetID()] = asset
+	case opAssetDelete:
+		delete(s.assets, rec.ID)
+	case opFavouritePut:
+		if rec.Favourite == nil {
+			return errors.New("favourite record without payload")
+		}
+		s.putFavourite(*rec.Favourite)
+	case opFavouriteDelete:
+		s.deleteFavourite(rec.UserID, rec.AssetID)
+	case opFavouriteBatch:
+		for _, change := range rec.Changes {
+			if change.Remove {
+				s.deleteFavourite(change.Favourite.UserId, change.Favourite.AssetId)
+			} else {
+				s.putFavourite(change.Favourite)
+			}
+		}
+	case opAssetUnfavourite:
+		s.deleteAssetFavourites(rec.AssetID, rec.Tombstone)
+	case opUserUnfavourite:
+		s.deleteUserFavourites(rec.UserID)
+	case opTombstoneDelete:
+		s.deleteTombstone(rec.UserID, rec.AssetID)
+	case opCollectionPut:
+		if rec.Collection == nil {
+			return errors.New("collection record without payload")
+		}
+		s.collections[rec.Collection.Id] = *rec.Collection
+	case opCollectionDelete:
+		delete(s.collections, rec.ID)
+	case opUserUncollect:
+		s.deleteUserCollections(rec.UserID)
+	case opAPIKeyPut:
+		if rec.APIKey == nil {
+			return errors.New("api key record without payload")
+		}
+		s.apiKeys[rec.APIKey.Id] = *rec.APIKey
+	default:
+		return fmt.Errorf("unknown record operation: %s", rec.Op)
+	}
+	return nil
+}
+
+func (s *Store) restore(snap snapshot) error {
+	s.users = make(map[string]entities.UserEntity, len(snap.Users))
+	s.assets = make(map[string]entities.AssetEntity, len(snap.Assets))
+	s.favourites = make(map[string]map[string]entities.FavouriteEntity)
+	s.collections = make(map[string]entities.CollectionEntity, len(snap.Collections))
+	s.tombstones = make(map[string]map[string]entities.FavouriteTombstoneEntity)
+	s.apiKeys = make(map[string]entities.APIKeyEntity, len(snap.APIKeys))
+	s.favouritedBy = make(map[string]map[string]bool)
+
+	for _, u := range snap.Users {
+		s.putUser(u)
+	}
+	for _, a := range snap.Assets {
+		asset, err := decodeAsset(a)
+		if err != nil {
+			return err
+		}
+		s.assets[asset.GetID()] = asset
+	}
+	for _, f := range snap.Favourites {
+		s.putFavourite(f)
+	}
+	for _, c := range snap.Collections {
+		s.collections[c.Id] = c
+	}
+	for _, t := range snap.Tombstones {
+		s.putTombstone(t)
+	}
+	for _, k := range snap.APIKeys {
+		s.apiKeys[k.Id] = k
+	}
+	return nil
+}
+
+// putUser stores u, treating users written before versioning was introduced as version 1
+func (s *Store) putUser(u entities.UserEntity) {
+	if u.Version == 0 {
+		u.Version = 1
+	}
+	s.users[u.Id] = u
+}
+
+func (s *Store) putFavourite(f entities.FavouriteEntity) {
+	// Records written before favourites carried their asset type get it from the asset, whose type never changes
+	if asset, ok := s.assets[f.AssetId]; ok {
+		f.AssetType = asset.GetType()
+	}
+
+	userFavourites, ok := s.favourites[f.UserId]
+	if !ok {
+		userFavourites = make(map[string]entities.FavouriteEntity)
+		s.favourites[f.UserId] = userFavourites
+	}
+	userFavourites[f.AssetId] = f
+
+	users, ok := s.favouritedBy[f.AssetId]
+	if !ok {
+		users = make(map[string]bool)
+		s.favouritedBy[f.AssetId] = users
+	}
+	users[f.UserId] = true
+}
+
+// userFavourites returns a sorted copy of the user's favourites, or an empty slice if there are none
+func (s *Store) userFavourites(userID string) []entities.FavouriteEntity {
+	userFavourites := s.favourites[userID]
+	favourites := make([]entities.FavouriteEntity, 0, len(userFavourites))
+	for _, f := range userFavourites {
+		favourites = append(favourites, f)
+	}
+	entities.SortFavourites(favourites)
+	return favourites
+}
+
+func (s *Store) deleteFavourite(userID, assetID string) {
+	userFavourites, ok := s.favourites[userID]
+	if !ok {
+		return
+	}
+	delete(userFavourites, assetID)
+	if len(userFavourites) == 0 {
+		delete(s.favourites, userID)
+	}
+
+	users := s.favouritedBy[assetID]
+	delete(users, userID)
+	if len(users) == 0 {
+		delete(s.favouritedBy, assetID)
+	}
+}
+
+// assetFavourites returns every user's favourite of the asset, newest first
+func (s *Store) assetFavourites(assetID string) []entities.FavouriteEntity {
+	favourites := make([]entities.FavouriteEntity, 0, len(s.favouritedBy[assetID]))
+	for userID := range s.favouritedBy[assetID] {
+		favourites = append(favourites, s.favourites[userID][assetID])
+	}
+	entities.SortFavourites(favourites)
+	return favourites
+}
+
+// deleteAssetFavourites removes every favourite of the asset, leaving a copy of tombstone, if any, for each user
+func (s *Store) deleteAssetFavourites(assetID string, tombstone *entities.FavouriteTombstoneEntity) {
+	for _, f := range s.assetFavourites(assetID) {
+		s.deleteFavourite(f.UserId, assetID)
+		if tombstone != nil {
+			s.putTombstone(tombstone.TombstoneFor(f))
+		}
+	}
+}
+
+// deleteUserFavourites removes every favourite and tombstone of the user
+func (s *Store) deleteUserFavourites(userID string) {
+	for assetID := range s.favourites[userID] {
+		s.deleteFavourite(userID, assetID)
+	}
+	delete(s.tombstones, userID)
+}
+
+func (s *Store) deleteUserCollections(userID string) {
+	for id, collection := range s.collections {
+		if collection.UserId == userID {
+			delete(s.collections, id)
+		}
+	}
+}
+
+func (s *Store) putTombstone(t entities.FavouriteTombstoneEntity) {
+	userTombstones, ok := s.tombstones[t.UserId]
+	if !ok {
+		userTombstones = make(map[string]entities.FavouriteTombstoneEntity)
+		s.tombstones[t.UserId] = userTombstones
+	}
+	userTombstones[t.AssetId] = t
+}
+
+func (s *Store) deleteTombstone(userID, assetID string) {
+	userTombstones, ok := s.tombstones[userID]
+	if !ok {
+		return
+	}
+	delete(userTombstones, assetID)
+	if len(userTombstones) == 0 {
+		delete(s.tombstones, userID)
+	}
+}
+
+// compact writes the current state as one snapshot record and swaps it in place of the log.
+// Callers must hold s.mu.
+func (s *Store) compact() error {
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	snap, err := s.snapshot()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(record{Op: opSnapshot, Snapshot: &snap})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	line = append(line, '\n')
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	if _, err := tmp.Write(line); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace store file: %w", err)
+	}
+
+	s.file.Close()
+	s.file = tmp
+	s.records = 1
+
+	// The rename only survives a crash once the directory entry is on disk
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		return fmt.Errorf("failed to sync store directory: %w", err)
+	}
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = dir.Sync()
+	if cerr := dir.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *Store) snapshot() (snapshot, error) {
+	snap := snapshot{
+		Users:       make([]entities.UserEntity, 0, len(s.users)),
+		Assets:      make([]assetRecord, 0, len(s.assets)),
+		Favourites:  make([]entities.FavouriteEntity, 0),
+		Collections: make([]entities.CollectionEntity, 0, len(s.collections)),
+	}
+
+	for _, u := range s.users {
+		snap.Users = append(snap.Users, u)
+	}
+	for _, a := range s.assets {
+		rec, err := encodeAsset(a)
+		if err != nil {
+			return snapshot{}, err
+		}
+		snap.Assets = append(snap.Assets, *rec)
+	}
+	for _, userFavourites := range s.favourites {
+		for _, f := range userFavourites {
+			snap.Favourites = append(snap.Favourites, f)
+		}
+	}
+	for _, c := range s.collections {
+		snap.Collections = append(snap.Collections, c)
+	}
+	for _, userTombstones := range s.tombstones {
+		for _, t := range userTombstones {
+			snap.Tombstones = append(snap.Tombstones, t)
+		}
+	}
+	for _, k := range s.apiKeys {
+		snap.APIKeys = append(snap.APIKeys, k)
+	}
+	return snap, nil
+}
+
+func encodeAsset(asset entities.AssetEntity) (*assetRecord, error) {
+	data, err := json.Marshal(asset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode asset %s: %w", asset.GetID(), err)
+	}
+	return &assetRecord{Type: asset.GetType(), Data: data}, nil
+}
+
+func decodeAsset(rec assetRecord) (entities.AssetEntity, error) {
+	var asset entities.AssetEntity
+	switch rec.Type {
+	case entities.AssetTypeAudience:
+		asset = &entities.AudienceEntity{}
+	case entities.AssetTypeChart:
+		asset = &entities.ChartEntity{}
+	case entities.AssetTypeInsight:
+		asset = &entities.InsightEntity{}
+	default:
+		return nil, fmt.Errorf("unknown asset type: %v", rec.Type)
+	}
+
+	if err := json.Unmarshal(rec.Data, asset); err != nil {
+		return nil, fmt.Errorf("failed to decode asset: %w", err)
+	}
+	// Assets written before versioning was introduced start at version 1
+	if asset.GetVersion() == 0 {
+		asset.SetVersion(1)
+	}
+	return asset, nil
+}
@@ -0,0 +1,468 @@
+package filestore_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/filestore"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T, path string, snapshotEvery int) *filestore.Store {
+	t.Helper()
+	store, err := filestore.Open(path, snapshotEvery)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func newInsightEntity(id string) *entities.InsightEntity {
+	return &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{
+			ID:        id,
+			Type:      entities.AssetTypeInsight,
+			Title:     "Insight " + id,
+			CreatedAt: time.Now().UTC(),
+		},
+		Text: "Some insight text",
+	}
+}
+
+func TestStore_SurvivesRestart(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+
+	users := filestore.NewUserRepository(store)
+	assets := filestore.NewAssetRepository(store)
+	favourites := filestore.NewFavouriteRepository(store)
+
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1", Name: "Alice"}))
+	_, err := assets.Save(newInsightEntity("a1"))
+	require.NoError(t, err)
+	_, err = assets.Save(&entities.ChartEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "c1", Type: entities.AssetTypeChart, Title: "Chart"},
+		AxesTitles:      `["x","y"]`,
+		Data:            `[[1,2]]`,
+	})
+	require.NoError(t, err)
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: time.Now().UTC()}))
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "c1", CreatedAt: time.Now().UTC()}))
+	require.NoError(t, favourites.Delete("u1", "c1"))
+	require.NoError(t, store.Close())
+
+	// Act
+	reopened := openStore(t, path, 0)
+
+	// Assert
+	user, err := filestore.NewUserRepository(reopened).GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user.Name)
+
+	insight, err := filestore.NewAssetRepository(reopened).GetByID("a1")
+	require.NoError(t, err)
+	require.IsType(t, &entities.InsightEntity{}, insight)
+	require.Equal(t, "Some insight text", insight.(*entities.InsightEntity).Text)
+
+	chart, err := filestore.NewAssetRepository(reopened).GetByID("c1")
+	require.NoError(t, err)
+	require.Equal(t, `[[1,2]]`, chart.(*entities.ChartEntity).Data)
+
+	favs, err := filestore.NewFavouriteRepository(reopened).GetByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	require.Equal(t, "a1", favs[0].AssetId)
+}
+
+func TestStore_NeverEvicts(t *testing.T) {
+	// Arrange
+	store := openStore(t, filepath.Join(t.TempDir(), "store.db"), 0)
+	users := filestore.NewUserRepository(store)
+	favourites := filestore.NewFavouriteRepository(store)
+
+	// Act
+	for i := 0; i < 50; i++ {
+		require.NoError(t, users.Save(entities.UserEntity{Id: string(rune('A' + i))}))
+	}
+	for i := 0; i < 500; i++ {
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "A", AssetId: string(rune(0x100 + i))}))
+	}
+
+	// Assert
+	all, err := users.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 50)
+
+	favs, err := favourites.GetByUserID("A")
+	require.NoError(t, err)
+	require.Len(t, favs, 500)
+}
+
+func TestStore_CompactsLog(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 10)
+	users := filestore.NewUserRepository(store)
+
+	// Act
+	for i := 0; i < 25; i++ {
+		require.NoError(t, users.Save(entities.UserEntity{Id: "u1", Name: string(rune('a' + i))}))
+	}
+	require.NoError(t, store.Close())
+
+	// Assert
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := 0
+	for _, b := range data {
+		if b == '\n' {
+			lines++
+		}
+	}
+	require.LessOrEqual(t, lines, 11)
+
+	reopened := openStore(t, path, 10)
+	user, err := filestore.NewUserRepository(reopened).GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "y", user.Name)
+}
+
+func TestStore_TruncatesTornRecord(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	require.NoError(t, filestore.NewUserRepository(store).Save(entities.UserEntity{Id: "u1"}))
+	require.NoError(t, store.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"user.put","user":{"Id":"u2"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// Act
+	reopened := openStore(t, path, 0)
+	users := filestore.NewUserRepository(reopened)
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u3"}))
+
+	// Assert
+	all, err := users.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	_, err = users.GetByID("u2")
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+}
+
+func TestStore_RejectsCorruptRecord(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+
+	// Act
+	_, err := filestore.Open(path, 0)
+
+	// Assert
+	require.Error(t, err)
+}
+
+func TestStore_DeleteUserFavourites(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	users := filestore.NewUserRepository(store)
+	favourites := filestore.NewFavouriteRepository(store)
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1"}))
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u2"}))
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: time.Now().UTC()}))
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u2", AssetId: "a1", CreatedAt: time.Now().UTC()}))
+
+	// Act
+	require.NoError(t, users.Delete("u1"))
+	require.NoError(t, store.Close())
+	reopened := filestore.NewFavouriteRepository(openStore(t, path, 0))
+
+	// Assert
+	favs, err := reopened.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, favs)
+	favs, err = reopened.GetByUserID("u2")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+}
+
+func TestStore_DeleteUserCollections(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	users := filestore.NewUserRepository(store)
+	collections := filestore.NewCollectionRepository(store)
+	now := time.Now().UTC()
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1"}))
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u2"}))
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "First", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c2", UserId: "u1", Name: "Second", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c3", UserId: "u2", Name: "First", CreatedAt: now, UpdatedAt: now}))
+
+	// Act
+	require.NoError(t, users.Delete("u1"))
+	require.NoError(t, store.Close())
+	reopened := filestore.NewCollectionRepository(openStore(t, path, 0))
+
+	// Assert
+	got, err := reopened.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, got)
+	got, err = reopened.GetByUserID("u2")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+}
+
+func TestStore_CompareAndSwap(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	users := filestore.NewUserRepository(store)
+	assets := filestore.NewAssetRepository(store)
+
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1", Name: "Alice"}))
+	_, err := assets.Save(newInsightEntity("a1"))
+	require.NoError(t, err)
+
+	// Act & Assert
+	version, err := users.CompareAndSwap(entities.UserEntity{Id: "u1", Name: "Bob"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+	_, err = users.CompareAndSwap(entities.UserEntity{Id: "u1", Name: "Carol"}, 1)
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	require.ErrorIs(t, users.CompareAndDelete("u1", 1), ports.ErrVersionConflict)
+	_, err = users.CompareAndSwap(entities.UserEntity{Id: "missing"}, 1)
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+
+	version, err = assets.CompareAndSwap(newInsightEntity("a1"), 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+	_, err = assets.CompareAndSwap(newInsightEntity("a1"), 1)
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	require.ErrorIs(t, assets.CompareAndDelete("a1", 1), ports.ErrVersionConflict)
+	require.NoError(t, store.Close())
+
+	// Versions survive a restart
+	reopened := openStore(t, path, 0)
+	user, err := filestore.NewUserRepository(reopened).GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", user.Name)
+	require.Equal(t, int64(2), user.Version)
+
+	reopenedAssets := filestore.NewAssetRepository(reopened)
+	asset, err := reopenedAssets.GetByID("a1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), asset.GetVersion())
+	require.NoError(t, reopenedAssets.CompareAndDelete("a1", 2))
+}
+
+func TestStore_AssetsAreCopied(t *testing.T) {
+	// Arrange
+	store := openStore(t, filepath.Join(t.TempDir(), "store.db"), 0)
+	assets := filestore.NewAssetRepository(store)
+	insight := newInsightEntity("a1")
+
+	// Act
+	saved, err := assets.Save(insight)
+	require.NoError(t, err)
+	saved.(*entities.InsightEntity).Text = "changed by the caller"
+	got, err := assets.GetByID("a1")
+	require.NoError(t, err)
+	got.SetVersion(7)
+	_, err = assets.Update(newInsightEntity("a1"))
+	require.NoError(t, err)
+
+	// Assert
+	stored, err := assets.GetByID("a1")
+	require.NoError(t, err)
+	require.Equal(t, "Some insight text", stored.(*entities.InsightEntity).Text)
+	require.Equal(t, int64(2), stored.GetVersion())
+	require.Equal(t, int64(0), insight.Version)
+}
+
+func TestStore_Collections(t *testing.T) {
+	for _, snapshotEvery := range []int{0, 1} {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "store.db")
+		store := openStore(t, path, snapshotEvery)
+		collections := filestore.NewCollectionRepository(store)
+
+		now := time.Now().UTC()
+		require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "First", CreatedAt: now, AssetIds: []string{"a1", "a2"}}))
+		require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c2", UserId: "u1", Name: "Second", CreatedAt: now.Add(time.Second), AssetIds: []string{"a1"}}))
+		require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c3", UserId: "u2", Name: "Other", CreatedAt: now}))
+		version, err := collections.Update(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "Renamed", CreatedAt: now, AssetIds: []string{"a2", "a1"}})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), version)
+		_, err = collections.CompareAndSwap(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "Stale", CreatedAt: now}, 1)
+		require.ErrorIs(t, err, ports.ErrVersionConflict)
+		require.NoError(t, collections.RemoveAsset("u1", "a1"))
+		require.NoError(t, collections.Delete("c3"))
+		require.ErrorIs(t, collections.Delete("c3"), ports.ErrCollectionNotFound)
+		_, err = collections.Update(entities.CollectionEntity{Id: "missing"})
+		require.ErrorIs(t, err, ports.ErrCollectionNotFound)
+		require.NoError(t, store.Close())
+
+		// Act
+		reopened := filestore.NewCollectionRepository(openStore(t, path, snapshotEvery))
+
+		// Assert
+		got, err := reopened.GetByUserID("u1")
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, "Renamed", got[0].Name)
+		require.Equal(t, []string{"a2"}, got[0].AssetIds)
+		require.Equal(t, int64(3), got[0].Version, "removing an item bumps the version")
+		require.Empty(t, got[1].AssetIds)
+		_, err = reopened.GetByID("c3")
+		require.ErrorIs(t, err, ports.ErrCollectionNotFound)
+	}
+}
+
+func TestStore_FavouriteOverrides(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	favourites := filestore.NewFavouriteRepository(store)
+	created := time.Now().UTC()
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: created}))
+	require.ErrorIs(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: created}), ports.ErrFavouriteExists)
+	require.NoError(t, favourites.Update(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", Note: "For the deck", Tags: `["q3"]`}))
+	require.ErrorIs(t, favourites.Update(entities.FavouriteEntity{UserId: "u1", AssetId: "missing"}), ports.ErrFavouriteNotFound)
+	require.NoError(t, store.Close())
+
+	// Act
+	got, err := filestore.NewFavouriteRepository(openStore(t, path, 0)).Get("u1", "a1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "For the deck", got.Note)
+	require.Equal(t, `["q3"]`, got.Tags)
+	require.True(t, got.CreatedAt.Equal(created), "the creation time must not change")
+}
+
+func TestStore_FavouriteAssetType(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "store.db")
+	store := openStore(t, path, 0)
+	_, err := filestore.NewAssetRepository(store).Save(newInsightEntity("a1"))
+	require.NoError(t, err)
+	// A favourite recorded before favourites carried their asset type
+	require.NoError(t, filestore.NewFavouriteRepository(store).Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: time.Now().UTC()}))
+	require.NoError(t, store.Close())
+
+	// Act
+	got, err := filestore.NewFavouriteRepository(openStore(t, path, 0)).Get("u1", "a1")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, entities.AssetTypeInsight, got.AssetType)
+}
+
+func TestStore_ApplyFavouriteChanges(t *testing.T) {
+	for _, snapshotEvery := range []int{0, 1} {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "store.db")
+		store := openStore(t, path, snapshotEvery)
+		favourites := filestore.NewFavouriteRepository(store)
+		now := time.Now().UTC()
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+
+		applied, err := favourites.ApplyChanges([]entities.FavouriteChange{
+			{Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}},
+			{Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a2", CreatedAt: now}},
+			{Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a2", CreatedAt: now}},
+			{Remove: true, Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a1"}},
+			{Remove: true, Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a3"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []bool{false, true, false, true, false}, applied)
+		require.NoError(t, store.Close())
+
+		// Act
+		got, err := filestore.NewFavouriteRepository(openStore(t, path, snapshotEvery)).GetByUserID("u1")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "a2", got[0].AssetId)
+	}
+}
+
+func TestStore_DeleteAssetFavourites(t *testing.T) {
+	for _, snapshotEvery := range []int{0, 1} {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "store.db")
+		store := openStore(t, path, snapshotEvery)
+		favourites := filestore.NewFavouriteRepository(store)
+		now := time.Now().UTC()
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u2", AssetId: "a1", CreatedAt: now}))
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u2", AssetId: "a2", CreatedAt: now}))
+
+		removed, err := favourites.DeleteByAssetID("a1", &entities.FavouriteTombstoneEntity{AssetTitle: "Sales", RemovedAt: now})
+		require.NoError(t, err)
+		require.Len(t, removed, 2)
+		require.NoError(t, favourites.DeleteTombstone("u1", "a1"))
+		require.NoError(t, store.Close())
+
+		// Act
+		reopened := filestore.NewFavouriteRepository(openStore(t, path, snapshotEvery))
+
+		// Assert
+		exists, err := reopened.Exists("u2", "a1")
+		require.NoError(t, err)
+		require.False(t, exists)
+		got, err := reopened.GetByUserID("u2")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		tombstones, err := reopened.GetTombstonesByUserID("u2")
+		require.NoError(t, err)
+		require.Len(t, tombstones, 1)
+		require.Equal(t, "a1", tombstones[0].AssetId)
+		require.Equal(t, "Sales", tombstones[0].AssetTitle)
+		tombstones, err = reopened.GetTombstonesByUserID("u1")
+		require.NoError(t, err)
+		require.Empty(t, tombstones)
+	}
+}
+
+func TestStore_APIKeys(t *testing.T) {
+	for _, snapshotEvery := range []int{0, 1} {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "store.db")
+		store := openStore(t, path, snapshotEvery)
+		keys := filestore.NewAPIKeyRepository(store)
+
+		now := time.Now().UTC()
+		require.NoError(t, keys.Save(entities.APIKeyEntity{Id: "k1", Name: "first", Hash: "h1", Roles: `["Users"]`, UserIds: `["u1"]`, CreatedAt: now}))
+		require.NoError(t, keys.Save(entities.APIKeyEntity{Id: "k2", Name: "second", Hash: "h2", Roles: `["Administrators"]`, UserIds: `[]`, CreatedAt: now.Add(time.Second)}))
+		require.NoError(t, keys.Revoke("k2", now.Add(time.Minute)))
+		require.ErrorIs(t, keys.Revoke("missing", now), ports.ErrAPIKeyNotFound)
+		require.NoError(t, store.Close())
+
+		// Act
+		reopened := filestore.NewAPIKeyRepository(openStore(t, path, snapshotEvery))
+
+		// Assert
+		all, err := reopened.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, "k1", all[0].Id)
+		require.True(t, all[0].RevokedAt.IsZero())
+		require.False(t, all[1].RevokedAt.IsZero())
+		got, err := reopened.GetByHash("h1")
+		require.NoError(t, err)
+		require.Equal(t, `["u1"]`, got.UserIds)
+		_, err = reopened.GetByHash("unknown")
+		require.ErrorIs(t, err, ports.ErrAPIKeyNotFound)
+	}
+}
@@ -0,0 +1,135 @@
+package filestore
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.UserRepository = (*FileUserRepositoryImpl)(nil)
+
+type FileUserRepositoryImpl struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *FileUserRepositoryImpl {
+	return &FileUserRepositoryImpl{store: store}
+}
+
+func (r *FileUserRepositoryImpl) Save(u entities.UserEntity) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u.Version = 1
+	return r.store.append(record{Op: opUserPut, User: &u})
+}
+
+func (r *FileUserRepositoryImpl) GetByID(id string) (entities.UserEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return entities.UserEntity{}, ports.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r *FileUserRepositoryImpl) GetBySubject(subject string) (entities.UserEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, u := range r.store.users {
+		if u.Subject == subject {
+			return u, nil
+		}
+	}
+	return entities.UserEntity{}, ports.ErrUserNotFound
+}
+
+func (r *FileUserRepositoryImpl) GetAll() ([]entities.UserEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]entities.UserEntity, 0, len(r.store.users))
+	for _, u := range r.store.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *FileUserRepositoryImpl) Delete(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return nil
+	}
+	return r.store.append(record{Op: opUserDelete, ID: id})
+}
+
+func (r *FileUserRepositoryImpl) Update(u entities.UserEntity) (int64, error) {
+	return r.update(u, nil)
+}
+
+func (r *FileUserRepositoryImpl) CompareAndSwap(u entities.UserEntity, expectedVersion int64) (int64, error) {
+	return r.update(u, &expectedVersion)
+}
+
+func (r *FileUserRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.users[id]
+	if !ok {
+		return ports.ErrUserNotFound
+	}
+	if stored.Version != expectedVersion {
+		return ports.ErrVersionConflict
+	}
+	return r.store.append(record{Op: opUserDelete, ID: id})
+}
+
+// update appends the user with the next version. A nil expectedVersion skips the version check.
+// It returns the version the user was stored at.
+func (r *FileUserRepositoryImpl) update(u entities.UserEntity, expectedVersion *int64) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.users[u.Id]
+	if !ok {
+		return 0, ports.ErrUserNotFound
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	u.Version = stored.Version + 1
+	if err := r.store.append(record{Op: opUserPut, User: &u}); err != nil {
+		return 0, err
+	}
+	return u.Version, nil
+}
+
+func (r *FileUserRepositoryImpl) GetFavouritesByID(id string) ([]entities.FavouriteEntity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// First verify user exists
+	if _, ok := r.store.users[id]; !ok {
+		return nil, ports.ErrUserNotFound
+	}
+
+	return r.store.userFavourites(id), nil
+}
+
+func (r *FileUserRepositoryImpl) GetFavouritesPageByID(id string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// First verify user exists
+	if _, ok := r.store.users[id]; !ok {
+		return entities.FavouritePage{}, ports.ErrUserNotFound
+	}
+
+	return entities.PaginateFavourites(r.store.userFavourites(id), after, limit), nil
+}
@@ -0,0 +1,68 @@
+package inmemory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.APIKeyRepository = (*MapAPIKeyRepositoryImpl)(nil)
+
+// MapAPIKeyRepositoryImpl keeps API keys in a plain map. Unlike a cache it never evicts, so a key stays valid until it
+// is revoked.
+type MapAPIKeyRepositoryImpl struct {
+	keys map[string]entities.APIKeyEntity
+
+	mu sync.RWMutex
+}
+
+func NewAPIKeyRepository() *MapAPIKeyRepositoryImpl {
+	return &MapAPIKeyRepositoryImpl{keys: make(map[string]entities.APIKeyEntity)}
+}
+
+func (r *MapAPIKeyRepositoryImpl) Save(key entities.APIKeyEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.Id] = key
+	return nil
+}
+
+func (r *MapAPIKeyRepositoryImpl) GetByHash(hash string) (entities.APIKeyEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return entities.APIKeyEntity{}, ports.ErrAPIKeyNotFound
+}
+
+func (r *MapAPIKeyRepositoryImpl) GetAll() ([]entities.APIKeyEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]entities.APIKeyEntity, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	entities.SortAPIKeys(keys)
+	return keys, nil
+}
+
+func (r *MapAPIKeyRepositoryImpl) Revoke(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ports.ErrAPIKeyNotFound
+	}
+	key.RevokedAt = at
+	r.keys[id] = key
+	return nil
+}
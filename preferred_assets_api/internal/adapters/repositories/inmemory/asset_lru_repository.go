@@ -1,7 +1,6 @@
 package inmemory
 
 import (
-	"errors"
 	"fmt"
 	"sync"
 
@@ -10,10 +9,6 @@ import (
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
-var (
-	ErrAssetNotFound = errors.New("asset not found")
-)
-
 var _ ports.AssetRepository = (*LRUAssetRepositoryImpl)(nil)
 
 type LRUAssetRepositoryImpl struct {
@@ -38,15 +33,16 @@ func (r *LRUAssetRepositoryImpl) Save(asset entities.AssetEntity) (entities.Asse
 	defer r.mu.Unlock()
 
 	if err := asset.Validate(); err != nil {
-		return entities.AssetBaseEntity{}, err
+		return nil, err
 	}
-
-	val, ok := r.cache.Get(asset.GetID())
-	if !ok {
-		return entities.AssetBaseEntity{}, ErrAssetNotFound
+	if r.cache.Contains(asset.GetID()) {
+		return nil, ports.ErrAssetExists
 	}
 
-	return val, nil
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(1)
+	r.cache.Add(asset.GetID(), asset)
+	return entities.CloneAsset(asset), nil
 }
 
 func (r *LRUAssetRepositoryImpl) GetByID(id string) (entities.AssetEntity, error) {
@@ -55,9 +51,9 @@ func (r *LRUAssetRepositoryImpl) GetByID(id string) (entities.AssetEntity, error
 
 	val, ok := r.cache.Get(id)
 	if !ok {
-		return nil, ErrAssetNotFound
+		return nil, ports.ErrAssetNotFound
 	}
-	return val, nil
+	return entities.CloneAsset(val), nil
 }
 
 func (r *LRUAssetRepositoryImpl) GetByIDs(ids []string) ([]entities.AssetEntity, error) {
@@ -81,7 +77,7 @@ func (r *LRUAssetRepositoryImpl) GetAll() ([]entities.AssetEntity, error) {
 	assets := make([]entities.AssetEntity, 0, r.cache.Len())
 	for _, key := range r.cache.Keys() {
 		if val, ok := r.cache.Peek(key); ok {
-			assets = append(assets, val)
+			assets = append(assets, entities.CloneAsset(val))
 		}
 	}
 	return assets, nil
@@ -94,37 +90,79 @@ func (r *LRUAssetRepositoryImpl) GetByType(typeId entities.AssetType) ([]entitie
 	assets := make([]entities.AssetEntity, 0)
 	for _, key := range r.cache.Keys() {
 		if val, ok := r.cache.Peek(key); ok && val.GetType() == typeId {
-			assets = append(assets, val)
+			assets = append(assets, entities.CloneAsset(val))
 		}
 	}
 	return assets, nil
 }
 
-func (r *LRUAssetRepositoryImpl) Delete(id string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *LRUAssetRepositoryImpl) Query(query entities.AssetQuery) (entities.AssetPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	if !r.cache.Contains(id) {
-		return ErrAssetNotFound
+	assets := make([]entities.AssetEntity, 0, r.cache.Len())
+	for _, key := range r.cache.Keys() {
+		if val, ok := r.cache.Peek(key); ok {
+			assets = append(assets, entities.CloneAsset(val))
+		}
 	}
+	return entities.RunAssetQuery(assets, query), nil
+}
 
-	r.cache.Remove(id)
-	return nil
+func (r *LRUAssetRepositoryImpl) Delete(id string) error {
+	return r.delete(id, nil)
 }
 
-func (r *LRUAssetRepositoryImpl) Update(asset entities.AssetEntity) error {
+func (r *LRUAssetRepositoryImpl) Update(asset entities.AssetEntity) (int64, error) {
+	return r.update(asset, nil)
+}
+
+func (r *LRUAssetRepositoryImpl) CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error) {
+	return r.update(asset, &expectedVersion)
+}
+
+func (r *LRUAssetRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	return r.delete(id, &expectedVersion)
+}
+
+// update stores the asset with the next version. A nil expectedVersion skips the version check.
+// It returns the version the asset was stored at.
+func (r *LRUAssetRepositoryImpl) update(asset entities.AssetEntity, expectedVersion *int64) (int64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if !r.cache.Contains(asset.GetID()) {
-		return ErrAssetNotFound
+	stored, ok := r.cache.Peek(asset.GetID())
+	if !ok {
+		return 0, ports.ErrAssetNotFound
+	}
+	if expectedVersion != nil && stored.GetVersion() != *expectedVersion {
+		return 0, ports.ErrVersionConflict
 	}
 
 	if err := asset.Validate(); err != nil {
-		return err
+		return 0, err
 	}
 
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(stored.GetVersion() + 1)
 	r.cache.Add(asset.GetID(), asset)
+	return asset.GetVersion(), nil
+}
+
+// delete removes the asset. A nil expectedVersion skips the version check.
+func (r *LRUAssetRepositoryImpl) delete(id string, expectedVersion *int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.cache.Peek(id)
+	if !ok {
+		return ports.ErrAssetNotFound
+	}
+	if expectedVersion != nil && stored.GetVersion() != *expectedVersion {
+		return ports.ErrVersionConflict
+	}
+
+	r.cache.Remove(id)
 	return nil
 }
 
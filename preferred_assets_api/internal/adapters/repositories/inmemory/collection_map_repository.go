@@ -0,0 +1,125 @@
+package inmemory
+
+import (
+	"sync"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.CollectionRepository = (*MapCollectionRepositoryImpl)(nil)
+
+// MapCollectionRepositoryImpl keeps collections in a plain map. Unlike a cache it never evicts, so a collection only
+// goes away when it is deleted.
+type MapCollectionRepositoryImpl struct {
+	collections map[string]entities.CollectionEntity
+
+	mu sync.RWMutex
+}
+
+func NewCollectionRepository() *MapCollectionRepositoryImpl {
+	return &MapCollectionRepositoryImpl{collections: make(map[string]entities.CollectionEntity)}
+}
+
+func (r *MapCollectionRepositoryImpl) Save(collection entities.CollectionEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection = collection.Clone()
+	collection.Version = 1
+	r.collections[collection.Id] = collection
+	return nil
+}
+
+func (r *MapCollectionRepositoryImpl) GetByID(id string) (entities.CollectionEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.collections[id]
+	if !ok {
+		return entities.CollectionEntity{}, ports.ErrCollectionNotFound
+	}
+	return collection.Clone(), nil
+}
+
+func (r *MapCollectionRepositoryImpl) GetByUserID(userID string) ([]entities.CollectionEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := make([]entities.CollectionEntity, 0)
+	for _, collection := range r.collections {
+		if collection.UserId == userID {
+			collections = append(collections, collection.Clone())
+		}
+	}
+	entities.SortCollections(collections)
+	return collections, nil
+}
+
+func (r *MapCollectionRepositoryImpl) Update(collection entities.CollectionEntity) (int64, error) {
+	return r.update(collection, nil)
+}
+
+func (r *MapCollectionRepositoryImpl) CompareAndSwap(collection entities.CollectionEntity, expectedVersion int64) (int64, error) {
+	return r.update(collection, &expectedVersion)
+}
+
+// update stores the collection with the next version. A nil expectedVersion skips the version check.
+// It returns the version the collection was stored at.
+func (r *MapCollectionRepositoryImpl) update(collection entities.CollectionEntity, expectedVersion *int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.collections[collection.Id]
+	if !ok {
+		return 0, ports.ErrCollectionNotFound
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	collection = collection.Clone()
+	collection.Version = stored.Version + 1
+	r.collections[collection.Id] = collection
+	return collection.Version, nil
+}
+
+func (r *MapCollectionRepositoryImpl) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.collections[id]; !ok {
+		return ports.ErrCollectionNotFound
+	}
+	delete(r.collections, id)
+	return nil
+}
+
+func (r *MapCollectionRepositoryImpl) RemoveAsset(userID, assetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, collection := range r.collections {
+		if collection.UserId != userID {
+			continue
+		}
+		collection = collection.Clone()
+		if collection.RemoveAsset(assetID) {
+			collection.Version++
+			r.collections[collection.Id] = collection
+		}
+	}
+	return nil
+}
+
+func (r *MapCollectionRepositoryImpl) DeleteByUserID(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, collection := range r.collections {
+		if collection.UserId == userID {
+			delete(r.collections, id)
+		}
+	}
+	return nil
+}
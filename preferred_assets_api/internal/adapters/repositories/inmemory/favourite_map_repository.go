@@ -0,0 +1,242 @@
+package inmemory
+
+import (
+	"sync"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.FavouriteRepository = (*MapFavouriteRepositoryImpl)(nil)
+
+// MapFavouriteRepositoryImpl keeps favourites in plain maps. Unlike a cache it never evicts, so a favourite only goes
+// away when it is deleted.
+type MapFavouriteRepositoryImpl struct {
+	// userAssets maps user id -> asset id -> favourite
+	userAssets map[string]map[string]entities.FavouriteEntity
+
+	// assetUsers is the reverse index asset id -> ids of the users who favourited it
+	assetUsers map[string]map[string]struct{}
+	tombstones map[string]map[string]entities.FavouriteTombstoneEntity
+
+	mu sync.RWMutex
+}
+
+func NewFavouriteRepository() *MapFavouriteRepositoryImpl {
+	return &MapFavouriteRepositoryImpl{
+		userAssets: make(map[string]map[string]entities.FavouriteEntity),
+		assetUsers: make(map[string]map[string]struct{}),
+		tombstones: make(map[string]map[string]entities.FavouriteTombstoneEntity),
+	}
+}
+
+func (c *MapFavouriteRepositoryImpl) Add(f entities.FavouriteEntity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.add(f) {
+		return ports.ErrFavouriteExists
+	}
+	return nil
+}
+
+func (c *MapFavouriteRepositoryImpl) Delete(userID, assetID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.delete(userID, assetID)
+	return nil
+}
+
+// ApplyChanges implements ports.FavouriteRepository.
+func (c *MapFavouriteRepositoryImpl) ApplyChanges(changes []entities.FavouriteChange) ([]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	applied := make([]bool, len(changes))
+	for i, change := range changes {
+		if change.Remove {
+			applied[i] = c.delete(change.Favourite.UserId, change.Favourite.AssetId)
+		} else {
+			applied[i] = c.add(change.Favourite)
+		}
+	}
+	return applied, nil
+}
+
+// add stores the favourite unless the user already has it, reporting whether it did. Callers must hold c.mu.
+func (c *MapFavouriteRepositoryImpl) add(f entities.FavouriteEntity) bool {
+	userAssets, ok := c.userAssets[f.UserId]
+	if !ok {
+		userAssets = make(map[string]entities.FavouriteEntity)
+		c.userAssets[f.UserId] = userAssets
+	}
+	if _, exists := userAssets[f.AssetId]; exists {
+		return false
+	}
+	userAssets[f.AssetId] = f
+
+	// Update reverse index
+	users, ok := c.assetUsers[f.AssetId]
+	if !ok {
+		users = make(map[string]struct{})
+		c.assetUsers[f.AssetId] = users
+	}
+	users[f.UserId] = struct{}{}
+
+	return true
+}
+
+// delete removes the user's favourite of the asset, reporting whether there was one. Callers must hold c.mu.
+func (c *MapFavouriteRepositoryImpl) delete(userID, assetID string) bool {
+	existed := false
+	if userAssets, ok := c.userAssets[userID]; ok {
+		_, existed = userAssets[assetID]
+		delete(userAssets, assetID)
+		// If user has no more favourites, remove the entry entirely
+		if len(userAssets) == 0 {
+			delete(c.userAssets, userID)
+		}
+	}
+
+	// Update reverse index
+	c.removeAssetUser(assetID, userID)
+
+	return existed
+}
+
+func (c *MapFavouriteRepositoryImpl) GetByUserID(userID string) ([]entities.FavouriteEntity, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	userAssets := c.userAssets[userID]
+	favourites := make([]entities.FavouriteEntity, 0, len(userAssets))
+	for _, f := range userAssets {
+		favourites = append(favourites, f)
+	}
+	entities.SortFavourites(favourites)
+	return favourites, nil
+}
+
+func (c *MapFavouriteRepositoryImpl) GetPageByUserID(userID string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	favourites, err := c.GetByUserID(userID)
+	if err != nil {
+		return entities.FavouritePage{}, err
+	}
+	return entities.PaginateFavourites(favourites, after, limit), nil
+}
+
+func (c *MapFavouriteRepositoryImpl) Exists(userID, assetID string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, exists := c.userAssets[userID][assetID]
+	return exists, nil
+}
+
+func (c *MapFavouriteRepositoryImpl) Get(userID, assetID string) (entities.FavouriteEntity, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if f, exists := c.userAssets[userID][assetID]; exists {
+		return f, nil
+	}
+
+	return entities.FavouriteEntity{}, ports.ErrFavouriteNotFound
+}
+
+func (c *MapFavouriteRepositoryImpl) Update(f entities.FavouriteEntity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing, exists := c.userAssets[f.UserId][f.AssetId]
+	if !exists {
+		return ports.ErrFavouriteNotFound
+	}
+
+	// The creation time is part of the favourite's identity for pagination and never changes
+	f.CreatedAt = existing.CreatedAt
+	c.userAssets[f.UserId][f.AssetId] = f
+	return nil
+}
+
+func (c *MapFavouriteRepositoryImpl) DeleteByAssetID(assetID string, tombstone *entities.FavouriteTombstoneEntity) ([]entities.FavouriteEntity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := make([]entities.FavouriteEntity, 0, len(c.assetUsers[assetID]))
+	for userID := range c.assetUsers[assetID] {
+		f, exists := c.userAssets[userID][assetID]
+		if !exists {
+			continue
+		}
+		c.delete(userID, assetID)
+		removed = append(removed, f)
+
+		if tombstone != nil {
+			c.putTombstone(tombstone.TombstoneFor(f))
+		}
+	}
+	delete(c.assetUsers, assetID)
+
+	entities.SortFavourites(removed)
+	return removed, nil
+}
+
+func (c *MapFavouriteRepositoryImpl) DeleteByUserID(userID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for assetID := range c.userAssets[userID] {
+		c.delete(userID, assetID)
+	}
+	delete(c.tombstones, userID)
+	return nil
+}
+
+func (c *MapFavouriteRepositoryImpl) GetTombstonesByUserID(userID string) ([]entities.FavouriteTombstoneEntity, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tombstones := make([]entities.FavouriteTombstoneEntity, 0, len(c.tombstones[userID]))
+	for _, t := range c.tombstones[userID] {
+		tombstones = append(tombstones, t)
+	}
+	entities.SortFavouriteTombstones(tombstones)
+	return tombstones, nil
+}
+
+func (c *MapFavouriteRepositoryImpl) DeleteTombstone(userID, assetID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if userTombstones, ok := c.tombstones[userID]; ok {
+		delete(userTombstones, assetID)
+		if len(userTombstones) == 0 {
+			delete(c.tombstones, userID)
+		}
+	}
+	return nil
+}
+
+// putTombstone stores t, replacing an earlier tombstone of the same asset. Callers must hold c.mu.
+func (c *MapFavouriteRepositoryImpl) putTombstone(t entities.FavouriteTombstoneEntity) {
+	userTombstones, ok := c.tombstones[t.UserId]
+	if !ok {
+		userTombstones = make(map[string]entities.FavouriteTombstoneEntity)
+		c.tombstones[t.UserId] = userTombstones
+	}
+	userTombstones[t.AssetId] = t
+}
+
+// removeAssetUser drops the user from the reverse index of the asset. Callers must hold c.mu.
+func (c *MapFavouriteRepositoryImpl) removeAssetUser(assetID, userID string) {
+	users, ok := c.assetUsers[assetID]
+	if !ok {
+		return
+	}
+	delete(users, userID)
+	if len(users) == 0 {
+		delete(c.assetUsers, assetID)
+	}
+}
@@ -0,0 +1,156 @@
+package inmemory
+
+import (
+	"sync"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.UserRepository = (*MapUserRepositoryImpl)(nil)
+
+// MapUserRepositoryImpl keeps users in a plain map. Unlike a cache it never evicts, so a user only goes away when it
+// is deleted, and a provisioned subject keeps its user and favourites.
+type MapUserRepositoryImpl struct {
+	users          map[string]entities.UserEntity
+	favouriteRepo  ports.FavouriteRepository
+	collectionRepo ports.CollectionRepository
+	mu             sync.RWMutex
+}
+
+func NewUserRepository(favouriteRepo ports.FavouriteRepository, collectionRepo ports.CollectionRepository) *MapUserRepositoryImpl {
+	return &MapUserRepositoryImpl{users: make(map[string]entities.UserEntity),
+		favouriteRepo:  favouriteRepo,
+		collectionRepo: collectionRepo}
+}
+
+func (r *MapUserRepositoryImpl) Save(u entities.UserEntity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u.Version = 1
+	r.users[u.Id] = u
+
+	return nil
+}
+
+func (r *MapUserRepositoryImpl) GetByID(id string) (entities.UserEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return entities.UserEntity{}, ports.ErrUserNotFound
+	}
+
+	return u, nil
+}
+
+func (r *MapUserRepositoryImpl) GetBySubject(subject string) (entities.UserEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.Subject == subject {
+			return u, nil
+		}
+	}
+	return entities.UserEntity{}, ports.ErrUserNotFound
+}
+
+func (r *MapUserRepositoryImpl) GetAll() ([]entities.UserEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]entities.UserEntity, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r *MapUserRepositoryImpl) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.users, id)
+
+	return r.deleteUserData(id)
+}
+
+func (r *MapUserRepositoryImpl) Update(u entities.UserEntity) (int64, error) {
+	return r.update(u, nil)
+}
+
+func (r *MapUserRepositoryImpl) CompareAndSwap(u entities.UserEntity, expectedVersion int64) (int64, error) {
+	return r.update(u, &expectedVersion)
+}
+
+func (r *MapUserRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[id]
+	if !ok {
+		return ports.ErrUserNotFound
+	}
+	if stored.Version != expectedVersion {
+		return ports.ErrVersionConflict
+	}
+
+	delete(r.users, id)
+	return r.deleteUserData(id)
+}
+
+// deleteUserData removes what belongs to a deleted user
+func (r *MapUserRepositoryImpl) deleteUserData(id string) error {
+	if err := r.favouriteRepo.DeleteByUserID(id); err != nil {
+		return err
+	}
+	return r.collectionRepo.DeleteByUserID(id)
+}
+
+// update stores the user with the next version. A nil expectedVersion skips the version check.
+// It returns the version the user was stored at.
+func (r *MapUserRepositoryImpl) update(u entities.UserEntity, expectedVersion *int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[u.Id]
+	if !ok {
+		return 0, ports.ErrUserNotFound
+	}
+	if expectedVersion != nil && stored.Version != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	u.Version = stored.Version + 1
+	r.users[u.Id] = u
+	return u.Version, nil
+}
+
+func (r *MapUserRepositoryImpl) GetFavouritesByID(id string) ([]entities.FavouriteEntity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// First verify user exists
+	if _, ok := r.users[id]; !ok {
+		return nil, ports.ErrUserNotFound
+	}
+
+	// Get favourites directly from favourite repository
+	return r.favouriteRepo.GetByUserID(id)
+}
+
+func (r *MapUserRepositoryImpl) GetFavouritesPageByID(id string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// First verify user exists
+	if _, ok := r.users[id]; !ok {
+		return entities.FavouritePage{}, ports.ErrUserNotFound
+	}
+
+	return r.favouriteRepo.GetPageByUserID(id, after, limit)
+}
@@ -0,0 +1,65 @@
+package inmemory_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_DeleteCascades(t *testing.T) {
+	// Arrange
+	favourites := inmemory.NewFavouriteRepository()
+	collections := inmemory.NewCollectionRepository()
+	users := inmemory.NewUserRepository(favourites, collections)
+	now := time.Now().UTC()
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1"}))
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u2"}))
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "Campaign", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c2", UserId: "u2", Name: "Campaign", CreatedAt: now, UpdatedAt: now}))
+
+	// Act
+	err := users.Delete("u1")
+
+	// Assert
+	require.NoError(t, err)
+	favs, err := favourites.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, favs)
+	got, err := collections.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, got)
+	got, err = collections.GetByUserID("u2")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+}
+
+func TestUserRepository_NeverEvicts(t *testing.T) {
+	// Arrange
+	favourites := inmemory.NewFavouriteRepository()
+	users := inmemory.NewUserRepository(favourites, inmemory.NewCollectionRepository())
+	now := time.Now().UTC()
+	const count = 500
+
+	// Act
+	for i := range count {
+		id := fmt.Sprintf("u%d", i)
+		require.NoError(t, users.Save(entities.UserEntity{Id: id, Subject: "sub-" + id}))
+		require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: id, AssetId: "a1", CreatedAt: now}))
+	}
+
+	// Assert
+	all, err := users.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, count)
+	first, err := users.GetBySubject("sub-u0")
+	require.NoError(t, err)
+	require.Equal(t, "u0", first.Id)
+	favs, err := users.GetFavouritesByID("u0")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+}
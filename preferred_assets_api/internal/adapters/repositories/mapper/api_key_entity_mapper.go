@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// APIKeyEntityFromDomain converts the domain key and the hash of its secret to an entity
+func APIKeyEntityFromDomain(key domain.APIKey, hash string) entities.APIKeyEntity {
+	return entities.APIKeyEntity{
+		Id:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Hash:      hash,
+		Roles:     safeMarshalToString(key.Roles, "[]", "roles of api key "+key.ID),
+		UserIds:   safeMarshalToString(key.UserIDs, "[]", "users of api key "+key.ID),
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// APIKeyEntityToDomain converts an entity to the domain key, leaving out the hash
+func APIKeyEntityToDomain(e entities.APIKeyEntity) domain.APIKey {
+	return domain.APIKey{
+		ID:        e.Id,
+		Name:      e.Name,
+		Prefix:    e.Prefix,
+		Roles:     safeUnmarshalStringArray(e.Roles, e.Id, "api key roles"),
+		UserIDs:   safeUnmarshalStringArray(e.UserIds, e.Id, "api key users"),
+		CreatedAt: e.CreatedAt,
+		RevokedAt: e.RevokedAt,
+	}
+}
+
+// APIKeyEntityToDomainList converts a slice of entities to domain keys
+func APIKeyEntityToDomainList(keys []entities.APIKeyEntity) []domain.APIKey {
+	result := make([]domain.APIKey, len(keys))
+	for i, k := range keys {
+		result[i] = APIKeyEntityToDomain(k)
+	}
+	return result
+}
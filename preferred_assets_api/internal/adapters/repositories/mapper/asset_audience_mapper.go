@@ -1,22 +1,24 @@
 package mapper
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 )
 
-// AudienceEntityToDomain converts entity to domain model
+// AudienceEntityToDomain converts entity to domain model. Audiences stored before criteria existed have their
+// single attributes turned into criteria.
 func AudienceEntityToDomain(a *entities.AudienceEntity) *domain.Audience {
 	if a == nil {
 		return nil
 	}
 	return &domain.Audience{
-		AssetBase:       AssetBaseEntityToDomain(a.AssetBaseEntity),
-		Gender:          a.Gender,
-		BirthCountry:    a.BirthCountry,
-		AgeGroup:        a.AgeGroup,
-		HoursSocial:     a.HoursSocial,
-		PurchasesLastMo: a.PurchasesLastMo,
+		AssetBase:          AssetBaseEntityToDomain(a.AssetBaseEntity),
+		Criteria:           audienceCriteriaEntityToDomain(a),
+		AverageHoursSocial: a.HoursSocial,
 	}
 }
 
@@ -25,12 +27,68 @@ func AudienceEntityFromDomain(a *domain.Audience) *entities.AudienceEntity {
 	if a == nil {
 		return nil
 	}
-	return &entities.AudienceEntity{
+	entity := &entities.AudienceEntity{
 		AssetBaseEntity: *AssetBaseEntityFromDomain(a.AssetBase),
-		Gender:          a.Gender,
-		BirthCountry:    a.BirthCountry,
-		AgeGroup:        a.AgeGroup,
-		HoursSocial:     a.HoursSocial,
-		PurchasesLastMo: a.PurchasesLastMo,
+		HoursSocial:     a.AverageHoursSocial,
+	}
+	if a.Criteria != nil {
+		entity.Criteria = safeMarshalToString(audienceCriterionEntityFromDomain(*a.Criteria), "", fmt.Sprintf("criteria for audience %s", a.GetID()))
+	}
+	return entity
+}
+
+func audienceCriterionEntityFromDomain(c domain.AudienceCriterion) entities.AudienceCriterionEntity {
+	entity := entities.AudienceCriterionEntity{
+		Operator: string(c.Operator),
+		Field:    string(c.Field),
+		Values:   c.Values,
+		Value:    c.Value,
+		Min:      c.Min,
+		Max:      c.Max,
+	}
+	for _, criterion := range c.Criteria {
+		entity.Criteria = append(entity.Criteria, audienceCriterionEntityFromDomain(criterion))
+	}
+	return entity
+}
+
+// audienceCriteriaEntityToDomain decodes the stored criteria of an audience, or turns the single attributes of an
+// audience stored before criteria existed into criteria
+func audienceCriteriaEntityToDomain(a *entities.AudienceEntity) *domain.AudienceCriterion {
+	if a.Criteria == "" {
+		attributes := domain.AudienceAttributes{
+			Gender:          a.Gender,
+			BirthCountry:    a.BirthCountry,
+			AgeGroup:        a.AgeGroup,
+			PurchasesLastMo: a.PurchasesLastMo,
+		}
+		criteria, err := attributes.Criteria()
+		if err != nil {
+			log.Printf("Warning: failed to turn the attributes of asset %s into criteria: %v", a.ID, err)
+			return nil
+		}
+		return criteria
+	}
+	var stored entities.AudienceCriterionEntity
+	if err := json.Unmarshal([]byte(a.Criteria), &stored); err != nil {
+		log.Printf("Warning: failed to unmarshal criteria for asset %s: %v", a.ID, err)
+		return nil
+	}
+	criterion := audienceCriterionEntityToDomain(stored)
+	return &criterion
+}
+
+func audienceCriterionEntityToDomain(e entities.AudienceCriterionEntity) domain.AudienceCriterion {
+	criterion := domain.AudienceCriterion{
+		Operator: domain.AudienceOperator(e.Operator),
+		Field:    domain.AudienceField(e.Field),
+		Values:   e.Values,
+		Value:    e.Value,
+		Min:      e.Min,
+		Max:      e.Max,
+	}
+	for _, child := range e.Criteria {
+		criterion.Criteria = append(criterion.Criteria, audienceCriterionEntityToDomain(child))
 	}
+	return criterion
 }
@@ -1,6 +1,7 @@
 package mapper_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -24,11 +25,7 @@ func TestAudienceEntityMapping(t *testing.T) {
 				CreatedAt:   created,
 				UpdatedAt:   updated,
 			},
-			Gender:          "female",
-			BirthCountry:    "Canada",
-			AgeGroup:        "25-34",
-			HoursSocial:     4,
-			PurchasesLastMo: 12,
+			Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
 		}
 
 		// Act
@@ -39,17 +36,72 @@ func TestAudienceEntityMapping(t *testing.T) {
 		if entityObj.ID != domainObj.ID || backToDomain.ID != domainObj.ID {
 			t.Errorf("IDs do not match")
 		}
-		if backToDomain.Gender != domainObj.Gender || backToDomain.BirthCountry != domainObj.BirthCountry {
-			t.Errorf("Fields did not map correctly")
+		if entityObj.Gender != "" || entityObj.BirthCountry != "" {
+			t.Errorf("single attributes were stored next to the criteria")
 		}
-		if backToDomain.HoursSocial != domainObj.HoursSocial || backToDomain.PurchasesLastMo != domainObj.PurchasesLastMo {
-			t.Errorf("Numeric fields did not map correctly")
+		if !reflect.DeepEqual(backToDomain.Criteria, domainObj.Criteria) {
+			t.Errorf("Fields did not map correctly")
 		}
 		if !backToDomain.CreatedAt.Equal(domainObj.CreatedAt) || !backToDomain.UpdatedAt.Equal(domainObj.UpdatedAt) {
 			t.Errorf("Timestamps did not map correctly")
 		}
 	})
 
+	t.Run("happy_path: criteria round trip", func(t *testing.T) {
+		// Arrange
+		min, max, hours := 24.0, 35.0, 3.0
+		domainObj := &domain.Audience{
+			AssetBase: domain.AssetBase{ID: "aud2", Type: domain.AssetTypeAudience, Title: "Young men"},
+			Criteria: &domain.AudienceCriterion{Operator: domain.AudienceAnd, Criteria: []domain.AudienceCriterion{
+				{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"male"}},
+				{Operator: domain.AudienceBetween, Field: domain.AudienceFieldAge, Min: &min, Max: &max},
+				{Operator: domain.AudienceGt, Field: domain.AudienceFieldHoursSocial, Value: &hours},
+			}},
+		}
+
+		// Act
+		entityObj := mapper.AudienceEntityFromDomain(domainObj)
+		backToDomain := mapper.AudienceEntityToDomain(entityObj)
+
+		// Assert
+		if entityObj.Criteria == "" {
+			t.Fatalf("criteria were not stored")
+		}
+		if !reflect.DeepEqual(backToDomain.Criteria, domainObj.Criteria) {
+			t.Errorf("criteria did not map correctly: %+v", backToDomain.Criteria)
+		}
+	})
+
+	t.Run("happy_path: audience stored before criteria existed", func(t *testing.T) {
+		// Arrange
+		entityObj := &entities.AudienceEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "aud3"}, Gender: "female", BirthCountry: "gr"}
+
+		// Act
+		domainObj := mapper.AudienceEntityToDomain(entityObj)
+
+		// Assert
+		want := &domain.AudienceCriterion{Operator: domain.AudienceAnd, Criteria: []domain.AudienceCriterion{
+			{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
+			{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"GR"}},
+		}}
+		if !reflect.DeepEqual(domainObj.Criteria, want) {
+			t.Errorf("expected the attributes as criteria, got %+v", domainObj.Criteria)
+		}
+	})
+
+	t.Run("unhappy_path: stored birth country is not a country code", func(t *testing.T) {
+		// Arrange
+		entityObj := &entities.AudienceEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "aud4"}, BirthCountry: "Narnia"}
+
+		// Act
+		domainObj := mapper.AudienceEntityToDomain(entityObj)
+
+		// Assert
+		if domainObj.Criteria != nil {
+			t.Errorf("expected no criteria, got %+v", domainObj.Criteria)
+		}
+	})
+
 	t.Run("unhappy_path: nil domain input", func(t *testing.T) {
 		// Arrange
 		var domainObj *domain.Audience = nil
@@ -103,11 +155,7 @@ func TestAudienceEntityMapping_List(t *testing.T) {
 					CreatedAt:   created,
 					UpdatedAt:   updated,
 				},
-				Gender:          "female",
-				BirthCountry:    "Canada",
-				AgeGroup:        "25-34",
-				HoursSocial:     4,
-				PurchasesLastMo: 12,
+				Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
 			},
 			{
 				AssetBase: domain.AssetBase{
@@ -118,11 +166,7 @@ func TestAudienceEntityMapping_List(t *testing.T) {
 					CreatedAt:   created,
 					UpdatedAt:   updated,
 				},
-				Gender:          "male",
-				BirthCountry:    "USA",
-				AgeGroup:        "35-44",
-				HoursSocial:     2,
-				PurchasesLastMo: 5,
+				Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"US"}},
 			},
 		}
 
@@ -139,7 +183,7 @@ func TestAudienceEntityMapping_List(t *testing.T) {
 
 		// Assert
 		for i := range domainSlice {
-			if backToDomain[i].ID != domainSlice[i].ID || backToDomain[i].Gender != domainSlice[i].Gender {
+			if backToDomain[i].ID != domainSlice[i].ID || !reflect.DeepEqual(backToDomain[i].Criteria, domainSlice[i].Criteria) {
 				t.Errorf("Mismatch in element %d: expected %+v got %+v", i, domainSlice[i], backToDomain[i])
 			}
 		}
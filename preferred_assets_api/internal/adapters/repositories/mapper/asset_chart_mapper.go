@@ -17,7 +17,11 @@ func ChartEntityToDomain(e *entities.ChartEntity) *domain.Chart {
 
 	return &domain.Chart{
 		AssetBase:  AssetBaseEntityToDomain(e.AssetBaseEntity),
+		Kind:       domain.ChartKind(e.Kind),
 		AxesTitles: safeUnmarshalStringArray(e.AxesTitles, e.ID, "axes titles"),
+		AxesUnits:  safeUnmarshalStringArray(e.AxesUnits, e.ID, "axes units"),
+		Categories: safeUnmarshalStringArray(e.Categories, e.ID, "categories"),
+		Series:     chartSeriesEntitiesToDomain(e.Series, e.ID),
 		Data:       safeUnmarshalFloatArray(e.Data, e.ID, "chart data"),
 	}
 }
@@ -30,11 +34,43 @@ func ChartEntityFromDomain(c *domain.Chart) *entities.ChartEntity {
 
 	return &entities.ChartEntity{
 		AssetBaseEntity: *AssetBaseEntityFromDomain(c.AssetBase),
+		Kind:            string(c.Kind),
 		AxesTitles:      safeMarshalToString(c.AxesTitles, "[]", fmt.Sprintf("axes titles for chart %s", c.GetID())),
+		AxesUnits:       safeMarshalToString(c.AxesUnits, "[]", fmt.Sprintf("axes units for chart %s", c.GetID())),
+		Categories:      safeMarshalToString(c.Categories, "[]", fmt.Sprintf("categories for chart %s", c.GetID())),
+		Series:          safeMarshalToString(chartSeriesEntitiesFromDomain(c.Series), "[]", fmt.Sprintf("series for chart %s", c.GetID())),
 		Data:            safeMarshalToString(c.Data, "[]", fmt.Sprintf("chart data for chart %s", c.GetID())),
 	}
 }
 
+func chartSeriesEntitiesFromDomain(series []domain.ChartSeries) []entities.ChartSeriesEntity {
+	result := make([]entities.ChartSeriesEntity, len(series))
+	for i, s := range series {
+		result[i] = entities.ChartSeriesEntity{Name: s.Name, X: s.X, Values: s.Values}
+	}
+	return result
+}
+
+// chartSeriesEntitiesToDomain decodes the stored series of a chart; charts stored before series existed have none
+func chartSeriesEntitiesToDomain(jsonStr, assetID string) []domain.ChartSeries {
+	if jsonStr == "" {
+		return nil
+	}
+	var stored []entities.ChartSeriesEntity
+	if err := json.Unmarshal([]byte(jsonStr), &stored); err != nil {
+		log.Printf("Warning: failed to unmarshal series for asset %s: %v", assetID, err)
+		return nil
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+	series := make([]domain.ChartSeries, len(stored))
+	for i, s := range stored {
+		series[i] = domain.ChartSeries{Name: s.Name, X: s.X, Values: s.Values}
+	}
+	return series
+}
+
 func safeUnmarshalStringArray(jsonStr, assetID, fieldName string) []string {
 	var result []string
 	if jsonStr == "" {
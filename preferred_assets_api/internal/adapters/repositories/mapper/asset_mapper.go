@@ -12,16 +12,21 @@ func AssetEntityToDomain(entity entities.AssetEntity) (domain.Asset, error) {
 		return nil, nil
 	}
 
-	switch entity.GetType() {
-	case entities.AssetTypeAudience:
-		return AudienceEntityToDomain(entity.(*entities.AudienceEntity)), nil
-	case entities.AssetTypeChart:
-		return ChartEntityToDomain(entity.(*entities.ChartEntity)), nil
-	case entities.AssetTypeInsight:
-		return InsightEntityToDomain(entity.(*entities.InsightEntity)), nil
-	default:
-		return nil, fmt.Errorf("unknown asset type: %v", entity.GetType())
+	switch e := entity.(type) {
+	case *entities.AudienceEntity:
+		if e.GetType() == entities.AssetTypeAudience {
+			return AudienceEntityToDomain(e), nil
+		}
+	case *entities.ChartEntity:
+		if e.GetType() == entities.AssetTypeChart {
+			return ChartEntityToDomain(e), nil
+		}
+	case *entities.InsightEntity:
+		if e.GetType() == entities.AssetTypeInsight {
+			return InsightEntityToDomain(e), nil
+		}
 	}
+	return nil, fmt.Errorf("unknown asset type: %v", entity.GetType())
 }
 
 func AssetEntityFromDomain(asset domain.Asset) (entities.AssetEntity, error) {
@@ -49,6 +54,7 @@ func AssetBaseEntityFromDomain(a domain.AssetBase) *entities.AssetBaseEntity {
 		Description: a.Description,
 		CreatedAt:   a.CreatedAt,
 		UpdatedAt:   a.UpdatedAt,
+		Version:     a.Version,
 	}
 }
 
@@ -61,5 +67,6 @@ func AssetBaseEntityToDomain(e entities.AssetBaseEntity) domain.AssetBase {
 		Description: e.Description,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
+		Version:     e.Version,
 	}
 }
@@ -0,0 +1,98 @@
+package mapper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// assetCursorToken is the wire format of an asset cursor before base64 encoding.
+// It records the ordering it was issued for, so it cannot be replayed against a different one.
+type assetCursorToken struct {
+	SortBy     entities.AssetSortField `json:"s"`
+	Descending bool                    `json:"d,omitempty"`
+	CreatedAt  time.Time               `json:"c"`
+	UpdatedAt  time.Time               `json:"u"`
+	Title      string                  `json:"t"`
+	ID         string                  `json:"i"`
+}
+
+// AssetQueryToEntity maps a domain query to a repository query, decoding its cursor
+func AssetQueryToEntity(q domain.AssetQuery) (entities.AssetQuery, error) {
+	query := entities.AssetQuery{
+		TitleContains: q.Title,
+		CreatedFrom:   q.CreatedFrom,
+		CreatedTo:     q.CreatedTo,
+		UpdatedFrom:   q.UpdatedFrom,
+		UpdatedTo:     q.UpdatedTo,
+		SortBy:        entities.AssetSortField(q.SortBy),
+		Descending:    q.Descending,
+		Limit:         q.Limit,
+	}
+	if q.HasAudienceFilters() {
+		query.MatchesAudience = func(a *entities.AudienceEntity) bool {
+			return q.MatchesAudience(AudienceEntityToDomain(a))
+		}
+	}
+	if query.SortBy == "" {
+		query.SortBy = entities.AssetSortCreatedAt
+	}
+	if q.Type != nil {
+		assetType := entities.AssetType(*q.Type)
+		query.Type = &assetType
+	}
+
+	after, err := assetCursorFromToken(q.Cursor, query)
+	if err != nil {
+		return entities.AssetQuery{}, err
+	}
+	query.After = after
+	return query, nil
+}
+
+// AssetCursorToToken encodes a cursor of the given query as an opaque URL-safe token, or "" for a nil cursor
+func AssetCursorToToken(c *entities.AssetCursor, q entities.AssetQuery) string {
+	if c == nil {
+		return ""
+	}
+	bytes, err := json.Marshal(assetCursorToken{
+		SortBy:     q.SortBy,
+		Descending: q.Descending,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+		Title:      c.Title,
+		ID:         c.ID,
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func assetCursorFromToken(token string, q entities.AssetQuery) (*entities.AssetCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	var t assetCursorToken
+	if err := json.Unmarshal(bytes, &t); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+	if t.ID == "" {
+		return nil, domain.ErrInvalidCursor
+	}
+	if t.SortBy != q.SortBy || t.Descending != q.Descending {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", domain.ErrInvalidCursor)
+	}
+
+	return &entities.AssetCursor{CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt, Title: t.Title, ID: t.ID}, nil
+}
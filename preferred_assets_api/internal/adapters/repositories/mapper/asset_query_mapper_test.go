@@ -0,0 +1,95 @@
+package mapper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+func TestAssetQueryToEntity(t *testing.T) {
+	// Arrange
+	chart := domain.AssetTypeChart
+	cursor := &entities.AssetCursor{CreatedAt: time.Date(2025, 10, 30, 15, 4, 5, 0, time.UTC), Title: "Sales", ID: "chart-1"}
+	token := mapper.AssetCursorToToken(cursor, entities.AssetQuery{SortBy: entities.AssetSortCreatedAt})
+
+	// Act
+	query, err := mapper.AssetQueryToEntity(domain.AssetQuery{Type: &chart, Title: "sales", Limit: 10, Cursor: token})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Type == nil || *query.Type != entities.AssetTypeChart {
+		t.Errorf("expected chart type, got %v", query.Type)
+	}
+	if query.SortBy != entities.AssetSortCreatedAt {
+		t.Errorf("expected default sort created_at, got %q", query.SortBy)
+	}
+	if query.TitleContains != "sales" || query.Limit != 10 {
+		t.Errorf("unexpected query %+v", query)
+	}
+	if query.After == nil || query.After.ID != "chart-1" || !query.After.CreatedAt.Equal(cursor.CreatedAt) {
+		t.Errorf("expected cursor %+v, got %+v", cursor, query.After)
+	}
+}
+
+func TestAssetQueryToEntity_AudienceFilters(t *testing.T) {
+	// Arrange
+	legacy := &entities.AudienceEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "aud-1"}, Gender: "female", BirthCountry: "GR"}
+	criteria := &entities.AudienceEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "aud-2"},
+		Criteria: `{"op":"in","field":"gender","values":["female","non-binary"]}`}
+
+	// Act
+	unfiltered, err := mapper.AssetQueryToEntity(domain.AssetQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query, err := mapper.AssetQueryToEntity(domain.AssetQuery{Gender: "female"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if unfiltered.HasAudienceFilters() {
+		t.Errorf("expected no audience filters")
+	}
+	if !query.HasAudienceFilters() || !query.MatchesAudience(legacy) || !query.MatchesAudience(criteria) {
+		t.Errorf("expected both audiences of women to match")
+	}
+	male, _ := mapper.AssetQueryToEntity(domain.AssetQuery{Gender: "male"})
+	if male.MatchesAudience(legacy) || male.MatchesAudience(criteria) {
+		t.Errorf("expected neither audience to match men")
+	}
+}
+
+func TestAssetQueryToEntity_InvalidCursor(t *testing.T) {
+	other := mapper.AssetCursorToToken(&entities.AssetCursor{ID: "a"}, entities.AssetQuery{SortBy: entities.AssetSortTitle})
+
+	tests := []struct {
+		name  string
+		query domain.AssetQuery
+	}{
+		{"not base64", domain.AssetQuery{Cursor: "%%%"}},
+		{"not json", domain.AssetQuery{Cursor: "bm90IGpzb24"}},
+		{"different sort field", domain.AssetQuery{SortBy: domain.AssetSortCreatedAt, Cursor: other}},
+		{"different direction", domain.AssetQuery{SortBy: domain.AssetSortTitle, Descending: true, Cursor: other}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mapper.AssetQueryToEntity(tt.query); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAssetCursorToToken_Nil(t *testing.T) {
+	if token := mapper.AssetCursorToToken(nil, entities.AssetQuery{}); token != "" {
+		t.Errorf("expected empty token for nil cursor, got %q", token)
+	}
+}
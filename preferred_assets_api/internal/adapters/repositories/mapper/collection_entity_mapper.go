@@ -0,0 +1,39 @@
+package mapper
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// CollectionEntityFromDomain maps a domain collection to its entity, without the loaded favourites
+func CollectionEntityFromDomain(c domain.Collection) entities.CollectionEntity {
+	return entities.CollectionEntity{
+		Id:        c.ID,
+		UserId:    c.UserID,
+		Name:      c.Name,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Version:   c.Version,
+		AssetIds:  append([]string{}, c.AssetIDs...),
+	}
+}
+
+func CollectionEntityToDomain(e entities.CollectionEntity) domain.Collection {
+	return domain.Collection{
+		ID:        e.Id,
+		UserID:    e.UserId,
+		Name:      e.Name,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+		Version:   e.Version,
+		AssetIDs:  append([]string{}, e.AssetIds...),
+	}
+}
+
+func CollectionEntityToDomainList(collections []entities.CollectionEntity) []domain.Collection {
+	result := make([]domain.Collection, len(collections))
+	for i, c := range collections {
+		result[i] = CollectionEntityToDomain(c)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// favouriteCursorToken is the wire format of a favourites cursor before base64 encoding
+type favouriteCursorToken struct {
+	CreatedAt time.Time `json:"t"`
+	AssetID   string    `json:"a"`
+}
+
+// FavouriteCursorToToken encodes a cursor as an opaque URL-safe token, or "" for a nil cursor
+func FavouriteCursorToToken(c *entities.FavouriteCursor) string {
+	if c == nil {
+		return ""
+	}
+	bytes, err := json.Marshal(favouriteCursorToken{CreatedAt: c.CreatedAt, AssetID: c.AssetId})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// FavouriteCursorFromToken decodes a token produced by FavouriteCursorToToken. An empty token yields a nil cursor.
+func FavouriteCursorFromToken(token string) (*entities.FavouriteCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+
+	var t favouriteCursorToken
+	if err := json.Unmarshal(bytes, &t); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+	if t.AssetID == "" || t.CreatedAt.IsZero() {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	return &entities.FavouriteCursor{CreatedAt: t.CreatedAt, AssetId: t.AssetID}, nil
+}
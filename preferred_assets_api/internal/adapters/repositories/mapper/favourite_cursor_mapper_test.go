@@ -0,0 +1,52 @@
+package mapper_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+func TestFavouriteCursorToken_RoundTrip(t *testing.T) {
+	// Arrange
+	cursor := &entities.FavouriteCursor{CreatedAt: time.Date(2025, 10, 30, 15, 4, 5, 123, time.UTC), AssetId: "asset_456"}
+
+	// Act
+	token := mapper.FavouriteCursorToToken(cursor)
+	decoded, err := mapper.FavouriteCursorFromToken(token)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.AssetId != cursor.AssetId || !decoded.CreatedAt.Equal(cursor.CreatedAt) {
+		t.Errorf("expected %+v, got %+v", cursor, decoded)
+	}
+}
+
+func TestFavouriteCursorToken_Empty(t *testing.T) {
+	if token := mapper.FavouriteCursorToToken(nil); token != "" {
+		t.Errorf("expected empty token for nil cursor, got %q", token)
+	}
+	cursor, err := mapper.FavouriteCursorFromToken("")
+	if err != nil || cursor != nil {
+		t.Errorf("expected nil cursor and no error, got %+v, %v", cursor, err)
+	}
+}
+
+func TestFavouriteCursorFromToken_Invalid(t *testing.T) {
+	for _, token := range []string{"%%%", "bm90IGpzb24", "e30"} {
+		t.Run(token, func(t *testing.T) {
+			// Act
+			_, err := mapper.FavouriteCursorFromToken(token)
+
+			// Assert
+			if !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package mapper
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 )
@@ -11,18 +14,26 @@ func FavouriteEntityToDomain(e *entities.FavouriteEntity) *domain.Favourite {
 		return nil
 	}
 	return &domain.Favourite{
-		UserID:    e.UserId,
-		AssetID:   e.AssetId,
-		CreatedAt: e.CreatedAt,
+		UserID:      e.UserId,
+		AssetID:     e.AssetId,
+		AssetType:   domain.AssetType(e.AssetType),
+		CreatedAt:   e.CreatedAt,
+		Note:        e.Note,
+		CustomTitle: e.CustomTitle,
+		Tags:        safeUnmarshalStringArray(e.Tags, e.AssetId, "favourite tags"),
 	}
 }
 
 // FavouriteEntityFromDomain converts domain model to entity
 func FavouriteEntityFromDomain(favourite domain.Favourite) entities.FavouriteEntity {
 	return entities.FavouriteEntity{
-		UserId:    favourite.UserID,
-		AssetId:   favourite.AssetID,
-		CreatedAt: favourite.CreatedAt,
+		UserId:      favourite.UserID,
+		AssetId:     favourite.AssetID,
+		AssetType:   entities.AssetType(favourite.AssetType),
+		CreatedAt:   favourite.CreatedAt,
+		Note:        favourite.Note,
+		CustomTitle: favourite.CustomTitle,
+		Tags:        safeMarshalToString(favourite.Tags, "[]", fmt.Sprintf("tags of favourite %s/%s", favourite.UserID, favourite.AssetID)),
 	}
 }
 
@@ -38,3 +49,29 @@ func FavouriteEntityToDomainList(favs []entities.FavouriteEntity) []domain.Favou
 	}
 	return ent
 }
+
+// FavouriteTombstoneEntityFromAsset builds the tombstone template left behind when the asset is deleted at removedAt
+func FavouriteTombstoneEntityFromAsset(asset entities.AssetEntity, removedAt time.Time) *entities.FavouriteTombstoneEntity {
+	return &entities.FavouriteTombstoneEntity{
+		AssetId:    asset.GetID(),
+		AssetType:  asset.GetType(),
+		AssetTitle: asset.GetTitle(),
+		RemovedAt:  removedAt,
+	}
+}
+
+// FavouriteTombstoneEntityToDomainList converts a list of tombstone entities to domain models, handling nil input
+func FavouriteTombstoneEntityToDomainList(tombstones []entities.FavouriteTombstoneEntity) []domain.FavouriteTombstone {
+	result := make([]domain.FavouriteTombstone, len(tombstones))
+	for i, t := range tombstones {
+		result[i] = domain.FavouriteTombstone{
+			UserID:       t.UserId,
+			AssetID:      t.AssetId,
+			AssetType:    domain.AssetType(t.AssetType),
+			AssetTitle:   t.AssetTitle,
+			FavouritedAt: t.FavouritedAt,
+			RemovedAt:    t.RemovedAt,
+		}
+	}
+	return result
+}
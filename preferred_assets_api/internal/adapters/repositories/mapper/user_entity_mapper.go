@@ -15,8 +15,10 @@ func UserEntityToDomain(e *entities.UserEntity) *domain.User {
 		Name:      e.Name,
 		Email:     e.Email,
 		Password:  e.Password,
+		Subject:   e.Subject,
 		CreatedAt: e.CreatedAt,
 		UpdatedAt: e.UpdatedAt,
+		Version:   e.Version,
 	}
 }
 
@@ -27,8 +29,10 @@ func UserEntityFromDomain(user domain.User) entities.UserEntity {
 		Name:      user.Name,
 		Email:     user.Email,
 		Password:  user.Password,
+		Subject:   user.Subject,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+		Version:   user.Version,
 	}
 }
 
@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.APIKeyRepository = (*SQLAPIKeyRepositoryImpl)(nil)
+
+type SQLAPIKeyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *SQLAPIKeyRepositoryImpl {
+	return &SQLAPIKeyRepositoryImpl{db: db}
+}
+
+func apiKeyColumns() string {
+	return strings.Join(names(dbColumns(&entities.APIKeyEntity{}), "", ""), ", ")
+}
+
+func (r *SQLAPIKeyRepositoryImpl) Save(key entities.APIKeyEntity) error {
+	columns := dbColumns(&key)
+	_, err := r.db.Exec(
+		`INSERT INTO api_keys (`+strings.Join(names(columns, "", ""), ", ")+`) VALUES (`+placeholders(len(columns))+`)`,
+		values(columns)...)
+	return err
+}
+
+func (r *SQLAPIKeyRepositoryImpl) GetByHash(hash string) (entities.APIKeyEntity, error) {
+	var key entities.APIKeyEntity
+	err := r.db.QueryRow(`SELECT `+apiKeyColumns()+` FROM api_keys WHERE hash = ?`, hash).
+		Scan(pointers(dbColumns(&key))...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.APIKeyEntity{}, ports.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return entities.APIKeyEntity{}, err
+	}
+	return key, nil
+}
+
+func (r *SQLAPIKeyRepositoryImpl) GetAll() ([]entities.APIKeyEntity, error) {
+	rows, err := r.db.Query(`SELECT ` + apiKeyColumns() + ` FROM api_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]entities.APIKeyEntity, 0)
+	for rows.Next() {
+		var key entities.APIKeyEntity
+		if err := rows.Scan(pointers(dbColumns(&key))...); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *SQLAPIKeyRepositoryImpl) Revoke(id string, at time.Time) error {
+	result, err := r.db.Exec(`UPDATE api_keys SET revoked_at = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ports.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.AssetRepository = (*SQLAssetRepositoryImpl)(nil)
+
+// assetTable describes the table holding the type-specific columns of an asset subtype
+type assetTable struct {
+	assetType entities.AssetType
+	name      string
+	newEntity func() entities.AssetEntity
+}
+
+var assetTables = []assetTable{
+	{entities.AssetTypeAudience, "audiences", func() entities.AssetEntity { return &entities.AudienceEntity{} }},
+	{entities.AssetTypeChart, "charts", func() entities.AssetEntity { return &entities.ChartEntity{} }},
+	{entities.AssetTypeInsight, "insights", func() entities.AssetEntity { return &entities.InsightEntity{} }},
+}
+
+func assetTableFor(assetType entities.AssetType) (assetTable, error) {
+	for _, t := range assetTables {
+		if t.assetType == assetType {
+			return t, nil
+		}
+	}
+	return assetTable{}, fmt.Errorf("unknown asset type: %v", assetType)
+}
+
+type SQLAssetRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAssetRepository(db *sql.DB) *SQLAssetRepositoryImpl {
+	return &SQLAssetRepositoryImpl{db: db}
+}
+
+func (r *SQLAssetRepositoryImpl) Exists(id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM assets WHERE id = ?)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (r *SQLAssetRepositoryImpl) Save(asset entities.AssetEntity) (entities.AssetEntity, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(1)
+	columns, table, err := assetColumns(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	base := filter(columns, true)
+	result, err := tx.Exec(
+		`INSERT INTO assets (`+strings.Join(names(base, "", ""), ", ")+`) VALUES (`+placeholders(len(base))+`)
+		ON CONFLICT (id) DO NOTHING`,
+		values(base)...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, ports.ErrAssetExists
+	}
+	if err := insertAssetDetails(tx, table, asset.GetID(), columns); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (r *SQLAssetRepositoryImpl) GetByID(id string) (entities.AssetEntity, error) {
+	assets, err := r.query(assetTables, `a.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, ports.ErrAssetNotFound
+	}
+	return assets[0], nil
+}
+
+func (r *SQLAssetRepositoryImpl) GetByIDs(ids []string) ([]entities.AssetEntity, error) {
+	if len(ids) == 0 {
+		return []entities.AssetEntity{}, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	found, err := r.query(assetTables, `a.id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the order of the requested ids, skipping missing ones
+	byID := make(map[string]entities.AssetEntity, len(found))
+	for _, asset := range found {
+		byID[asset.GetID()] = asset
+	}
+	assets := make([]entities.AssetEntity, 0, len(found))
+	for _, id := range ids {
+		if asset, ok := byID[id]; ok {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+
+func (r *SQLAssetRepositoryImpl) GetAll() ([]entities.AssetEntity, error) {
+	return r.query(assetTables, "")
+}
+
+func (r *SQLAssetRepositoryImpl) GetByType(assetType entities.AssetType) ([]entities.AssetEntity, error) {
+	table, err := assetTableFor(assetType)
+	if err != nil {
+		return []entities.AssetEntity{}, nil
+	}
+	return r.query([]assetTable{table}, "")
+}
+
+func (r *SQLAssetRepositoryImpl) Query(query entities.AssetQuery) (entities.AssetPage, error) {
+	tables := assetTables
+	if query.Type != nil {
+		table, err := assetTableFor(*query.Type)
+		if err != nil {
+			return entities.AssetPage{Assets: []entities.AssetEntity{}}, nil
+		}
+		tables = []assetTable{table}
+	}
+	if query.HasAudienceFilters() {
+		if query.Type != nil && *query.Type != entities.AssetTypeAudience {
+			return entities.AssetPage{Assets: []entities.AssetEntity{}}, nil
+		}
+		tables = []assetTable{assetTables[0]}
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	orderBy := assetSortColumn(query.SortBy) + ` ` + direction + `, a.id ` + direction
+
+	// One extra row tells whether there is a next page. Audience filters are matched against the definition
+	// of each audience, which SQL cannot evaluate, so every audience is loaded and filtered here instead.
+	limit := 0
+	if query.Limit > 0 && !query.HasAudienceFilters() {
+		limit = query.Limit + 1
+	}
+
+	where, args := assetQueryConditions(query)
+	assets, err := r.queryOrdered(tables, where, orderBy, limit, args...)
+	if err != nil {
+		return entities.AssetPage{}, err
+	}
+	if query.HasAudienceFilters() {
+		assets = slices.DeleteFunc(assets, func(asset entities.AssetEntity) bool {
+			return !query.Matches(asset)
+		})
+	}
+
+	// Each table is ordered on its own, so merge them into the query ordering before cutting the page.
+	// The rows already start after the cursor.
+	sort.Slice(assets, func(i, j int) bool {
+		return query.Less(assets[i], assets[j])
+	})
+	unbounded := query
+	unbounded.After = nil
+	return entities.PaginateAssets(assets, unbounded), nil
+}
+
+func (r *SQLAssetRepositoryImpl) Update(asset entities.AssetEntity) (int64, error) {
+	return r.update(asset, nil)
+}
+
+func (r *SQLAssetRepositoryImpl) Delete(id string) error {
+	return r.delete(id, nil)
+}
+
+func (r *SQLAssetRepositoryImpl) CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error) {
+	return r.update(asset, &expectedVersion)
+}
+
+func (r *SQLAssetRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	return r.delete(id, &expectedVersion)
+}
+
+// update writes the asset with the next version. A nil expectedVersion skips the version check.
+// It returns the version the asset was stored at.
+func (r *SQLAssetRepositoryImpl) update(asset entities.AssetEntity, expectedVersion *int64) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	current, err := storedVersion(tx, "assets", asset.GetID())
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ports.ErrAssetNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if expectedVersion != nil && current != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	asset = entities.CloneAsset(asset)
+	asset.SetVersion(current + 1)
+	columns, table, err := assetColumns(asset)
+	if err != nil {
+		return 0, err
+	}
+
+	base := filterOut(filter(columns, true), "id")
+	result, err := tx.Exec(`UPDATE assets SET `+assignments(names(base, "", ""))+` WHERE id = ? AND version = ?`,
+		append(values(base), asset.GetID(), current)...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ports.ErrVersionConflict
+	}
+
+	// Replace the details row, which also handles a change of asset type
+	if err := deleteAssetDetails(tx, asset.GetID()); err != nil {
+		return 0, err
+	}
+	if err := insertAssetDetails(tx, table, asset.GetID(), columns); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return asset.GetVersion(), nil
+}
+
+// delete removes the asset and its details row. A nil expectedVersion skips the version check.
+func (r *SQLAssetRepositoryImpl) delete(id string, expectedVersion *int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := storedVersion(tx, "assets", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ports.ErrAssetNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if expectedVersion != nil && current != *expectedVersion {
+		return ports.ErrVersionConflict
+	}
+
+	if err := deleteAssetDetails(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM assets WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// query loads the assets of the given subtypes matching the optional where clause, ordered by creation time
+func (r *SQLAssetRepositoryImpl) query(tables []assetTable, where string, args ...any) ([]entities.AssetEntity, error) {
+	return r.queryOrdered(tables, where, `a.created_at, a.id`, 0, args...)
+}
+
+// queryOrdered loads up to limit assets of each of the given subtypes (no limit when limit <= 0),
+// matching the optional where clause and ordered by orderBy
+func (r *SQLAssetRepositoryImpl) queryOrdered(tables []assetTable, where, orderBy string, limit int, args ...any) ([]entities.AssetEntity, error) {
+	assets := make([]entities.AssetEntity, 0)
+
+	for _, table := range tables {
+		stmt := `SELECT ` + strings.Join(names(dbColumns(table.newEntity()), "a", "d"), ", ") +
+			` FROM assets a JOIN ` + table.name + ` d ON d.asset_id = a.id`
+		if where != "" {
+			stmt += ` WHERE ` + where
+		}
+		stmt += ` ORDER BY ` + orderBy
+		if limit > 0 {
+			stmt += fmt.Sprintf(` LIMIT %d`, limit)
+		}
+
+		rows, err := r.db.Query(stmt, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			asset := table.newEntity()
+			if err := rows.Scan(pointers(dbColumns(asset))...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			assets = append(assets, asset)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return assets, nil
+}
+
+func assetSortColumn(field entities.AssetSortField) string {
+	switch field {
+	case entities.AssetSortUpdatedAt:
+		return `a.updated_at`
+	case entities.AssetSortTitle:
+		return `a.title`
+	default:
+		return `a.created_at`
+	}
+}
+
+// assetQueryConditions translates the filters and cursor of a query into a where clause, leaving out the
+// audience filters
+func assetQueryConditions(query entities.AssetQuery) (string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	add := func(condition string, values ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	if query.TitleContains != "" {
+		add(`instr(lower(a.title), lower(?)) > 0`, query.TitleContains)
+	}
+	if !query.CreatedFrom.IsZero() {
+		add(`a.created_at >= ?`, query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		add(`a.created_at <= ?`, query.CreatedTo)
+	}
+	if !query.UpdatedFrom.IsZero() {
+		add(`a.updated_at >= ?`, query.UpdatedFrom)
+	}
+	if !query.UpdatedTo.IsZero() {
+		add(`a.updated_at <= ?`, query.UpdatedTo)
+	}
+
+	if query.After != nil {
+		operator := `>`
+		if query.Descending {
+			operator = `<`
+		}
+		column := assetSortColumn(query.SortBy)
+		var value any
+		switch query.SortBy {
+		case entities.AssetSortUpdatedAt:
+			value = query.After.UpdatedAt
+		case entities.AssetSortTitle:
+			value = query.After.Title
+		default:
+			value = query.After.CreatedAt
+		}
+		add(`(`+column+` `+operator+` ? OR (`+column+` = ? AND a.id `+operator+` ?))`, value, value, query.After.ID)
+	}
+
+	return strings.Join(conditions, ` AND `), args
+}
+
+// assetColumns resolves the tagged columns of a concrete asset entity and the table holding its details
+func assetColumns(asset entities.AssetEntity) ([]column, assetTable, error) {
+	table, err := assetTableFor(asset.GetType())
+	if err != nil {
+		return nil, assetTable{}, err
+	}
+
+	switch asset.(type) {
+	case *entities.AudienceEntity, *entities.ChartEntity, *entities.InsightEntity:
+		return dbColumns(asset), table, nil
+	default:
+		return nil, assetTable{}, fmt.Errorf("unsupported asset entity: %T", asset)
+	}
+}
+
+func insertAssetDetails(tx *sql.Tx, table assetTable, id string, columns []column) error {
+	details := filter(columns, false)
+	_, err := tx.Exec(
+		`INSERT INTO `+table.name+` (asset_id, `+strings.Join(names(details, "", ""), ", ")+`) VALUES (?, `+placeholders(len(details))+`)`,
+		append([]any{id}, values(details)...)...)
+	return err
+}
+
+func deleteAssetDetails(tx *sql.Tx, id string) error {
+	for _, table := range assetTables {
+		if _, err := tx.Exec(`DELETE FROM `+table.name+` WHERE asset_id = ?`, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.CollectionRepository = (*SQLCollectionRepositoryImpl)(nil)
+
+// SQLCollectionRepositoryImpl keeps collections in the collections table and their items,
+// with their display position, in collection_items
+type SQLCollectionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCollectionRepository(db *sql.DB) *SQLCollectionRepositoryImpl {
+	return &SQLCollectionRepositoryImpl{db: db}
+}
+
+func collectionColumns() string {
+	return strings.Join(names(dbColumns(&entities.CollectionEntity{}), "", ""), ", ")
+}
+
+func (r *SQLCollectionRepositoryImpl) Save(collection entities.CollectionEntity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	collection.Version = 1
+	columns := dbColumns(&collection)
+	if _, err := tx.Exec(
+		`INSERT INTO collections (`+strings.Join(names(columns, "", ""), ", ")+`) VALUES (`+placeholders(len(columns))+`)`,
+		values(columns)...); err != nil {
+		return err
+	}
+	if err := insertCollectionItems(tx, collection); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SQLCollectionRepositoryImpl) GetByID(id string) (entities.CollectionEntity, error) {
+	collections, err := r.queryCollections(`id = ?`, id)
+	if err != nil {
+		return entities.CollectionEntity{}, err
+	}
+	if len(collections) == 0 {
+		return entities.CollectionEntity{}, ports.ErrCollectionNotFound
+	}
+	return collections[0], nil
+}
+
+func (r *SQLCollectionRepositoryImpl) GetByUserID(userID string) ([]entities.CollectionEntity, error) {
+	return r.queryCollections(`user_id = ?`, userID)
+}
+
+func (r *SQLCollectionRepositoryImpl) Update(collection entities.CollectionEntity) (int64, error) {
+	return r.update(collection, nil)
+}
+
+func (r *SQLCollectionRepositoryImpl) CompareAndSwap(collection entities.CollectionEntity, expectedVersion int64) (int64, error) {
+	return r.update(collection, &expectedVersion)
+}
+
+// update writes the collection and its items with the next version. A nil expectedVersion skips the version check.
+// It returns the version the collection was stored at.
+func (r *SQLCollectionRepositoryImpl) update(collection entities.CollectionEntity, expectedVersion *int64) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	current, err := storedVersion(tx, "collections", collection.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ports.ErrCollectionNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if expectedVersion != nil && current != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	result, err := tx.Exec(`UPDATE collections SET name = ?, updated_at = ?, version = ? WHERE id = ? AND version = ?`,
+		collection.Name, collection.UpdatedAt, current+1, collection.Id, current)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ports.ErrVersionConflict
+	}
+	if _, err := tx.Exec(`DELETE FROM collection_items WHERE collection_id = ?`, collection.Id); err != nil {
+		return 0, err
+	}
+	if err := insertCollectionItems(tx, collection); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return current + 1, nil
+}
+
+func (r *SQLCollectionRepositoryImpl) Delete(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Items are deleted explicitly as SQLite only cascades when foreign keys are enabled
+	if _, err := tx.Exec(`DELETE FROM collection_items WHERE collection_id = ?`, id); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`DELETE FROM collections WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if err := ensureCollectionAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SQLCollectionRepositoryImpl) RemoveAsset(userID, assetID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE collections SET version = version + 1
+		WHERE user_id = ? AND id IN (SELECT collection_id FROM collection_items WHERE asset_id = ?)`, userID, assetID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM collection_items
+		WHERE asset_id = ? AND collection_id IN (SELECT id FROM collections WHERE user_id = ?)`, assetID, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SQLCollectionRepositoryImpl) DeleteByUserID(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteUserCollections(tx, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteUserCollections removes every collection of the user with its items
+func deleteUserCollections(e execer, userID string) error {
+	// Items are deleted explicitly as SQLite only cascades when foreign keys are enabled
+	if _, err := e.Exec(`DELETE FROM collection_items
+		WHERE collection_id IN (SELECT id FROM collections WHERE user_id = ?)`, userID); err != nil {
+		return err
+	}
+	_, err := e.Exec(`DELETE FROM collections WHERE user_id = ?`, userID)
+	return err
+}
+
+// queryCollections returns the collections matching where with their items, oldest first
+func (r *SQLCollectionRepositoryImpl) queryCollections(where string, args ...any) ([]entities.CollectionEntity, error) {
+	rows, err := r.db.Query(`SELECT `+collectionColumns()+` FROM collections WHERE `+where+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := make([]entities.CollectionEntity, 0)
+	byID := make(map[string]int)
+	for rows.Next() {
+		var c entities.CollectionEntity
+		if err := rows.Scan(pointers(dbColumns(&c))...); err != nil {
+			return nil, err
+		}
+		c.AssetIds = []string{}
+		byID[c.Id] = len(collections)
+		collections = append(collections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return collections, nil
+	}
+
+	items, err := r.db.Query(`SELECT collection_id, asset_id FROM collection_items
+		WHERE collection_id IN (SELECT id FROM collections WHERE `+where+`) ORDER BY collection_id, position`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer items.Close()
+
+	for items.Next() {
+		var collectionID, assetID string
+		if err := items.Scan(&collectionID, &assetID); err != nil {
+			return nil, err
+		}
+		if i, ok := byID[collectionID]; ok {
+			collections[i].AssetIds = append(collections[i].AssetIds, assetID)
+		}
+	}
+	return collections, items.Err()
+}
+
+func insertCollectionItems(tx *sql.Tx, collection entities.CollectionEntity) error {
+	for position, assetID := range collection.AssetIds {
+		if _, err := tx.Exec(`INSERT INTO collection_items (collection_id, asset_id, position) VALUES (?, ?, ?)`,
+			collection.Id, assetID, position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureCollectionAffected maps a statement that touched no row to ErrCollectionNotFound
+func ensureCollectionAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ports.ErrCollectionNotFound
+	}
+	return nil
+}
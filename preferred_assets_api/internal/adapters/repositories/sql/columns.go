@@ -0,0 +1,140 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// column is a struct field mapped to a table column through its `db` tag
+type column struct {
+	name     string
+	embedded bool // declared on an embedded struct, e.g. AssetBaseEntity
+	value    reflect.Value
+}
+
+// dbColumns walks the `db` tags of the struct pointed to by v, flattening embedded structs.
+// Fields without a tag, or tagged "-", are skipped.
+func dbColumns(v any) []column {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("dbColumns: expected pointer to struct, got %T", v))
+	}
+	return collectColumns(rv.Elem(), false)
+}
+
+func collectColumns(rv reflect.Value, embedded bool) []column {
+	rt := rv.Type()
+	columns := make([]column, 0, rt.NumField())
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, collectColumns(rv.Field(i), true)...)
+			continue
+		}
+
+		tag := field.Tag.Get("db")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		columns = append(columns, column{name: tag, embedded: embedded, value: rv.Field(i)})
+	}
+	return columns
+}
+
+// names returns the column names, optionally prefixing embedded and own columns with different table aliases
+func names(columns []column, embeddedAlias, ownAlias string) []string {
+	result := make([]string, len(columns))
+	for i, c := range columns {
+		alias := ownAlias
+		if c.embedded {
+			alias = embeddedAlias
+		}
+		if alias != "" {
+			result[i] = alias + "." + c.name
+		} else {
+			result[i] = c.name
+		}
+	}
+	return result
+}
+
+// pointers returns scan destinations for the columns
+func pointers(columns []column) []any {
+	result := make([]any, len(columns))
+	for i, c := range columns {
+		result[i] = c.value.Addr().Interface()
+	}
+	return result
+}
+
+// values returns the current column values for use as query arguments
+func values(columns []column) []any {
+	result := make([]any, len(columns))
+	for i, c := range columns {
+		result[i] = c.value.Interface()
+	}
+	return result
+}
+
+// filter returns the columns declared directly on the struct (embedded=false) or on its embedded struct (embedded=true)
+func filter(columns []column, embedded bool) []column {
+	result := make([]column, 0, len(columns))
+	for _, c := range columns {
+		if c.embedded == embedded {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// filterOut drops the named columns
+func filterOut(columns []column, excluded ...string) []column {
+	result := make([]column, 0, len(columns))
+outer:
+	for _, c := range columns {
+		for _, name := range excluded {
+			if c.name == name {
+				continue outer
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// placeholders returns "?, ?, ..." for n arguments
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// assignments returns "a = ?, b = ?" for the given column names
+func assignments(columnNames []string) string {
+	parts := make([]string, len(columnNames))
+	for i, name := range columnNames {
+		parts[i] = name + " = ?"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// storedVersion reads the optimistic concurrency version of a row keyed by id, returning sql.ErrNoRows if it is missing
+func storedVersion(tx *sql.Tx, table, id string) (int64, error) {
+	var version int64
+	err := tx.QueryRow(`SELECT version FROM `+table+` WHERE id = ?`, id).Scan(&version)
+	return version, err
+}
@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// sqlitePragmas are set on every SQLite connection unless the DSN sets them already. SQLite ignores foreign keys
+// unless every connection enables them, and the cascades of the schema rely on them. WAL lets readers carry on
+// while a write is going on, and the busy timeout makes a connection wait for a lock instead of failing at once.
+var sqlitePragmas = []struct{ name, value string }{
+	{"foreign_keys", "foreign_keys(1)"},
+	{"busy_timeout", "busy_timeout(5000)"},
+	{"journal_mode", "journal_mode(WAL)"},
+}
+
+// Open opens the database. For the sqlite driver the DSN is extended with sqlitePragmas and the pool is limited
+// to one connection: SQLite allows a single writer, and concurrent writers on separate connections would fail
+// with SQLITE_BUSY instead of waiting their turn.
+func Open(driver, dsn string) (*sql.DB, error) {
+	if driver != "sqlite" {
+		return sql.Open(driver, dsn)
+	}
+
+	for _, pragma := range sqlitePragmas {
+		if strings.Contains(dsn, pragma.name) {
+			continue
+		}
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "_pragma=" + pragma.value
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
@@ -0,0 +1,253 @@
+package sql
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.FavouriteRepository = (*SQLFavouriteRepositoryImpl)(nil)
+
+type SQLFavouriteRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewFavouriteRepository(db *sql.DB) *SQLFavouriteRepositoryImpl {
+	return &SQLFavouriteRepositoryImpl{db: db}
+}
+
+func (r *SQLFavouriteRepositoryImpl) Add(f entities.FavouriteEntity) error {
+	added, err := insertFavourite(r.db, f)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ports.ErrFavouriteExists
+	}
+	return nil
+}
+
+func (r *SQLFavouriteRepositoryImpl) Delete(userID, assetID string) error {
+	_, err := deleteFavourite(r.db, userID, assetID)
+	return err
+}
+
+// ApplyChanges implements ports.FavouriteRepository.
+func (r *SQLFavouriteRepositoryImpl) ApplyChanges(changes []entities.FavouriteChange) ([]bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	applied := make([]bool, len(changes))
+	for i, change := range changes {
+		if change.Remove {
+			applied[i], err = deleteFavourite(tx, change.Favourite.UserId, change.Favourite.AssetId)
+		} else {
+			applied[i], err = insertFavourite(tx, change.Favourite)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return applied, tx.Commit()
+}
+
+// insertFavourite adds the favourite unless the user already has it, reporting whether it did
+func insertFavourite(e execer, f entities.FavouriteEntity) (bool, error) {
+	columns := dbColumns(&f)
+	result, err := e.Exec(
+		`INSERT INTO favourites (`+strings.Join(names(columns, "", ""), ", ")+`) VALUES (`+placeholders(len(columns))+`)
+		ON CONFLICT (user_id, asset_id) DO NOTHING`,
+		values(columns)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// deleteFavourite removes the user's favourite of the asset, reporting whether there was one
+func deleteFavourite(e execer, userID, assetID string) (bool, error) {
+	result, err := e.Exec(`DELETE FROM favourites WHERE user_id = ? AND asset_id = ?`, userID, assetID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *SQLFavouriteRepositoryImpl) Get(userID, assetID string) (entities.FavouriteEntity, error) {
+	favourites, err := queryFavourites(r.db, `user_id = ? AND asset_id = ?`, "", userID, assetID)
+	if err != nil {
+		return entities.FavouriteEntity{}, err
+	}
+	if len(favourites) == 0 {
+		return entities.FavouriteEntity{}, ports.ErrFavouriteNotFound
+	}
+	return favourites[0], nil
+}
+
+// Update replaces the user's overrides; the key columns and creation time are left untouched
+func (r *SQLFavouriteRepositoryImpl) Update(f entities.FavouriteEntity) error {
+	columns := filterOut(dbColumns(&f), "user_id", "asset_id", "created_at")
+	result, err := r.db.Exec(
+		`UPDATE favourites SET `+assignments(names(columns, "", ""))+` WHERE user_id = ? AND asset_id = ?`,
+		append(values(columns), f.UserId, f.AssetId)...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ports.ErrFavouriteNotFound
+	}
+	return nil
+}
+
+func (r *SQLFavouriteRepositoryImpl) GetByUserID(userID string) ([]entities.FavouriteEntity, error) {
+	return queryFavourites(r.db, `user_id = ?`, "", userID)
+}
+
+func (r *SQLFavouriteRepositoryImpl) GetPageByUserID(userID string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	where := `user_id = ?`
+	args := []any{userID}
+	if after != nil {
+		where += ` AND (created_at < ? OR (created_at = ? AND asset_id > ?))`
+		args = append(args, after.CreatedAt, after.CreatedAt, after.AssetId)
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	limitClause := ""
+	if limit > 0 {
+		limitClause = ` LIMIT ?`
+		args = append(args, limit+1)
+	}
+
+	favourites, err := queryFavourites(r.db, where, limitClause, args...)
+	if err != nil {
+		return entities.FavouritePage{}, err
+	}
+
+	page := entities.FavouritePage{Favourites: favourites}
+	if limit > 0 && len(favourites) > limit {
+		page.Favourites = favourites[:limit]
+		last := page.Favourites[limit-1]
+		page.Next = &entities.FavouriteCursor{CreatedAt: last.CreatedAt, AssetId: last.AssetId}
+	}
+	return page, nil
+}
+
+// queryFavourites returns the favourites matching where, newest first
+func queryFavourites(q querier, where, suffix string, args ...any) ([]entities.FavouriteEntity, error) {
+	rows, err := q.Query(
+		`SELECT `+strings.Join(names(dbColumns(&entities.FavouriteEntity{}), "", ""), ", ")+
+			` FROM favourites WHERE `+where+` ORDER BY created_at DESC, asset_id`+suffix, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	favourites := make([]entities.FavouriteEntity, 0)
+	for rows.Next() {
+		var f entities.FavouriteEntity
+		if err := rows.Scan(pointers(dbColumns(&f))...); err != nil {
+			return nil, err
+		}
+		favourites = append(favourites, f)
+	}
+	return favourites, rows.Err()
+}
+
+func (r *SQLFavouriteRepositoryImpl) Exists(userID, assetID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM favourites WHERE user_id = ? AND asset_id = ?)`, userID, assetID).
+		Scan(&exists)
+	return exists, err
+}
+
+func (r *SQLFavouriteRepositoryImpl) DeleteByAssetID(assetID string, tombstone *entities.FavouriteTombstoneEntity) ([]entities.FavouriteEntity, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	removed, err := queryFavourites(tx, `asset_id = ?`, "", assetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM favourites WHERE asset_id = ?`, assetID); err != nil {
+		return nil, err
+	}
+
+	if tombstone != nil {
+		for _, f := range removed {
+			t := tombstone.TombstoneFor(f)
+			columns := dbColumns(&t)
+			// A tombstone left by an earlier asset with the same id is replaced
+			if _, err := tx.Exec(`DELETE FROM favourite_tombstones WHERE user_id = ? AND asset_id = ?`, t.UserId, t.AssetId); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec(
+				`INSERT INTO favourite_tombstones (`+strings.Join(names(columns, "", ""), ", ")+`) VALUES (`+placeholders(len(columns))+`)`,
+				values(columns)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return removed, tx.Commit()
+}
+
+func (r *SQLFavouriteRepositoryImpl) DeleteByUserID(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteUserFavourites(tx, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteUserFavourites removes every favourite and tombstone of the user
+func deleteUserFavourites(e execer, userID string) error {
+	if _, err := e.Exec(`DELETE FROM favourites WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	_, err := e.Exec(`DELETE FROM favourite_tombstones WHERE user_id = ?`, userID)
+	return err
+}
+
+func (r *SQLFavouriteRepositoryImpl) GetTombstonesByUserID(userID string) ([]entities.FavouriteTombstoneEntity, error) {
+	rows, err := r.db.Query(
+		`SELECT `+strings.Join(names(dbColumns(&entities.FavouriteTombstoneEntity{}), "", ""), ", ")+
+			` FROM favourite_tombstones WHERE user_id = ? ORDER BY removed_at DESC, asset_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tombstones := make([]entities.FavouriteTombstoneEntity, 0)
+	for rows.Next() {
+		var t entities.FavouriteTombstoneEntity
+		if err := rows.Scan(pointers(dbColumns(&t))...); err != nil {
+			return nil, err
+		}
+		tombstones = append(tombstones, t)
+	}
+	return tombstones, rows.Err()
+}
+
+func (r *SQLFavouriteRepositoryImpl) DeleteTombstone(userID, assetID string) error {
+	_, err := r.db.Exec(`DELETE FROM favourite_tombstones WHERE user_id = ? AND asset_id = ?`, userID, assetID)
+	return err
+}
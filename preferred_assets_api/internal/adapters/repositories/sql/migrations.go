@@ -0,0 +1,271 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+type migration struct {
+	version    int
+	name       string
+	statements []string
+}
+
+// migrations are applied in order and recorded in schema_migrations. Never edit a released migration, add a new one.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create users",
+		statements: []string{
+			`CREATE TABLE users (
+				id         TEXT PRIMARY KEY,
+				name       TEXT NOT NULL,
+				email      TEXT NOT NULL,
+				password   TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			)`,
+		},
+	},
+	{
+		version: 2,
+		name:    "create assets",
+		statements: []string{
+			`CREATE TABLE assets (
+				id          TEXT PRIMARY KEY,
+				type        INTEGER NOT NULL,
+				title       TEXT NOT NULL,
+				description TEXT NOT NULL,
+				created_at  TIMESTAMP NOT NULL,
+				updated_at  TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX idx_assets_type ON assets (type)`,
+			`CREATE TABLE audiences (
+				asset_id          TEXT PRIMARY KEY REFERENCES assets (id) ON DELETE CASCADE,
+				gender            TEXT NOT NULL,
+				birth_country     TEXT NOT NULL,
+				age_group         TEXT NOT NULL,
+				hours_social      REAL NOT NULL,
+				purchases_last_mo INTEGER NOT NULL
+			)`,
+			`CREATE TABLE charts (
+				asset_id    TEXT PRIMARY KEY REFERENCES assets (id) ON DELETE CASCADE,
+				axes_titles TEXT NOT NULL,
+				data        TEXT NOT NULL
+			)`,
+			`CREATE TABLE insights (
+				asset_id TEXT PRIMARY KEY REFERENCES assets (id) ON DELETE CASCADE,
+				text     TEXT NOT NULL
+			)`,
+		},
+	},
+	{
+		version: 3,
+		name:    "create favourites",
+		statements: []string{
+			`CREATE TABLE favourites (
+				user_id    TEXT NOT NULL,
+				asset_id   TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				CONSTRAINT uq_favourites_user_asset UNIQUE (user_id, asset_id)
+			)`,
+			`CREATE INDEX idx_favourites_asset ON favourites (asset_id)`,
+		},
+	},
+	{
+		version: 4,
+		name:    "add optimistic concurrency versions",
+		statements: []string{
+			`ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+			`ALTER TABLE assets ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+		},
+	},
+	{
+		version: 5,
+		name:    "create favourite collections",
+		statements: []string{
+			`CREATE TABLE collections (
+				id         TEXT PRIMARY KEY,
+				user_id    TEXT NOT NULL,
+				name       TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			)`,
+			`CREATE INDEX idx_collections_user ON collections (user_id)`,
+			`CREATE TABLE collection_items (
+				collection_id TEXT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+				asset_id      TEXT NOT NULL,
+				position      INTEGER NOT NULL,
+				PRIMARY KEY (collection_id, asset_id)
+			)`,
+		},
+	},
+	{
+		version: 6,
+		name:    "add favourite notes, custom titles and tags",
+		statements: []string{
+			`ALTER TABLE favourites ADD COLUMN note TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE favourites ADD COLUMN custom_title TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE favourites ADD COLUMN tags TEXT NOT NULL DEFAULT '[]'`,
+		},
+	},
+	{
+		version: 7,
+		name:    "create favourite tombstones",
+		statements: []string{
+			`CREATE TABLE favourite_tombstones (
+				user_id       TEXT NOT NULL,
+				asset_id      TEXT NOT NULL,
+				asset_type    INTEGER NOT NULL,
+				asset_title   TEXT NOT NULL,
+				favourited_at TIMESTAMP NOT NULL,
+				removed_at    TIMESTAMP NOT NULL,
+				PRIMARY KEY (user_id, asset_id)
+			)`,
+		},
+	},
+	{
+		version: 8,
+		name:    "link users to identity provider subjects",
+		statements: []string{
+			`ALTER TABLE users ADD COLUMN subject TEXT NOT NULL DEFAULT ''`,
+			`CREATE UNIQUE INDEX uq_users_subject ON users (subject) WHERE subject <> ''`,
+		},
+	},
+	{
+		version: 9,
+		name:    "create api keys",
+		statements: []string{
+			`CREATE TABLE api_keys (
+				id         TEXT PRIMARY KEY,
+				name       TEXT NOT NULL,
+				prefix     TEXT NOT NULL,
+				hash       TEXT NOT NULL UNIQUE,
+				roles      TEXT NOT NULL,
+				user_ids   TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				revoked_at TIMESTAMP NOT NULL
+			)`,
+		},
+	},
+	{
+		version: 10,
+		name:    "record the asset type of favourites",
+		statements: []string{
+			`ALTER TABLE favourites ADD COLUMN asset_type INTEGER NOT NULL DEFAULT 0`,
+			`UPDATE favourites SET asset_type = (SELECT a.type FROM assets a WHERE a.id = favourites.asset_id)
+				WHERE asset_id IN (SELECT id FROM assets)`,
+		},
+	},
+	{
+		version: 11,
+		name:    "add chart kinds, series, categories and axes units",
+		statements: []string{
+			`ALTER TABLE charts ADD COLUMN kind TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE charts ADD COLUMN axes_units TEXT NOT NULL DEFAULT '[]'`,
+			`ALTER TABLE charts ADD COLUMN categories TEXT NOT NULL DEFAULT '[]'`,
+			`ALTER TABLE charts ADD COLUMN series TEXT NOT NULL DEFAULT '[]'`,
+		},
+	},
+	{
+		version: 12,
+		name:    "add audience criteria",
+		statements: []string{
+			`ALTER TABLE audiences ADD COLUMN criteria TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		version: 13,
+		name:    "add collection versions",
+		statements: []string{
+			`ALTER TABLE collections ADD COLUMN version INTEGER NOT NULL DEFAULT 1`,
+		},
+	},
+	{
+		version: 14,
+		name:    "reference users and assets from favourites",
+		// SQLite cannot add a foreign key to an existing table, so favourites is rebuilt. Favourites of users or assets
+		// that no longer exist are dropped, and the asset type migration 10 defaulted to a chart is taken from the asset.
+		statements: []string{
+			`CREATE TABLE favourites_new (
+				user_id      TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+				asset_id     TEXT NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+				created_at   TIMESTAMP NOT NULL,
+				note         TEXT NOT NULL DEFAULT '',
+				custom_title TEXT NOT NULL DEFAULT '',
+				tags         TEXT NOT NULL DEFAULT '[]',
+				asset_type   INTEGER NOT NULL,
+				CONSTRAINT uq_favourites_user_asset UNIQUE (user_id, asset_id)
+			)`,
+			`INSERT INTO favourites_new (user_id, asset_id, created_at, note, custom_title, tags, asset_type)
+				SELECT f.user_id, f.asset_id, f.created_at, f.note, f.custom_title, f.tags, a.type
+				FROM favourites f
+				JOIN assets a ON a.id = f.asset_id
+				JOIN users u ON u.id = f.user_id`,
+			`DROP TABLE favourites`,
+			`ALTER TABLE favourites_new RENAME TO favourites`,
+			`CREATE INDEX idx_favourites_asset ON favourites (asset_id)`,
+		},
+	},
+}
+
+// Migrate brings the database schema up to date, applying each pending migration in its own transaction
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]bool)
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		log.Printf("[sql] Applied migration %d: %s", m.version, m.name)
+	}
+	return nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range m.statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
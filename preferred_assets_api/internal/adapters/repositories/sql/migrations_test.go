@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestMigrate_ReferencesFavourites(t *testing.T) {
+	// Arrange
+	db, err := Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	all := migrations
+	migrations = all[:13]
+	t.Cleanup(func() { migrations = all })
+	require.NoError(t, Migrate(db))
+	for _, stmt := range []string{
+		`INSERT INTO users (id, name, email, password, created_at, updated_at) VALUES ('u1', 'Alice', 'alice@example.com', 'hash', '2025-01-01', '2025-01-01')`,
+		`INSERT INTO assets (id, type, title, description, created_at, updated_at) VALUES ('a1', 2, 'Insight', '', '2025-01-01', '2025-01-01')`,
+		// Left at the chart type migration 10 defaults asset_type to
+		`INSERT INTO favourites (user_id, asset_id, created_at, note) VALUES ('u1', 'a1', '2025-01-02', 'kept')`,
+		`INSERT INTO favourites (user_id, asset_id, created_at) VALUES ('u1', 'gone', '2025-01-02')`,
+		`INSERT INTO favourites (user_id, asset_id, created_at) VALUES ('gone', 'a1', '2025-01-02')`,
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	migrations = all
+
+	// Act
+	err = Migrate(db)
+
+	// Assert
+	require.NoError(t, err)
+	var userID, assetID, note string
+	var assetType int
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM favourites`).Scan(&count))
+	require.Equal(t, 1, count, "expected the favourites of missing users and assets to be dropped")
+	require.NoError(t, db.QueryRow(`SELECT user_id, asset_id, note, asset_type FROM favourites`).Scan(&userID, &assetID, &note, &assetType))
+	require.Equal(t, []any{"u1", "a1", "kept", 2}, []any{userID, assetID, note, assetType})
+	_, err = db.Exec(`INSERT INTO favourites (user_id, asset_id, created_at, asset_type) VALUES ('u1', 'gone', '2025-01-03', 0)`)
+	require.Error(t, err, "expected a favourite of a missing asset to be rejected")
+}
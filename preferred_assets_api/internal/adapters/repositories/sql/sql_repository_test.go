@@ -0,0 +1,723 @@
+package sql_test
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
+	sqlrepo "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/sql"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlrepo.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, sqlrepo.Migrate(db))
+	return db
+}
+
+func newAudienceEntity(id string) *entities.AudienceEntity {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &entities.AudienceEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{
+			ID:          id,
+			Type:        entities.AssetTypeAudience,
+			Title:       "Audience " + id,
+			Description: "Young adults",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Gender:          "female",
+		BirthCountry:    "GR",
+		AgeGroup:        "25-34",
+		HoursSocial:     2.5,
+		PurchasesLastMo: 3,
+	}
+}
+
+// saveUsers stores users with the given ids, for the rows that reference them
+func saveUsers(t *testing.T, db *sql.DB, ids ...string) {
+	t.Helper()
+	repo := sqlrepo.NewUserRepository(db, sqlrepo.NewFavouriteRepository(db))
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, id := range ids {
+		require.NoError(t, repo.Save(entities.UserEntity{Id: id, Name: id, Email: id + "@example.com", Password: "hash", CreatedAt: now, UpdatedAt: now}))
+	}
+}
+
+// saveAssets stores audiences with the given ids, for the rows that reference them
+func saveAssets(t *testing.T, db *sql.DB, ids ...string) {
+	t.Helper()
+	repo := sqlrepo.NewAssetRepository(db)
+	for _, id := range ids {
+		_, err := repo.Save(newAudienceEntity(id))
+		require.NoError(t, err)
+	}
+}
+
+func TestMigrate_IsIdempotent(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+
+	// Act
+	err := sqlrepo.Migrate(db)
+
+	// Assert
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count))
+	require.Equal(t, 14, count)
+}
+
+func TestOpen_EnforcesForeignKeys(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAssetRepository(db)
+	_, err := repo.Save(newAudienceEntity("a1"))
+	require.NoError(t, err)
+
+	// Act
+	err = repo.Delete("a1")
+
+	// Assert
+	require.NoError(t, err)
+	var details int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM audiences WHERE asset_id = ?`, "a1").Scan(&details))
+	require.Zero(t, details, "expected the audience details to cascade with the asset")
+	_, err = db.Exec(`INSERT INTO collection_items (collection_id, asset_id, position) VALUES ('missing', 'a1', 0)`)
+	require.Error(t, err, "expected an item of a missing collection to be rejected")
+}
+
+func TestOpen_SerialisesConcurrentWrites(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAssetRepository(db)
+	const writers = 50
+	errs := make(chan error, writers)
+	var wg sync.WaitGroup
+
+	// Act
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Save(newAudienceEntity(fmt.Sprintf("a%d", i)))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM assets`).Scan(&count))
+	require.Equal(t, writers, count)
+}
+
+func TestSQLUserRepository(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	favourites := sqlrepo.NewFavouriteRepository(db)
+	repo := sqlrepo.NewUserRepository(db, favourites)
+	now := time.Now().UTC().Truncate(time.Second)
+	user := entities.UserEntity{Id: "u1", Name: "Alice", Email: "alice@example.com", Password: "hash", CreatedAt: now, UpdatedAt: now}
+
+	// Act & Assert
+	require.NoError(t, repo.Save(user))
+
+	got, err := repo.GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", got.Name)
+	require.True(t, now.Equal(got.CreatedAt))
+
+	user.Name = "Alice Smith"
+	version, err := repo.Update(user)
+	require.NoError(t, err)
+	got, err = repo.GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "Alice Smith", got.Name)
+	require.Equal(t, got.Version, version)
+
+	_, err = repo.Update(entities.UserEntity{Id: "missing"})
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+
+	saveAssets(t, db, "a1")
+	require.NoError(t, favourites.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+	favs, err := repo.GetFavouritesByID("u1")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+
+	_, err = repo.GetFavouritesByID("missing")
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	collections := sqlrepo.NewCollectionRepository(db)
+	require.NoError(t, collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "Campaign", AssetIds: []string{"a1"}, CreatedAt: now, UpdatedAt: now}))
+
+	require.NoError(t, repo.Delete("u1"))
+	_, err = repo.GetByID("u1")
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+	favs, err = favourites.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, favs)
+	userCollections, err := collections.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Empty(t, userCollections)
+	var items int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM collection_items`).Scan(&items))
+	require.Zero(t, items)
+}
+
+func TestSQLUserRepository_GetBySubject(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewUserRepository(db, sqlrepo.NewFavouriteRepository(db))
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.Save(entities.UserEntity{Id: "u1", Name: "Alice", Subject: "alice-sub", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, repo.Save(entities.UserEntity{Id: "u2", Name: "Bob", CreatedAt: now, UpdatedAt: now}))
+	require.NoError(t, repo.Save(entities.UserEntity{Id: "u3", Name: "Carol", CreatedAt: now, UpdatedAt: now}))
+
+	// Act
+	got, err := repo.GetBySubject("alice-sub")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "u1", got.Id)
+
+	_, err = repo.GetBySubject("missing")
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+
+	err = repo.Save(entities.UserEntity{Id: "u4", Name: "Dave", Subject: "alice-sub", CreatedAt: now, UpdatedAt: now})
+	require.Error(t, err)
+}
+
+func TestSQLAssetRepository_ChartSeries(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAssetRepository(db)
+	chart := &entities.ChartEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "chart-1", Type: entities.AssetTypeChart, Title: "Revenue", CreatedAt: time.Now().UTC()},
+		Kind:            "bar",
+		AxesTitles:      `["Month","Revenue"]`,
+		AxesUnits:       `["","EUR"]`,
+		Categories:      `["Jan","Feb"]`,
+		Series:          `[{"name":"2024","values":[10,12]}]`,
+		Data:            `[]`,
+	}
+
+	// Act
+	_, err := repo.Save(chart)
+	require.NoError(t, err)
+	got, err := repo.GetByID("chart-1")
+
+	// Assert
+	require.NoError(t, err)
+	chart.Version = 1
+	require.Equal(t, chart, got)
+}
+
+func TestSQLAssetRepository(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAssetRepository(db)
+
+	audience := newAudienceEntity("aud-1")
+	chart := &entities.ChartEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "chart-1", Type: entities.AssetTypeChart, Title: "Chart", CreatedAt: time.Now().UTC()},
+		AxesTitles:      `["x","y"]`,
+		Data:            `[[1,2],[3,4]]`,
+	}
+	insight := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "ins-1", Type: entities.AssetTypeInsight, Title: "Insight", CreatedAt: time.Now().UTC()},
+		Text:            "40% of millennials",
+	}
+
+	// Act
+	for _, asset := range []entities.AssetEntity{audience, chart, insight} {
+		_, err := repo.Save(asset)
+		require.NoError(t, err)
+	}
+
+	// Assert
+	got, err := repo.GetByID("aud-1")
+	require.NoError(t, err)
+	audience.Version = 1
+	require.Equal(t, audience, got)
+
+	got, err = repo.GetByID("chart-1")
+	require.NoError(t, err)
+	require.Equal(t, `[[1,2],[3,4]]`, got.(*entities.ChartEntity).Data)
+
+	_, err = repo.GetByID("missing")
+	require.ErrorIs(t, err, ports.ErrAssetNotFound)
+
+	byIDs, err := repo.GetByIDs([]string{"ins-1", "missing", "aud-1"})
+	require.NoError(t, err)
+	require.Len(t, byIDs, 2)
+	require.Equal(t, "ins-1", byIDs[0].GetID())
+	require.Equal(t, "aud-1", byIDs[1].GetID())
+
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	charts, err := repo.GetByType(entities.AssetTypeChart)
+	require.NoError(t, err)
+	require.Len(t, charts, 1)
+
+	insight.Text = "Updated text"
+	_, err = repo.Update(insight)
+	require.NoError(t, err)
+	got, err = repo.GetByID("ins-1")
+	require.NoError(t, err)
+	require.Equal(t, "Updated text", got.(*entities.InsightEntity).Text)
+
+	_, err = repo.Update(newAudienceEntity("missing"))
+	require.ErrorIs(t, err, ports.ErrAssetNotFound)
+
+	require.NoError(t, repo.Delete("chart-1"))
+	exists, err := repo.Exists("chart-1")
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.ErrorIs(t, repo.Delete("chart-1"), ports.ErrAssetNotFound)
+}
+
+func TestSQLFavouriteRepository(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1")
+	saveAssets(t, db, "a1", "a2")
+	now := time.Now().UTC()
+
+	// Act & Assert
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a2", AssetType: entities.AssetTypeInsight, CreatedAt: now.Add(time.Second)}))
+
+	// The unique (user_id, asset_id) constraint rejects duplicates
+	require.ErrorIs(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}), ports.ErrFavouriteExists)
+
+	exists, err := repo.Exists("u1", "a1")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	favs, err := repo.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, favs, 2)
+	require.Equal(t, "a2", favs[0].AssetId)
+	require.Equal(t, entities.AssetTypeInsight, favs[0].AssetType)
+
+	require.NoError(t, repo.Delete("u1", "a1"))
+	exists, err = repo.Exists("u1", "a1")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestSQLFavouriteRepository_ReferencesUsersAndAssets(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1", "u2")
+	saveAssets(t, db, "a1", "a2")
+	now := time.Now().UTC()
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u2", AssetId: "a2", CreatedAt: now}))
+
+	// Act
+	unknownUserErr := repo.Add(entities.FavouriteEntity{UserId: "missing", AssetId: "a1", CreatedAt: now})
+	unknownAssetErr := repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "missing", CreatedAt: now})
+	_, err := db.Exec(`DELETE FROM users WHERE id = ?`, "u1")
+	require.NoError(t, err)
+	require.NoError(t, sqlrepo.NewAssetRepository(db).Delete("a2"))
+
+	// Assert
+	require.Error(t, unknownUserErr, "expected a favourite of a missing user to be rejected")
+	require.Error(t, unknownAssetErr, "expected a favourite of a missing asset to be rejected")
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM favourites`).Scan(&count))
+	require.Zero(t, count, "expected the favourites to cascade with their user and asset")
+}
+
+func TestSQLFavouriteRepository_ApplyChanges(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1")
+	saveAssets(t, db, "a1", "a2")
+	now := time.Now().UTC()
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}))
+
+	// Act
+	applied, err := repo.ApplyChanges([]entities.FavouriteChange{
+		{Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: now}},
+		{Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a2", CreatedAt: now}},
+		{Remove: true, Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a1"}},
+		{Remove: true, Favourite: entities.FavouriteEntity{UserId: "u1", AssetId: "a3"}},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true, true, false}, applied)
+	favs, err := repo.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	require.Equal(t, "a2", favs[0].AssetId)
+}
+
+func TestSQLFavouriteRepository_Update(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1")
+	saveAssets(t, db, "a1")
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: created, Tags: "[]"}))
+
+	// Act
+	err := repo.Update(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: created.Add(time.Hour),
+		Note: "For the deck", CustomTitle: "My title", Tags: `["q3"]`})
+
+	// Assert
+	require.NoError(t, err)
+	got, err := repo.Get("u1", "a1")
+	require.NoError(t, err)
+	require.Equal(t, "For the deck", got.Note)
+	require.Equal(t, "My title", got.CustomTitle)
+	require.Equal(t, `["q3"]`, got.Tags)
+	require.True(t, got.CreatedAt.Equal(created), "the creation time must not change")
+
+	require.ErrorIs(t, repo.Update(entities.FavouriteEntity{UserId: "u1", AssetId: "missing"}), ports.ErrFavouriteNotFound)
+	_, err = repo.Get("u2", "a1")
+	require.ErrorIs(t, err, ports.ErrFavouriteNotFound)
+}
+
+func TestSQLFavouriteRepository_DeleteByAssetID(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1", "u2")
+	saveAssets(t, db, "a1", "a2")
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	removedAt := created.Add(24 * time.Hour)
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CreatedAt: created}))
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u2", AssetId: "a1", CreatedAt: created}))
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a2", CreatedAt: created}))
+
+	// Act
+	removed, err := repo.DeleteByAssetID("a1", &entities.FavouriteTombstoneEntity{
+		AssetId: "a1", AssetType: entities.AssetTypeChart, AssetTitle: "Sales", RemovedAt: removedAt})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, removed, 2)
+	favs, err := repo.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	require.Equal(t, "a2", favs[0].AssetId)
+
+	tombstones, err := repo.GetTombstonesByUserID("u2")
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.Equal(t, "Sales", tombstones[0].AssetTitle)
+	require.Equal(t, entities.AssetTypeChart, tombstones[0].AssetType)
+	require.True(t, tombstones[0].FavouritedAt.Equal(created))
+	require.True(t, tombstones[0].RemovedAt.Equal(removedAt))
+
+	require.NoError(t, repo.DeleteTombstone("u2", "a1"))
+	tombstones, err = repo.GetTombstonesByUserID("u2")
+	require.NoError(t, err)
+	require.Empty(t, tombstones)
+
+	// Without a tombstone the favourites are only removed
+	removed, err = repo.DeleteByAssetID("a2", nil)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	tombstones, err = repo.GetTombstonesByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+}
+
+func TestSQLFavouriteRepository_GetPageByUserID(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewFavouriteRepository(db)
+	saveUsers(t, db, "u1")
+	saveAssets(t, db, "a1", "a2", "a3", "a4", "a5", "a6")
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: id, CreatedAt: base.Add(time.Duration(i) * time.Hour)}))
+	}
+	// Same timestamp as a5, ordered after it by asset id
+	require.NoError(t, repo.Add(entities.FavouriteEntity{UserId: "u1", AssetId: "a6", CreatedAt: base.Add(4 * time.Hour)}))
+
+	// Act
+	var seen []string
+	var after *entities.FavouriteCursor
+	pages := 0
+	for {
+		page, err := repo.GetPageByUserID("u1", after, 2)
+		require.NoError(t, err)
+		for _, f := range page.Favourites {
+			seen = append(seen, f.AssetId)
+		}
+		pages++
+		if page.Next == nil {
+			break
+		}
+		after = page.Next
+	}
+
+	// Assert
+	require.Equal(t, []string{"a5", "a6", "a4", "a3", "a2", "a1"}, seen)
+	require.Equal(t, 3, pages)
+}
+
+func TestSQLRepositories_CompareAndSwap(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	users := sqlrepo.NewUserRepository(db, sqlrepo.NewFavouriteRepository(db))
+	assets := sqlrepo.NewAssetRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, users.Save(entities.UserEntity{Id: "u1", Name: "Alice", CreatedAt: now, UpdatedAt: now}))
+	_, err := assets.Save(newAudienceEntity("aud-1"))
+	require.NoError(t, err)
+
+	// Act & Assert
+	version, err := users.CompareAndSwap(entities.UserEntity{Id: "u1", Name: "Bob", CreatedAt: now, UpdatedAt: now}, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+	_, err = users.CompareAndSwap(entities.UserEntity{Id: "u1", Name: "Carol", CreatedAt: now, UpdatedAt: now}, 1)
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	_, err = users.CompareAndSwap(entities.UserEntity{Id: "missing"}, 1)
+	require.ErrorIs(t, err, ports.ErrUserNotFound)
+	user, err := users.GetByID("u1")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", user.Name)
+	require.Equal(t, int64(2), user.Version)
+
+	require.ErrorIs(t, users.CompareAndDelete("u1", 1), ports.ErrVersionConflict)
+	require.ErrorIs(t, users.CompareAndDelete("missing", 1), ports.ErrUserNotFound)
+	require.NoError(t, users.CompareAndDelete("u1", 2))
+
+	audience := newAudienceEntity("aud-1")
+	audience.Gender = "male"
+	version, err = assets.CompareAndSwap(audience, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+	_, err = assets.CompareAndSwap(newAudienceEntity("aud-1"), 1)
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+
+	// A plain update still bumps the version
+	_, err = assets.Update(newAudienceEntity("aud-1"))
+	require.NoError(t, err)
+	got, err := assets.GetByID("aud-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got.GetVersion())
+
+	require.ErrorIs(t, assets.CompareAndDelete("aud-1", 2), ports.ErrVersionConflict)
+	require.NoError(t, assets.CompareAndDelete("aud-1", 3))
+}
+
+func TestSQLAssetRepository_Query(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAssetRepository(db)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	greek := newAudienceEntity("aud-1")
+	greek.Title, greek.CreatedAt, greek.UpdatedAt = "Greek millennials", base.Add(2*time.Hour), base.Add(2*time.Hour)
+	gamers := newAudienceEntity("aud-2")
+	gamers.Title, gamers.CreatedAt, gamers.UpdatedAt = "UK gamers", base.Add(2*time.Hour), base.Add(2*time.Hour)
+	gamers.BirthCountry, gamers.HoursSocial = "UK", 5
+	heavyUsers := newAudienceEntity("aud-3")
+	heavyUsers.Title, heavyUsers.CreatedAt, heavyUsers.UpdatedAt = "Heavy users", base.Add(2*time.Hour), base.Add(2*time.Hour)
+	heavyUsers.Gender, heavyUsers.BirthCountry, heavyUsers.AgeGroup, heavyUsers.HoursSocial, heavyUsers.PurchasesLastMo = "", "", "", 0, 0
+	heavyUsers.Criteria = `{"op":"and","criteria":[{"op":"in","field":"gender","values":["female","male"]},{"op":"gt","field":"hours_social","value":4}]}`
+	chart := &entities.ChartEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "chart-1", Type: entities.AssetTypeChart, Title: "Sales chart", CreatedAt: base.Add(time.Hour), UpdatedAt: base.Add(time.Hour)},
+		AxesTitles:      `["x","y"]`,
+		Data:            `[[1,2]]`,
+	}
+	insight := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "ins-1", Type: entities.AssetTypeInsight, Title: "Millennial habits", CreatedAt: base, UpdatedAt: base.Add(3 * time.Hour)},
+		Text:            "40% of millennials",
+	}
+	for _, asset := range []entities.AssetEntity{greek, gamers, heavyUsers, chart, insight} {
+		_, err := repo.Save(asset)
+		require.NoError(t, err)
+	}
+
+	ids := func(page entities.AssetPage) []string {
+		result := []string{}
+		for _, asset := range page.Assets {
+			result = append(result, asset.GetID())
+		}
+		return result
+	}
+	audienceQuery := func(q domain.AssetQuery) entities.AssetQuery {
+		query, err := mapper.AssetQueryToEntity(q)
+		require.NoError(t, err)
+		return query
+	}
+	insightType := domain.AssetTypeInsight
+	three := 3.0
+
+	// Act & Assert
+	page, err := repo.Query(entities.AssetQuery{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ins-1", "chart-1", "aud-1", "aud-2", "aud-3"}, ids(page))
+	require.Nil(t, page.Next)
+
+	page, err = repo.Query(entities.AssetQuery{TitleContains: "MILLENNIAL"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ins-1", "aud-1"}, ids(page))
+
+	page, err = repo.Query(audienceQuery(domain.AssetQuery{HoursSocialMax: &three}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-1"}, ids(page))
+
+	page, err = repo.Query(audienceQuery(domain.AssetQuery{Gender: "male"}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-3"}, ids(page))
+
+	// The single birth country of the gamers was stored as UK, and is matched as its ISO code
+	page, err = repo.Query(audienceQuery(domain.AssetQuery{BirthCountry: "GB"}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-2"}, ids(page))
+
+	page, err = repo.Query(audienceQuery(domain.AssetQuery{Type: &insightType, BirthCountry: "GR"}))
+	require.NoError(t, err)
+	require.Empty(t, page.Assets)
+
+	page, err = repo.Query(audienceQuery(domain.AssetQuery{Gender: "female", Limit: 2}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-1", "aud-2"}, ids(page))
+	require.NotNil(t, page.Next)
+	next := audienceQuery(domain.AssetQuery{Gender: "female", Limit: 2})
+	next.After = page.Next
+	page, err = repo.Query(next)
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-3"}, ids(page))
+	require.Nil(t, page.Next)
+
+	page, err = repo.Query(entities.AssetQuery{CreatedFrom: base.Add(time.Hour), CreatedTo: base.Add(2 * time.Hour), SortBy: entities.AssetSortTitle})
+	require.NoError(t, err)
+	require.Equal(t, []string{"aud-1", "aud-3", "chart-1", "aud-2"}, ids(page))
+
+	query := entities.AssetQuery{SortBy: entities.AssetSortUpdatedAt, Descending: true, Limit: 1}
+	var paged []string
+	for {
+		page, err = repo.Query(query)
+		require.NoError(t, err)
+		paged = append(paged, ids(page)...)
+		if page.Next == nil {
+			break
+		}
+		query.After = page.Next
+	}
+	require.Equal(t, []string{"ins-1", "aud-3", "aud-2", "aud-1", "chart-1"}, paged)
+}
+
+func TestSQLCollectionRepository(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewCollectionRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	first := entities.CollectionEntity{Id: "c1", UserId: "u1", Name: "First", CreatedAt: now, UpdatedAt: now, AssetIds: []string{"a2", "a1", "a3"}}
+	second := entities.CollectionEntity{Id: "c2", UserId: "u1", Name: "Second", CreatedAt: now.Add(time.Second), UpdatedAt: now, AssetIds: []string{"a1"}}
+	other := entities.CollectionEntity{Id: "c3", UserId: "u2", Name: "Other", CreatedAt: now, UpdatedAt: now, AssetIds: []string{"a1"}}
+
+	// Act
+	for _, c := range []entities.CollectionEntity{first, second, other} {
+		require.NoError(t, repo.Save(c))
+	}
+
+	// Assert
+	got, err := repo.GetByID("c1")
+	require.NoError(t, err)
+	first.Version = 1
+	require.Equal(t, first, got)
+
+	_, err = repo.GetByID("missing")
+	require.ErrorIs(t, err, ports.ErrCollectionNotFound)
+
+	first.Name = "Renamed"
+	first.AssetIds = []string{"a3", "a2"}
+	version, err := repo.CompareAndSwap(first, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+	_, err = repo.CompareAndSwap(first, 1)
+	require.ErrorIs(t, err, ports.ErrVersionConflict)
+	_, err = repo.Update(entities.CollectionEntity{Id: "missing"})
+	require.ErrorIs(t, err, ports.ErrCollectionNotFound)
+
+	require.NoError(t, repo.RemoveAsset("u1", "a1"))
+
+	byUser, err := repo.GetByUserID("u1")
+	require.NoError(t, err)
+	require.Len(t, byUser, 2)
+	require.Equal(t, "Renamed", byUser[0].Name)
+	require.Equal(t, []string{"a3", "a2"}, byUser[0].AssetIds)
+	require.Equal(t, int64(2), byUser[0].Version, "collections without the removed item keep their version")
+	require.Equal(t, []string{}, byUser[1].AssetIds)
+	require.Equal(t, int64(2), byUser[1].Version, "removing an item bumps the version")
+
+	got, err = repo.GetByID("c3")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a1"}, got.AssetIds, "other users' collections are untouched")
+
+	require.NoError(t, repo.Delete("c1"))
+	require.ErrorIs(t, repo.Delete("c1"), ports.ErrCollectionNotFound)
+	var items int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM collection_items WHERE collection_id = 'c1'`).Scan(&items))
+	require.Zero(t, items)
+}
+
+func TestSQLAPIKeyRepository(t *testing.T) {
+	// Arrange
+	db := openDB(t)
+	repo := sqlrepo.NewAPIKeyRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+	key := entities.APIKeyEntity{Id: "k1", Name: "dashboard", Prefix: "pak_abcd", Hash: "hash-1",
+		Roles: `["Users"]`, UserIds: `["u1"]`, CreatedAt: now}
+
+	// Act & Assert
+	require.NoError(t, repo.Save(key))
+	require.Error(t, repo.Save(entities.APIKeyEntity{Id: "k2", Hash: "hash-1", CreatedAt: now}))
+
+	got, err := repo.GetByHash("hash-1")
+	require.NoError(t, err)
+	require.Equal(t, "k1", got.Id)
+	require.Equal(t, `["u1"]`, got.UserIds)
+	require.True(t, got.RevokedAt.IsZero())
+
+	_, err = repo.GetByHash("missing")
+	require.ErrorIs(t, err, ports.ErrAPIKeyNotFound)
+
+	revokedAt := now.Add(time.Minute)
+	require.NoError(t, repo.Revoke("k1", revokedAt))
+	require.ErrorIs(t, repo.Revoke("missing", revokedAt), ports.ErrAPIKeyNotFound)
+
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.True(t, revokedAt.Equal(all[0].RevokedAt))
+}
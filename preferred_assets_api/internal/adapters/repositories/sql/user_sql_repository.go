@@ -0,0 +1,207 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.UserRepository = (*SQLUserRepositoryImpl)(nil)
+
+type SQLUserRepositoryImpl struct {
+	db            *sql.DB
+	favouriteRepo ports.FavouriteRepository
+}
+
+func NewUserRepository(db *sql.DB, favouriteRepo ports.FavouriteRepository) *SQLUserRepositoryImpl {
+	return &SQLUserRepositoryImpl{db: db,
+		favouriteRepo: favouriteRepo}
+}
+
+func userColumns() string {
+	return strings.Join(names(dbColumns(&entities.UserEntity{}), "", ""), ", ")
+}
+
+func (r *SQLUserRepositoryImpl) Save(u entities.UserEntity) error {
+	u.Version = 1
+	columns := dbColumns(&u)
+	_, err := r.db.Exec(
+		`INSERT INTO users (`+strings.Join(names(columns, "", ""), ", ")+`) VALUES (`+placeholders(len(columns))+`)`,
+		values(columns)...)
+	return err
+}
+
+func (r *SQLUserRepositoryImpl) GetByID(id string) (entities.UserEntity, error) {
+	var u entities.UserEntity
+	err := r.db.QueryRow(`SELECT `+userColumns()+` FROM users WHERE id = ?`, id).
+		Scan(pointers(dbColumns(&u))...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.UserEntity{}, ports.ErrUserNotFound
+	}
+	if err != nil {
+		return entities.UserEntity{}, err
+	}
+	return u, nil
+}
+
+func (r *SQLUserRepositoryImpl) GetBySubject(subject string) (entities.UserEntity, error) {
+	var u entities.UserEntity
+	err := r.db.QueryRow(`SELECT `+userColumns()+` FROM users WHERE subject = ?`, subject).
+		Scan(pointers(dbColumns(&u))...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.UserEntity{}, ports.ErrUserNotFound
+	}
+	if err != nil {
+		return entities.UserEntity{}, err
+	}
+	return u, nil
+}
+
+func (r *SQLUserRepositoryImpl) GetAll() ([]entities.UserEntity, error) {
+	rows, err := r.db.Query(`SELECT ` + userColumns() + ` FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]entities.UserEntity, 0)
+	for rows.Next() {
+		var u entities.UserEntity
+		if err := rows.Scan(pointers(dbColumns(&u))...); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *SQLUserRepositoryImpl) Delete(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM users WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if err := deleteUserFavourites(tx, id); err != nil {
+		return err
+	}
+	if err := deleteUserCollections(tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SQLUserRepositoryImpl) Update(u entities.UserEntity) (int64, error) {
+	return r.update(u, nil)
+}
+
+func (r *SQLUserRepositoryImpl) CompareAndSwap(u entities.UserEntity, expectedVersion int64) (int64, error) {
+	return r.update(u, &expectedVersion)
+}
+
+func (r *SQLUserRepositoryImpl) CompareAndDelete(id string, expectedVersion int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM users WHERE id = ? AND version = ?`, id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		tx.Rollback()
+		if err := r.ensureExists(id); err != nil {
+			return err
+		}
+		return ports.ErrVersionConflict
+	}
+	if err := deleteUserFavourites(tx, id); err != nil {
+		return err
+	}
+	if err := deleteUserCollections(tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// update writes the user with the next version. A nil expectedVersion skips the version check.
+// It returns the version the user was stored at.
+func (r *SQLUserRepositoryImpl) update(u entities.UserEntity, expectedVersion *int64) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	current, err := storedVersion(tx, "users", u.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ports.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if expectedVersion != nil && current != *expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+
+	u.Version = current + 1
+	columns := filterOut(dbColumns(&u), "id")
+	args := append(values(columns), u.Id, current)
+
+	result, err := tx.Exec(`UPDATE users SET `+assignments(names(columns, "", ""))+` WHERE id = ? AND version = ?`, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ports.ErrVersionConflict
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return u.Version, nil
+}
+
+func (r *SQLUserRepositoryImpl) GetFavouritesByID(id string) ([]entities.FavouriteEntity, error) {
+	// First verify user exists
+	if err := r.ensureExists(id); err != nil {
+		return nil, err
+	}
+
+	return r.favouriteRepo.GetByUserID(id)
+}
+
+func (r *SQLUserRepositoryImpl) GetFavouritesPageByID(id string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	// First verify user exists
+	if err := r.ensureExists(id); err != nil {
+		return entities.FavouritePage{}, err
+	}
+
+	return r.favouriteRepo.GetPageByUserID(id, after, limit)
+}
+
+func (r *SQLUserRepositoryImpl) ensureExists(id string) error {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ports.ErrUserNotFound
+	}
+	return nil
+}
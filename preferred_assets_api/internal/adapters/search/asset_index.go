@@ -0,0 +1,56 @@
+package search
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+var _ ports.AssetSearchIndex = (*AssetIndex)(nil)
+
+// AssetIndex is the in-memory full-text index of the asset catalogue.
+// It covers titles and descriptions of every asset, insight text, audience summaries and chart axes titles,
+// categories and series names.
+type AssetIndex struct {
+	index *Index
+}
+
+func NewAssetIndex() *AssetIndex {
+	return &AssetIndex{index: NewIndex()}
+}
+
+// Index implements ports.AssetSearchIndex.
+func (a *AssetIndex) Index(asset domain.Asset) {
+	a.index.Add(asset.GetID(), searchableText(asset)...)
+}
+
+// Remove implements ports.AssetSearchIndex.
+func (a *AssetIndex) Remove(id string) {
+	a.index.Remove(id)
+}
+
+// Search implements ports.AssetSearchIndex.
+func (a *AssetIndex) Search(query string, limit int, within map[string]bool) []domain.AssetSearchHit {
+	hits := a.index.Search(query, limit, within)
+	results := make([]domain.AssetSearchHit, len(hits))
+	for i, hit := range hits {
+		results[i] = domain.AssetSearchHit{AssetID: hit.ID, Score: hit.Score}
+	}
+	return results
+}
+
+func searchableText(asset domain.Asset) []string {
+	text := []string{asset.GetTitle(), asset.GetDescription()}
+	switch a := asset.(type) {
+	case *domain.Insight:
+		text = append(text, a.Text)
+	case *domain.Audience:
+		text = append(text, a.Summary())
+	case *domain.Chart:
+		text = append(text, a.AxesTitles...)
+		text = append(text, a.Categories...)
+		for _, series := range a.Series {
+			text = append(text, series.Name)
+		}
+	}
+	return text
+}
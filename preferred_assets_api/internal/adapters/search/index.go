@@ -0,0 +1,147 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters: k1 controls term frequency saturation and b the document length normalisation
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Hit is a document matching a search, with its BM25 relevance score
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is an inverted index over text documents, ranked with Okapi BM25. It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	postings    map[string]map[string]int // term -> document id -> term frequency
+	documents   map[string]map[string]int // document id -> term frequencies, to unindex it
+	lengths     map[string]int            // document id -> number of tokens
+	totalLength int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings:  make(map[string]map[string]int),
+		documents: make(map[string]map[string]int),
+		lengths:   make(map[string]int),
+	}
+}
+
+// Add indexes the text of a document, replacing any previously indexed text with the same id
+func (idx *Index) Add(id string, text ...string) {
+	frequencies := make(map[string]int)
+	length := 0
+	for _, field := range text {
+		for _, token := range Tokenize(field) {
+			frequencies[token]++
+			length++
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+	for term, frequency := range frequencies {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]int)
+		}
+		idx.postings[term][id] = frequency
+	}
+	idx.documents[id] = frequencies
+	idx.lengths[id] = length
+	idx.totalLength += length
+}
+
+// Remove unindexes a document; removing an unknown id is a no-op
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id string) {
+	frequencies, ok := idx.documents[id]
+	if !ok {
+		return
+	}
+	for term := range frequencies {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLength -= idx.lengths[id]
+	delete(idx.documents, id)
+	delete(idx.lengths, id)
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.documents)
+}
+
+// Search returns the documents matching any term of the query, most relevant first with ties broken by id.
+// A nil within searches every document, otherwise only the documents in it. limit <= 0 returns every hit.
+func (idx *Index) Search(query string, limit int, within map[string]bool) []Hit {
+	terms := make(map[string]struct{})
+	for _, token := range Tokenize(query) {
+		terms[token] = struct{}{}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if len(idx.documents) == 0 {
+		return []Hit{}
+	}
+	documentCount := float64(len(idx.documents))
+	averageLength := float64(idx.totalLength) / documentCount
+
+	scores := make(map[string]float64)
+	for term := range terms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		matching := float64(len(postings))
+		idf := math.Log(1 + (documentCount-matching+0.5)/(matching+0.5))
+
+		for id, frequency := range postings {
+			if within != nil && !within[id] {
+				continue
+			}
+			tf := float64(frequency)
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[id])/averageLength
+			scores[id] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
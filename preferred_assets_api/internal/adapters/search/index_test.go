@@ -0,0 +1,115 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	// Arrange
+	idx := NewIndex()
+	idx.Add("short", "social media")
+	idx.Add("long", "social media habits of people who spend many hours watching television every single day")
+	idx.Add("rare", "millennials")
+	idx.Add("unrelated", "boomers")
+
+	tests := []struct {
+		name   string
+		query  string
+		limit  int
+		within map[string]bool
+		want   []string
+	}{
+		{"shorter documents rank higher", "social", 0, nil, []string{"short", "long"}},
+		{"rare terms outweigh common ones", "social millennials", 0, nil, []string{"rare", "short", "long"}},
+		{"matches on stems", "socials", 0, nil, []string{"short", "long"}},
+		{"limit", "social", 1, nil, []string{"short"}},
+		{"within", "social", 0, map[string]bool{"long": true}, []string{"long"}},
+		{"stop words only", "the of", 0, nil, []string{}},
+		{"no match", "podcasts", 0, nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := hitIDs(idx.Search(tt.query, tt.limit, tt.within))
+
+			// Assert
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_AddReplacesAndRemoveDrops(t *testing.T) {
+	// Arrange
+	idx := NewIndex()
+	idx.Add("a", "television")
+
+	// Act
+	idx.Add("a", "podcasts")
+
+	// Assert
+	if hits := idx.Search("television", 0, nil); len(hits) != 0 {
+		t.Errorf("expected re-indexed text to replace the old one, got %v", hitIDs(hits))
+	}
+	if hits := idx.Search("podcasts", 0, nil); len(hits) != 1 {
+		t.Errorf("expected the new text to be indexed, got %v", hitIDs(hits))
+	}
+
+	// Act
+	idx.Remove("a")
+	idx.Remove("missing")
+
+	// Assert
+	if idx.Len() != 0 || len(idx.postings) != 0 || idx.totalLength != 0 {
+		t.Errorf("expected an empty index, got %d documents and %d terms", idx.Len(), len(idx.postings))
+	}
+}
+
+func TestAssetIndex_IndexesEveryTextField(t *testing.T) {
+	// Arrange
+	idx := NewAssetIndex()
+	idx.Index(&domain.Insight{AssetBase: domain.AssetBase{ID: "ins", Title: "Habits", Description: "Survey"}, Text: "millennials"})
+	idx.Index(&domain.Chart{AssetBase: domain.AssetBase{ID: "chart", Title: "Sales"}, AxesTitles: []string{"Month", "Revenue"}})
+	idx.Index(&domain.Audience{AssetBase: domain.AssetBase{ID: "aud", Title: "Gamers", Description: "Console players"}})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"habits", "ins"},
+		{"survey", "ins"},
+		{"millennial", "ins"},
+		{"revenue", "chart"},
+		{"players", "aud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Act
+			hits := idx.Search(tt.query, 0, nil)
+
+			// Assert
+			if len(hits) != 1 || hits[0].AssetID != tt.want || hits[0].Score <= 0 {
+				t.Errorf("expected a single hit on %s, got %+v", tt.want, hits)
+			}
+		})
+	}
+}
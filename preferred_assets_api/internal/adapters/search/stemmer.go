@@ -0,0 +1,230 @@
+package search
+
+import "strings"
+
+// Stem reduces a lower-case English word to its stem with the Porter stemming algorithm,
+// so that "running", "runs" and "run" index as the same term.
+// Words shorter than three letters and words with characters outside a-z are returned unchanged.
+func Stem(word string) string {
+	if len(word) < 3 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+type stemmer struct {
+	b []byte
+}
+
+// suffixRule rewrites a word ending in suffix to end in replacement instead; applyRules decides when it applies
+type suffixRule struct {
+	suffix      string
+	replacement string
+}
+
+// Rules are ordered so that the longest matching suffix is always tried first
+var (
+	step2Rules = []suffixRule{
+		{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+		{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+		{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+		{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	}
+	step3Rules = []suffixRule{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+	}
+	step4Suffixes = []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+		"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	}
+)
+
+// consonant reports whether b[i] is a consonant; y is a consonant unless it follows one
+func (s *stemmer) consonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.consonant(i-1)
+	default:
+		return true
+	}
+}
+
+// measure counts the vowel-consonant sequences in b[:n]
+func (s *stemmer) measure(n int) int {
+	m, i := 0, 0
+	for i < n && s.consonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !s.consonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && s.consonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func (s *stemmer) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !s.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant reports whether b[:n] ends with a double consonant
+func (s *stemmer) doubleConsonant(n int) bool {
+	return n >= 2 && s.b[n-1] == s.b[n-2] && s.consonant(n-1)
+}
+
+// cvc reports whether b[:n] ends consonant-vowel-consonant, where the last consonant is not w, x or y
+func (s *stemmer) cvc(n int) bool {
+	if n < 3 || !s.consonant(n-3) || s.consonant(n-2) || !s.consonant(n-1) {
+		return false
+	}
+	last := s.b[n-1]
+	return last != 'w' && last != 'x' && last != 'y'
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// stemLen is the length of the word without the given suffix
+func (s *stemmer) stemLen(suffix string) int {
+	return len(s.b) - len(suffix)
+}
+
+func (s *stemmer) replace(suffix, replacement string) {
+	s.b = append(s.b[:s.stemLen(suffix)], replacement...)
+}
+
+// step1a removes plurals
+func (s *stemmer) step1a() {
+	switch {
+	case s.hasSuffix("sses"):
+		s.replace("sses", "ss")
+	case s.hasSuffix("ies"):
+		s.replace("ies", "i")
+	case s.hasSuffix("ss"):
+	case s.hasSuffix("s"):
+		s.replace("s", "")
+	}
+}
+
+// step1b removes -ed and -ing, then repairs the stem
+func (s *stemmer) step1b() {
+	if s.hasSuffix("eed") {
+		if s.measure(s.stemLen("eed")) > 0 {
+			s.replace("eed", "ee")
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if s.hasSuffix(suffix) && s.hasVowel(s.stemLen(suffix)) {
+			s.replace(suffix, "")
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	n := len(s.b)
+	switch {
+	case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+		s.b = append(s.b, 'e')
+	case s.doubleConsonant(n) && s.b[n-1] != 'l' && s.b[n-1] != 's' && s.b[n-1] != 'z':
+		s.b = s.b[:n-1]
+	case s.measure(n) == 1 && s.cvc(n):
+		s.b = append(s.b, 'e')
+	}
+}
+
+// step1c turns a terminal y into i when the stem has a vowel
+func (s *stemmer) step1c() {
+	if s.hasSuffix("y") && s.hasVowel(s.stemLen("y")) {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+// applyRules rewrites the longest matching suffix when the remaining stem has a measure above minMeasure
+func (s *stemmer) applyRules(rules []suffixRule, minMeasure int) {
+	for _, rule := range rules {
+		if s.hasSuffix(rule.suffix) {
+			if s.measure(s.stemLen(rule.suffix)) > minMeasure {
+				s.replace(rule.suffix, rule.replacement)
+			}
+			return
+		}
+	}
+}
+
+// step2 maps double suffixes to single ones
+func (s *stemmer) step2() {
+	s.applyRules(step2Rules, 0)
+}
+
+// step3 handles -ic-, -full, -ness and similar
+func (s *stemmer) step3() {
+	s.applyRules(step3Rules, 0)
+}
+
+// step4 removes the remaining suffixes from stems with a measure above one
+func (s *stemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		n := s.stemLen(suffix)
+		if s.measure(n) <= 1 {
+			return
+		}
+		if suffix == "ion" && (n == 0 || (s.b[n-1] != 's' && s.b[n-1] != 't')) {
+			return
+		}
+		s.replace(suffix, "")
+		return
+	}
+}
+
+// step5 removes a final -e and reduces a final -ll
+func (s *stemmer) step5() {
+	if s.hasSuffix("e") {
+		n := s.stemLen("e")
+		if m := s.measure(n); m > 1 || (m == 1 && !s.cvc(n)) {
+			s.b = s.b[:n]
+		}
+	}
+
+	n := len(s.b)
+	if s.b[n-1] == 'l' && s.doubleConsonant(n) && s.measure(n) > 1 {
+		s.b = s.b[:n-1]
+	}
+}
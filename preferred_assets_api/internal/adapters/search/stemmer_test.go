@@ -0,0 +1,65 @@
+package search
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"cats", "cat"},
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"hopping", "hop"},
+		{"falling", "fall"},
+		{"filing", "file"},
+		{"happy", "happi"},
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"digitizer", "digit"},
+		{"hopefulness", "hope"},
+		{"triplicate", "triplic"},
+		{"electrical", "electr"},
+		{"adjustment", "adjust"},
+		{"adoption", "adopt"},
+		{"controlling", "control"},
+		{"generalization", "gener"},
+		{"socializing", "social"},
+		{"running", "run"},
+		{"runs", "run"},
+		{"is", "is"},
+		{"2025", "2025"},
+		{"café", "café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := Stem(tt.word); got != tt.want {
+				t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	// Act
+	tokens := Tokenize("The Millennials, on Social-Media: 40% of them!")
+
+	// Assert
+	want := []string{"millenni", "social", "media", "40", "them"}
+	if len(tokens) != len(want) {
+		t.Fatalf("expected %v, got %v", want, tokens)
+	}
+	for i := range want {
+		if tokens[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, tokens)
+			break
+		}
+	}
+}
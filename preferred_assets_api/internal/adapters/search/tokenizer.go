@@ -0,0 +1,29 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no meaning for ranking
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "for": {},
+	"from": {}, "has": {}, "in": {}, "is": {}, "it": {}, "its": {}, "of": {}, "on": {}, "or": {},
+	"that": {}, "the": {}, "to": {}, "was": {}, "were": {}, "will": {}, "with": {},
+}
+
+// Tokenize splits text into lower-case words, drops stop words and reduces each word to its stem
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if _, stop := stopWords[word]; stop {
+			continue
+		}
+		tokens = append(tokens, Stem(word))
+	}
+	return tokens
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+// APIKeyRequest represents a request to issue an API key for a machine client
+// swagger:model APIKeyRequest
+type APIKeyRequest struct {
+	// A name to recognise the client by
+	// required: true
+	// example: "dashboard backend"
+	Name string `json:"name" validate:"required,max=100"`
+	// The roles the key acts with
+	// required: true
+	// example: ["Users"]
+	Roles []string `json:"roles" validate:"required,min=1,dive,oneof=Administrators Users"`
+	// The users whose resources the key may reach; omit for any user the roles allow.
+	// A key restricted to a single user acts as that user.
+	// example: ["user_123"]
+	UserIds []string `json:"user_ids" validate:"omitempty,dive,required"`
+}
+
+// APIKeyResponse represents an API key returned by the API. The key itself is never listed.
+// swagger:model APIKeyResponse
+type APIKeyResponse struct {
+	// The ID of the key
+	// example: "0b6f4a2e-6c1d-4f57-9a43-3c1e7d2f8b90"
+	Id string `json:"id"`
+
+	// The name of the client
+	// example: "dashboard backend"
+	Name string `json:"name"`
+
+	// The first characters of the key
+	// example: "pak_Xk3v9QfA"
+	Prefix string `json:"prefix"`
+
+	// The roles the key acts with
+	// example: ["Users"]
+	Roles []string `json:"roles"`
+
+	// The users the key is restricted to, if any
+	// example: ["user_123"]
+	UserIds []string `json:"user_ids,omitempty"`
+
+	// Timestamp when the key was issued
+	// example: "2025-10-30T15:04:05Z"
+	CreatedAt time.Time `json:"created_at"`
+
+	// Timestamp when the key was revoked; omitted while it is active
+	// example: "2025-11-02T09:00:00Z"
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeyCreatedResponse is returned once when a key is issued and is the only time the key is shown
+// swagger:model APIKeyCreatedResponse
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+
+	// The key, to send in the X-API-Key header
+	// example: "pak_Xk3v9QfA0cW2b7nLr1YhJ5tUe8sMq4zD6gKpVxN2aBo"
+	Key string `json:"key"`
+}
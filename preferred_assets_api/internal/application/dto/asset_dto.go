@@ -36,35 +36,164 @@ type AssetRequest struct {
 	/// example: This insight highlights key trends in customer behavior.
 	Text *string `json:"text,omitempty"`
 
-	// Gender associated with the audience (optional)
+	// Gender associated with the audience in any case (optional, turned into criteria)
 	// example: female
 	Gender *string `json:"gender,omitempty"`
 
-	// Birth country of the audience (optional)
-	// example: Canada
+	// Birth country of the audience as an ISO 3166-1 alpha-2 code in any case or an English country name (optional, turned into criteria)
+	// example: CA
 	BirthCountry *string `json:"birth_country,omitempty"`
 
-	// Age group of the audience (optional)
+	// Age group of the audience (optional, turned into criteria)
 	// example: 25-34
 	AgeGroup *string `json:"age_group,omitempty"`
 
-	// Average hours spent on social media per day (optional)
+	// Average hours spent on social media per day by the audience (optional, kept as it is rather than turned into criteria)
 	// example: 4
-	HoursSocial *float64 `json:"hours_social,omitempty"`
+	HoursSocial *float64 `json:"hours_social,omitempty" validate:"omitempty,min=0,max=24"`
 
-	// Number of purchases made in the last month (optional)
+	// Minimum number of purchases made in the last month (optional, turned into criteria)
 	// example: 12
 	PurchasesLastMo *int `json:"purchases_last_month,omitempty"`
 
-	// Titles for the chart axes (for chart-type assets)
+	// Criteria respondents of the audience meet (for audience-type assets). The single attributes above replace the
+	// conditions on their fields at the top of the criteria.
+	Criteria *AudienceCriterion `json:"criteria,omitempty"`
+
+	// How the chart is drawn, line when omitted (for chart-type assets)
+	// enum: line,bar,pie,scatter
+	// example: bar
+	Kind string `json:"kind,omitempty" validate:"omitempty,oneof=line bar pie scatter"`
+
+	// Titles for the chart axes, x first (for chart-type assets)
 	// example: ["Age Group", "Average Purchases"]
 	AxesTitles []string `json:"axes_titles,omitempty"`
 
-	// Data points for the chart (for chart-type assets)
+	// Units of the chart axes, in the order of the axes titles (for chart-type assets)
+	// example: ["years", "purchases"]
+	AxesUnits []string `json:"axes_units,omitempty"`
+
+	// Labels of the x axis of line and bar charts, or of the slices of a pie chart (for chart-type assets)
+	// example: ["18-24", "25-34", "35-44"]
+	Categories []string `json:"categories,omitempty"`
+
+	// Named series of values, one value per category; the alternative to data (for chart-type assets)
+	Series []ChartSeries `json:"series,omitempty" validate:"omitempty,dive"`
+
+	// Data points for the chart when it has no series: the first column of every row is its x value and every
+	// other column one series (for chart-type assets)
 	// example: [[18, 2.3], [25, 3.5], [34, 4.1]]
 	Data [][]float64 `json:"data,omitempty"`
 }
 
+// ChartSeries is one named series of the values of a chart
+// swagger:model ChartSeries
+type ChartSeries struct {
+	// Name of the series, shown in the legend
+	// example: Average Purchases
+	Name string `json:"name" validate:"required"`
+
+	// The x value of every value, for scatter charts and line charts without categories
+	// example: [18, 25, 34]
+	X []float64 `json:"x,omitempty"`
+
+	// The values, one per category or per x value
+	// example: [2.3, 3.5, 4.1]
+	Values []float64 `json:"values" validate:"required,min=1"`
+}
+
+// AudienceCriterion is a node of the criteria of an audience: an and or or group of criteria, or a condition on one
+// respondent attribute. Genders and birth countries are compared with values by in and not_in; age, hours_social and
+// purchases_last_month with a value by eq, ne, lt, lte, gt and gte, or with an inclusive min and max by between.
+// swagger:model AudienceCriterion
+type AudienceCriterion struct {
+	// Operator combining the criteria, or comparing the field
+	// enum: and,or,in,not_in,eq,ne,lt,lte,gt,gte,between
+	// example: between
+	Operator string `json:"op" validate:"required,oneof=and or in not_in eq ne lt lte gt gte between"`
+
+	// Criteria of an and or or group
+	Criteria []AudienceCriterion `json:"criteria,omitempty" validate:"omitempty,dive"`
+
+	// Respondent attribute of a condition
+	// enum: gender,birth_country,age,hours_social,purchases_last_month
+	// example: age
+	Field string `json:"field,omitempty" validate:"omitempty,oneof=gender birth_country age hours_social purchases_last_month"`
+
+	// Genders (male, female, non-binary, other) or ISO 3166-1 alpha-2 birth countries for in and not_in
+	// example: ["GB", "IE"]
+	Values []string `json:"values,omitempty"`
+
+	// Number compared with by eq, ne, lt, lte, gt and gte
+	// example: 3
+	Value *float64 `json:"value,omitempty"`
+
+	// Lowest number matched by between
+	// example: 24
+	Min *float64 `json:"min,omitempty"`
+
+	// Highest number matched by between
+	// example: 35
+	Max *float64 `json:"max,omitempty"`
+}
+
+// AssetImportResponse reports the outcome of a bulk asset import
+// swagger:model AssetImportResponse
+type AssetImportResponse struct {
+	// Number of assets created
+	// example: 120
+	Created int `json:"created"`
+
+	// Number of existing assets replaced (upsert mode only)
+	// example: 3
+	Updated int `json:"updated"`
+
+	// Number of lines rejected
+	// example: 1
+	Failed int `json:"failed"`
+
+	// True when the body could not be read past the last error; the lines after it were not imported
+	// example: false
+	Incomplete bool `json:"incomplete,omitempty"`
+
+	// Why each rejected line was rejected, in line order; only the first 100 are listed
+	Errors []AssetImportErrorResponse `json:"errors"`
+
+	// True when more lines were rejected than errors lists
+	// example: false
+	ErrorsTruncated bool `json:"errors_truncated,omitempty"`
+}
+
+// AssetImportErrorResponse explains why one line of an import was rejected
+// swagger:model AssetImportErrorResponse
+type AssetImportErrorResponse struct {
+	// Number of the line in the request body, starting at 1
+	// example: 42
+	Line int `json:"line"`
+
+	// ID of the asset on the line, when it could be read
+	// example: chart_042
+	ID string `json:"id,omitempty"`
+
+	// What was wrong with the line
+	// example: request validation failed
+	Detail string `json:"detail"`
+
+	// Every invalid field of the line
+	Fields []AssetImportFieldError `json:"fields,omitempty"`
+}
+
+// AssetImportFieldError describes why a field of an imported line was rejected
+// swagger:model AssetImportFieldError
+type AssetImportFieldError struct {
+	// JSON name or CSV column of the field
+	// example: title
+	Field string `json:"field"`
+
+	// example: is required
+	Message string `json:"message"`
+}
+
 // internal/application/dto/asset_dto.go
 
 // AssetBaseResponse represents the common base fields for all asset responses
@@ -95,35 +224,75 @@ type AssetBaseResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// AssetsPageResponse represents one page of the asset catalogue
+// swagger:model AssetsPageResponse
+type AssetsPageResponse struct {
+	// The assets on this page, in the requested order
+	Assets []AssetCreationResponse `json:"assets"`
+
+	// Opaque cursor to pass as the cursor query parameter for the next page; omitted on the last page
+	// example: "eyJzIjoiY3JlYXRlZF9hdCIsImkiOiJhc3NldF80NTYifQ"
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AssetSearchResponse represents the results of a full-text asset search
+// swagger:model AssetSearchResponse
+type AssetSearchResponse struct {
+	// The matching assets, most relevant first
+	Results []AssetSearchResultResponse `json:"results"`
+}
+
+// AssetSearchResultResponse represents one asset matching a search
+// swagger:model AssetSearchResultResponse
+type AssetSearchResultResponse struct {
+	// BM25 relevance score; higher is more relevant
+	// example: 2.47
+	Score float64 `json:"score"`
+
+	// The matching asset
+	Asset AssetCreationResponse `json:"asset"`
+}
+
 // AssetCreationResponse represents the response after successfully creating an asset
 // swagger:model AssetCreationResponse
 type AssetCreationResponse struct {
 	AssetBaseResponse
 
-	// Gender of the audience segment (only for audience assets)
-	// example: female
-	Gender *string `json:"gender,omitempty"`
+	// Criteria respondents of the audience meet (only for audience assets)
+	Criteria *AudienceCriterion `json:"criteria,omitempty"`
 
-	// BirthCountry of the audience segment (only for audience assets)
-	// example: US
-	BirthCountry *string `json:"birth_country,omitempty"`
+	// HoursSocial is the average hours a day the audience spends on social media, recorded for audiences defined by
+	// single attributes (only for audience assets)
+	// example: 4
+	HoursSocial *float64 `json:"hours_social,omitempty"`
 
-	// AgeGroup of the audience segment (only for audience assets)
-	// example: 18-24
-	AgeGroup *string `json:"age_group,omitempty"`
+	// Summary describes the audience in words (only for audience assets)
+	// example: Gender is male, aged 24 to 35 and spending more than 3 hours a day on social media
+	Summary string `json:"summary,omitempty"`
 
-	// HoursSocial represents hours spent on social media per week (only for audience assets)
-	// example: 15
-	HoursSocial *float64 `json:"hours_social,omitempty"`
+	// Text of the insight (only for insight assets)
+	// example: 40% of millennials spend more than 3 hours on social media daily
+	Text *string `json:"text,omitempty"`
 
-	// PurchasesLastMo represents number of purchases in the last month (only for audience assets)
-	// example: 3
-	PurchasesLastMo *int `json:"purchases_last_month,omitempty"`
+	// Kind is how the chart is drawn: line, bar, pie or scatter (only for chart assets)
+	// example: line
+	Kind string `json:"kind,omitempty"`
 
 	// AxesTitles contains the titles for chart axes (only for chart assets)
 	// example: {"x": "Time", "y": "Revenue"}
 	AxesTitles []string `json:"axes_titles,omitempty"`
 
+	// AxesUnits contains the units of the chart axes, in the order of the axes titles (only for chart assets)
+	// example: ["", "EUR"]
+	AxesUnits []string `json:"axes_units,omitempty"`
+
+	// Categories label the x axis, or the slices of a pie chart (only for chart assets)
+	// example: ["2023-01", "2023-02"]
+	Categories []string `json:"categories,omitempty"`
+
+	// Series are the named values of the chart (only for chart assets with series)
+	Series []ChartSeries `json:"series,omitempty"`
+
 	// Data contains the chart or insight data (only for chart and insight assets)
 	// example: [{"x": "2023-01", "y": 1000}, {"x": "2023-02", "y": 1500}]
 	Data interface{} `json:"data,omitempty"`
@@ -135,41 +304,3 @@ type AssetCreationSuccessResponse struct {
 	// in: body
 	Body AssetCreationResponse
 }
-
-// AssetErrorResponse represents an error response for asset operations
-// swagger:response AssetErrorResponse
-type AssetErrorResponse struct {
-	// in: body
-	Body struct {
-		// Error message
-		// example: "invalid asset type: unknown_type"
-		Error string `json:"error"`
-
-		// Error code
-		// example: "VALIDATION_ERROR"
-		Code string `json:"code"`
-
-		// Detailed error message
-		// example: "The provided asset type is not supported"
-		Details string `json:"details,omitempty"`
-	}
-}
-
-// AssetValidationErrorResponse represents a validation error response
-// swagger:response AssetValidationErrorResponse
-type AssetValidationErrorResponse struct {
-	// in: body
-	Body struct {
-		// Error message
-		// example: "request validation failed"
-		Error string `json:"error"`
-
-		// Error code
-		// example: "VALIDATION_ERROR"
-		Code string `json:"code"`
-
-		// Field-level validation errors
-		// example: {"type": "asset type is required", "title": "title must be at least 3 characters"}
-		Fields map[string]string `json:"fields,omitempty"`
-	}
-}
@@ -0,0 +1,51 @@
+package dto
+
+// AudienceMatchRequest asks which audiences one respondent profile, or each of a batch of them, belongs to
+// swagger:model AudienceMatchRequest
+type AudienceMatchRequest struct {
+	// A single profile; the alternative to profiles
+	Profile *RespondentProfile `json:"profile,omitempty" validate:"required_without=Profiles,excluded_with=Profiles"`
+
+	// A batch of up to 1000 profiles; the alternative to profile
+	Profiles []RespondentProfile `json:"profiles,omitempty" validate:"omitempty,min=1,max=1000,dive"`
+}
+
+// RespondentProfile describes a respondent. Omitted attributes are unknown, and no criterion on them holds.
+// swagger:model RespondentProfile
+type RespondentProfile struct {
+	// Gender of the respondent
+	// enum: male,female,non-binary,other
+	// example: male
+	Gender string `json:"gender,omitempty" validate:"omitempty,oneof=male female non-binary other"`
+
+	// ISO 3166-1 alpha-2 code of the country the respondent was born in
+	// example: GB
+	BirthCountry string `json:"birth_country,omitempty"`
+
+	// Age in years
+	// example: 29
+	Age *int `json:"age,omitempty" validate:"omitempty,min=0,max=150"`
+
+	// Hours spent on social media a day
+	// example: 4.5
+	HoursSocial *float64 `json:"hours_social,omitempty" validate:"omitempty,min=0,max=24"`
+
+	// Number of purchases made last month
+	// example: 2
+	PurchasesLastMonth *int `json:"purchases_last_month,omitempty" validate:"omitempty,min=0"`
+}
+
+// AudienceMatchResponse lists the audiences of every profile of an AudienceMatchRequest
+// swagger:model AudienceMatchResponse
+type AudienceMatchResponse struct {
+	// One result per profile, in the order of the request; a single profile has one result
+	Results []AudienceMatchResult `json:"results"`
+}
+
+// AudienceMatchResult lists the audiences one profile belongs to
+// swagger:model AudienceMatchResult
+type AudienceMatchResult struct {
+	// IDs of the matching audience assets, in ascending order
+	// example: ["audience_001", "audience_007"]
+	AudienceIDs []string `json:"audience_ids"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// CollectionRequest represents a request to create or rename a collection
+// swagger:model CollectionRequest
+type CollectionRequest struct {
+	// The name of the collection, unique per user
+	// required: true
+	// example: "Q3 campaign"
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CollectionOrderRequest represents a new item order for a collection
+// swagger:model CollectionOrderRequest
+type CollectionOrderRequest struct {
+	// Every asset ID of the collection exactly once, in the new order
+	// required: true
+	// example: ["asset_456", "asset_123"]
+	AssetIds []string `json:"asset_ids" validate:"required"`
+}
+
+// FavouriteCollectionsRequest represents a request to file a favourite into collections
+// swagger:model FavouriteCollectionsRequest
+type FavouriteCollectionsRequest struct {
+	// The IDs of the collections to add the favourite to
+	// required: true
+	// example: ["5f0c7a51-2a8f-4a3e-9f4e-0d8f2b1c7e11"]
+	CollectionIds []string `json:"collection_ids" validate:"required,min=1,dive,required"`
+}
+
+// CollectionResponse represents a collection returned by the API
+// swagger:model CollectionResponse
+type CollectionResponse struct {
+	// The ID of the collection
+	// example: "5f0c7a51-2a8f-4a3e-9f4e-0d8f2b1c7e11"
+	Id string `json:"id"`
+
+	// The ID of the user owning the collection
+	// example: "user_123"
+	UserId string `json:"user_id"`
+
+	// The name of the collection
+	// example: "Q3 campaign"
+	Name string `json:"name"`
+
+	// The asset IDs of the items, in display order
+	// example: ["asset_456", "asset_123"]
+	AssetIds []string `json:"asset_ids"`
+
+	// Timestamp when the collection was created
+	// example: "2025-10-30T15:04:05Z"
+	CreatedAt time.Time `json:"created_at"`
+
+	// Timestamp when the collection was last changed
+	// example: "2025-10-30T15:04:05Z"
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// The items with their assets, in display order; only returned when a single non-empty collection is read
+	Favourites []FavouriteResponse `json:"favourites,omitempty"`
+}
@@ -15,6 +15,15 @@ type FavouriteRequest struct {
 	AssetId string `json:"asset_id" validate:"required"`
 }
 
+// MyFavouriteRequest represents a request to add a favourite for the signed-in user
+// swagger:model MyFavouriteRequest
+type MyFavouriteRequest struct {
+	// The ID of the asset
+	// required: true
+	// example: "asset_456"
+	AssetId string `json:"asset_id" validate:"required"`
+}
+
 // FavouriteResponse represents a favourite returned by the API
 // swagger:model FavouriteResponse
 type FavouriteResponse struct {
@@ -35,7 +44,125 @@ type FavouriteResponse struct {
 	// example: "2025-10-30T15:04:05Z"
 	CreatedAt time.Time `json:"created_at"`
 
+	// The user's private note about the asset
+	// example: "Use in the Q3 deck"
+	Note string `json:"note,omitempty"`
+
+	// The title the user sees instead of the asset's title
+	// example: "Q3 sales"
+	CustomTitle string `json:"custom_title,omitempty"`
+
+	// The user's tags, lowercased
+	// example: ["q3","sales"]
+	Tags []string `json:"tags,omitempty"`
+
 	// The full asset object (can be any type)
 	// example: {"id":"asset_456","title":"Sales Chart","description":"Monthly sales chart","type":"chart"}
 	Asset any `json:"asset"`
 }
+
+// FavouriteUpdateRequest changes the user's private overrides of a favourite.
+// Omitted fields are left unchanged; an empty string clears the note or custom title and an empty list clears the tags.
+// swagger:model FavouriteUpdateRequest
+type FavouriteUpdateRequest struct {
+	// A private note about the asset
+	// example: "Use in the Q3 deck"
+	Note *string `json:"note" validate:"omitempty,max=2000"`
+	// A title to show instead of the asset's title
+	// example: "Q3 sales"
+	CustomTitle *string `json:"custom_title" validate:"omitempty,max=200"`
+	// Tags for the favourite; matching ignores case
+	// example: ["q3","sales"]
+	Tags []string `json:"tags" validate:"omitempty,max=20,dive,max=50"`
+}
+
+// FavouritesPageResponse represents one page of a user's favourites
+// swagger:model FavouritesPageResponse
+type FavouritesPageResponse struct {
+	// The favourites on this page, newest first
+	Favourites []FavouriteResponse `json:"favourites"`
+
+	// Opaque cursor to pass as the cursor query parameter for the next page; omitted on the last page
+	// example: "eyJ0IjoiMjAyNS0xMC0zMFQxNTowNDowNVoiLCJhIjoiYXNzZXRfNDU2In0"
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// RemovedFavouriteResponse tells the user that an asset they had favourited was deleted
+// swagger:model RemovedFavouriteResponse
+type RemovedFavouriteResponse struct {
+	// The ID of the user
+	// example: "user_123"
+	UserID string `json:"user_id"`
+
+	// The ID of the deleted asset
+	// example: "asset_456"
+	AssetID string `json:"asset_id"`
+
+	// The type of the deleted asset
+	// example: "chart"
+	AssetType string `json:"asset_type"`
+
+	// The title of the asset when it was deleted
+	// example: "Sales Chart"
+	AssetTitle string `json:"asset_title"`
+
+	// Timestamp when the user had favourited the asset
+	// example: "2025-10-30T15:04:05Z"
+	FavouritedAt time.Time `json:"favourited_at"`
+
+	// Timestamp when the asset was deleted
+	// example: "2025-11-02T09:00:00Z"
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+// FavouriteBatchRequest adds and removes many favourites of a user in one request
+// swagger:model FavouriteBatchRequest
+type FavouriteBatchRequest struct {
+	// The ID of the user
+	// required: true
+	// example: "user_123"
+	UserId string `json:"_id" validate:"required"`
+	// Apply nothing when an operation adds an asset that does not exist
+	// example: true
+	AllOrNothing bool `json:"all_or_nothing"`
+	// The operations, applied in order
+	// required: true
+	Operations []FavouriteOperationRequest `json:"operations" validate:"required,min=1,max=500,dive"`
+}
+
+// FavouriteOperationRequest adds or removes one favourite of a batch
+// swagger:model FavouriteOperationRequest
+type FavouriteOperationRequest struct {
+	// What to do with the favourite
+	// required: true
+	// example: "add"
+	Op string `json:"op" validate:"required,oneof=add remove"`
+	// The ID of the asset
+	// required: true
+	// example: "asset_456"
+	AssetId string `json:"asset_id" validate:"required"`
+}
+
+// FavouriteBatchResponse reports the outcome of every operation of a batch
+// swagger:model FavouriteBatchResponse
+type FavouriteBatchResponse struct {
+	// Whether the batch was applied; false when an all-or-nothing batch was rejected
+	// example: true
+	Applied bool `json:"applied"`
+	// The outcome of each operation, in request order
+	Results []FavouriteOperationResponse `json:"results"`
+}
+
+// FavouriteOperationResponse reports the outcome of one operation of a batch
+// swagger:model FavouriteOperationResponse
+type FavouriteOperationResponse struct {
+	// What the operation asked for
+	// example: "add"
+	Op string `json:"op"`
+	// The ID of the asset
+	// example: "asset_456"
+	AssetID string `json:"asset_id"`
+	// What the operation did: added, already_present, removed, not_present, asset_missing or skipped
+	// example: "added"
+	Status string `json:"status"`
+}
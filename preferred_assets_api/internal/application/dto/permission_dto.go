@@ -0,0 +1,13 @@
+package dto
+
+// PermissionsResponse represents what the signed-in caller is allowed to do
+// swagger:model PermissionsResponse
+type PermissionsResponse struct {
+	// The caller's realm and client roles
+	// example: ["Users"]
+	Roles []string `json:"roles"`
+
+	// The permissions the roles grant
+	// example: ["assets:read","favourites:read","favourites:write"]
+	Permissions []string `json:"permissions"`
+}
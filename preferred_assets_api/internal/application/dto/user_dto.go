@@ -17,6 +17,10 @@ type CreateUserRequest struct {
 	// required: true
 	// example: "P@ssword123"
 	Password string `json:"password" validate:"required,min=8"`
+
+	// The identity provider subject (sub claim) that signs in as this user
+	// example: "bea950c6-ae5f-442d-babc-1ab6db7c6c7b"
+	Subject string `json:"subject,omitempty"`
 }
 
 // UpdateUserRequest represents a request to update an existing user
@@ -33,6 +37,10 @@ type UpdateUserRequest struct {
 	// The user's password
 	// example: "NewP@ssword123"
 	Password string `json:"password,omitempty" validate:"required,min=8"`
+
+	// The identity provider subject (sub claim) that signs in as this user
+	// example: "bea950c6-ae5f-442d-babc-1ab6db7c6c7b"
+	Subject string `json:"subject,omitempty"`
 }
 
 // UserResponse represents a user returned by the API
@@ -49,6 +57,10 @@ type UserResponse struct {
 	// The user's full name
 	// example: "Alice Johnson"
 	Name string `json:"name"`
+
+	// The identity provider subject linked to the user
+	// example: "bea950c6-ae5f-442d-babc-1ab6db7c6c7b"
+	Subject string `json:"subject,omitempty"`
 }
 
 // UserFavouritesResponse represents a user's favourite asset
@@ -0,0 +1,34 @@
+package mapping
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// API key to DTO, without the key itself
+func APIKeyToResponse(k domain.APIKey) dto.APIKeyResponse {
+	response := dto.APIKeyResponse{
+		Id:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Roles:     append([]string{}, k.Roles...),
+		CreatedAt: k.CreatedAt,
+	}
+	if len(k.UserIDs) > 0 {
+		response.UserIds = append([]string{}, k.UserIDs...)
+	}
+	if k.Revoked() {
+		revokedAt := k.RevokedAt
+		response.RevokedAt = &revokedAt
+	}
+	return response
+}
+
+// Multiple API keys to DTOs
+func APIKeysToResponse(keys []domain.APIKey) []dto.APIKeyResponse {
+	responses := make([]dto.APIKeyResponse, len(keys))
+	for i, k := range keys {
+		responses[i] = APIKeyToResponse(k)
+	}
+	return responses
+}
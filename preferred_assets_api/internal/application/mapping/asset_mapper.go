@@ -25,26 +25,35 @@ func AssetReqToDomain(req dto.AssetRequest) (domain.Asset, error) {
 
 	switch assetType {
 	case domain.AssetTypeAudience:
+		criteria, err := audienceCriteriaReqToDomain(req)
+		if err != nil {
+			return nil, err
+		}
 		return &domain.Audience{
-			AssetBase:       base,
-			Gender:          safeDerefString(req.Gender),
-			BirthCountry:    safeDerefString(req.BirthCountry),
-			AgeGroup:        safeDerefString(req.AgeGroup),
-			HoursSocial:     safeDerefFloat64(req.HoursSocial),
-			PurchasesLastMo: safeDerefInt(req.PurchasesLastMo),
+			AssetBase:          base,
+			Criteria:           criteria,
+			AverageHoursSocial: safeDerefFloat64(req.HoursSocial),
 		}, nil
 
 	case domain.AssetTypeChart:
+		kind := domain.ChartLine
+		if req.Kind != "" {
+			kind = domain.ChartKind(req.Kind)
+		}
 		return &domain.Chart{
 			AssetBase:  base,
+			Kind:       kind,
 			AxesTitles: req.AxesTitles,
+			AxesUnits:  req.AxesUnits,
+			Categories: req.Categories,
+			Series:     chartSeriesToDomain(req.Series),
 			Data:       req.Data,
 		}, nil
 
 	case domain.AssetTypeInsight:
 		return &domain.Insight{
 			AssetBase: base,
-			// Add insight-specific fields as needed
+			Text:      safeDerefString(req.Text),
 		}, nil
 
 	default:
@@ -68,24 +77,30 @@ func AssetDomainToCreationResponse(asset domain.Asset) dto.AssetCreationResponse
 	case *domain.Audience:
 		return dto.AssetCreationResponse{
 			AssetBaseResponse: base,
-			Gender:            safeRefString(a.Gender),
-			BirthCountry:      safeRefString(a.BirthCountry),
-			AgeGroup:          safeRefString(a.AgeGroup),
-			HoursSocial:       safeRefFloat64(a.HoursSocial),
-			PurchasesLastMo:   safeRefInt(a.PurchasesLastMo),
+			Criteria:          AudienceCriterionToDTO(a.Criteria),
+			HoursSocial:       safeRefFloat64(a.AverageHoursSocial),
+			Summary:           a.Summary(),
 		}
 
 	case *domain.Chart:
-		return dto.AssetCreationResponse{
+		response := dto.AssetCreationResponse{
 			AssetBaseResponse: base,
+			Kind:              string(a.DrawnAs()),
 			AxesTitles:        a.AxesTitles,
-			Data:              a.Data,
+			AxesUnits:         a.AxesUnits,
+			Categories:        a.Categories,
+			Series:            ChartSeriesToDTO(a.Series),
+		}
+		// An empty matrix would still be encoded, as data is an interface
+		if len(a.Data) > 0 {
+			response.Data = a.Data
 		}
+		return response
 
 	case *domain.Insight:
 		return dto.AssetCreationResponse{
 			AssetBaseResponse: base,
-			// Insight-specific fields can be added here as needed
+			Text:              safeRefString(a.Text),
 		}
 
 	default:
@@ -96,6 +111,113 @@ func AssetDomainToCreationResponse(asset domain.Asset) dto.AssetCreationResponse
 	}
 }
 
+// AssetPageToResponse maps a page of the asset catalogue to its response DTO
+func AssetPageToResponse(page domain.AssetPage) dto.AssetsPageResponse {
+	assets := make([]dto.AssetCreationResponse, len(page.Assets))
+	for i, asset := range page.Assets {
+		assets[i] = AssetDomainToCreationResponse(asset)
+	}
+	return dto.AssetsPageResponse{
+		Assets:     assets,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// AssetSearchResultsToResponse maps ranked search results to their response DTO
+func AssetSearchResultsToResponse(results []domain.AssetSearchResult) dto.AssetSearchResponse {
+	responses := make([]dto.AssetSearchResultResponse, len(results))
+	for i, result := range results {
+		responses[i] = dto.AssetSearchResultResponse{
+			Score: result.Score,
+			Asset: AssetDomainToCreationResponse(result.Asset),
+		}
+	}
+	return dto.AssetSearchResponse{Results: responses}
+}
+
+// AssetDomainToRequest maps a domain Asset back to its request representation,
+// the document a JSON merge patch is applied to
+func AssetDomainToRequest(asset domain.Asset) (dto.AssetRequest, error) {
+	req, ok := mapAssetToDTO(asset).(dto.AssetRequest)
+	if !ok {
+		return dto.AssetRequest{}, fmt.Errorf("unsupported asset type: %T", asset)
+	}
+	return req, nil
+}
+
+func chartSeriesToDomain(series []dto.ChartSeries) []domain.ChartSeries {
+	if len(series) == 0 {
+		return nil
+	}
+	result := make([]domain.ChartSeries, len(series))
+	for i, s := range series {
+		result[i] = domain.ChartSeries{Name: s.Name, X: s.X, Values: s.Values}
+	}
+	return result
+}
+
+// ChartSeriesToDTO maps the series of a chart to their JSON representation
+func ChartSeriesToDTO(series []domain.ChartSeries) []dto.ChartSeries {
+	if len(series) == 0 {
+		return nil
+	}
+	result := make([]dto.ChartSeries, len(series))
+	for i, s := range series {
+		result[i] = dto.ChartSeries{Name: s.Name, X: s.X, Values: s.Values}
+	}
+	return result
+}
+
+// audienceCriteriaReqToDomain returns the criteria of an audience request. The single attributes of requests made
+// before criteria existed are turned into criteria, in place of the conditions on the same fields when the request
+// has criteria too, as a merge patch of a single attribute does.
+func audienceCriteriaReqToDomain(req dto.AssetRequest) (*domain.AudienceCriterion, error) {
+	attributes := domain.AudienceAttributes{
+		Gender:          safeDerefString(req.Gender),
+		BirthCountry:    safeDerefString(req.BirthCountry),
+		AgeGroup:        safeDerefString(req.AgeGroup),
+		PurchasesLastMo: safeDerefInt(req.PurchasesLastMo),
+	}
+	return attributes.Rewrite(audienceCriterionToDomain(req.Criteria))
+}
+
+func audienceCriterionToDomain(criterion *dto.AudienceCriterion) *domain.AudienceCriterion {
+	if criterion == nil {
+		return nil
+	}
+	result := &domain.AudienceCriterion{
+		Operator: domain.AudienceOperator(criterion.Operator),
+		Field:    domain.AudienceField(criterion.Field),
+		Values:   criterion.Values,
+		Value:    criterion.Value,
+		Min:      criterion.Min,
+		Max:      criterion.Max,
+	}
+	for i := range criterion.Criteria {
+		result.Criteria = append(result.Criteria, *audienceCriterionToDomain(&criterion.Criteria[i]))
+	}
+	return result
+}
+
+// AudienceCriterionToDTO maps the criteria of an audience to their JSON representation
+func AudienceCriterionToDTO(criterion *domain.AudienceCriterion) *dto.AudienceCriterion {
+	if criterion == nil {
+		return nil
+	}
+	result := &dto.AudienceCriterion{
+		Operator: string(criterion.Operator),
+		Field:    string(criterion.Field),
+		Values:   criterion.Values,
+		Value:    criterion.Value,
+		Min:      criterion.Min,
+		Max:      criterion.Max,
+	}
+	for i := range criterion.Criteria {
+		result.Criteria = append(result.Criteria, *AudienceCriterionToDTO(&criterion.Criteria[i]))
+	}
+	return result
+}
+
 // Helper functions for safe referencing
 func safeRefString(s string) *string {
 	if s == "" {
@@ -104,11 +226,11 @@ func safeRefString(s string) *string {
 	return &s
 }
 
-func safeRefInt(i int) *int {
-	if i == 0 {
+func safeRefFloat64(f float64) *float64 {
+	if f == 0 {
 		return nil
 	}
-	return &i
+	return &f
 }
 
 // Helper functions for safe dereferencing
@@ -125,12 +247,6 @@ func safeDerefInt(i *int) int {
 	}
 	return *i
 }
-func safeRefFloat64(f float64) *float64 {
-	if f == 0 {
-		return nil
-	}
-	return &f
-}
 func safeDerefFloat64(i *float64) float64 {
 	if i == nil {
 		return 0
@@ -1,6 +1,7 @@
 package mapping_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -11,7 +12,8 @@ import (
 
 func TestAssetReqToDomain_Audience(t *testing.T) {
 	// Arrange
-	gender := "female"
+	// A payload from before criteria existed, with a capitalised gender and a country name
+	gender := "Female"
 	birthCountry := "Canada"
 	ageGroup := "25-34"
 	hours := 4.5
@@ -41,8 +43,112 @@ func TestAssetReqToDomain_Audience(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected Audience, got %T", asset)
 	}
-	if aud.GetID() != "a1" || aud.Gender != "female" || aud.BirthCountry != "Canada" || aud.AgeGroup != "25-34" || aud.HoursSocial != 4.5 || aud.PurchasesLastMo != 12 {
-		t.Errorf("audience fields not mapped correctly: %+v", aud)
+	if aud.GetID() != "a1" || aud.Criteria == nil || aud.Criteria.Operator != domain.AudienceAnd || len(aud.Criteria.Criteria) != 4 {
+		t.Errorf("audience attributes not turned into criteria: %+v", aud)
+	}
+	if aud.AverageHoursSocial != 4.5 {
+		t.Errorf("expected the average hours to be kept, got %v", aud.AverageHoursSocial)
+	}
+	if want := "Gender is female, born in CA, aged 25 to 34 and with at least 12 purchases last month, averaging 4.5 hours a day on social media"; aud.Summary() != want {
+		t.Errorf("unexpected summary: %q", aud.Summary())
+	}
+}
+
+func TestAssetReqToDomain_AudienceAttributeErrors(t *testing.T) {
+	gender, country := "robot", "Atlantis"
+
+	tests := []struct {
+		name    string
+		req     dto.AssetRequest
+		wantErr string
+	}{
+		{
+			name:    "birth country that is neither a code nor a country name",
+			req:     dto.AssetRequest{ID: "a1", Type: "audience", Title: "Atlanteans", BirthCountry: &country},
+			wantErr: "invalid birth country",
+		},
+		{
+			name:    "unknown gender",
+			req:     dto.AssetRequest{ID: "a1", Type: "audience", Title: "Robots", Gender: &gender},
+			wantErr: "invalid gender",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := mapping.AssetReqToDomain(tt.req)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAssetReqToDomain_AudienceAttributesRewriteCriteria(t *testing.T) {
+	// Arrange
+	min, max := 25.0, 34.0
+	gender := "Male"
+	req := dto.AssetRequest{
+		ID:     "a1",
+		Type:   "audience",
+		Title:  "Young adults",
+		Gender: &gender,
+		Criteria: &dto.AudienceCriterion{Operator: "and", Criteria: []dto.AudienceCriterion{
+			{Operator: "in", Field: "gender", Values: []string{"female"}},
+			{Operator: "between", Field: "age", Min: &min, Max: &max},
+		}},
+	}
+
+	// Act
+	asset, err := mapping.AssetReqToDomain(req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := asset.(*domain.Audience).Summary(); got != "Aged 25 to 34 and gender is male" {
+		t.Errorf("expected the gender condition to be replaced, got %q", got)
+	}
+}
+
+func TestAssetReqToDomain_AudienceCriteria(t *testing.T) {
+	// Arrange
+	min, max, hours := 24.0, 35.0, 3.0
+	req := dto.AssetRequest{
+		ID:    "a2",
+		Type:  "audience",
+		Title: "Young men",
+		Criteria: &dto.AudienceCriterion{Operator: "and", Criteria: []dto.AudienceCriterion{
+			{Operator: "in", Field: "gender", Values: []string{"male"}},
+			{Operator: "between", Field: "age", Min: &min, Max: &max},
+			{Operator: "gt", Field: "hours_social", Value: &hours},
+		}},
+	}
+
+	// Act
+	asset, err := mapping.AssetReqToDomain(req)
+	response := mapping.AssetDomainToCreationResponse(asset)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aud, ok := asset.(*domain.Audience)
+	if !ok {
+		t.Fatalf("expected Audience, got %T", asset)
+	}
+	if aud.Criteria == nil || aud.Criteria.Operator != domain.AudienceAnd || len(aud.Criteria.Criteria) != 3 ||
+		aud.Criteria.Criteria[1].Field != domain.AudienceFieldAge || *aud.Criteria.Criteria[1].Max != 35 {
+		t.Errorf("audience criteria not mapped correctly: %+v", aud.Criteria)
+	}
+	if response.Criteria == nil || len(response.Criteria.Criteria) != 3 {
+		t.Errorf("audience response not mapped correctly: %+v", response)
+	}
+	if response.Summary != "Gender is male, aged 24 to 35 and spending more than 3 hours a day on social media" {
+		t.Errorf("unexpected summary: %q", response.Summary)
 	}
 }
 
@@ -68,11 +174,45 @@ func TestAssetReqToDomain_Chart(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected Chart, got %T", asset)
 	}
-	if chart.GetID() != "c1" || len(chart.AxesTitles) != 2 || len(chart.Data) != 2 {
+	if chart.GetID() != "c1" || chart.Kind != domain.ChartLine || len(chart.AxesTitles) != 2 || len(chart.Data) != 2 {
 		t.Errorf("chart fields not mapped correctly: %+v", chart)
 	}
 }
 
+func TestAssetReqToDomain_ChartSeries(t *testing.T) {
+	// Arrange
+	req := dto.AssetRequest{
+		ID:         "c2",
+		Type:       "chart",
+		Title:      "Social media use",
+		Kind:       "bar",
+		AxesTitles: []string{"Country", "Share"},
+		AxesUnits:  []string{"", "%"},
+		Categories: []string{"UK", "US"},
+		Series:     []dto.ChartSeries{{Name: "2024", Values: []float64{41, 47}}, {Name: "2025", Values: []float64{44, 49}}},
+	}
+
+	// Act
+	asset, err := mapping.AssetReqToDomain(req)
+	response := mapping.AssetDomainToCreationResponse(asset)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chart, ok := asset.(*domain.Chart)
+	if !ok {
+		t.Fatalf("expected Chart, got %T", asset)
+	}
+	if chart.Kind != domain.ChartBar || len(chart.AxesUnits) != 2 || len(chart.Categories) != 2 || len(chart.Series) != 2 ||
+		chart.Series[1].Name != "2025" || chart.Series[1].Values[1] != 49 || chart.Data != nil {
+		t.Errorf("chart fields not mapped correctly: %+v", chart)
+	}
+	if response.Kind != "bar" || len(response.Series) != 2 || response.Series[0].Name != "2024" || response.Data != nil {
+		t.Errorf("chart response not mapped correctly: %+v", response)
+	}
+}
+
 func TestAssetReqToDomain_Insight(t *testing.T) {
 	// Arrange
 	req := dto.AssetRequest{
@@ -138,8 +278,8 @@ func TestAssetReqToDomain_NilPointers(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected Audience, got %T", asset)
 	}
-	if aud.Gender != "" || aud.BirthCountry != "" || aud.AgeGroup != "" || aud.HoursSocial != 0 || aud.PurchasesLastMo != 0 {
-		t.Errorf("expected zero values for nil pointers, got %+v", aud)
+	if aud.Criteria != nil {
+		t.Errorf("expected no criteria for nil pointers, got %+v", aud.Criteria)
 	}
 }
 
@@ -187,7 +327,7 @@ func TestAssetReqToDomain_MultipleAssets(t *testing.T) {
 		t.Fatalf("expected 3 assets, got %d", len(assets))
 	}
 
-	if aud, ok := assets[0].(*domain.Audience); !ok || aud.GetID() != "a1" || aud.Gender != "male" {
+	if aud, ok := assets[0].(*domain.Audience); !ok || aud.GetID() != "a1" || aud.Summary() != "Gender is male, averaging 5.5 hours a day on social media" {
 		t.Errorf("Audience asset not mapped correctly: %+v", assets[0])
 	}
 	if chart, ok := assets[1].(*domain.Chart); !ok || chart.GetID() != "c1" || len(chart.AxesTitles) != 2 {
@@ -0,0 +1,38 @@
+package mapping
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// AudienceMatchReqToDomain maps the profile or the batch of profiles of a match request to domain profiles
+func AudienceMatchReqToDomain(req dto.AudienceMatchRequest) []domain.RespondentProfile {
+	profiles := req.Profiles
+	if req.Profile != nil {
+		profiles = []dto.RespondentProfile{*req.Profile}
+	}
+
+	result := make([]domain.RespondentProfile, len(profiles))
+	for i, profile := range profiles {
+		result[i] = domain.RespondentProfile{
+			Gender:             domain.Gender(profile.Gender),
+			BirthCountry:       profile.BirthCountry,
+			Age:                profile.Age,
+			HoursSocial:        profile.HoursSocial,
+			PurchasesLastMonth: profile.PurchasesLastMonth,
+		}
+	}
+	return result
+}
+
+// AudienceMatchesToResponse maps the audiences of every profile to the match response DTO
+func AudienceMatchesToResponse(matches [][]string) dto.AudienceMatchResponse {
+	results := make([]dto.AudienceMatchResult, len(matches))
+	for i, ids := range matches {
+		if ids == nil {
+			ids = []string{}
+		}
+		results[i] = dto.AudienceMatchResult{AudienceIDs: ids}
+	}
+	return dto.AudienceMatchResponse{Results: results}
+}
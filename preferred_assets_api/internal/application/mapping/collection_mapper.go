@@ -0,0 +1,31 @@
+package mapping
+
+import (
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/dto"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// Collection to DTO, including its favourites when they are loaded
+func CollectionToResponse(c domain.Collection) dto.CollectionResponse {
+	response := dto.CollectionResponse{
+		Id:        c.ID,
+		UserId:    c.UserID,
+		Name:      c.Name,
+		AssetIds:  append([]string{}, c.AssetIDs...),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.Favourites != nil {
+		response.Favourites = FavouritesToResponse(c.Favourites)
+	}
+	return response
+}
+
+// Multiple collections to DTOs
+func CollectionsToResponse(collections []domain.Collection) []dto.CollectionResponse {
+	responses := make([]dto.CollectionResponse, len(collections))
+	for i, c := range collections {
+		responses[i] = CollectionToResponse(c)
+	}
+	return responses
+}
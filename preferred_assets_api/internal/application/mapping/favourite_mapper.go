@@ -12,6 +12,14 @@ func FavouriteReqToDomain(req dto.FavouriteRequest) domain.Favourite {
 	}
 }
 
+// MyFavouriteReqToDomain maps a favourite of the signed-in user with the given id
+func MyFavouriteReqToDomain(userID string, req dto.MyFavouriteRequest) domain.Favourite {
+	return domain.Favourite{
+		UserID:  userID,
+		AssetID: req.AssetId,
+	}
+}
+
 // Single favourite to DTO
 func FavouriteToResponse(fav *domain.Favourite) dto.FavouriteResponse {
 	if fav == nil {
@@ -20,10 +28,21 @@ func FavouriteToResponse(fav *domain.Favourite) dto.FavouriteResponse {
 	}
 
 	return dto.FavouriteResponse{
-		UserID:    fav.UserID,
-		AssetID:   fav.AssetID,
-		Asset:     mapAssetToDTO(fav.GetAsset()),
-		CreatedAt: fav.CreatedAt,
+		UserID:      fav.UserID,
+		AssetID:     fav.AssetID,
+		Asset:       mapAssetToDTO(fav.GetAsset()),
+		CreatedAt:   fav.CreatedAt,
+		Note:        fav.Note,
+		CustomTitle: fav.CustomTitle,
+		Tags:        fav.Tags,
+	}
+}
+
+func FavouriteUpdateReqToDomain(req dto.FavouriteUpdateRequest) domain.FavouritePatch {
+	return domain.FavouritePatch{
+		Note:        req.Note,
+		CustomTitle: req.CustomTitle,
+		Tags:        req.Tags,
 	}
 }
 
@@ -36,6 +55,48 @@ func FavouritesToResponse(favourites []domain.Favourite) []dto.FavouriteResponse
 	return responses
 }
 
+// Page of favourites to DTO
+func FavouritePageToResponse(page domain.FavouritePage) dto.FavouritesPageResponse {
+	return dto.FavouritesPageResponse{
+		Favourites: FavouritesToResponse(page.Favourites),
+		NextCursor: page.NextCursor,
+	}
+}
+
+// Batch operations to domain
+func FavouriteBatchReqToDomain(req dto.FavouriteBatchRequest) []domain.FavouriteOperation {
+	operations := make([]domain.FavouriteOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		operations[i] = domain.FavouriteOperation{Action: domain.FavouriteAction(op.Op), AssetID: op.AssetId}
+	}
+	return operations
+}
+
+// Batch outcome to DTO
+func FavouriteBatchResultToResponse(result domain.FavouriteBatchResult) dto.FavouriteBatchResponse {
+	results := make([]dto.FavouriteOperationResponse, len(result.Results))
+	for i, r := range result.Results {
+		results[i] = dto.FavouriteOperationResponse{Op: string(r.Action), AssetID: r.AssetID, Status: string(r.Outcome)}
+	}
+	return dto.FavouriteBatchResponse{Applied: result.Applied, Results: results}
+}
+
+// Tombstones of removed favourites to DTOs
+func RemovedFavouritesToResponse(tombstones []domain.FavouriteTombstone) []dto.RemovedFavouriteResponse {
+	responses := make([]dto.RemovedFavouriteResponse, len(tombstones))
+	for i, t := range tombstones {
+		responses[i] = dto.RemovedFavouriteResponse{
+			UserID:       t.UserID,
+			AssetID:      t.AssetID,
+			AssetType:    assetTypeToString(t.AssetType),
+			AssetTitle:   t.AssetTitle,
+			FavouritedAt: t.FavouritedAt,
+			RemovedAt:    t.RemovedAt,
+		}
+	}
+	return responses
+}
+
 // Map the asset to the appropriate DTO
 func mapAssetToDTO(asset domain.Asset) interface{} {
 	if asset == nil {
@@ -66,17 +127,14 @@ func mapAssetToDTO(asset domain.Asset) interface{} {
 
 func mapAudienceToDTO(audience *domain.Audience) dto.AssetRequest {
 	return dto.AssetRequest{
-		ID:              audience.GetID(),
-		Type:            assetTypeToString(audience.Type),
-		Title:           audience.GetTitle(),
-		Description:     audience.GetDescription(),
-		Gender:          &audience.Gender,
-		BirthCountry:    &audience.BirthCountry,
-		AgeGroup:        &audience.AgeGroup,
-		HoursSocial:     safeRefFloat64(audience.HoursSocial),
-		PurchasesLastMo: &audience.PurchasesLastMo,
-		CreatedAt:       audience.GetCreatedAt(),
-		UpdatedAt:       audience.GetUpdatedAt(),
+		ID:          audience.GetID(),
+		Type:        assetTypeToString(audience.Type),
+		Title:       audience.GetTitle(),
+		Description: audience.GetDescription(),
+		Criteria:    AudienceCriterionToDTO(audience.Criteria),
+		HoursSocial: safeRefFloat64(audience.AverageHoursSocial),
+		CreatedAt:   audience.GetCreatedAt(),
+		UpdatedAt:   audience.GetUpdatedAt(),
 	}
 }
 
@@ -86,7 +144,11 @@ func mapChartToDTO(chart *domain.Chart) dto.AssetRequest {
 		Type:        assetTypeToString(chart.Type),
 		Title:       chart.GetTitle(),
 		Description: chart.GetDescription(),
+		Kind:        string(chart.DrawnAs()),
 		AxesTitles:  chart.AxesTitles,
+		AxesUnits:   chart.AxesUnits,
+		Categories:  chart.Categories,
+		Series:      ChartSeriesToDTO(chart.Series),
 		Data:        chart.Data,
 		CreatedAt:   chart.GetCreatedAt(),
 		UpdatedAt:   chart.GetUpdatedAt(),
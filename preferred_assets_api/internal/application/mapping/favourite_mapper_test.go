@@ -50,11 +50,7 @@ func TestFavouriteToResponse_WithAudience(t *testing.T) {
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
-		Gender:          "M",
-		BirthCountry:    "US",
-		AgeGroup:        "25-34",
-		HoursSocial:     4.5,
-		PurchasesLastMo: 12,
+		Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"US"}},
 	}
 
 	favDomain := domain.Favourite{UserID: "u1", AssetID: "a1", CreatedAt: time.Now()}
@@ -74,11 +70,8 @@ func TestFavouriteToResponse_WithAudience(t *testing.T) {
 	assert.Equal(t, "audience", dtoAsset.Type)
 	assert.Equal(t, "Audience1", dtoAsset.Title)
 	assert.Equal(t, "Test Audience", dtoAsset.Description)
-	assert.Equal(t, "M", *dtoAsset.Gender)
-	assert.Equal(t, "US", *dtoAsset.BirthCountry)
-	assert.Equal(t, "25-34", *dtoAsset.AgeGroup)
-	assert.Equal(t, 4.5, *dtoAsset.HoursSocial)
-	assert.Equal(t, 12, *dtoAsset.PurchasesLastMo)
+	assert.Equal(t, &dto.AudienceCriterion{Operator: "in", Field: "birth_country", Values: []string{"US"}}, dtoAsset.Criteria)
+	assert.Nil(t, dtoAsset.BirthCountry)
 }
 
 // --- FavouriteToResponse: Chart ---
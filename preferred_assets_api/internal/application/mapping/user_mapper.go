@@ -13,6 +13,7 @@ func UserReqToDomain(req dto.CreateUserRequest) (domain.User, error) {
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
+		Subject:  req.Subject,
 	}, nil
 }
 
@@ -26,14 +27,18 @@ func UpdateReqToDomain(existingUser *domain.User, req dto.UpdateUserRequest) *do
 	if req.Password != "" {
 		existingUser.Password = req.Password
 	}
+	if req.Subject != "" {
+		existingUser.Subject = req.Subject
+	}
 	return existingUser
 }
 
 func DomainToUserRes(user domain.User) dto.UserResponse {
 	return dto.UserResponse{
-		ID:    user.Id,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:      user.Id,
+		Name:    user.Name,
+		Email:   user.Email,
+		Subject: user.Subject,
 	}
 }
 
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// apiKeyPrefix marks the API's keys so they are easy to spot, e.g. by secret scanners
+	apiKeyPrefix = "pak_"
+	// apiKeyShownLength is how much of a key is kept in clear to recognise it in listings
+	apiKeyShownLength = len(apiKeyPrefix) + 8
+)
+
+var _ ports.APIKeyService = (*APIKeyServiceImpl)(nil)
+
+type APIKeyServiceImpl struct {
+	repo     ports.APIKeyRepository
+	userRepo ports.UserRepository
+}
+
+func NewAPIKeyService(repo ports.APIKeyRepository, userRepo ports.UserRepository) *APIKeyServiceImpl {
+	return &APIKeyServiceImpl{repo: repo, userRepo: userRepo}
+}
+
+// CreateAPIKey implements ports.APIKeyService.
+func (s *APIKeyServiceImpl) CreateAPIKey(name string, roles []string, userIDs []string) (domain.APIKey, string, error) {
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetByID(userID); err != nil {
+			return domain.APIKey{}, "", fmt.Errorf("%w: %s", domain.ErrUserNotFound, userID)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	plain := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := domain.APIKey{
+		ID:        uuid.NewString(),
+		Name:      strings.TrimSpace(name),
+		Prefix:    plain[:apiKeyShownLength],
+		Roles:     append([]string{}, roles...),
+		UserIDs:   append([]string{}, userIDs...),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.Save(mapper.APIKeyEntityFromDomain(key, hashAPIKey(plain))); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, plain, nil
+}
+
+// ListAPIKeys implements ports.APIKeyService.
+func (s *APIKeyServiceImpl) ListAPIKeys() ([]domain.APIKey, error) {
+	keys, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return mapper.APIKeyEntityToDomainList(keys), nil
+}
+
+// RevokeAPIKey implements ports.APIKeyService.
+func (s *APIKeyServiceImpl) RevokeAPIKey(id string) error {
+	err := s.repo.Revoke(id, time.Now().UTC())
+	if errors.Is(err, ports.ErrAPIKeyNotFound) {
+		return domain.ErrAPIKeyNotFound
+	}
+	return err
+}
+
+// Authenticate implements ports.APIKeyService.
+func (s *APIKeyServiceImpl) Authenticate(plain string) (domain.APIKey, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+
+	entity, err := s.repo.GetByHash(hashAPIKey(plain))
+	if errors.Is(err, ports.ErrAPIKeyNotFound) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	key := mapper.APIKeyEntityToDomain(entity)
+	if key.Revoked() {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of the key. Keys are 256 random bits, so unlike passwords they need no slow hash.
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+// Mocks
+type mockAPIKeyRepo struct {
+	keys map[string]entities.APIKeyEntity
+}
+
+func newMockAPIKeyRepo() *mockAPIKeyRepo {
+	return &mockAPIKeyRepo{keys: make(map[string]entities.APIKeyEntity)}
+}
+
+func (m *mockAPIKeyRepo) Save(key entities.APIKeyEntity) error {
+	m.keys[key.Id] = key
+	return nil
+}
+
+func (m *mockAPIKeyRepo) GetByHash(hash string) (entities.APIKeyEntity, error) {
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return entities.APIKeyEntity{}, ports.ErrAPIKeyNotFound
+}
+
+func (m *mockAPIKeyRepo) GetAll() ([]entities.APIKeyEntity, error) {
+	result := []entities.APIKeyEntity{}
+	for _, key := range m.keys {
+		result = append(result, key)
+	}
+	entities.SortAPIKeys(result)
+	return result, nil
+}
+
+func (m *mockAPIKeyRepo) Revoke(id string, at time.Time) error {
+	key, ok := m.keys[id]
+	if !ok {
+		return ports.ErrAPIKeyNotFound
+	}
+	key.RevokedAt = at
+	m.keys[id] = key
+	return nil
+}
+
+func newAPIKeyService() (*services.APIKeyServiceImpl, *mockAPIKeyRepo) {
+	keys := newMockAPIKeyRepo()
+	users := &mockUserRepo{users: map[string]entities.UserEntity{"u1": {Id: "u1"}}}
+	return services.NewAPIKeyService(keys, users), keys
+}
+
+// Tests
+
+func TestCreateAPIKey(t *testing.T) {
+	// Arrange
+	service, keys := newAPIKeyService()
+
+	// Act
+	key, plain, err := service.CreateAPIKey(" reporting ", []string{"Users"}, []string{"u1"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(plain, "pak_") || !strings.HasPrefix(plain, key.Prefix) {
+		t.Errorf("unexpected key %q with prefix %q", plain, key.Prefix)
+	}
+	if key.Name != "reporting" || key.Revoked() {
+		t.Errorf("unexpected key %+v", key)
+	}
+	stored := keys.keys[key.ID]
+	if stored.Hash == "" || strings.Contains(stored.Hash, plain) {
+		t.Error("expected only the hash of the key to be stored")
+	}
+}
+
+func TestCreateAPIKey_UnknownUser(t *testing.T) {
+	// Arrange
+	service, keys := newAPIKeyService()
+
+	// Act
+	_, _, err := service.CreateAPIKey("reporting", []string{"Users"}, []string{"u1", "missing"})
+
+	// Assert
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+	if len(keys.keys) != 0 {
+		t.Error("expected no key to be saved")
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	// Arrange
+	service, _ := newAPIKeyService()
+	active, activePlain, err := service.CreateAPIKey("active", []string{"Administrators"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revoked, revokedPlain, err := service.CreateAPIKey("revoked", []string{"Users"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.RevokeAPIKey(revoked.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		plain   string
+		wantID  string
+		wantErr error
+	}{
+		{"active key", activePlain, active.ID, nil},
+		{"revoked key", revokedPlain, "", domain.ErrInvalidAPIKey},
+		{"unknown key", "pak_unknown", "", domain.ErrInvalidAPIKey},
+		{"not an api key", "Bearer something", "", domain.ErrInvalidAPIKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			key, err := service.Authenticate(tt.plain)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if key.ID != tt.wantID {
+				t.Errorf("expected key '%s', got '%s'", tt.wantID, key.ID)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	// Arrange
+	service, _ := newAPIKeyService()
+
+	// Act
+	err := service.RevokeAPIKey("missing")
+
+	// Assert
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
@@ -11,16 +14,52 @@ import (
 var _ ports.AssetService = (*AssetServiceImpl)(nil)
 
 type AssetServiceImpl struct {
-	assetRepo ports.AssetRepository
+	assetRepo      ports.AssetRepository
+	favouriteRepo  ports.FavouriteRepository
+	collectionRepo ports.CollectionRepository
+	searchIndex    ports.AssetSearchIndex
+	matcher        ports.AudienceMatcher
+	chartRenderer  ports.ChartRenderer
+	keepTombstones bool
 }
 
-func NewAssetService(assetRepo ports.AssetRepository) *AssetServiceImpl {
+// NewAssetService creates the asset service. With keepTombstones, deleting an asset leaves a tombstone
+// for every user who had favourited it.
+func NewAssetService(assetRepo ports.AssetRepository, favouriteRepo ports.FavouriteRepository, collectionRepo ports.CollectionRepository,
+	searchIndex ports.AssetSearchIndex, matcher ports.AudienceMatcher, chartRenderer ports.ChartRenderer, keepTombstones bool) *AssetServiceImpl {
 	return &AssetServiceImpl{
-		assetRepo: assetRepo}
+		assetRepo:      assetRepo,
+		favouriteRepo:  favouriteRepo,
+		collectionRepo: collectionRepo,
+		searchIndex:    searchIndex,
+		matcher:        matcher,
+		chartRenderer:  chartRenderer,
+		keepTombstones: keepTombstones}
+}
+
+// RebuildIndexes indexes every stored asset for search and audience matching, so that both cover assets
+// persisted before startup
+func (assetService *AssetServiceImpl) RebuildIndexes() error {
+	assetEntities, err := assetService.assetRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, assetEntity := range assetEntities {
+		asset, err := mapper.AssetEntityToDomain(assetEntity)
+		if err != nil {
+			return err
+		}
+		assetService.searchIndex.Index(asset)
+		assetService.matcher.Index(asset)
+	}
+	return nil
 }
 
 // CreateAsset implements ports.AssetService.
 func (assetService *AssetServiceImpl) CreateAsset(asset domain.Asset) (domain.Asset, error) {
+	if err := asset.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAsset, err)
+	}
 	asset.SetCreatedAt(time.Now().UTC())
 	assetEntity, err := mapper.AssetEntityFromDomain(asset)
 	if err != nil {
@@ -36,10 +75,269 @@ func (assetService *AssetServiceImpl) CreateAsset(asset domain.Asset) (domain.As
 	if err != nil {
 		return nil, err
 	}
+	assetService.searchIndex.Index(createdAssetDomain)
+	assetService.matcher.Index(createdAssetDomain)
 	return createdAssetDomain, nil
 }
 
+// GetAsset implements ports.AssetService.
+func (assetService *AssetServiceImpl) GetAsset(id string) (domain.Asset, error) {
+	exists, err := assetService.assetRepo.Exists(id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrAssetNotFound
+	}
+
+	assetEntity, err := assetService.assetRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AssetEntityToDomain(assetEntity)
+}
+
+// ListAssets implements ports.AssetService.
+func (assetService *AssetServiceImpl) ListAssets(query domain.AssetQuery) (domain.AssetPage, error) {
+	entityQuery, err := mapper.AssetQueryToEntity(query)
+	if err != nil {
+		return domain.AssetPage{}, err
+	}
+
+	page, err := assetService.assetRepo.Query(entityQuery)
+	if err != nil {
+		return domain.AssetPage{}, err
+	}
+
+	assets := make([]domain.Asset, 0, len(page.Assets))
+	for _, assetEntity := range page.Assets {
+		asset, err := mapper.AssetEntityToDomain(assetEntity)
+		if err != nil {
+			return domain.AssetPage{}, err
+		}
+		assets = append(assets, asset)
+	}
+
+	return domain.AssetPage{
+		Assets:     assets,
+		NextCursor: mapper.AssetCursorToToken(page.Next, entityQuery),
+	}, nil
+}
+
+// SearchAssets implements ports.AssetService.
+// Hits whose asset has disappeared since it was indexed are skipped.
+func (assetService *AssetServiceImpl) SearchAssets(query string, favouritesOf string, limit int) ([]domain.AssetSearchResult, error) {
+	var within map[string]bool
+	if favouritesOf != "" {
+		favourites, err := assetService.favouriteRepo.GetByUserID(favouritesOf)
+		if err != nil {
+			return nil, err
+		}
+		within = make(map[string]bool, len(favourites))
+		for _, favourite := range favourites {
+			within[favourite.AssetId] = true
+		}
+		if len(within) == 0 {
+			return []domain.AssetSearchResult{}, nil
+		}
+	}
+
+	hits := assetService.searchIndex.Search(query, limit, within)
+	if len(hits) == 0 {
+		return []domain.AssetSearchResult{}, nil
+	}
+
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.AssetID
+	}
+	assetEntities, err := assetService.assetRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Asset, len(assetEntities))
+	for _, assetEntity := range assetEntities {
+		asset, err := mapper.AssetEntityToDomain(assetEntity)
+		if err != nil {
+			return nil, err
+		}
+		byID[asset.GetID()] = asset
+	}
+
+	results := make([]domain.AssetSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if asset, ok := byID[hit.AssetID]; ok {
+			results = append(results, domain.AssetSearchResult{Asset: asset, Score: hit.Score})
+		}
+	}
+	return results, nil
+}
+
+// UpdateAsset implements ports.AssetService.
+// The asset replaces the stored one as a whole, except for its type and creation time which never change.
+// Unless expectedVersion is ports.AnyVersion the update only succeeds if the stored asset is still at that version.
+func (assetService *AssetServiceImpl) UpdateAsset(asset domain.Asset, expectedVersion int64) (domain.Asset, error) {
+	existing, err := assetService.GetAsset(asset.GetID())
+	if err != nil {
+		return nil, err
+	}
+	if existing.GetType() != asset.GetType() {
+		return nil, domain.ErrAssetTypeImmutable
+	}
+
+	asset.SetCreatedAt(existing.GetCreatedAt())
+	asset.SetUpdatedAt(time.Now().UTC())
+	if err := asset.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAsset, err)
+	}
+
+	assetEntity, err := mapper.AssetEntityFromDomain(asset)
+	if err != nil {
+		return nil, err
+	}
+	var version int64
+	if expectedVersion == ports.AnyVersion {
+		version, err = assetService.assetRepo.Update(assetEntity)
+	} else {
+		version, err = assetService.assetRepo.CompareAndSwap(assetEntity, expectedVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	asset.SetVersion(version)
+	assetService.searchIndex.Index(asset)
+	assetService.matcher.Index(asset)
+	return asset, nil
+}
+
+// ImportAsset implements ports.AssetService.
+// The asset is validated before its id is looked up, so an invalid asset is rejected whether or not its id is taken.
+func (assetService *AssetServiceImpl) ImportAsset(asset domain.Asset, mode domain.AssetImportMode) (domain.AssetImportOutcome, error) {
+	if err := asset.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrInvalidAsset, err)
+	}
+
+	exists, err := assetService.assetRepo.Exists(asset.GetID())
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		_, err := assetService.CreateAsset(asset)
+		switch {
+		case err == nil:
+			return domain.AssetImportCreated, nil
+		case !errors.Is(err, domain.ErrAssetExists):
+			return "", err
+		}
+		// The id was taken since it was looked up, so the asset is handled as an existing one
+	}
+
+	if mode != domain.AssetImportUpsert {
+		return "", domain.ErrAssetExists
+	}
+	if _, err := assetService.UpdateAsset(asset, ports.AnyVersion); err != nil {
+		return "", err
+	}
+	return domain.AssetImportUpdated, nil
+}
+
+// RenderChart implements ports.AssetService.
+func (assetService *AssetServiceImpl) RenderChart(id string, options domain.ChartRenderOptions) ([]byte, error) {
+	asset, err := assetService.GetAsset(id)
+	if err != nil {
+		return nil, err
+	}
+	chart, ok := asset.(*domain.Chart)
+	if !ok {
+		return nil, domain.ErrNotAChart
+	}
+	return assetService.chartRenderer.Render(chart, options)
+}
+
+// MatchAudiences implements ports.AssetService.
+// Every profile is validated before any is matched.
+func (assetService *AssetServiceImpl) MatchAudiences(profiles []domain.RespondentProfile) ([][]string, error) {
+	for i, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: profile %d: %v", domain.ErrInvalidProfile, i, err)
+		}
+	}
+
+	matches := make([][]string, len(profiles))
+	for i, profile := range profiles {
+		matches[i] = assetService.matcher.Match(profile)
+	}
+	return matches, nil
+}
+
 // DeleteAsset implements ports.AssetService.
-func (assetService *AssetServiceImpl) DeleteAsset(id string) error {
-	return assetService.assetRepo.Delete(id)
+// Unless expectedVersion is ports.AnyVersion the asset is only deleted if it is still at that version.
+func (assetService *AssetServiceImpl) DeleteAsset(id string, expectedVersion int64) error {
+	// Read the asset first so its tombstones can name it; a missing asset is reported by the delete below
+	var tombstone *entities.FavouriteTombstoneEntity
+	if assetService.keepTombstones {
+		if asset, err := assetService.assetRepo.GetByID(id); err == nil {
+			tombstone = mapper.FavouriteTombstoneEntityFromAsset(asset, time.Now().UTC())
+		}
+	}
+
+	// The favourites go first: a store that references the asset from them deletes them along with it, leaving
+	// nothing to tombstone
+	removed, err := assetService.favouriteRepo.DeleteByAssetID(id, tombstone)
+	if err != nil {
+		return fmt.Errorf("favourites of asset %s were not removed: %w", id, err)
+	}
+
+	if expectedVersion == ports.AnyVersion {
+		err = assetService.assetRepo.Delete(id)
+	} else {
+		err = assetService.assetRepo.CompareAndDelete(id, expectedVersion)
+	}
+	if err != nil {
+		// The asset stays, so it keeps its favourites; one that is gone already keeps none
+		if !errors.Is(err, ports.ErrAssetNotFound) {
+			if restoreErr := assetService.restoreFavourites(id, removed, tombstone != nil); restoreErr != nil {
+				return errors.Join(err, restoreErr)
+			}
+		}
+		return err
+	}
+
+	assetService.searchIndex.Remove(id)
+	assetService.matcher.Remove(id)
+
+	// Sweep up the favourites added while the asset was being deleted
+	late, err := assetService.favouriteRepo.DeleteByAssetID(id, tombstone)
+	if err != nil {
+		return fmt.Errorf("asset %s deleted but its favourites were not removed: %w", id, err)
+	}
+	return assetService.removeFromCollections(id, append(removed, late...))
+}
+
+// restoreFavourites gives back the favourites removed for an asset that was not deleted after all, along with
+// their tombstones
+func (assetService *AssetServiceImpl) restoreFavourites(assetID string, removed []entities.FavouriteEntity, tombstoned bool) error {
+	for _, f := range removed {
+		if err := assetService.favouriteRepo.Add(f); err != nil && !errors.Is(err, ports.ErrFavouriteExists) {
+			return fmt.Errorf("asset %s not deleted but the favourite of user %s was not restored: %w", assetID, f.UserId, err)
+		}
+		if !tombstoned {
+			continue
+		}
+		if err := assetService.favouriteRepo.DeleteTombstone(f.UserId, assetID); err != nil {
+			return fmt.Errorf("asset %s not deleted but the tombstone of user %s was not removed: %w", assetID, f.UserId, err)
+		}
+	}
+	return nil
+}
+
+// removeFromCollections cascades the deletion of an asset to the collections of the users who favourited it
+func (assetService *AssetServiceImpl) removeFromCollections(assetID string, removed []entities.FavouriteEntity) error {
+	for _, f := range removed {
+		if err := assetService.collectionRepo.RemoveAsset(f.UserId, assetID); err != nil {
+			return fmt.Errorf("asset %s deleted but it was not removed from the collections of user %s: %w", assetID, f.UserId, err)
+		}
+	}
+	return nil
 }
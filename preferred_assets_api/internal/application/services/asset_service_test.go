@@ -2,25 +2,47 @@ package services_test
 
 import (
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/matching"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/render"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/filestore"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/inmemory"
+	sqlrepo "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/sql"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/search"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	lru "github.com/hashicorp/golang-lru/v2"
+	_ "modernc.org/sqlite"
 )
 
 // Mocks
 type mockAssetServiceRepo struct {
-	saveCalled   bool
-	saveErr      error
-	deleteCalled bool
-	deleteErr    error
+	saveCalled      bool
+	saveErr         error
+	deleteCalled    bool
+	deleteErr       error
+	stored          entities.AssetEntity
+	updated         entities.AssetEntity
+	expectedVersion int64
+	query           entities.AssetQuery
+	page            entities.AssetPage
+	assets          []entities.AssetEntity
+	// savedAfterLookup makes the first Exists miss the stored asset, as if it was saved right after that lookup
+	savedAfterLookup bool
 }
 
 func (m *mockAssetServiceRepo) Save(asset entities.AssetEntity) (entities.AssetEntity, error) {
 	m.saveCalled = true
-	return nil, m.saveErr
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	return asset, nil
 }
 
 func (m *mockAssetServiceRepo) Delete(id string) error {
@@ -29,17 +51,52 @@ func (m *mockAssetServiceRepo) Delete(id string) error {
 }
 
 func (m *mockAssetServiceRepo) GetByID(id string) (entities.AssetEntity, error) {
-	return entities.AssetBaseEntity{}, nil
+	if m.stored != nil {
+		return m.stored, nil
+	}
+	return nil, ports.ErrAssetNotFound
 }
 func (m *mockAssetServiceRepo) GetByIDs(ids []string) ([]entities.AssetEntity, error) {
-	return nil, nil
+	var found []entities.AssetEntity
+	for _, id := range ids {
+		for _, asset := range m.assets {
+			if asset.GetID() == id {
+				found = append(found, asset)
+			}
+		}
+	}
+	return found, nil
+}
+func (m *mockAssetServiceRepo) GetAll() ([]entities.AssetEntity, error) { return m.assets, nil }
+func (m *mockAssetServiceRepo) Query(query entities.AssetQuery) (entities.AssetPage, error) {
+	m.query = query
+	return m.page, nil
 }
-func (m *mockAssetServiceRepo) GetAll() ([]entities.AssetEntity, error) { return nil, nil }
 func (m *mockAssetServiceRepo) GetByType(assetType entities.AssetType) ([]entities.AssetEntity, error) {
 	return nil, nil
 }
-func (m *mockAssetServiceRepo) Update(asset entities.AssetEntity) error { return nil }
-func (m *mockAssetServiceRepo) Exists(id string) (bool, error)          { return false, nil }
+func (m *mockAssetServiceRepo) Update(asset entities.AssetEntity) (int64, error) {
+	m.updated = asset
+	return m.stored.GetVersion() + 1, nil
+}
+func (m *mockAssetServiceRepo) CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error) {
+	m.expectedVersion = expectedVersion
+	if m.stored.GetVersion() != expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+	return m.Update(asset)
+}
+func (m *mockAssetServiceRepo) CompareAndDelete(id string, expectedVersion int64) error {
+	m.expectedVersion = expectedVersion
+	return m.Delete(id)
+}
+func (m *mockAssetServiceRepo) Exists(id string) (bool, error) {
+	if m.savedAfterLookup {
+		m.savedAfterLookup = false
+		return false, nil
+	}
+	return m.stored != nil && m.stored.GetID() == id, nil
+}
 
 func newValidInsight() *domain.Insight {
 	return &domain.Insight{
@@ -57,7 +114,7 @@ func newValidInsight() *domain.Insight {
 func TestCreateAsset_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockAssetServiceRepo{}
-	service := services.NewAssetService(mockRepo)
+	service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
 	asset := newValidInsight()
 
 	// Act
@@ -86,7 +143,7 @@ func TestCreateAsset_Success(t *testing.T) {
 func TestCreateAsset_SaveFails(t *testing.T) {
 	// Arrange
 	mockRepo := &mockAssetServiceRepo{saveErr: errors.New("save failed")}
-	service := services.NewAssetService(mockRepo)
+	service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
 	asset := newValidInsight()
 
 	// Act
@@ -98,13 +155,84 @@ func TestCreateAsset_SaveFails(t *testing.T) {
 	}
 }
 
+func TestCreateAsset_TakenID(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T) ports.AssetRepository
+	}{
+		{
+			name: "in-memory",
+			open: func(t *testing.T) ports.AssetRepository {
+				assetCache, _ := lru.New[string, entities.AssetEntity](100)
+				return inmemory.NewAssetRepository(assetCache)
+			},
+		},
+		{
+			name: "file",
+			open: func(t *testing.T) ports.AssetRepository {
+				store, err := filestore.Open(filepath.Join(t.TempDir(), "store.db"), 0)
+				if err != nil {
+					t.Fatalf("failed to open store: %v", err)
+				}
+				t.Cleanup(func() { store.Close() })
+				return filestore.NewAssetRepository(store)
+			},
+		},
+		{
+			name: "sql",
+			open: func(t *testing.T) ports.AssetRepository {
+				db, err := sqlrepo.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+				if err != nil {
+					t.Fatalf("failed to open database: %v", err)
+				}
+				t.Cleanup(func() { db.Close() })
+				if err := sqlrepo.Migrate(db); err != nil {
+					t.Fatalf("failed to migrate database: %v", err)
+				}
+				return sqlrepo.NewAssetRepository(db)
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			// Arrange
+			repo := backend.open(t)
+			service := services.NewAssetService(repo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+			if _, err := service.CreateAsset(newValidInsight()); err != nil {
+				t.Fatalf("failed to create asset: %v", err)
+			}
+			if _, err := service.UpdateAsset(newValidInsight(), 1); err != nil {
+				t.Fatalf("failed to update asset: %v", err)
+			}
+			duplicate := newValidInsight()
+			duplicate.Title = "Duplicate"
+
+			// Act
+			_, err := service.CreateAsset(duplicate)
+
+			// Assert
+			if !errors.Is(err, domain.ErrAssetExists) {
+				t.Fatalf("expected ErrAssetExists, got %v", err)
+			}
+			stored, err := service.GetAsset("1")
+			if err != nil {
+				t.Fatalf("failed to get asset: %v", err)
+			}
+			if stored.GetTitle() != "Example Insight" || stored.GetVersion() != 2 {
+				t.Errorf("expected the stored asset to be kept at version 2, got %q at version %d", stored.GetTitle(), stored.GetVersion())
+			}
+		})
+	}
+}
+
 func TestDeleteAsset_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockAssetServiceRepo{}
-	service := services.NewAssetService(mockRepo)
+	service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
 
 	// Act
-	err := service.DeleteAsset("asset1")
+	err := service.DeleteAsset("asset1", ports.AnyVersion)
 
 	// Assert
 	if err != nil {
@@ -118,13 +246,530 @@ func TestDeleteAsset_Success(t *testing.T) {
 func TestDeleteAsset_DeleteFails(t *testing.T) {
 	// Arrange
 	mockRepo := &mockAssetServiceRepo{deleteErr: errors.New("delete failed")}
-	service := services.NewAssetService(mockRepo)
+	service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
 
 	// Act
-	err := service.DeleteAsset("asset1")
+	err := service.DeleteAsset("asset1", ports.AnyVersion)
 
 	// Assert
 	if err == nil {
 		t.Error("expected error when Delete fails")
 	}
 }
+
+func TestDeleteAsset_CascadesToFavourites(t *testing.T) {
+	for _, keepTombstones := range []bool{true, false} {
+		// Arrange
+		asset := &entities.ChartEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a1", Type: entities.AssetTypeChart, Title: "Sales"}}
+		favourites := &mockFavouriteRepo{favourites: []entities.FavouriteEntity{
+			{UserId: "u1", AssetId: "a1"}, {UserId: "u2", AssetId: "a1"}, {UserId: "u1", AssetId: "a2"},
+		}}
+		collections := newMockCollectionRepo()
+		collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", AssetIds: []string{"a1", "a2"}})
+		service := services.NewAssetService(&mockAssetServiceRepo{stored: asset}, favourites, collections, search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), keepTombstones)
+
+		// Act
+		err := service.DeleteAsset("a1", ports.AnyVersion)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(favourites.favourites) != 1 || favourites.favourites[0].AssetId != "a2" {
+			t.Errorf("expected only the favourites of a1 to be removed, got %+v", favourites.favourites)
+		}
+		if got := collections.collections["c1"].AssetIds; len(got) != 1 || got[0] != "a2" {
+			t.Errorf("expected a1 to leave its collections, got %v", got)
+		}
+		if !keepTombstones {
+			if len(favourites.tombstones) != 0 {
+				t.Errorf("expected no tombstones, got %+v", favourites.tombstones)
+			}
+			continue
+		}
+		if len(favourites.tombstones) != 2 {
+			t.Fatalf("expected a tombstone per user, got %+v", favourites.tombstones)
+		}
+		for _, tombstone := range favourites.tombstones {
+			if tombstone.AssetTitle != "Sales" || tombstone.AssetType != entities.AssetTypeChart || tombstone.RemovedAt.IsZero() {
+				t.Errorf("unexpected tombstone: %+v", tombstone)
+			}
+		}
+	}
+}
+
+func TestDeleteAsset_ConflictKeepsFavourites(t *testing.T) {
+	// Arrange
+	asset := &entities.ChartEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a1", Type: entities.AssetTypeChart, Title: "Sales"}}
+	favourites := &mockFavouriteRepo{favourites: []entities.FavouriteEntity{{UserId: "u1", AssetId: "a1"}, {UserId: "u2", AssetId: "a1"}}}
+	collections := newMockCollectionRepo()
+	collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", AssetIds: []string{"a1"}})
+	service := services.NewAssetService(&mockAssetServiceRepo{stored: asset, deleteErr: ports.ErrVersionConflict}, favourites, collections, search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+	// Act
+	err := service.DeleteAsset("a1", 3)
+
+	// Assert
+	if !errors.Is(err, ports.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+	if len(favourites.favourites) != 2 {
+		t.Errorf("expected the favourites of a1 to be restored, got %+v", favourites.favourites)
+	}
+	if len(favourites.tombstones) != 0 {
+		t.Errorf("expected the tombstones to be taken back, got %+v", favourites.tombstones)
+	}
+	if got := collections.collections["c1"].AssetIds; len(got) != 1 {
+		t.Errorf("expected a1 to stay in its collections, got %v", got)
+	}
+}
+
+func TestGetAsset_NotFound(t *testing.T) {
+	// Arrange
+	service := services.NewAssetService(&mockAssetServiceRepo{}, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+	// Act
+	_, err := service.GetAsset("missing")
+
+	// Assert
+	if !errors.Is(err, domain.ErrAssetNotFound) {
+		t.Errorf("expected ErrAssetNotFound, got %v", err)
+	}
+}
+
+func TestListAssets(t *testing.T) {
+	insight := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "1", Type: entities.AssetTypeInsight, Title: "Example Insight"},
+		Text:            "Valid insight text",
+	}
+
+	t.Run("maps the query and encodes the next cursor", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{page: entities.AssetPage{
+			Assets: []entities.AssetEntity{insight},
+			Next:   entities.CursorFor(insight),
+		}}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		assetType := domain.AssetTypeInsight
+
+		// Act
+		page, err := service.ListAssets(domain.AssetQuery{Type: &assetType, SortBy: domain.AssetSortTitle, Limit: 1})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mockRepo.query.Type == nil || *mockRepo.query.Type != entities.AssetTypeInsight {
+			t.Errorf("expected insight type filter, got %+v", mockRepo.query.Type)
+		}
+		if mockRepo.query.SortBy != entities.AssetSortTitle || mockRepo.query.Limit != 1 {
+			t.Errorf("unexpected repository query %+v", mockRepo.query)
+		}
+		if len(page.Assets) != 1 || page.Assets[0].GetID() != "1" {
+			t.Errorf("expected the insight to be returned, got %+v", page.Assets)
+		}
+		if page.NextCursor == "" {
+			t.Fatal("expected a next cursor")
+		}
+
+		// Act
+		_, err = service.ListAssets(domain.AssetQuery{SortBy: domain.AssetSortTitle, Limit: 1, Cursor: page.NextCursor})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mockRepo.query.After == nil || mockRepo.query.After.ID != "1" {
+			t.Errorf("expected the cursor to resume after asset 1, got %+v", mockRepo.query.After)
+		}
+	})
+
+	t.Run("rejects a cursor issued for another sort order", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{page: entities.AssetPage{Next: entities.CursorFor(insight)}}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		page, _ := service.ListAssets(domain.AssetQuery{SortBy: domain.AssetSortTitle})
+
+		// Act
+		_, err := service.ListAssets(domain.AssetQuery{SortBy: domain.AssetSortTitle, Descending: true, Cursor: page.NextCursor})
+
+		// Assert
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("rejects a malformed cursor", func(t *testing.T) {
+		// Arrange
+		service := services.NewAssetService(&mockAssetServiceRepo{}, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+		// Act
+		_, err := service.ListAssets(domain.AssetQuery{Cursor: "not a cursor"})
+
+		// Assert
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestSearchAssets(t *testing.T) {
+	newInsightEntity := func(id, title, text string) *entities.InsightEntity {
+		return &entities.InsightEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{ID: id, Type: entities.AssetTypeInsight, Title: title},
+			Text:            text,
+		}
+	}
+	stored := []entities.AssetEntity{
+		newInsightEntity("1", "Millennials on social media", "Millennials spend hours socialising online"),
+		newInsightEntity("2", "Gen Z shopping", "Gen Z prefers social commerce"),
+		newInsightEntity("3", "Boomers and television", "Boomers still watch linear TV"),
+	}
+
+	newService := func(favourites *mockFavouriteRepo) *services.AssetServiceImpl {
+		service := services.NewAssetService(&mockAssetServiceRepo{assets: stored}, favourites, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		if err := service.RebuildIndexes(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return service
+	}
+
+	t.Run("ranks every matching asset", func(t *testing.T) {
+		// Arrange
+		service := newService(&mockFavouriteRepo{})
+
+		// Act
+		results, err := service.SearchAssets("millennials social media", "", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 2 || results[0].Asset.GetID() != "1" || results[1].Asset.GetID() != "2" {
+			t.Fatalf("expected assets 1 then 2, got %+v", results)
+		}
+		if results[0].Score <= results[1].Score {
+			t.Errorf("expected descending scores, got %v and %v", results[0].Score, results[1].Score)
+		}
+	})
+
+	t.Run("restricts to the user's favourites", func(t *testing.T) {
+		// Arrange
+		service := newService(&mockFavouriteRepo{favourites: []entities.FavouriteEntity{{UserId: "u1", AssetId: "2"}}})
+
+		// Act
+		results, err := service.SearchAssets("social", "u1", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Asset.GetID() != "2" {
+			t.Errorf("expected only asset 2, got %+v", results)
+		}
+	})
+
+	t.Run("user without favourites finds nothing", func(t *testing.T) {
+		// Arrange
+		service := newService(&mockFavouriteRepo{})
+
+		// Act
+		results, err := service.SearchAssets("social", "u1", 10)
+
+		// Assert
+		if err != nil || len(results) != 0 {
+			t.Errorf("expected no results, got %+v, %v", results, err)
+		}
+	})
+
+	t.Run("deleted assets leave the index", func(t *testing.T) {
+		// Arrange
+		service := newService(&mockFavouriteRepo{})
+
+		// Act
+		err := service.DeleteAsset("3", ports.AnyVersion)
+		results, _ := service.SearchAssets("television", "", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected deleted asset not to be found, got %+v", results)
+		}
+	})
+}
+
+func TestUpdateAsset(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{
+			ID:        "1",
+			Type:      entities.AssetTypeInsight,
+			Title:     "Example Insight",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Version:   4,
+		},
+		Text: "Valid insight text",
+	}
+
+	t.Run("replaces fields, keeps CreatedAt and bumps UpdatedAt", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{stored: stored}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		asset := newValidInsight()
+		asset.Description = "New description"
+
+		// Act
+		updated, err := service.UpdateAsset(asset, ports.AnyVersion)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !updated.GetCreatedAt().Equal(createdAt) {
+			t.Errorf("expected CreatedAt %v, got %v", createdAt, updated.GetCreatedAt())
+		}
+		if !updated.GetUpdatedAt().After(createdAt) {
+			t.Error("expected UpdatedAt to be bumped")
+		}
+		if updated.GetVersion() != 5 {
+			t.Errorf("expected version 5, got %d", updated.GetVersion())
+		}
+		saved, ok := mockRepo.updated.(*entities.InsightEntity)
+		if !ok || saved.Description != "New description" {
+			t.Errorf("expected updated insight to be stored, got %+v", mockRepo.updated)
+		}
+	})
+
+	t.Run("conditions the write on the expected version", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{stored: stored}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+		// Act
+		_, err := service.UpdateAsset(newValidInsight(), 3)
+
+		// Assert
+		if !errors.Is(err, ports.ErrVersionConflict) {
+			t.Errorf("expected ErrVersionConflict, got %v", err)
+		}
+		if mockRepo.expectedVersion != 3 {
+			t.Errorf("expected CompareAndSwap with version 3, got %d", mockRepo.expectedVersion)
+		}
+	})
+
+	t.Run("rejects type changes", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{stored: stored}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		chart := &domain.Chart{
+			AssetBase: domain.AssetBase{ID: "1", Type: domain.AssetTypeChart, Title: "Chart"},
+			Data:      [][]float64{{1, 2}},
+		}
+
+		// Act
+		_, err := service.UpdateAsset(chart, ports.AnyVersion)
+
+		// Assert
+		if !errors.Is(err, domain.ErrAssetTypeImmutable) {
+			t.Errorf("expected ErrAssetTypeImmutable, got %v", err)
+		}
+		if mockRepo.updated != nil {
+			t.Error("expected Update not to be called")
+		}
+	})
+
+	t.Run("validates through the type-specific Validate", func(t *testing.T) {
+		// Arrange
+		mockRepo := &mockAssetServiceRepo{stored: stored}
+		service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+		asset := newValidInsight()
+		asset.Text = " "
+
+		// Act
+		_, err := service.UpdateAsset(asset, ports.AnyVersion)
+
+		// Assert
+		if !errors.Is(err, domain.ErrInvalidAsset) {
+			t.Errorf("expected ErrInvalidAsset, got %v", err)
+		}
+		if mockRepo.updated != nil {
+			t.Error("expected Update not to be called")
+		}
+	})
+}
+
+func TestImportAsset(t *testing.T) {
+	stored := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "1", Type: entities.AssetTypeInsight, Title: "Example Insight", Version: 1},
+		Text:            "Valid insight text",
+	}
+	chart := &domain.Chart{AssetBase: domain.AssetBase{ID: "1", Type: domain.AssetTypeChart, Title: "Chart"}, Data: [][]float64{{1, 2}}}
+	invalid := newValidInsight()
+	invalid.Title = " "
+
+	tests := []struct {
+		name             string
+		stored           entities.AssetEntity
+		savedAfterLookup bool
+		asset            domain.Asset
+		mode             domain.AssetImportMode
+		wantOutcome      domain.AssetImportOutcome
+		wantErr          error
+		wantSaved        bool
+		wantUpdated      bool
+	}{
+		{name: "creates a new asset", asset: newValidInsight(), mode: domain.AssetImportInsert, wantOutcome: domain.AssetImportCreated, wantSaved: true},
+		{name: "upsert creates a new asset", asset: newValidInsight(), mode: domain.AssetImportUpsert, wantOutcome: domain.AssetImportCreated, wantSaved: true},
+		{name: "insert rejects a taken id", stored: stored, asset: newValidInsight(), mode: domain.AssetImportInsert, wantErr: domain.ErrAssetExists},
+		{name: "upsert replaces a stored asset", stored: stored, asset: newValidInsight(), mode: domain.AssetImportUpsert, wantOutcome: domain.AssetImportUpdated, wantUpdated: true},
+		{name: "insert rejects an id taken after the lookup", stored: stored, savedAfterLookup: true, asset: newValidInsight(), mode: domain.AssetImportInsert, wantErr: domain.ErrAssetExists, wantSaved: true},
+		{name: "upsert replaces an asset saved after the lookup", stored: stored, savedAfterLookup: true, asset: newValidInsight(), mode: domain.AssetImportUpsert, wantOutcome: domain.AssetImportUpdated, wantSaved: true, wantUpdated: true},
+		{name: "upsert keeps the stored type", stored: stored, asset: chart, mode: domain.AssetImportUpsert, wantErr: domain.ErrAssetTypeImmutable},
+		{name: "rejects an invalid asset", asset: invalid, mode: domain.AssetImportInsert, wantErr: domain.ErrInvalidAsset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := &mockAssetServiceRepo{stored: tt.stored, savedAfterLookup: tt.savedAfterLookup}
+			if tt.savedAfterLookup {
+				mockRepo.saveErr = ports.ErrAssetExists
+			}
+			service := services.NewAssetService(mockRepo, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+			// Act
+			outcome, err := service.ImportAsset(tt.asset, tt.mode)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %q, got %q", tt.wantOutcome, outcome)
+			}
+			if mockRepo.saveCalled != tt.wantSaved {
+				t.Errorf("expected Save called %v, got %v", tt.wantSaved, mockRepo.saveCalled)
+			}
+			if (mockRepo.updated != nil) != tt.wantUpdated {
+				t.Errorf("expected Update called %v, got %v", tt.wantUpdated, mockRepo.updated != nil)
+			}
+		})
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	chart := &entities.ChartEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "c1", Type: entities.AssetTypeChart, Title: "Sales", Version: 1},
+		AxesTitles:      `["Month","Units"]`,
+		Data:            `[[1,10],[2,12]]`,
+	}
+	insight := &entities.InsightEntity{
+		AssetBaseEntity: entities.AssetBaseEntity{ID: "c1", Type: entities.AssetTypeInsight, Title: "Example Insight", Version: 1},
+		Text:            "Valid insight text",
+	}
+	options := domain.ChartRenderOptions{Format: domain.ChartSVG, Kind: domain.ChartBar, Width: 300, Height: 200}
+
+	tests := []struct {
+		name       string
+		stored     entities.AssetEntity
+		wantErr    error
+		wantPrefix string
+	}{
+		{name: "renders a chart", stored: chart, wantPrefix: "<svg"},
+		{name: "rejects other assets", stored: insight, wantErr: domain.ErrNotAChart},
+		{name: "missing asset", wantErr: domain.ErrAssetNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := services.NewAssetService(&mockAssetServiceRepo{stored: tt.stored}, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+
+			// Act
+			image, err := service.RenderChart("c1", options)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !strings.HasPrefix(string(image), tt.wantPrefix) {
+				t.Errorf("expected an image starting with %q, got %q", tt.wantPrefix, image)
+			}
+		})
+	}
+}
+
+func TestMatchAudiences(t *testing.T) {
+	newAudienceEntity := func(id, criteria string) *entities.AudienceEntity {
+		return &entities.AudienceEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{ID: id, Type: entities.AssetTypeAudience, Title: id},
+			Criteria:        criteria,
+		}
+	}
+	stored := []entities.AssetEntity{
+		newAudienceEntity("aud-2", `{"op":"in","field":"gender","values":["female"]}`),
+		newAudienceEntity("aud-1", `{"op":"and","criteria":[{"op":"between","field":"age","min":18,"max":24},{"op":"gt","field":"hours_social","value":3}]}`),
+		&entities.AudienceEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{ID: "aud-3", Type: entities.AssetTypeAudience, Title: "Canadians"},
+			BirthCountry:    "CA",
+		},
+	}
+	age, hours := 20, 4.0
+
+	tests := []struct {
+		name     string
+		profiles []domain.RespondentProfile
+		want     [][]string
+		wantErr  string
+	}{
+		{
+			name: "matches every profile in order",
+			profiles: []domain.RespondentProfile{
+				{Gender: domain.GenderFemale, Age: &age, HoursSocial: &hours},
+				{BirthCountry: "CA"},
+				{Gender: domain.GenderMale},
+			},
+			want: [][]string{{"aud-1", "aud-2"}, {"aud-3"}, {}},
+		},
+		{
+			name:     "rejects an invalid profile before matching any",
+			profiles: []domain.RespondentProfile{{BirthCountry: "GB"}, {BirthCountry: "Canada"}},
+			wantErr:  `profile 1: invalid birth country "Canada"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := services.NewAssetService(&mockAssetServiceRepo{assets: stored}, &mockFavouriteRepo{}, newMockCollectionRepo(), search.NewAssetIndex(), matching.NewAudienceMatcher(), render.NewChartRenderer(16), true)
+			if err := service.RebuildIndexes(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Act
+			got, err := service.MatchAudiences(tt.profiles)
+
+			// Assert
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrInvalidProfile) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an invalid profile error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if strings.Join(got[i], ",") != strings.Join(tt.want[i], ",") {
+					t.Errorf("profile %d: expected %v, got %v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
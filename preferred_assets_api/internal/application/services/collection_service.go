@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/google/uuid"
+)
+
+var _ ports.CollectionService = (*CollectionServiceImpl)(nil)
+
+// collectionUpdateAttempts bounds how often a change is reapplied to a fresh read after a concurrent change of the same
+// collection got in first
+const collectionUpdateAttempts = 5
+
+type CollectionServiceImpl struct {
+	collectionRepo ports.CollectionRepository
+	favouriteRepo  ports.FavouriteRepository
+	assetRepo      ports.AssetRepository
+	userRepo       ports.UserRepository
+}
+
+func NewCollectionService(collectionRepo ports.CollectionRepository, favouriteRepo ports.FavouriteRepository, assetRepo ports.AssetRepository, userRepo ports.UserRepository) *CollectionServiceImpl {
+	return &CollectionServiceImpl{
+		collectionRepo: collectionRepo,
+		favouriteRepo:  favouriteRepo,
+		assetRepo:      assetRepo,
+		userRepo:       userRepo}
+}
+
+// CreateCollection implements ports.CollectionService.
+func (s *CollectionServiceImpl) CreateCollection(userID string, name string) (domain.Collection, error) {
+	if err := s.ensureUserExists(userID); err != nil {
+		return domain.Collection{}, err
+	}
+
+	now := time.Now().UTC()
+	collection := domain.Collection{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		AssetIDs:  []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := collection.Validate(); err != nil {
+		return domain.Collection{}, err
+	}
+	if err := s.ensureNameAvailable(userID, "", collection.Name); err != nil {
+		return domain.Collection{}, err
+	}
+
+	if err := s.collectionRepo.Save(mapper.CollectionEntityFromDomain(collection)); err != nil {
+		return domain.Collection{}, err
+	}
+
+	// Deleting a user removes it before its collections, so a user still there now cannot leave this collection
+	// behind. One deleted meanwhile may have been cleaned up before the save; take the collection back.
+	if err := s.ensureUserExists(userID); err != nil {
+		if deleteErr := s.collectionRepo.Delete(collection.ID); deleteErr != nil && !errors.Is(deleteErr, ports.ErrCollectionNotFound) {
+			return domain.Collection{}, deleteErr
+		}
+		return domain.Collection{}, err
+	}
+	return collection, nil
+}
+
+// ensureUserExists returns domain.ErrUnknownUser if the user does not exist
+func (s *CollectionServiceImpl) ensureUserExists(userID string) error {
+	_, err := s.userRepo.GetByID(userID)
+	if errors.Is(err, ports.ErrUserNotFound) {
+		return fmt.Errorf("%w: %s", domain.ErrUnknownUser, userID)
+	}
+	return err
+}
+
+// GetCollections implements ports.CollectionService.
+func (s *CollectionServiceImpl) GetCollections(userID string) ([]domain.Collection, error) {
+	collections, err := s.collectionRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CollectionEntityToDomainList(collections), nil
+}
+
+// GetCollection implements ports.CollectionService.
+// Items whose asset no longer exists are left out, like in the user's favourites list.
+func (s *CollectionServiceImpl) GetCollection(userID string, collectionID string) (domain.Collection, error) {
+	collection, err := s.getOwned(userID, collectionID)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+
+	favouriteEntities, err := s.favouriteRepo.GetByUserID(userID)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	favouritesByAsset := make(map[string]domain.Favourite, len(favouriteEntities))
+	for _, favourite := range mapper.FavouriteEntityToDomainList(favouriteEntities) {
+		favouritesByAsset[favourite.AssetID] = favourite
+	}
+
+	items := make([]domain.Favourite, 0, len(collection.AssetIDs))
+	for _, assetID := range collection.AssetIDs {
+		if favourite, ok := favouritesByAsset[assetID]; ok {
+			items = append(items, favourite)
+		}
+	}
+
+	collection.Favourites, err = batchEnhanceFavourites(s.assetRepo, items)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return collection, nil
+}
+
+// RenameCollection implements ports.CollectionService.
+func (s *CollectionServiceImpl) RenameCollection(userID string, collectionID string, name string) (domain.Collection, error) {
+	return s.modify(userID, collectionID, func(collection *domain.Collection) (bool, error) {
+		collection.Name = strings.TrimSpace(name)
+		if err := collection.Validate(); err != nil {
+			return false, err
+		}
+		return true, s.ensureNameAvailable(userID, collectionID, collection.Name)
+	})
+}
+
+// DeleteCollection implements ports.CollectionService.
+// The favourites in the collection are kept.
+func (s *CollectionServiceImpl) DeleteCollection(userID string, collectionID string) error {
+	if _, err := s.getOwned(userID, collectionID); err != nil {
+		return err
+	}
+	return collectionNotFound(s.collectionRepo.Delete(collectionID))
+}
+
+// AddFavourite implements ports.CollectionService.
+// Every collection is checked before any is changed. The favourite is appended to the end of the
+// collections it is not in yet and keeps its position in the others.
+func (s *CollectionServiceImpl) AddFavourite(userID string, assetID string, collectionIDs []string) error {
+	isFavourite, err := s.favouriteRepo.Exists(userID, assetID)
+	if err != nil {
+		return err
+	}
+	if !isFavourite {
+		return domain.ErrNotAFavourite
+	}
+
+	collections := make([]domain.Collection, 0, len(collectionIDs))
+	seen := make(map[string]bool, len(collectionIDs))
+	for _, collectionID := range collectionIDs {
+		if seen[collectionID] {
+			continue
+		}
+		seen[collectionID] = true
+
+		collection, err := s.getOwned(userID, collectionID)
+		if err != nil {
+			return err
+		}
+		collections = append(collections, collection)
+	}
+
+	for _, collection := range collections {
+		if _, err := s.modify(userID, collection.ID, func(collection *domain.Collection) (bool, error) {
+			if collection.HasAsset(assetID) {
+				return false, nil
+			}
+			collection.AssetIDs = append(collection.AssetIDs, assetID)
+			return true, nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveFavourite implements ports.CollectionService.
+// The favourite itself is kept; removing an asset that is not in the collection is a no-op.
+func (s *CollectionServiceImpl) RemoveFavourite(userID string, collectionID string, assetID string) error {
+	_, err := s.modify(userID, collectionID, func(collection *domain.Collection) (bool, error) {
+		entity := mapper.CollectionEntityFromDomain(*collection)
+		if !entity.RemoveAsset(assetID) {
+			return false, nil
+		}
+		collection.AssetIDs = entity.AssetIds
+		return true, nil
+	})
+	return err
+}
+
+// ReorderCollection implements ports.CollectionService.
+func (s *CollectionServiceImpl) ReorderCollection(userID string, collectionID string, assetIDs []string) (domain.Collection, error) {
+	return s.modify(userID, collectionID, func(collection *domain.Collection) (bool, error) {
+		return true, collection.Reorder(assetIDs)
+	})
+}
+
+// getOwned reads a collection of the user; collections of other users are reported as not found
+func (s *CollectionServiceImpl) getOwned(userID string, collectionID string) (domain.Collection, error) {
+	entity, err := s.collectionRepo.GetByID(collectionID)
+	if err != nil {
+		return domain.Collection{}, collectionNotFound(err)
+	}
+	if entity.UserId != userID {
+		return domain.Collection{}, domain.ErrCollectionNotFound
+	}
+	return mapper.CollectionEntityToDomain(entity), nil
+}
+
+// modify applies change to the latest copy of the user's collection and stores it only if the collection is still at
+// the version read. When another change got in first it starts over from a fresh read, so no change is lost.
+// change reports whether it changed the collection; an unchanged collection is not stored.
+func (s *CollectionServiceImpl) modify(userID string, collectionID string, change func(*domain.Collection) (bool, error)) (domain.Collection, error) {
+	for attempt := 1; ; attempt++ {
+		collection, err := s.getOwned(userID, collectionID)
+		if err != nil {
+			return domain.Collection{}, err
+		}
+		changed, err := change(&collection)
+		if err != nil {
+			return domain.Collection{}, err
+		}
+		if !changed {
+			return collection, nil
+		}
+
+		collection.UpdatedAt = time.Now().UTC()
+		version, err := s.collectionRepo.CompareAndSwap(mapper.CollectionEntityFromDomain(collection), collection.Version)
+		if errors.Is(err, ports.ErrVersionConflict) {
+			if attempt < collectionUpdateAttempts {
+				continue
+			}
+			return domain.Collection{}, domain.ErrVersionConflict
+		}
+		if err != nil {
+			return domain.Collection{}, collectionNotFound(err)
+		}
+		collection.Version = version
+		return collection, nil
+	}
+}
+
+// ensureNameAvailable rejects a name already used, case-insensitively, by another collection of the user
+func (s *CollectionServiceImpl) ensureNameAvailable(userID string, collectionID string, name string) error {
+	collections, err := s.collectionRepo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	for _, collection := range collections {
+		if collection.Id != collectionID && strings.EqualFold(collection.Name, name) {
+			return domain.ErrCollectionNameTaken
+		}
+	}
+	return nil
+}
+
+// collectionNotFound translates the repository not-found error into its domain counterpart
+func collectionNotFound(err error) error {
+	if errors.Is(err, ports.ErrCollectionNotFound) {
+		return domain.ErrCollectionNotFound
+	}
+	return err
+}
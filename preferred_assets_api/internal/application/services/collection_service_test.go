@@ -0,0 +1,414 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/filestore"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+)
+
+// collectingUsers are the users the collection tests create collections for
+var collectingUsers = &mockUserRepo{users: map[string]entities.UserEntity{"u1": {Id: "u1"}, "u2": {Id: "u2"}}}
+
+// Mocks
+type mockCollectionRepo struct {
+	collections map[string]entities.CollectionEntity
+
+	// concurrentChange, if set, is applied to the stored collection by the next concurrentChanges compare-and-swaps
+	// before they compare versions, as if another request got in first
+	concurrentChange  func(*entities.CollectionEntity)
+	concurrentChanges int
+}
+
+func newMockCollectionRepo() *mockCollectionRepo {
+	return &mockCollectionRepo{collections: make(map[string]entities.CollectionEntity)}
+}
+
+func (m *mockCollectionRepo) Save(c entities.CollectionEntity) error {
+	c = c.Clone()
+	c.Version = 1
+	m.collections[c.Id] = c
+	return nil
+}
+
+func (m *mockCollectionRepo) GetByID(id string) (entities.CollectionEntity, error) {
+	c, ok := m.collections[id]
+	if !ok {
+		return entities.CollectionEntity{}, ports.ErrCollectionNotFound
+	}
+	return c.Clone(), nil
+}
+
+func (m *mockCollectionRepo) GetByUserID(userID string) ([]entities.CollectionEntity, error) {
+	result := []entities.CollectionEntity{}
+	for _, c := range m.collections {
+		if c.UserId == userID {
+			result = append(result, c.Clone())
+		}
+	}
+	entities.SortCollections(result)
+	return result, nil
+}
+
+func (m *mockCollectionRepo) Update(c entities.CollectionEntity) (int64, error) {
+	stored, ok := m.collections[c.Id]
+	if !ok {
+		return 0, ports.ErrCollectionNotFound
+	}
+	return m.CompareAndSwap(c, stored.Version)
+}
+
+func (m *mockCollectionRepo) CompareAndSwap(c entities.CollectionEntity, expectedVersion int64) (int64, error) {
+	stored, ok := m.collections[c.Id]
+	if !ok {
+		return 0, ports.ErrCollectionNotFound
+	}
+	if m.concurrentChanges > 0 {
+		m.concurrentChanges--
+		m.concurrentChange(&stored)
+		stored.Version++
+		m.collections[c.Id] = stored
+	}
+	if stored.Version != expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+	c = c.Clone()
+	c.Version = stored.Version + 1
+	m.collections[c.Id] = c
+	return c.Version, nil
+}
+
+func (m *mockCollectionRepo) Delete(id string) error {
+	if _, ok := m.collections[id]; !ok {
+		return ports.ErrCollectionNotFound
+	}
+	delete(m.collections, id)
+	return nil
+}
+
+func (m *mockCollectionRepo) DeleteByUserID(userID string) error {
+	for id, c := range m.collections {
+		if c.UserId == userID {
+			delete(m.collections, id)
+		}
+	}
+	return nil
+}
+
+func (m *mockCollectionRepo) RemoveAsset(userID, assetID string) error {
+	for id, c := range m.collections {
+		if c.UserId == userID && c.RemoveAsset(assetID) {
+			m.collections[id] = c
+		}
+	}
+	return nil
+}
+
+func newCollectionService(t *testing.T) (*services.CollectionServiceImpl, *mockCollectionRepo) {
+	t.Helper()
+	now := time.Now().UTC()
+	favourites := &mockFavouriteRepo{favourites: []entities.FavouriteEntity{
+		{UserId: "u1", AssetId: "a1", CreatedAt: now},
+		{UserId: "u1", AssetId: "a2", CreatedAt: now},
+		{UserId: "u1", AssetId: "gone", CreatedAt: now},
+	}}
+	assets := &mockAssetServiceRepo{assets: []entities.AssetEntity{
+		&entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a1", Type: entities.AssetTypeInsight, Title: "First"}, Text: "one"},
+		&entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a2", Type: entities.AssetTypeInsight, Title: "Second"}, Text: "two"},
+	}}
+	collections := newMockCollectionRepo()
+	return services.NewCollectionService(collections, favourites, assets, collectingUsers), collections
+}
+
+// Tests
+
+func TestCreateCollection(t *testing.T) {
+	// Arrange
+	service, collections := newCollectionService(t)
+
+	// Act
+	created, err := service.CreateCollection("u1", "  Campaign  ")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == "" || created.Name != "Campaign" || created.UserID != "u1" {
+		t.Errorf("unexpected collection %+v", created)
+	}
+	if _, ok := collections.collections[created.ID]; !ok {
+		t.Error("expected the collection to be saved")
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		newName string
+		wantErr error
+	}{
+		{"duplicate name is case-insensitive", "u1", "campaign", domain.ErrCollectionNameTaken},
+		{"blank name", "u1", "  ", domain.ErrInvalidCollection},
+		{"same name for another user", "u2", "Campaign", nil},
+		{"unknown user", "missing", "Campaign", domain.ErrUnknownUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := service.CreateCollection(tt.userID, tt.newName)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateCollection_UserDeletedMeanwhile(t *testing.T) {
+	// Arrange
+	collections := newMockCollectionRepo()
+	users := &vanishingUserRepo{mockUserRepo: *collectingUsers}
+	service := services.NewCollectionService(collections, &mockFavouriteRepo{}, &mockAssetServiceRepo{}, users)
+
+	// Act
+	_, err := service.CreateCollection("u1", "Campaign")
+
+	// Assert
+	if !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("expected ErrUnknownUser, got %v", err)
+	}
+	if len(collections.collections) != 0 {
+		t.Errorf("expected the collection to be taken back, got %+v", collections.collections)
+	}
+}
+
+func TestCollectionItems(t *testing.T) {
+	// Arrange
+	service, _ := newCollectionService(t)
+	first, _ := service.CreateCollection("u1", "First")
+	second, _ := service.CreateCollection("u1", "Second")
+
+	// Act
+	err := service.AddFavourite("u1", "a1", []string{first.ID, second.ID})
+	if err == nil {
+		err = service.AddFavourite("u1", "a2", []string{first.ID})
+	}
+	if err == nil {
+		err = service.AddFavourite("u1", "gone", []string{first.ID})
+	}
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := service.GetCollection("u1", first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Favourites) != 2 || got.Favourites[0].AssetID != "a1" || got.Favourites[1].AssetID != "a2" {
+		t.Fatalf("expected a1 and a2 with their assets and without the deleted asset, got %+v", got.Favourites)
+	}
+	if got.Favourites[0].Insight == nil || got.Favourites[0].Insight.Title != "First" {
+		t.Errorf("expected favourites to be enhanced with their assets, got %+v", got.Favourites[0])
+	}
+
+	// Act
+	reordered, err := service.ReorderCollection("u1", first.ID, []string{"gone", "a2", "a1"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reordered.AssetIDs[0] != "gone" || reordered.AssetIDs[2] != "a1" {
+		t.Errorf("expected the new order, got %v", reordered.AssetIDs)
+	}
+
+	// Act
+	err = service.RemoveFavourite("u1", first.ID, "a2")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ = service.GetCollection("u1", first.ID)
+	if len(got.AssetIDs) != 2 || got.HasAsset("a2") {
+		t.Errorf("expected a2 to be removed, got %v", got.AssetIDs)
+	}
+}
+
+func TestCollectionErrors(t *testing.T) {
+	service, _ := newCollectionService(t)
+	mine, _ := service.CreateCollection("u1", "Mine")
+	theirs, _ := service.CreateCollection("u2", "Theirs")
+	service.AddFavourite("u1", "a1", []string{mine.ID})
+
+	tests := []struct {
+		name    string
+		act     func() error
+		wantErr error
+	}{
+		{"unknown collection", func() error {
+			_, err := service.GetCollection("u1", "missing")
+			return err
+		}, domain.ErrCollectionNotFound},
+		{"another user's collection", func() error {
+			_, err := service.GetCollection("u1", theirs.ID)
+			return err
+		}, domain.ErrCollectionNotFound},
+		{"delete another user's collection", func() error {
+			return service.DeleteCollection("u1", theirs.ID)
+		}, domain.ErrCollectionNotFound},
+		{"add an asset that is not a favourite", func() error {
+			return service.AddFavourite("u1", "not-favourite", []string{mine.ID})
+		}, domain.ErrNotAFavourite},
+		{"add to an unknown collection among valid ones", func() error {
+			return service.AddFavourite("u1", "a2", []string{mine.ID, "missing"})
+		}, domain.ErrCollectionNotFound},
+		{"order missing an item", func() error {
+			_, err := service.ReorderCollection("u1", mine.ID, []string{})
+			return err
+		}, domain.ErrInvalidCollectionOrder},
+		{"order with an unknown item", func() error {
+			_, err := service.ReorderCollection("u1", mine.ID, []string{"a2"})
+			return err
+		}, domain.ErrInvalidCollectionOrder},
+		{"rename to a taken name", func() error {
+			service.CreateCollection("u1", "Other")
+			_, err := service.RenameCollection("u1", mine.ID, "OTHER")
+			return err
+		}, domain.ErrCollectionNameTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.act()
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	got, _ := service.GetCollection("u1", mine.ID)
+	if got.HasAsset("a2") {
+		t.Error("expected a failed multi-collection add not to change any collection")
+	}
+}
+
+func TestRenameAndDeleteCollection(t *testing.T) {
+	// Arrange
+	service, collections := newCollectionService(t)
+	created, _ := service.CreateCollection("u1", "Draft")
+
+	// Act
+	renamed, err := service.RenameCollection("u1", created.ID, "Draft")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected renaming to the same name to succeed, got %v", err)
+	}
+	if renamed.Name != "Draft" {
+		t.Errorf("unexpected name %q", renamed.Name)
+	}
+
+	// Act
+	err = service.DeleteCollection("u1", created.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collections.collections) != 0 {
+		t.Error("expected the collection to be deleted")
+	}
+}
+
+func TestCollectionConcurrentChanges(t *testing.T) {
+	addA2 := func(c *entities.CollectionEntity) {
+		if !slices.Contains(c.AssetIds, "a2") {
+			c.AssetIds = append(c.AssetIds, "a2")
+		}
+	}
+
+	t.Run("reapplies the change on top of one made meanwhile", func(t *testing.T) {
+		// Arrange
+		service, collections := newCollectionService(t)
+		created, _ := service.CreateCollection("u1", "Campaign")
+		collections.concurrentChange, collections.concurrentChanges = addA2, 1
+
+		// Act
+		err := service.AddFavourite("u1", "a1", []string{created.ID})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := service.GetCollection("u1", created.ID)
+		if !slices.Equal(got.AssetIDs, []string{"a2", "a1"}) {
+			t.Errorf("expected both changes to be kept, got %v", got.AssetIDs)
+		}
+		if got.Version != 3 {
+			t.Errorf("expected version 3, got %d", got.Version)
+		}
+	})
+
+	t.Run("gives up when other changes keep getting in first", func(t *testing.T) {
+		// Arrange
+		service, collections := newCollectionService(t)
+		created, _ := service.CreateCollection("u1", "Campaign")
+		collections.concurrentChange, collections.concurrentChanges = func(*entities.CollectionEntity) {}, 100
+
+		// Act
+		_, err := service.RenameCollection("u1", created.ID, "Renamed")
+
+		// Assert
+		if !errors.Is(err, domain.ErrVersionConflict) {
+			t.Errorf("expected ErrVersionConflict, got %v", err)
+		}
+	})
+
+	t.Run("keeps every one of many concurrent adds", func(t *testing.T) {
+		// Arrange
+		store, err := filestore.Open(filepath.Join(t.TempDir(), "store.db"), 0)
+		if err != nil {
+			t.Fatalf("failed to open store: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		service := services.NewCollectionService(filestore.NewCollectionRepository(store), &mockFavouriteRepo{existsResult: true}, &mockAssetServiceRepo{}, collectingUsers)
+		created, _ := service.CreateCollection("u1", "Campaign")
+
+		// Act
+		errs := make([]error, 8)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = service.AddFavourite("u1", fmt.Sprintf("a%d", i), []string{created.ID})
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		for _, err := range errs {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		got, _ := service.GetCollections("u1")
+		if len(got) != 1 || len(got[0].AssetIDs) != len(errs) {
+			t.Errorf("expected all %d items to be kept, got %+v", len(errs), got)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
@@ -12,24 +14,301 @@ import (
 var _ ports.FavouriteService = (*FavouriteServiceImpl)(nil)
 
 type FavouriteServiceImpl struct {
-	repo ports.FavouriteRepository
+	repo           ports.FavouriteRepository
+	collectionRepo ports.CollectionRepository
+	userRepo       ports.UserRepository
+	assetRepo      ports.AssetRepository
 }
 
-func NewFavouriteService(r ports.FavouriteRepository) *FavouriteServiceImpl {
-	return &FavouriteServiceImpl{repo: r}
+func NewFavouriteService(r ports.FavouriteRepository, collectionRepo ports.CollectionRepository, userRepo ports.UserRepository, assetRepo ports.AssetRepository) *FavouriteServiceImpl {
+	return &FavouriteServiceImpl{repo: r,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		assetRepo:      assetRepo}
 }
 
+// CreateFavourite implements ports.FavouriteService.
+// Both the user and the asset must exist: it returns domain.ErrUnknownUser and/or domain.ErrUnknownAsset naming
+// each missing side, and domain.ErrFavouriteExists for a duplicate. The favourite records the asset's type.
 func (s FavouriteServiceImpl) CreateFavourite(f domain.Favourite) error {
-	existing, _ := s.repo.Exists(f.UserID, f.AssetID)
-	if existing {
-		return fmt.Errorf("asset already favourited")
+	asset, err := s.referencedAsset(f)
+	if err != nil {
+		return err
 	}
 
 	fav := mapper.FavouriteEntityFromDomain(f)
+	fav.AssetType = asset.GetType()
 	fav.CreatedAt = time.Now().UTC()
-	return s.repo.Add(fav)
+	if err := s.repo.Add(fav); err != nil {
+		// A store that references users and assets from favourites rejects one whose user or asset was deleted meanwhile
+		if _, refErr := s.referencedAsset(f); refErr != nil {
+			return refErr
+		}
+		return err
+	}
+
+	// Deleting a user or an asset removes it before its favourites, so one still there now cannot leave this favourite
+	// behind. One deleted meanwhile may have been cleaned up before the add; take the favourite back.
+	_, userErr := s.userRepo.GetByID(f.UserID)
+	if userErr != nil && !errors.Is(userErr, ports.ErrUserNotFound) {
+		return userErr
+	}
+	assetExists, err := s.assetRepo.Exists(f.AssetID)
+	if err != nil {
+		return err
+	}
+	if userErr == nil && assetExists {
+		return nil
+	}
+
+	if err := s.repo.Delete(f.UserID, f.AssetID); err != nil {
+		return err
+	}
+	switch {
+	case userErr != nil && !assetExists:
+		return fmt.Errorf("%w: %s; %w: %s", domain.ErrUnknownUser, f.UserID, domain.ErrUnknownAsset, f.AssetID)
+	case userErr != nil:
+		return fmt.Errorf("%w: %s", domain.ErrUnknownUser, f.UserID)
+	default:
+		return fmt.Errorf("%w: %s", domain.ErrUnknownAsset, f.AssetID)
+	}
+}
+
+// referencedAsset looks up both sides of the favourite, reporting every missing one
+func (s FavouriteServiceImpl) referencedAsset(f domain.Favourite) (entities.AssetEntity, error) {
+	_, userErr := s.userRepo.GetByID(f.UserID)
+	if userErr != nil && !errors.Is(userErr, ports.ErrUserNotFound) {
+		return nil, userErr
+	}
+	asset, assetErr := s.assetRepo.GetByID(f.AssetID)
+	if assetErr != nil && !errors.Is(assetErr, ports.ErrAssetNotFound) {
+		return nil, assetErr
+	}
+
+	switch {
+	case userErr != nil && assetErr != nil:
+		return nil, fmt.Errorf("%w: %s; %w: %s", domain.ErrUnknownUser, f.UserID, domain.ErrUnknownAsset, f.AssetID)
+	case userErr != nil:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownUser, f.UserID)
+	case assetErr != nil:
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownAsset, f.AssetID)
+	}
+	return asset, nil
+}
+
+// ApplyFavouriteBatch implements ports.FavouriteService.
+// The changes are applied in request order by the repository at once. Adding an asset that does not exist is
+// reported as missing; with allOrNothing such a batch changes nothing and every other operation is skipped.
+func (s FavouriteServiceImpl) ApplyFavouriteBatch(userID string, operations []domain.FavouriteOperation, allOrNothing bool) (domain.FavouriteBatchResult, error) {
+	if err := s.ensureUserExists(userID); err != nil {
+		return domain.FavouriteBatchResult{}, err
+	}
+
+	assets, err := s.addedAssets(operations)
+	if err != nil {
+		return domain.FavouriteBatchResult{}, err
+	}
+
+	results := make([]domain.FavouriteOperationResult, len(operations))
+	changes := make([]entities.FavouriteChange, 0, len(operations))
+	// operationOf holds the index of the operation behind each change
+	operationOf := make([]int, 0, len(operations))
+	createdAt := time.Now().UTC()
+	missing := false
+	for i, op := range operations {
+		results[i].FavouriteOperation = op
+		change := entities.FavouriteChange{Remove: op.Action == domain.FavouriteActionRemove}
+		if change.Remove {
+			change.Favourite = entities.FavouriteEntity{UserId: userID, AssetId: op.AssetID}
+		} else {
+			asset, ok := assets[op.AssetID]
+			if !ok {
+				results[i].Outcome = domain.FavouriteAssetMissing
+				missing = true
+				continue
+			}
+			change.Favourite = mapper.FavouriteEntityFromDomain(domain.Favourite{UserID: userID, AssetID: op.AssetID, CreatedAt: createdAt})
+			change.Favourite.AssetType = asset.GetType()
+		}
+		changes = append(changes, change)
+		operationOf = append(operationOf, i)
+	}
+
+	if missing && allOrNothing {
+		for i := range results {
+			if results[i].Outcome == "" {
+				results[i].Outcome = domain.FavouriteSkipped
+			}
+		}
+		return domain.FavouriteBatchResult{Results: results}, nil
+	}
+
+	applied, err := s.repo.ApplyChanges(changes)
+	if err != nil {
+		// A store that references users from favourites rejects the changes of a user deleted meanwhile
+		if userErr := s.ensureUserExists(userID); userErr != nil {
+			return domain.FavouriteBatchResult{}, userErr
+		}
+		return domain.FavouriteBatchResult{}, err
+	}
+	for c, i := range operationOf {
+		results[i].Outcome = batchOutcome(changes[c].Remove, applied[c])
+		if changes[c].Remove && applied[c] {
+			if err := s.collectionRepo.RemoveAsset(userID, operations[i].AssetID); err != nil {
+				return domain.FavouriteBatchResult{}, err
+			}
+		}
+	}
+
+	// As in CreateFavourite, a user deleted meanwhile may have been cleaned up before the changes; take the added
+	// favourites back
+	if err := s.ensureUserExists(userID); err != nil {
+		if !errors.Is(err, domain.ErrUnknownUser) {
+			return domain.FavouriteBatchResult{}, err
+		}
+		for _, result := range results {
+			if result.Outcome != domain.FavouriteAdded {
+				continue
+			}
+			if err := s.repo.Delete(userID, result.AssetID); err != nil {
+				return domain.FavouriteBatchResult{}, err
+			}
+		}
+		return domain.FavouriteBatchResult{}, err
+	}
+
+	if err := s.dropVanishedAssets(userID, results); err != nil {
+		return domain.FavouriteBatchResult{}, err
+	}
+	return domain.FavouriteBatchResult{Applied: true, Results: results}, nil
+}
+
+// ensureUserExists returns domain.ErrUnknownUser if the user does not exist
+func (s FavouriteServiceImpl) ensureUserExists(userID string) error {
+	_, err := s.userRepo.GetByID(userID)
+	if errors.Is(err, ports.ErrUserNotFound) {
+		return fmt.Errorf("%w: %s", domain.ErrUnknownUser, userID)
+	}
+	return err
+}
+
+// addedAssets fetches, by id, the existing assets the batch adds
+func (s FavouriteServiceImpl) addedAssets(operations []domain.FavouriteOperation) (map[string]entities.AssetEntity, error) {
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		if op.Action == domain.FavouriteActionAdd {
+			ids = append(ids, op.AssetID)
+		}
+	}
+
+	found, err := s.assetRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	assets := make(map[string]entities.AssetEntity, len(found))
+	for _, asset := range found {
+		assets[asset.GetID()] = asset
+	}
+	return assets, nil
 }
 
+// dropVanishedAssets takes back the favourites the batch added for assets deleted meanwhile, as CreateFavourite does
+func (s FavouriteServiceImpl) dropVanishedAssets(userID string, results []domain.FavouriteOperationResult) error {
+	var added []domain.FavouriteOperation
+	for _, result := range results {
+		if result.Outcome == domain.FavouriteAdded {
+			added = append(added, result.FavouriteOperation)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	assets, err := s.addedAssets(added)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Outcome != domain.FavouriteAdded {
+			continue
+		}
+		if _, ok := assets[result.AssetID]; ok {
+			continue
+		}
+		if err := s.repo.Delete(userID, result.AssetID); err != nil {
+			return err
+		}
+		results[i].Outcome = domain.FavouriteAssetMissing
+	}
+	return nil
+}
+
+func batchOutcome(remove bool, applied bool) domain.FavouriteOutcome {
+	switch {
+	case remove && applied:
+		return domain.FavouriteRemoved
+	case remove:
+		return domain.FavouriteNotPresent
+	case applied:
+		return domain.FavouriteAdded
+	default:
+		return domain.FavouriteAlreadyPresent
+	}
+}
+
+// DeleteFavourite removes the favourite and takes it out of every collection of the user
 func (s FavouriteServiceImpl) DeleteFavourite(userID, assetID string) error {
-	return s.repo.Delete(userID, assetID)
+	if err := s.repo.Delete(userID, assetID); err != nil {
+		return err
+	}
+	return s.collectionRepo.RemoveAsset(userID, assetID)
+}
+
+// UpdateFavourite implements ports.FavouriteService.
+// The returned favourite carries its asset unless the asset no longer exists.
+func (s FavouriteServiceImpl) UpdateFavourite(userID, assetID string, patch domain.FavouritePatch) (domain.Favourite, error) {
+	entity, err := s.repo.Get(userID, assetID)
+	if err != nil {
+		return domain.Favourite{}, favouriteNotFound(err)
+	}
+
+	favourite := *mapper.FavouriteEntityToDomain(&entity)
+	patch.Apply(&favourite)
+	if err := favourite.Validate(); err != nil {
+		return domain.Favourite{}, err
+	}
+
+	if err := s.repo.Update(mapper.FavouriteEntityFromDomain(favourite)); err != nil {
+		return domain.Favourite{}, favouriteNotFound(err)
+	}
+
+	enhanced, err := batchEnhanceFavourites(s.assetRepo, []domain.Favourite{favourite})
+	if err != nil {
+		return domain.Favourite{}, err
+	}
+	if len(enhanced) == 0 {
+		return favourite, nil
+	}
+	return enhanced[0], nil
+}
+
+// GetRemovedFavourites implements ports.FavouriteService.
+func (s FavouriteServiceImpl) GetRemovedFavourites(userID string) ([]domain.FavouriteTombstone, error) {
+	tombstones, err := s.repo.GetTombstonesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.FavouriteTombstoneEntityToDomainList(tombstones), nil
+}
+
+// DismissRemovedFavourite implements ports.FavouriteService.
+func (s FavouriteServiceImpl) DismissRemovedFavourite(userID, assetID string) error {
+	return s.repo.DeleteTombstone(userID, assetID)
+}
+
+func favouriteNotFound(err error) error {
+	if errors.Is(err, ports.ErrFavouriteNotFound) {
+		return domain.ErrFavouriteNotFound
+	}
+	return err
 }
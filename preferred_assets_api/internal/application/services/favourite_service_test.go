@@ -2,72 +2,369 @@ package services_test
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/filestore"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/inmemory"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	lru "github.com/hashicorp/golang-lru/v2"
 )
 
 // Mocks
 type mockFavouriteRepo struct {
 	existsResult bool
 	addErr       error
+	added        *entities.FavouriteEntity
+	deleted      bool
 	deleteErr    error
+	favourites   []entities.FavouriteEntity
+	updated      *entities.FavouriteEntity
+	tombstones   []entities.FavouriteTombstoneEntity
 }
 
 func (m *mockFavouriteRepo) Exists(userID, assetID string) (bool, error) {
+	for _, f := range m.favourites {
+		if f.UserId == userID && f.AssetId == assetID {
+			return true, nil
+		}
+	}
 	return m.existsResult, nil
 }
 
 func (m *mockFavouriteRepo) Add(f entities.FavouriteEntity) error {
+	if exists, _ := m.Exists(f.UserId, f.AssetId); exists {
+		return ports.ErrFavouriteExists
+	}
+	if m.addErr == nil {
+		m.added = &f
+		m.favourites = append(m.favourites, f)
+	}
 	return m.addErr
 }
 
 func (m *mockFavouriteRepo) Delete(userID, assetID string) error {
+	m.deleted = m.deleteErr == nil
 	return m.deleteErr
 }
 
+func (m *mockFavouriteRepo) ApplyChanges(changes []entities.FavouriteChange) ([]bool, error) {
+	applied := make([]bool, len(changes))
+	for i, c := range changes {
+		exists, _ := m.Exists(c.Favourite.UserId, c.Favourite.AssetId)
+		switch {
+		case c.Remove && exists:
+			var kept []entities.FavouriteEntity
+			for _, f := range m.favourites {
+				if f.UserId != c.Favourite.UserId || f.AssetId != c.Favourite.AssetId {
+					kept = append(kept, f)
+				}
+			}
+			m.favourites = kept
+			applied[i] = true
+		case !c.Remove && !exists:
+			m.favourites = append(m.favourites, c.Favourite)
+			applied[i] = true
+		}
+	}
+	return applied, nil
+}
+
 func (m *mockFavouriteRepo) GetByUserID(userID string) ([]entities.FavouriteEntity, error) {
-	return nil, nil
+	return m.favourites, nil
+}
+
+func (m *mockFavouriteRepo) GetPageByUserID(userID string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	return entities.FavouritePage{}, nil
+}
+
+func (m *mockFavouriteRepo) Get(userID, assetID string) (entities.FavouriteEntity, error) {
+	for _, f := range m.favourites {
+		if f.UserId == userID && f.AssetId == assetID {
+			return f, nil
+		}
+	}
+	return entities.FavouriteEntity{}, ports.ErrFavouriteNotFound
+}
+
+func (m *mockFavouriteRepo) Update(f entities.FavouriteEntity) error {
+	m.updated = &f
+	return nil
+}
+
+func (m *mockFavouriteRepo) DeleteByAssetID(assetID string, tombstone *entities.FavouriteTombstoneEntity) ([]entities.FavouriteEntity, error) {
+	var removed, kept []entities.FavouriteEntity
+	for _, f := range m.favourites {
+		if f.AssetId != assetID {
+			kept = append(kept, f)
+			continue
+		}
+		removed = append(removed, f)
+		if tombstone != nil {
+			m.tombstones = append(m.tombstones, tombstone.TombstoneFor(f))
+		}
+	}
+	m.favourites = kept
+	return removed, nil
+}
+
+func (m *mockFavouriteRepo) DeleteByUserID(userID string) error {
+	var kept []entities.FavouriteEntity
+	for _, f := range m.favourites {
+		if f.UserId != userID {
+			kept = append(kept, f)
+		}
+	}
+	m.favourites = kept
+	return nil
+}
+
+func (m *mockFavouriteRepo) GetTombstonesByUserID(userID string) ([]entities.FavouriteTombstoneEntity, error) {
+	var tombstones []entities.FavouriteTombstoneEntity
+	for _, t := range m.tombstones {
+		if t.UserId == userID {
+			tombstones = append(tombstones, t)
+		}
+	}
+	return tombstones, nil
+}
+
+func (m *mockFavouriteRepo) DeleteTombstone(userID, assetID string) error {
+	var kept []entities.FavouriteTombstoneEntity
+	for _, t := range m.tombstones {
+		if t.UserId != userID || t.AssetId != assetID {
+			kept = append(kept, t)
+		}
+	}
+	m.tombstones = kept
+	return nil
 }
 
 // --- Tests ---
 
-func TestCreateFavourite_Success(t *testing.T) {
-	// Arrange
-	mockRepo := &mockFavouriteRepo{}
-	service := services.NewFavouriteService(mockRepo)
-	fav := domain.Favourite{UserID: "u1", AssetID: "a1"}
+// favouritableAsset and favouritingUsers are the asset and user the CreateFavourite tests favourite with
+var (
+	favouritableAsset = &entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a1", Type: entities.AssetTypeInsight, Title: "Insight"}, Text: "text"}
+	favouritingUsers  = &mockUserRepo{users: map[string]entities.UserEntity{"u1": {Id: "u1"}}}
+)
 
-	// Act
-	err := service.CreateFavourite(fav)
+// vanishingAssetRepo finds the asset on the first lookup but not afterwards, as if it was deleted meanwhile
+type vanishingAssetRepo struct {
+	mockAssetServiceRepo
+}
 
-	// Assert
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+func (m *vanishingAssetRepo) Exists(id string) (bool, error) {
+	return false, nil
+}
+
+// vanishingUserRepo finds the user on the first lookup but not afterwards, as if it was deleted meanwhile
+type vanishingUserRepo struct {
+	mockUserRepo
+	lookups int
+}
+
+func (m *vanishingUserRepo) GetByID(id string) (entities.UserEntity, error) {
+	m.lookups++
+	if m.lookups > 1 {
+		return entities.UserEntity{}, ports.ErrUserNotFound
+	}
+	return m.mockUserRepo.GetByID(id)
+}
+
+func TestCreateFavourite(t *testing.T) {
+	tests := []struct {
+		name      string
+		favourite domain.Favourite
+		users     ports.UserRepository
+		assets    ports.AssetRepository
+		wantErrs  []error
+	}{
+		{name: "records the asset type", favourite: domain.Favourite{UserID: "u1", AssetID: "a1"}, assets: &mockAssetServiceRepo{stored: favouritableAsset}},
+		{
+			name:      "unknown user",
+			favourite: domain.Favourite{UserID: "missing", AssetID: "a1"},
+			assets:    &mockAssetServiceRepo{stored: favouritableAsset},
+			wantErrs:  []error{domain.ErrUnknownUser},
+		},
+		{
+			name:      "unknown asset",
+			favourite: domain.Favourite{UserID: "u1", AssetID: "missing"},
+			assets:    &mockAssetServiceRepo{},
+			wantErrs:  []error{domain.ErrUnknownAsset},
+		},
+		{
+			name:      "unknown user and asset",
+			favourite: domain.Favourite{UserID: "missing", AssetID: "missing"},
+			assets:    &mockAssetServiceRepo{},
+			wantErrs:  []error{domain.ErrUnknownUser, domain.ErrUnknownAsset},
+		},
+		{
+			name:      "asset deleted while adding",
+			favourite: domain.Favourite{UserID: "u1", AssetID: "a1"},
+			assets:    &vanishingAssetRepo{mockAssetServiceRepo{stored: favouritableAsset}},
+			wantErrs:  []error{domain.ErrUnknownAsset},
+		},
+		{
+			name:      "user deleted while adding",
+			favourite: domain.Favourite{UserID: "u1", AssetID: "a1"},
+			users:     &vanishingUserRepo{mockUserRepo: *favouritingUsers},
+			assets:    &mockAssetServiceRepo{stored: favouritableAsset},
+			wantErrs:  []error{domain.ErrUnknownUser},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := &mockFavouriteRepo{}
+			users := tt.users
+			if users == nil {
+				users = favouritingUsers
+			}
+			service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), users, tt.assets)
+
+			// Act
+			err := service.CreateFavourite(tt.favourite)
+
+			// Assert
+			if tt.wantErrs == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if mockRepo.added == nil || mockRepo.added.AssetType != entities.AssetTypeInsight {
+					t.Errorf("expected the favourite to record the insight type, got %+v", mockRepo.added)
+				}
+				return
+			}
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("expected %v, got %v", want, err)
+				}
+			}
+			if mockRepo.added != nil && !mockRepo.deleted {
+				t.Error("expected no favourite to be left behind")
+			}
+		})
+	}
+}
+
+// pausingAddRepo announces the add on adding and then pauses, so a concurrent delete can land between the service's
+// checks and the insert
+type pausingAddRepo struct {
+	ports.FavouriteRepository
+	adding chan struct{}
+}
+
+func (r pausingAddRepo) Add(f entities.FavouriteEntity) error {
+	close(r.adding)
+	time.Sleep(time.Millisecond)
+	return r.FavouriteRepository.Add(f)
+}
+
+func TestCreateFavourite_UserDeletedConcurrently(t *testing.T) {
+	backends := []struct {
+		name string
+		open func(t *testing.T) (ports.UserRepository, ports.AssetRepository, ports.FavouriteRepository)
+	}{
+		{
+			name: "in-memory",
+			open: func(t *testing.T) (ports.UserRepository, ports.AssetRepository, ports.FavouriteRepository) {
+				assetCache, _ := lru.New[string, entities.AssetEntity](100)
+				favourites := inmemory.NewFavouriteRepository()
+				return inmemory.NewUserRepository(favourites, inmemory.NewCollectionRepository()), inmemory.NewAssetRepository(assetCache), favourites
+			},
+		},
+		{
+			name: "file",
+			open: func(t *testing.T) (ports.UserRepository, ports.AssetRepository, ports.FavouriteRepository) {
+				store, err := filestore.Open(filepath.Join(t.TempDir(), "store.db"), 0)
+				if err != nil {
+					t.Fatalf("failed to open store: %v", err)
+				}
+				t.Cleanup(func() { store.Close() })
+				return filestore.NewUserRepository(store), filestore.NewAssetRepository(store), filestore.NewFavouriteRepository(store)
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			// Arrange
+			users, assets, favourites := backend.open(t)
+			if _, err := assets.Save(favouritableAsset); err != nil {
+				t.Fatalf("failed to save asset: %v", err)
+			}
+
+			for i := 0; i < 20; i++ {
+				userID := fmt.Sprintf("u%d", i)
+				if err := users.Save(entities.UserEntity{Id: userID}); err != nil {
+					t.Fatalf("failed to save user: %v", err)
+				}
+				adding := make(chan struct{})
+				service := services.NewFavouriteService(pausingAddRepo{favourites, adding}, newMockCollectionRepo(), users, assets)
+
+				// Act
+				var createErr, deleteErr error
+				var wg sync.WaitGroup
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					createErr = service.CreateFavourite(domain.Favourite{UserID: userID, AssetID: "a1"})
+				}()
+				go func() {
+					defer wg.Done()
+					<-adding
+					deleteErr = users.Delete(userID)
+				}()
+				wg.Wait()
+
+				// Assert
+				if deleteErr != nil {
+					t.Fatalf("unexpected delete error: %v", deleteErr)
+				}
+				if createErr != nil && !errors.Is(createErr, domain.ErrUnknownUser) {
+					t.Fatalf("expected success or ErrUnknownUser, got %v", createErr)
+				}
+				left, err := favourites.GetByUserID(userID)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(left) != 0 {
+					t.Fatalf("expected no favourite of the deleted user %s, got %+v", userID, left)
+				}
+			}
+		})
 	}
 }
 
 func TestCreateFavourite_AlreadyExists(t *testing.T) {
 	// Arrange
 	mockRepo := &mockFavouriteRepo{existsResult: true}
-	service := services.NewFavouriteService(mockRepo)
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{stored: favouritableAsset})
 	fav := domain.Favourite{UserID: "u1", AssetID: "a1"}
 
 	// Act
 	err := service.CreateFavourite(fav)
 
 	// Assert
-	if err == nil {
-		t.Error("expected error when favourite already exists")
+	if !errors.Is(err, domain.ErrFavouriteExists) {
+		t.Errorf("expected ErrFavouriteExists, got %v", err)
 	}
 }
 
 func TestCreateFavourite_AddFails(t *testing.T) {
 	// Arrange
 	mockRepo := &mockFavouriteRepo{addErr: errors.New("db failed")}
-	service := services.NewFavouriteService(mockRepo)
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{stored: favouritableAsset})
 	fav := domain.Favourite{UserID: "u1", AssetID: "a1"}
 
 	// Act
@@ -82,7 +379,9 @@ func TestCreateFavourite_AddFails(t *testing.T) {
 func TestDeleteFavourite_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockFavouriteRepo{}
-	service := services.NewFavouriteService(mockRepo)
+	collections := newMockCollectionRepo()
+	collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", AssetIds: []string{"a1", "a2"}})
+	service := services.NewFavouriteService(mockRepo, collections, favouritingUsers, &mockAssetServiceRepo{})
 
 	// Act
 	err := service.DeleteFavourite("u1", "a1")
@@ -91,12 +390,15 @@ func TestDeleteFavourite_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if got := collections.collections["c1"].AssetIds; len(got) != 1 || got[0] != "a2" {
+		t.Errorf("expected the favourite to leave its collections, got %v", got)
+	}
 }
 
 func TestDeleteFavourite_Fails(t *testing.T) {
 	// Arrange
 	mockRepo := &mockFavouriteRepo{deleteErr: errors.New("delete failed")}
-	service := services.NewFavouriteService(mockRepo)
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{})
 
 	// Act
 	err := service.DeleteFavourite("u1", "a1")
@@ -106,3 +408,249 @@ func TestDeleteFavourite_Fails(t *testing.T) {
 		t.Error("expected error when Delete fails")
 	}
 }
+
+func TestApplyFavouriteBatch(t *testing.T) {
+	add := func(assetID string) domain.FavouriteOperation {
+		return domain.FavouriteOperation{Action: domain.FavouriteActionAdd, AssetID: assetID}
+	}
+	remove := func(assetID string) domain.FavouriteOperation {
+		return domain.FavouriteOperation{Action: domain.FavouriteActionRemove, AssetID: assetID}
+	}
+	chart := &entities.ChartEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a2", Type: entities.AssetTypeChart, Title: "Chart"}}
+
+	tests := []struct {
+		name           string
+		operations     []domain.FavouriteOperation
+		allOrNothing   bool
+		wantApplied    bool
+		wantOutcomes   []domain.FavouriteOutcome
+		wantFavourites []string
+	}{
+		{
+			name:           "reports every operation in order",
+			operations:     []domain.FavouriteOperation{add("a1"), add("a2"), add("a3"), remove("a3"), remove("a4")},
+			wantApplied:    true,
+			wantOutcomes:   []domain.FavouriteOutcome{domain.FavouriteAdded, domain.FavouriteAdded, domain.FavouriteAlreadyPresent, domain.FavouriteRemoved, domain.FavouriteNotPresent},
+			wantFavourites: []string{"a1", "a2"},
+		},
+		{
+			name:           "applies the others around a missing asset",
+			operations:     []domain.FavouriteOperation{add("a1"), add("missing"), remove("a3")},
+			wantApplied:    true,
+			wantOutcomes:   []domain.FavouriteOutcome{domain.FavouriteAdded, domain.FavouriteAssetMissing, domain.FavouriteRemoved},
+			wantFavourites: []string{"a1"},
+		},
+		{
+			name:           "all or nothing changes nothing for a missing asset",
+			operations:     []domain.FavouriteOperation{add("a1"), add("missing"), remove("a3")},
+			allOrNothing:   true,
+			wantOutcomes:   []domain.FavouriteOutcome{domain.FavouriteSkipped, domain.FavouriteAssetMissing, domain.FavouriteSkipped},
+			wantFavourites: []string{"a3"},
+		},
+		{
+			name:           "all or nothing applies a batch of existing assets",
+			operations:     []domain.FavouriteOperation{add("a1"), remove("a3")},
+			allOrNothing:   true,
+			wantApplied:    true,
+			wantOutcomes:   []domain.FavouriteOutcome{domain.FavouriteAdded, domain.FavouriteRemoved},
+			wantFavourites: []string{"a1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := &mockFavouriteRepo{favourites: []entities.FavouriteEntity{{UserId: "u1", AssetId: "a3"}}}
+			collections := newMockCollectionRepo()
+			collections.Save(entities.CollectionEntity{Id: "c1", UserId: "u1", AssetIds: []string{"a3"}})
+			assets := &mockAssetServiceRepo{assets: []entities.AssetEntity{favouritableAsset, chart, &entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a3"}}}}
+			service := services.NewFavouriteService(mockRepo, collections, favouritingUsers, assets)
+
+			// Act
+			result, err := service.ApplyFavouriteBatch("u1", tt.operations, tt.allOrNothing)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Applied != tt.wantApplied {
+				t.Errorf("expected applied %v, got %v", tt.wantApplied, result.Applied)
+			}
+			for i, r := range result.Results {
+				if r.FavouriteOperation != tt.operations[i] || r.Outcome != tt.wantOutcomes[i] {
+					t.Errorf("operation %d: expected %v %s, got %v %s", i, tt.operations[i], tt.wantOutcomes[i], r.FavouriteOperation, r.Outcome)
+				}
+			}
+			var favourites []string
+			for _, f := range mockRepo.favourites {
+				favourites = append(favourites, f.AssetId)
+			}
+			if fmt.Sprint(favourites) != fmt.Sprint(tt.wantFavourites) {
+				t.Errorf("expected favourites %v, got %v", tt.wantFavourites, favourites)
+			}
+			if inCollection := len(collections.collections["c1"].AssetIds) == 1; inCollection != slices.Contains(favourites, "a3") {
+				t.Errorf("expected only favourites in collections, got %v", collections.collections["c1"].AssetIds)
+			}
+		})
+	}
+}
+
+func TestApplyFavouriteBatch_RecordsAssetType(t *testing.T) {
+	// Arrange
+	mockRepo := &mockFavouriteRepo{}
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{assets: []entities.AssetEntity{favouritableAsset}})
+
+	// Act
+	_, err := service.ApplyFavouriteBatch("u1", []domain.FavouriteOperation{{Action: domain.FavouriteActionAdd, AssetID: "a1"}}, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockRepo.favourites) != 1 || mockRepo.favourites[0].AssetType != entities.AssetTypeInsight || mockRepo.favourites[0].CreatedAt.IsZero() {
+		t.Errorf("expected an insight favourite with a creation time, got %+v", mockRepo.favourites)
+	}
+}
+
+func TestApplyFavouriteBatch_UnknownUser(t *testing.T) {
+	// Arrange
+	mockRepo := &mockFavouriteRepo{}
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{assets: []entities.AssetEntity{favouritableAsset}})
+
+	// Act
+	_, err := service.ApplyFavouriteBatch("missing", []domain.FavouriteOperation{{Action: domain.FavouriteActionAdd, AssetID: "a1"}}, false)
+
+	// Assert
+	if !errors.Is(err, domain.ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser, got %v", err)
+	}
+	if len(mockRepo.favourites) != 0 {
+		t.Errorf("expected no favourites, got %v", mockRepo.favourites)
+	}
+}
+
+func TestApplyFavouriteBatch_UserDeletedMeanwhile(t *testing.T) {
+	// Arrange
+	mockRepo := &mockFavouriteRepo{}
+	users := &vanishingUserRepo{mockUserRepo: *favouritingUsers}
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), users, &mockAssetServiceRepo{assets: []entities.AssetEntity{favouritableAsset}})
+
+	// Act
+	_, err := service.ApplyFavouriteBatch("u1", []domain.FavouriteOperation{{Action: domain.FavouriteActionAdd, AssetID: "a1"}}, false)
+
+	// Assert
+	if !errors.Is(err, domain.ErrUnknownUser) {
+		t.Errorf("expected ErrUnknownUser, got %v", err)
+	}
+	if !mockRepo.deleted {
+		t.Error("expected the added favourite to be taken back")
+	}
+}
+
+func TestUpdateFavourite(t *testing.T) {
+	note := "  Use in the Q3 deck "
+	empty := ""
+	longTitle := strings.Repeat("t", domain.MaxFavouriteTitleLength+1)
+	stored := entities.FavouriteEntity{UserId: "u1", AssetId: "a1", CustomTitle: "Old title", Tags: `["old"]`}
+	asset := &entities.InsightEntity{AssetBaseEntity: entities.AssetBaseEntity{ID: "a1", Type: entities.AssetTypeInsight, Title: "Canonical"}, Text: "text"}
+
+	tests := []struct {
+		name          string
+		assetID       string
+		patch         domain.FavouritePatch
+		expectedErr   error
+		expectedNote  string
+		expectedTitle string
+		expectedTags  []string
+	}{
+		{
+			name:          "sets the note and keeps omitted fields",
+			assetID:       "a1",
+			patch:         domain.FavouritePatch{Note: &note},
+			expectedNote:  "Use in the Q3 deck",
+			expectedTitle: "Old title",
+			expectedTags:  []string{"old"},
+		},
+		{
+			name:         "clears the title and normalises tags",
+			assetID:      "a1",
+			patch:        domain.FavouritePatch{CustomTitle: &empty, Tags: []string{" Q3", "q3", "Sales", ""}},
+			expectedTags: []string{"q3", "sales"},
+		},
+		{
+			name:        "rejects a title that is too long",
+			assetID:     "a1",
+			patch:       domain.FavouritePatch{CustomTitle: &longTitle},
+			expectedErr: domain.ErrInvalidFavourite,
+		},
+		{
+			name:        "unknown favourite",
+			assetID:     "a2",
+			patch:       domain.FavouritePatch{Note: &note},
+			expectedErr: domain.ErrFavouriteNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := &mockFavouriteRepo{favourites: []entities.FavouriteEntity{stored}}
+			service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{assets: []entities.AssetEntity{asset}})
+
+			// Act
+			fav, err := service.UpdateFavourite("u1", tt.assetID, tt.patch)
+
+			// Assert
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("expected %v, got %v", tt.expectedErr, err)
+				}
+				if mockRepo.updated != nil {
+					t.Error("expected nothing to be stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fav.Note != tt.expectedNote || fav.CustomTitle != tt.expectedTitle || !reflect.DeepEqual(fav.Tags, tt.expectedTags) {
+				t.Errorf("unexpected overrides: note %q, title %q, tags %v", fav.Note, fav.CustomTitle, fav.Tags)
+			}
+			if fav.Insight == nil || fav.Insight.Title != "Canonical" {
+				t.Errorf("expected the canonical asset alongside the overrides, got %+v", fav.GetAsset())
+			}
+			if mockRepo.updated == nil || mockRepo.updated.Note != tt.expectedNote {
+				t.Errorf("expected the overrides to be stored, got %+v", mockRepo.updated)
+			}
+		})
+	}
+}
+
+func TestRemovedFavourites(t *testing.T) {
+	// Arrange
+	removedAt := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	mockRepo := &mockFavouriteRepo{tombstones: []entities.FavouriteTombstoneEntity{
+		{UserId: "u1", AssetId: "a1", AssetType: entities.AssetTypeChart, AssetTitle: "Sales", RemovedAt: removedAt},
+		{UserId: "u2", AssetId: "a1", AssetType: entities.AssetTypeChart, AssetTitle: "Sales", RemovedAt: removedAt},
+	}}
+	service := services.NewFavouriteService(mockRepo, newMockCollectionRepo(), favouritingUsers, &mockAssetServiceRepo{})
+
+	// Act
+	removed, err := service.GetRemovedFavourites("u1")
+	dismissErr := service.DismissRemovedFavourite("u1", "a1")
+	afterDismiss, _ := service.GetRemovedFavourites("u1")
+
+	// Assert
+	if err != nil || dismissErr != nil {
+		t.Fatalf("unexpected errors: %v, %v", err, dismissErr)
+	}
+	if len(removed) != 1 || removed[0].AssetTitle != "Sales" || removed[0].AssetType != domain.AssetTypeChart || !removed[0].RemovedAt.Equal(removedAt) {
+		t.Errorf("unexpected tombstones: %+v", removed)
+	}
+	if len(afterDismiss) != 0 {
+		t.Errorf("expected the tombstone to be dismissed, got %+v", afterDismiss)
+	}
+	if others, _ := service.GetRemovedFavourites("u2"); len(others) != 1 {
+		t.Errorf("expected other users' tombstones to be kept, got %+v", others)
+	}
+}
@@ -1,14 +1,17 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/mapper"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,11 +20,14 @@ var _ ports.UserService = (*UserServiceImpl)(nil)
 type UserServiceImpl struct {
 	repo      ports.UserRepository
 	assetRepo ports.AssetRepository
+	// provisionMu serialises just-in-time provisioning so concurrent first requests create a single user
+	provisionMu *sync.Mutex
 }
 
 func NewUserService(usrRepo ports.UserRepository, assetRepo ports.AssetRepository) *UserServiceImpl {
 	return &UserServiceImpl{repo: usrRepo,
-		assetRepo: assetRepo}
+		assetRepo:   assetRepo,
+		provisionMu: &sync.Mutex{}}
 }
 
 func (usrService UserServiceImpl) GetUserByID(id string) (*domain.User, error) {
@@ -45,26 +51,41 @@ func (usrService UserServiceImpl) CreateUser(usr domain.User) error {
 		return err
 	}
 	usr.Password = string(hashedPassword)
+	if err := usrService.ensureSubjectAvailable(usr); err != nil {
+		return err
+	}
 	usr.CreatedAt = time.Now().UTC()
 	user := mapper.UserEntityFromDomain(usr)
 
 	return usrService.repo.Save(user)
 }
 
-func (usrService UserServiceImpl) DeleteUser(id string) error {
-	return usrService.repo.Delete(id)
+// DeleteUser deletes the user. Unless expectedVersion is ports.AnyVersion it only succeeds if the user is still at that version.
+func (usrService UserServiceImpl) DeleteUser(id string, expectedVersion int64) error {
+	if expectedVersion == ports.AnyVersion {
+		return usrService.repo.Delete(id)
+	}
+	return usrService.repo.CompareAndDelete(id, expectedVersion)
 }
 
-func (usrService UserServiceImpl) UpdateUser(usr domain.User) error {
+// UpdateUser stores the user and returns the version it was stored at.
+// Unless expectedVersion is ports.AnyVersion it only succeeds if the user is still at that version.
+func (usrService UserServiceImpl) UpdateUser(usr domain.User, expectedVersion int64) (int64, error) {
+	if err := usrService.ensureSubjectAvailable(usr); err != nil {
+		return 0, err
+	}
 	usr.UpdatedAt = time.Now().UTC()
 	user := mapper.UserEntityFromDomain(usr)
-	return usrService.repo.Update(user)
+	if expectedVersion == ports.AnyVersion {
+		return usrService.repo.Update(user)
+	}
+	return usrService.repo.CompareAndSwap(user, expectedVersion)
 }
 
 func (usrService UserServiceImpl) GetFavouritesByUser(id string) ([]domain.Favourite, error) {
 	favs, err := usrService.repo.GetFavouritesByID(id)
 	favsList := mapper.FavouriteEntityToDomainList(favs)
-	enhancedFavs, err := usrService.batchEnhanceFavourites(favsList)
+	enhancedFavs, err := batchEnhanceFavourites(usrService.assetRepo, favsList)
 	if err != nil {
 		return nil, err
 	}
@@ -72,9 +93,102 @@ func (usrService UserServiceImpl) GetFavouritesByUser(id string) ([]domain.Favou
 	return enhancedFavs, err
 }
 
+// GetFavouritesPageByUser returns one page of the user's favourites, newest first.
+// cursor is the NextCursor of the previous page, or "" for the first page.
+func (usrService UserServiceImpl) GetFavouritesPageByUser(id string, limit int, cursor string) (domain.FavouritePage, error) {
+	after, err := mapper.FavouriteCursorFromToken(cursor)
+	if err != nil {
+		return domain.FavouritePage{}, err
+	}
+
+	page, err := usrService.repo.GetFavouritesPageByID(id, after, limit)
+	if err != nil {
+		return domain.FavouritePage{}, err
+	}
+
+	enhancedFavs, err := batchEnhanceFavourites(usrService.assetRepo, mapper.FavouriteEntityToDomainList(page.Favourites))
+	if err != nil {
+		return domain.FavouritePage{}, err
+	}
+
+	return domain.FavouritePage{
+		Favourites: enhancedFavs,
+		NextCursor: mapper.FavouriteCursorToToken(page.Next),
+	}, nil
+}
+
+// ResolveUserID implements ports.UserService.
+// Only the subject identifies a caller: a username is chosen by its owner and may equal another user's subject.
+func (usrService UserServiceImpl) ResolveUserID(subject string) (string, error) {
+	if subject == "" {
+		return "", domain.ErrUserNotFound
+	}
+	user, err := usrService.repo.GetBySubject(subject)
+	if errors.Is(err, ports.ErrUserNotFound) {
+		return "", domain.ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Id, nil
+}
+
+// ProvisionUser implements ports.UserService.
+func (usrService UserServiceImpl) ProvisionUser(subject string, username string, email string) (string, error) {
+	if subject == "" {
+		return "", domain.ErrUserNotFound
+	}
+
+	usrService.provisionMu.Lock()
+	defer usrService.provisionMu.Unlock()
+
+	id, err := usrService.ResolveUserID(subject)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return id, err
+	}
+
+	name := username
+	if name == "" {
+		name = email
+	}
+	now := time.Now().UTC()
+	// Provisioned users sign in through the identity provider, so they have no local password
+	user := mapper.UserEntityFromDomain(domain.User{
+		Id:        uuid.NewString(),
+		Name:      name,
+		Email:     email,
+		Subject:   subject,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err := usrService.repo.Save(user); err != nil {
+		return "", err
+	}
+	log.Printf("Provisioned user %s for subject %s", user.Id, subject)
+	return user.Id, nil
+}
+
+// ensureSubjectAvailable rejects a subject that is already linked to a different user
+func (usrService UserServiceImpl) ensureSubjectAvailable(usr domain.User) error {
+	if usr.Subject == "" {
+		return nil
+	}
+	linked, err := usrService.repo.GetBySubject(usr.Subject)
+	if errors.Is(err, ports.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if linked.Id != usr.Id {
+		return domain.ErrUserSubjectTaken
+	}
+	return nil
+}
+
 // batchEnhanceFavourites Fetch all Assets based on AssetIds in Favourites slide
 // returns a slice of Favourites domain objects enhanced with the corresponding Asset domain objects
-func (usrService UserServiceImpl) batchEnhanceFavourites(favourites []domain.Favourite) ([]domain.Favourite, error) {
+func batchEnhanceFavourites(assetRepo ports.AssetRepository, favourites []domain.Favourite) ([]domain.Favourite, error) {
 	if len(favourites) == 0 {
 		return favourites, nil
 	}
@@ -86,7 +200,7 @@ func (usrService UserServiceImpl) batchEnhanceFavourites(favourites []domain.Fav
 	}
 
 	// Batch fetch all assets
-	assetsEntities, err := usrService.assetRepo.GetByIDs(assetIDs)
+	assetsEntities, err := assetRepo.GetByIDs(assetIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch assets: %w", err)
 	}
@@ -107,22 +221,19 @@ func (usrService UserServiceImpl) batchEnhanceFavourites(favourites []domain.Fav
 		}
 
 		// Convert AssetEntity -> Domain
-		if len(enhancedFavs) != 0 {
-			asset, err := mapper.AssetEntityToDomain(assetEntity)
-			if err != nil {
-				log.Printf("[batchEnhanceFavourites] Failed to map asset %s: %v", fav.AssetID, err)
-				continue // skip if mapping fails
-			}
-
-			// Attach asset to favourite
-			if err := fav.SetAsset(asset); err != nil {
-				log.Printf("[batchEnhanceFavourites] Failed to set asset %s on favourite %s: %v", fav.AssetID, fav.UserID, err)
-				continue // skip invalid favourite
-			}
-
-			enhancedFavs = append(enhancedFavs, fav)
+		asset, err := mapper.AssetEntityToDomain(assetEntity)
+		if err != nil {
+			log.Printf("[batchEnhanceFavourites] Failed to map asset %s: %v", fav.AssetID, err)
+			continue // skip if mapping fails
+		}
+
+		// Attach asset to favourite
+		if err := fav.SetAsset(asset); err != nil {
+			log.Printf("[batchEnhanceFavourites] Failed to set asset %s on favourite %s: %v", fav.AssetID, fav.UserID, err)
+			continue // skip invalid favourite
 		}
 
+		enhancedFavs = append(enhancedFavs, fav)
 	}
 	return enhancedFavs, nil
 }
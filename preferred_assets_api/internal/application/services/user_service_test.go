@@ -3,10 +3,12 @@ package services_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/application/services"
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/ports"
 )
 
 // Mocks
@@ -17,7 +19,7 @@ type mockUserRepo struct {
 func (m *mockUserRepo) GetByID(id string) (entities.UserEntity, error) {
 	u, ok := m.users[id]
 	if !ok {
-		return entities.UserEntity{}, errors.New("not found")
+		return entities.UserEntity{}, ports.ErrUserNotFound
 	}
 	return u, nil
 }
@@ -37,21 +39,56 @@ func (m *mockUserRepo) Save(user entities.UserEntity) error {
 	m.users[user.Id] = user
 	return nil
 }
-func (m *mockUserRepo) Update(user entities.UserEntity) error {
+func (m *mockUserRepo) Update(user entities.UserEntity) (int64, error) {
 	if m.users == nil {
 		m.users = make(map[string]entities.UserEntity)
 	}
 	m.users[user.Id] = user
-	return nil
+	return user.Version, nil
+}
+
+// CompareAndSwap advances the version by ten, so that callers cannot assume the next version is the expected one plus one
+func (m *mockUserRepo) CompareAndSwap(user entities.UserEntity, expectedVersion int64) (int64, error) {
+	if m.users[user.Id].Version != expectedVersion {
+		return 0, ports.ErrVersionConflict
+	}
+	user.Version = expectedVersion + 10
+	return m.Update(user)
+}
+
+func (m *mockUserRepo) CompareAndDelete(id string, expectedVersion int64) error {
+	if m.users[id].Version != expectedVersion {
+		return ports.ErrVersionConflict
+	}
+	return m.Delete(id)
 }
 
 func (m *mockUserRepo) Delete(id string) error { return nil }
+func (m *mockUserRepo) GetBySubject(subject string) (entities.UserEntity, error) {
+	for _, u := range m.users {
+		if u.Subject == subject {
+			return u, nil
+		}
+	}
+	return entities.UserEntity{}, ports.ErrUserNotFound
+}
 func (m *mockUserRepo) GetFavouritesByID(id string) ([]entities.FavouriteEntity, error) {
 	return []entities.FavouriteEntity{
 		{UserId: "1", AssetId: "a1"},
 		{UserId: "1", AssetId: "a2"},
 	}, nil
 }
+func (m *mockUserRepo) GetFavouritesPageByID(id string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error) {
+	if _, ok := m.users[id]; !ok {
+		return entities.FavouritePage{}, errors.New("not found")
+	}
+	favs := []entities.FavouriteEntity{
+		{UserId: id, AssetId: "a1", CreatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{UserId: id, AssetId: "a2", CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{UserId: id, AssetId: "a3", CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	return entities.PaginateFavourites(favs, after, limit), nil
+}
 
 type mockAssetRepository struct {
 	assets map[string]entities.AssetEntity
@@ -66,6 +103,10 @@ func newMockAssetRepo() *mockAssetRepository {
 func (m *mockAssetRepository) GetByIDs(ids []string) ([]entities.AssetEntity, error) {
 	assets := make([]entities.AssetEntity, 0, len(ids))
 	for _, id := range ids {
+		if asset, ok := m.assets[id]; ok {
+			assets = append(assets, asset)
+			continue
+		}
 		assets = append(assets, &entities.AssetBaseEntity{
 			ID:    id,
 			Type:  entities.AssetTypeChart,
@@ -80,12 +121,19 @@ func (m *mockAssetRepository) Save(asset entities.AssetEntity) (entities.AssetEn
 }
 func (m *mockAssetRepository) GetByID(id string) (entities.AssetEntity, error) { return nil, nil }
 func (m *mockAssetRepository) GetAll() ([]entities.AssetEntity, error)         { return nil, nil }
+func (m *mockAssetRepository) Query(query entities.AssetQuery) (entities.AssetPage, error) {
+	return entities.AssetPage{}, nil
+}
 func (m *mockAssetRepository) GetByType(assetType entities.AssetType) ([]entities.AssetEntity, error) {
 	return nil, nil
 }
-func (m *mockAssetRepository) Update(asset entities.AssetEntity) error { return nil }
-func (m *mockAssetRepository) Delete(id string) error                  { return nil }
-func (m *mockAssetRepository) Exists(id string) (bool, error)          { return true, nil }
+func (m *mockAssetRepository) Update(asset entities.AssetEntity) (int64, error) { return 1, nil }
+func (m *mockAssetRepository) Delete(id string) error                           { return nil }
+func (m *mockAssetRepository) CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error) {
+	return 1, nil
+}
+func (m *mockAssetRepository) CompareAndDelete(id string, expectedVersion int64) error { return nil }
+func (m *mockAssetRepository) Exists(id string) (bool, error)                          { return true, nil }
 
 // Happy PathTests
 
@@ -180,7 +228,7 @@ func TestDeleteUser(t *testing.T) {
 	service := services.NewUserService(&mockUserRepo{}, &mockAssetRepository{})
 
 	// Act
-	err := service.DeleteUser("1")
+	err := service.DeleteUser("1", ports.AnyVersion)
 
 	// Assert
 	if err != nil {
@@ -194,7 +242,7 @@ func TestUpdateUser(t *testing.T) {
 	user := domain.User{Id: "1", Name: "Alice"}
 
 	// Act
-	err := service.UpdateUser(user)
+	_, err := service.UpdateUser(user, ports.AnyVersion)
 	userEntity, _ := service.GetUserByID("1")
 
 	// Assert
@@ -206,6 +254,27 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUser_VersionConflict(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{users: map[string]entities.UserEntity{"1": {Id: "1", Name: "Alice", Version: 2}}}
+	service := services.NewUserService(repo, &mockAssetRepository{})
+
+	// Act
+	_, staleErr := service.UpdateUser(domain.User{Id: "1", Name: "Bob"}, 1)
+	version, err := service.UpdateUser(domain.User{Id: "1", Name: "Carol"}, 2)
+
+	// Assert
+	if !errors.Is(staleErr, ports.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict, got %v", staleErr)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.users["1"]; got.Name != "Carol" || got.Version != 12 || version != 12 {
+		t.Errorf("expected Carol at version 12, got %+v at version %d", got, version)
+	}
+}
+
 func TestGetFavouritesByUser(t *testing.T) {
 	// Arrange
 	service := services.NewUserService(&mockUserRepo{}, &mockAssetRepository{})
@@ -226,3 +295,165 @@ func TestGetFavouritesByUser(t *testing.T) {
 		}
 	}
 }
+
+func TestGetFavouritesPageByUser(t *testing.T) {
+	// Arrange
+	users := map[string]entities.UserEntity{"1": {Id: "1"}}
+	assetRepo := newMockAssetRepo()
+	for _, id := range []string{"a1", "a2", "a3"} {
+		assetRepo.assets[id] = &entities.InsightEntity{
+			AssetBaseEntity: entities.AssetBaseEntity{ID: id, Type: entities.AssetTypeInsight, Title: "Insight " + id},
+			Text:            "text",
+		}
+	}
+	service := services.NewUserService(&mockUserRepo{users: users}, assetRepo)
+
+	// Act
+	first, err := service.GetFavouritesPageByUser("1", 2, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := service.GetFavouritesPageByUser("1", 2, first.NextCursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if len(first.Favourites) != 2 || first.Favourites[0].AssetID != "a1" || first.Favourites[1].AssetID != "a2" {
+		t.Errorf("unexpected first page: %+v", first.Favourites)
+	}
+	if first.NextCursor == "" {
+		t.Error("expected a next cursor on the first page")
+	}
+	if first.Favourites[0].Insight == nil {
+		t.Error("expected favourites to be enhanced with their asset")
+	}
+	if len(second.Favourites) != 1 || second.Favourites[0].AssetID != "a3" {
+		t.Errorf("unexpected second page: %+v", second.Favourites)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("expected no next cursor on the last page, got %q", second.NextCursor)
+	}
+}
+
+func TestGetFavouritesPageByUser_InvalidCursor(t *testing.T) {
+	// Arrange
+	users := map[string]entities.UserEntity{"1": {Id: "1"}}
+	service := services.NewUserService(&mockUserRepo{users: users}, newMockAssetRepo())
+
+	// Act
+	_, err := service.GetFavouritesPageByUser("1", 2, "not-a-cursor")
+
+	// Assert
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestResolveUserID(t *testing.T) {
+	users := map[string]entities.UserEntity{
+		"1": {Id: "1", Name: "Alice", Subject: "bea950c6-ae5f-442d-babc-1ab6db7c6c7b"},
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		wantID  string
+		wantErr error
+	}{
+		{name: "matches subject", subject: "bea950c6-ae5f-442d-babc-1ab6db7c6c7b", wantID: "1"},
+		{name: "not linked", subject: "unknown-sub", wantErr: domain.ErrUserNotFound},
+		{name: "empty subject", wantErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := services.NewUserService(&mockUserRepo{users: users}, &mockAssetRepository{})
+
+			// Act
+			id, err := service.ResolveUserID(tt.subject)
+
+			// Assert
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if id != tt.wantID {
+				t.Errorf("expected ID '%s', got '%s'", tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestProvisionUser_UsernameMatchingAnotherSubject(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{users: map[string]entities.UserEntity{"1": {Id: "1", Name: "Alice", Subject: "alice-sub"}}}
+	service := services.NewUserService(repo, &mockAssetRepository{})
+
+	// Act
+	// Mallory's own subject is not linked yet, and she chose Alice's subject as her username
+	_, resolveErr := service.ResolveUserID("mallory-sub")
+	id, provisionErr := service.ProvisionUser("mallory-sub", "alice-sub", "mallory@example.com")
+
+	// Assert
+	if !errors.Is(resolveErr, domain.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", resolveErr)
+	}
+	if provisionErr != nil {
+		t.Fatalf("unexpected error: %v", provisionErr)
+	}
+	if id == "1" || repo.users[id].Subject != "mallory-sub" {
+		t.Errorf("expected a new user linked to mallory-sub, got '%s': %+v", id, repo.users[id])
+	}
+}
+
+func TestUserSubject_MustBeUnique(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{users: map[string]entities.UserEntity{"1": {Id: "1", Name: "Alice", Subject: "alice"}}}
+	service := services.NewUserService(repo, &mockAssetRepository{})
+
+	// Act
+	createErr := service.CreateUser(domain.User{Id: "2", Name: "Bob", Password: "secret", Subject: "alice"})
+	_, updateErr := service.UpdateUser(domain.User{Id: "1", Name: "Alice Smith", Subject: "alice"}, ports.AnyVersion)
+
+	// Assert
+	if !errors.Is(createErr, domain.ErrUserSubjectTaken) {
+		t.Errorf("expected ErrUserSubjectTaken, got %v", createErr)
+	}
+	if updateErr != nil {
+		t.Errorf("expected a user to keep its own subject, got %v", updateErr)
+	}
+}
+
+func TestProvisionUser(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{users: map[string]entities.UserEntity{"1": {Id: "1", Name: "Alice", Subject: "alice"}}}
+	service := services.NewUserService(repo, &mockAssetRepository{})
+
+	// Act
+	existingID, existingErr := service.ProvisionUser("alice", "alice", "alice@example.com")
+	newID, newErr := service.ProvisionUser("bob-sub", "bob", "bob@example.com")
+	againID, againErr := service.ProvisionUser("bob-sub", "bob", "bob@example.com")
+	_, noSubjectErr := service.ProvisionUser("", "carol", "")
+
+	// Assert
+	if existingErr != nil || existingID != "1" {
+		t.Errorf("expected the user linked to the subject, got '%s' (%v)", existingID, existingErr)
+	}
+	if newErr != nil || newID == "" {
+		t.Fatalf("expected a new user, got '%s' (%v)", newID, newErr)
+	}
+	if againErr != nil || againID != newID {
+		t.Errorf("expected the provisioned user '%s' to be reused, got '%s' (%v)", newID, againID, againErr)
+	}
+	if len(repo.users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(repo.users))
+	}
+	provisioned := repo.users[newID]
+	if provisioned.Subject != "bob-sub" || provisioned.Name != "bob" || provisioned.Email != "bob@example.com" {
+		t.Errorf("unexpected provisioned user: %+v", provisioned)
+	}
+	if !errors.Is(noSubjectErr, domain.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound without a subject, got %v", noSubjectErr)
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+var (
+	ErrAPIKeyNotFound = NewError(KindNotFound, "api key not found")
+	ErrInvalidAPIKey  = NewError(KindUnauthorized, "invalid or revoked api key")
+)
+
+// APIKey lets a machine client call the API without an identity provider token.
+// The key acts with its Roles; when UserIDs is not empty it can only reach the resources of those users.
+type APIKey struct {
+	ID        string
+	Name      string
+	Prefix    string // first characters of the key, to recognise it without revealing it
+	Roles     []string
+	UserIDs   []string
+	CreatedAt time.Time
+	RevokedAt time.Time // zero while the key is active
+}
+
+// Revoked reports whether the key can no longer be used
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
@@ -7,6 +7,12 @@ import (
 	"time"
 )
 
+var (
+	ErrAssetNotFound      = NewError(KindNotFound, "asset not found")
+	ErrAssetTypeImmutable = NewError(KindConflict, "asset type cannot be changed")
+	ErrInvalidAsset       = NewError(KindValidation, "invalid asset")
+)
+
 type AssetBase struct {
 	ID          string
 	Type        AssetType
@@ -14,6 +20,7 @@ type AssetBase struct {
 	Description string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	Version     int64
 }
 
 type Asset interface {
@@ -23,12 +30,14 @@ type Asset interface {
 	GetDescription() string
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
+	GetVersion() int64
 	SetID(id string)
 	SetType(typ AssetType)
 	SetTitle(title string)
 	SetDescription(desc string)
 	SetCreatedAt(t time.Time)
 	SetUpdatedAt(t time.Time)
+	SetVersion(version int64)
 	Validate() error
 }
 
@@ -67,6 +76,7 @@ func (a AssetBase) GetTitle() string        { return a.Title }
 func (a AssetBase) GetDescription() string  { return a.Description }
 func (a AssetBase) GetCreatedAt() time.Time { return a.CreatedAt }
 func (a AssetBase) GetUpdatedAt() time.Time { return a.UpdatedAt }
+func (a AssetBase) GetVersion() int64       { return a.Version }
 
 // Common Setter methods
 func (a *AssetBase) SetID(id string)            { a.ID = id }
@@ -75,6 +85,7 @@ func (a *AssetBase) SetTitle(title string)      { a.Title = title }
 func (a *AssetBase) SetDescription(desc string) { a.Description = desc }
 func (a *AssetBase) SetCreatedAt(t time.Time)   { a.CreatedAt = t }
 func (a *AssetBase) SetUpdatedAt(t time.Time)   { a.UpdatedAt = t }
+func (a *AssetBase) SetVersion(version int64)   { a.Version = version }
 
 // Domain validation
 func (a AssetBase) Validate() error {
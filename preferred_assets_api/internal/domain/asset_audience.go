@@ -1,12 +1,19 @@
 package domain
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Audience is defined by Criteria on the attributes of respondents. Audiences defined by single attributes before
+// criteria existed may also record AverageHoursSocial, which describes the audience rather than selecting respondents.
 type Audience struct {
 	AssetBase
-	Gender          string
-	BirthCountry    string
-	AgeGroup        string
-	HoursSocial     float64
-	PurchasesLastMo int
+	Criteria           *AudienceCriterion
+	AverageHoursSocial float64
 }
 
 var _ Asset = (*Audience)(nil)
@@ -16,5 +23,172 @@ func (a *Audience) Validate() error {
 	if err := a.AssetBase.Validate(); err != nil {
 		return err
 	}
-	return nil
+	if a.AverageHoursSocial < 0 || a.AverageHoursSocial > AudienceFieldHoursSocial.maximum() {
+		return fmt.Errorf("average hours on social media must be between 0 and %g", AudienceFieldHoursSocial.maximum())
+	}
+	if a.Criteria == nil {
+		return nil
+	}
+	return a.Criteria.Validate()
+}
+
+// Definition returns the criteria of the audience, and is nil for an audience without criteria
+func (a *Audience) Definition() *AudienceCriterion {
+	return a.Criteria
+}
+
+// AudienceAttributes are the single attributes audiences were defined by before criteria existed. Requests and
+// audiences stored back then may still hold them, and are turned into criteria with Criteria or Rewrite. The average
+// hours on social media they also held are kept on the Audience, as no criterion on respondents can express them.
+type AudienceAttributes struct {
+	Gender          string
+	BirthCountry    string
+	AgeGroup        string
+	PurchasesLastMo int
+}
+
+// IsZero reports whether no attribute is set
+func (a AudienceAttributes) IsZero() bool {
+	return a == AudienceAttributes{}
+}
+
+// Criteria requires every attribute that is set: the age group as a range of ages and purchases as a minimum. The
+// gender may be in any case and the birth country an ISO code or an English country name, and an attribute that
+// cannot be turned into a valid condition is an error. It is nil when no attribute is set.
+func (a AudienceAttributes) Criteria() (*AudienceCriterion, error) {
+	return a.Rewrite(nil)
+}
+
+// Rewrite returns criteria with a condition for every attribute that is set in place of the conditions on the same
+// field at the top of criteria, which are either its single condition or the conditions its and group combines.
+// Groups nested below are kept as they are. Criteria are returned unchanged when no attribute is set.
+func (a AudienceAttributes) Rewrite(criteria *AudienceCriterion) (*AudienceCriterion, error) {
+	conditions, err := a.conditions()
+	if err != nil {
+		return nil, err
+	}
+	if len(conditions) == 0 {
+		return criteria, nil
+	}
+
+	replaced := make(map[AudienceField]bool, len(conditions))
+	for _, condition := range conditions {
+		replaced[condition.Field] = true
+	}
+	var kept []AudienceCriterion
+	if criteria != nil {
+		top := []AudienceCriterion{*criteria}
+		if criteria.Operator == AudienceAnd {
+			top = criteria.Criteria
+		}
+		for _, criterion := range top {
+			if criterion.IsGroup() || !replaced[criterion.Field] {
+				kept = append(kept, criterion)
+			}
+		}
+	}
+	conditions = append(kept, conditions...)
+
+	rewritten := &conditions[0]
+	if len(conditions) > 1 {
+		rewritten = &AudienceCriterion{Operator: AudienceAnd, Criteria: conditions}
+	}
+	if err := rewritten.Validate(); err != nil {
+		return nil, err
+	}
+	return rewritten, nil
+}
+
+// conditions returns a condition for every attribute that is set
+func (a AudienceAttributes) conditions() ([]AudienceCriterion, error) {
+	var conditions []AudienceCriterion
+	if a.Gender != "" {
+		gender, ok := ParseGender(strings.ToLower(strings.TrimSpace(a.Gender)))
+		if !ok {
+			return nil, fmt.Errorf("invalid gender %q: must be male, female, non-binary or other", a.Gender)
+		}
+		conditions = append(conditions, AudienceCriterion{Operator: AudienceIn, Field: AudienceFieldGender, Values: []string{string(gender)}})
+	}
+	if a.BirthCountry != "" {
+		country, ok := NormalizeCountry(a.BirthCountry)
+		if !ok {
+			return nil, fmt.Errorf("invalid birth country %q: must be an ISO 3166-1 alpha-2 code or a country name", a.BirthCountry)
+		}
+		conditions = append(conditions, AudienceCriterion{Operator: AudienceIn, Field: AudienceFieldBirthCountry, Values: []string{country}})
+	}
+	if a.AgeGroup != "" {
+		age, ok := ageGroupCriterion(a.AgeGroup)
+		if !ok {
+			return nil, fmt.Errorf("invalid age group %q: must be a range such as 25-34, or a minimum such as 65+", a.AgeGroup)
+		}
+		conditions = append(conditions, age)
+	}
+	if a.PurchasesLastMo > 0 {
+		purchases := float64(a.PurchasesLastMo)
+		conditions = append(conditions, AudienceCriterion{Operator: AudienceGte, Field: AudienceFieldPurchases, Value: &purchases})
+	}
+	return conditions, nil
+}
+
+// Summary describes the audience in words for display, and is empty for an audience without a definition or an
+// average of hours on social media
+func (a *Audience) Summary() string {
+	var summary string
+	if definition := a.Definition(); definition != nil {
+		summary = definition.Summary()
+	}
+	if a.AverageHoursSocial > 0 {
+		average := "averaging " + formatNumber(a.AverageHoursSocial) + " hours a day on social media"
+		if summary == "" {
+			summary = average
+		} else {
+			summary += ", " + average
+		}
+	}
+
+	// Capitalise the first letter, which follows a parenthesis when the summary starts with a group
+	first := strings.IndexFunc(summary, unicode.IsLetter)
+	if first < 0 {
+		return summary
+	}
+	return summary[:first] + strings.ToUpper(summary[first:first+1]) + summary[first+1:]
+}
+
+// ageGroupCriterion turns an age group such as "25-34" or "65+" into a condition on age
+func ageGroupCriterion(group string) (AudienceCriterion, bool) {
+	min, max, ok := ageGroupRange(group)
+	if !ok {
+		return AudienceCriterion{}, false
+	}
+	if math.IsInf(max, 1) {
+		return AudienceCriterion{Operator: AudienceGte, Field: AudienceFieldAge, Value: &min}, true
+	}
+	return AudienceCriterion{Operator: AudienceBetween, Field: AudienceFieldAge, Min: &min, Max: &max}, true
+}
+
+// ageGroupRange returns the inclusive range of ages of an age group such as "25-34", or "65+" whose range has no
+// upper bound
+func ageGroupRange(group string) (float64, float64, bool) {
+	group = strings.TrimSpace(group)
+	if lower, ok := strings.CutSuffix(group, "+"); ok {
+		min, err := strconv.ParseFloat(lower, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return min, math.Inf(1), true
+	}
+
+	lower, upper, ok := strings.Cut(group, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	min, err := strconv.ParseFloat(strings.TrimSpace(lower), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	max, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return min, max, true
 }
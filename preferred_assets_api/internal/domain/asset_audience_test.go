@@ -16,11 +16,7 @@ func newValidAudience() *domain.Audience {
 			Type:  domain.AssetTypeAudience,
 			Title: "Audience Example",
 		},
-		Gender:          "female",
-		BirthCountry:    "Canada",
-		AgeGroup:        "25-34",
-		HoursSocial:     3,
-		PurchasesLastMo: 5,
+		Criteria: &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"CA"}},
 	}
 }
 
@@ -60,3 +56,198 @@ func TestAudience_Validate(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestAudience_ValidateCriteria(t *testing.T) {
+	t.Run("valid criteria", func(t *testing.T) {
+		// Arrange
+		a := &domain.Audience{
+			AssetBase: domain.AssetBase{ID: "aud-2", Type: domain.AssetTypeAudience, Title: "Young men"},
+			Criteria:  &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"male"}},
+		}
+
+		// Act
+		err := a.Validate()
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid criteria", func(t *testing.T) {
+		// Arrange
+		a := &domain.Audience{
+			AssetBase: domain.AssetBase{ID: "aud-3", Type: domain.AssetTypeAudience, Title: "Nobody"},
+			Criteria:  &domain.AudienceCriterion{Operator: domain.AudienceAnd},
+		}
+
+		// Act
+		err := a.Validate()
+
+		// Assert
+		require.Error(t, err)
+	})
+}
+
+func TestAudienceAttributes_Criteria(t *testing.T) {
+	tests := []struct {
+		name        string
+		attributes  domain.AudienceAttributes
+		wantSummary string
+		wantErr     string
+	}{
+		{
+			name:        "every attribute",
+			attributes:  domain.AudienceAttributes{Gender: "female", BirthCountry: "CA", AgeGroup: "25-34", PurchasesLastMo: 2},
+			wantSummary: "Gender is female, born in CA, aged 25 to 34 and with at least 2 purchases last month",
+		},
+		{
+			name:        "gender in any case",
+			attributes:  domain.AudienceAttributes{Gender: " Male"},
+			wantSummary: "Gender is male",
+		},
+		{
+			name:        "spaced age group",
+			attributes:  domain.AudienceAttributes{AgeGroup: "25 - 34"},
+			wantSummary: "Aged 25 to 34",
+		},
+		{
+			name:        "open ended age group",
+			attributes:  domain.AudienceAttributes{AgeGroup: "65+"},
+			wantSummary: "Aged at least 65",
+		},
+		{
+			name:        "country in lower case",
+			attributes:  domain.AudienceAttributes{BirthCountry: " gr "},
+			wantSummary: "Born in GR",
+		},
+		{
+			name:        "country alias",
+			attributes:  domain.AudienceAttributes{BirthCountry: "UK"},
+			wantSummary: "Born in GB",
+		},
+		{
+			name:        "country name",
+			attributes:  domain.AudienceAttributes{BirthCountry: "Canada"},
+			wantSummary: "Born in CA",
+		},
+		{
+			name:        "country name in any case",
+			attributes:  domain.AudienceAttributes{BirthCountry: "united states of america"},
+			wantSummary: "Born in US",
+		},
+		{
+			name:       "unknown country",
+			attributes: domain.AudienceAttributes{BirthCountry: "Atlantis"},
+			wantErr:    "invalid birth country",
+		},
+		{
+			name:       "unknown gender",
+			attributes: domain.AudienceAttributes{Gender: "robot"},
+			wantErr:    "invalid gender",
+		},
+		{
+			name:       "malformed age group",
+			attributes: domain.AudienceAttributes{AgeGroup: "young"},
+			wantErr:    "invalid age group",
+		},
+		{
+			name:       "age group out of range",
+			attributes: domain.AudienceAttributes{AgeGroup: "150-200"},
+			wantErr:    "age must be between 0 and 150",
+		},
+		{
+			name:       "no attributes",
+			attributes: domain.AudienceAttributes{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			criteria, err := tt.attributes.Criteria()
+
+			// Assert
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			audience := &domain.Audience{Criteria: criteria}
+			require.Equal(t, tt.wantSummary, audience.Summary())
+		})
+	}
+}
+
+func TestAudienceAttributes_Rewrite(t *testing.T) {
+	age := func(min, max float64) domain.AudienceCriterion {
+		return domain.AudienceCriterion{Operator: domain.AudienceBetween, Field: domain.AudienceFieldAge, Min: &min, Max: &max}
+	}
+	gender := func(values ...string) domain.AudienceCriterion {
+		return domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: values}
+	}
+	country := func(values ...string) domain.AudienceCriterion {
+		return domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: values}
+	}
+
+	tests := []struct {
+		name        string
+		attributes  domain.AudienceAttributes
+		criteria    *domain.AudienceCriterion
+		wantSummary string
+	}{
+		{
+			name:        "replaces the condition on the same field",
+			attributes:  domain.AudienceAttributes{Gender: "male"},
+			criteria:    &domain.AudienceCriterion{Operator: domain.AudienceAnd, Criteria: []domain.AudienceCriterion{gender("female"), age(25, 34)}},
+			wantSummary: "Aged 25 to 34 and gender is male",
+		},
+		{
+			name:        "replaces a single condition",
+			attributes:  domain.AudienceAttributes{AgeGroup: "35-44"},
+			criteria:    ptr(age(25, 34)),
+			wantSummary: "Aged 35 to 44",
+		},
+		{
+			name:        "adds to a single condition on another field",
+			attributes:  domain.AudienceAttributes{BirthCountry: "Greece"},
+			criteria:    ptr(gender("female")),
+			wantSummary: "Gender is female and born in GR",
+		},
+		{
+			name:        "keeps nested groups",
+			attributes:  domain.AudienceAttributes{Gender: "male"},
+			criteria:    &domain.AudienceCriterion{Operator: domain.AudienceOr, Criteria: []domain.AudienceCriterion{gender("female"), country("GR")}},
+			wantSummary: "(Gender is female or born in GR) and gender is male",
+		},
+		{
+			name:        "no attributes",
+			criteria:    ptr(gender("female")),
+			wantSummary: "Gender is female",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			criteria, err := tt.attributes.Rewrite(tt.criteria)
+
+			// Assert
+			require.NoError(t, err)
+			audience := &domain.Audience{Criteria: criteria}
+			require.Equal(t, tt.wantSummary, audience.Summary())
+		})
+	}
+}
+
+func TestAudience_SummaryWithAverageHours(t *testing.T) {
+	// Arrange
+	withCriteria := &domain.Audience{
+		Criteria:           &domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"female"}},
+		AverageHoursSocial: 4.5,
+	}
+	averageOnly := &domain.Audience{AverageHoursSocial: 2}
+
+	// Act & Assert
+	require.Equal(t, "Gender is female, averaging 4.5 hours a day on social media", withCriteria.Summary())
+	require.Equal(t, "Averaging 2 hours a day on social media", averageOnly.Summary())
+}
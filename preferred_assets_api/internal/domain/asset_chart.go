@@ -1,28 +1,114 @@
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
 
+// ChartKind is how the data of a chart is drawn
+type ChartKind string
+
+const (
+	// ChartLine draws one line per series
+	ChartLine ChartKind = "line"
+	// ChartBar draws one group of bars per category, one bar per series
+	ChartBar ChartKind = "bar"
+	// ChartPie draws the single series of the chart as the slices of a circle, one per category
+	ChartPie ChartKind = "pie"
+	// ChartScatter draws the points of every series without joining them
+	ChartScatter ChartKind = "scatter"
+)
+
+// ParseChartKind validates a chart kind name
+func ParseChartKind(s string) (ChartKind, bool) {
+	switch kind := ChartKind(s); kind {
+	case ChartLine, ChartBar, ChartPie, ChartScatter:
+		return kind, true
+	default:
+		return "", false
+	}
+}
+
+// ChartSeries is one named sequence of values of a chart. Without X the values belong to the categories of the
+// chart in order; with X every value is placed at the x value of the same index.
+type ChartSeries struct {
+	Name   string
+	X      []float64
+	Values []float64
+}
+
+// Chart holds its values either as named Series, or in the Data matrix of charts created before series existed:
+// there the first column of every row is its x value and every other column the y value of one unnamed series.
 type Chart struct {
 	AssetBase
+	// Kind is empty for charts created before kinds existed, which are line charts
+	Kind ChartKind
+	// AxesTitles and AxesUnits describe the x and the y axis, in that order
 	AxesTitles []string
+	AxesUnits  []string
+	// Categories label the x axis of line and bar charts, and the slices of pie charts
+	Categories []string
+	Series     []ChartSeries
 	Data       [][]float64
 }
 
 var _ Asset = (*Chart)(nil)
 
+// DrawnAs returns the kind of the chart, line for charts without one
+func (c *Chart) DrawnAs() ChartKind {
+	if c.Kind == "" {
+		return ChartLine
+	}
+	return c.Kind
+}
+
 // Validate performs domain validation for the Chart type.
 func (c *Chart) Validate() error {
 	if err := c.AssetBase.Validate(); err != nil {
 		return err
 	}
 
-	if len(c.Data) == 0 {
-		return fmt.Errorf("data cannot be empty")
+	kind := c.DrawnAs()
+	if _, ok := ParseChartKind(string(kind)); !ok {
+		return fmt.Errorf("invalid chart kind %q: must be line, bar, pie or scatter", c.Kind)
 	}
-
 	if len(c.AxesTitles) > 2 {
 		return fmt.Errorf("maximum 2 axes titles allowed")
 	}
+	if len(c.AxesUnits) > 2 {
+		return fmt.Errorf("maximum 2 axes units allowed")
+	}
+	for i, category := range c.Categories {
+		if strings.TrimSpace(category) == "" {
+			return fmt.Errorf("category %d cannot be blank", i)
+		}
+		if slices.Contains(c.Categories[:i], category) {
+			return fmt.Errorf("duplicate category %q", category)
+		}
+	}
+
+	if len(c.Series) > 0 {
+		if len(c.Data) > 0 {
+			return errors.New("a chart has either series or data, not both")
+		}
+		return c.validateSeries(kind)
+	}
+	return c.validateData(kind)
+}
+
+// validateData checks the data matrix of a chart without series
+func (c *Chart) validateData(kind ChartKind) error {
+	if len(c.Data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	if kind == ChartPie {
+		return errors.New("pie charts need a series of values for their categories")
+	}
+	if len(c.Categories) > 0 {
+		return errors.New("categories need series: data rows are placed by their first column")
+	}
 
 	firstRowLength := len(c.Data[0])
 	for i, row := range c.Data {
@@ -33,3 +119,52 @@ func (c *Chart) Validate() error {
 
 	return nil
 }
+
+// validateSeries checks that the series of a chart fit its kind: values by category for bar and pie charts,
+// values by x for scatter charts, and either for line charts
+func (c *Chart) validateSeries(kind ChartKind) error {
+	if kind == ChartPie && len(c.Series) != 1 {
+		return errors.New("pie charts have exactly one series")
+	}
+
+	for i, series := range c.Series {
+		if strings.TrimSpace(series.Name) == "" {
+			return fmt.Errorf("series %d needs a name", i)
+		}
+		for _, earlier := range c.Series[:i] {
+			if earlier.Name == series.Name {
+				return fmt.Errorf("duplicate series %q", series.Name)
+			}
+		}
+		if len(series.Values) == 0 {
+			return fmt.Errorf("series %q has no values", series.Name)
+		}
+
+		switch {
+		case series.X != nil && kind != ChartLine && kind != ChartScatter:
+			return fmt.Errorf("series %q: %s charts place values by category, not by x", series.Name, kind)
+		case series.X == nil && kind == ChartScatter:
+			return fmt.Errorf("series %q: scatter charts need an x value for every value", series.Name)
+		case series.X != nil && len(c.Categories) > 0:
+			return fmt.Errorf("series %q: values are placed by category or by x, not both", series.Name)
+		case series.X != nil && len(series.X) != len(series.Values):
+			return fmt.Errorf("series %q has %d x values for %d values", series.Name, len(series.X), len(series.Values))
+		case series.X == nil && len(series.Values) != len(c.Categories):
+			return fmt.Errorf("series %q has %d values for %d categories", series.Name, len(series.Values), len(c.Categories))
+		}
+	}
+
+	if kind == ChartPie {
+		total := 0.0
+		for _, value := range c.Series[0].Values {
+			if value < 0 {
+				return errors.New("pie chart values cannot be negative")
+			}
+			total += value
+		}
+		if total == 0 {
+			return errors.New("pie chart values cannot all be zero")
+		}
+	}
+	return nil
+}
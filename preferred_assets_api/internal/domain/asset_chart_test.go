@@ -98,3 +98,102 @@ func TestChartValidate(t *testing.T) {
 		require.Contains(t, err.Error(), "data cannot be empty")
 	})
 }
+
+func TestChartValidate_Series(t *testing.T) {
+	base := domain.AssetBase{ID: "5", Type: domain.AssetTypeChart, Title: "Series"}
+	months := []string{"Jan", "Feb", "Mar"}
+
+	tests := []struct {
+		name    string
+		chart   domain.Chart
+		wantErr string
+	}{
+		{
+			name: "bar chart by category",
+			chart: domain.Chart{AssetBase: base, Kind: domain.ChartBar, Categories: months, AxesUnits: []string{"", "%"},
+				Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2, 3}}, {Name: "US", Values: []float64{3, 2, 1}}}},
+		},
+		{
+			name: "line chart by x",
+			chart: domain.Chart{AssetBase: base, Kind: domain.ChartLine,
+				Series: []domain.ChartSeries{{Name: "UK", X: []float64{18, 25}, Values: []float64{2.3, 3.5}}}},
+		},
+		{
+			name:  "pie chart",
+			chart: domain.Chart{AssetBase: base, Kind: domain.ChartPie, Categories: months, Series: []domain.ChartSeries{{Name: "Share", Values: []float64{0, 2, 3}}}},
+		},
+		{
+			name:    "unknown kind",
+			chart:   domain.Chart{AssetBase: base, Kind: "radar", Data: [][]float64{{1, 2}}},
+			wantErr: "invalid chart kind",
+		},
+		{
+			name:    "too many axes units",
+			chart:   domain.Chart{AssetBase: base, AxesUnits: []string{"a", "b", "c"}, Data: [][]float64{{1, 2}}},
+			wantErr: "maximum 2 axes units allowed",
+		},
+		{
+			name:    "duplicate category",
+			chart:   domain.Chart{AssetBase: base, Categories: []string{"Jan", "Jan"}, Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2}}}},
+			wantErr: `duplicate category "Jan"`,
+		},
+		{
+			name: "series and data",
+			chart: domain.Chart{AssetBase: base, Categories: months, Data: [][]float64{{1, 2}},
+				Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2, 3}}}},
+			wantErr: "either series or data",
+		},
+		{
+			name: "duplicate series",
+			chart: domain.Chart{AssetBase: base, Categories: months,
+				Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2, 3}}, {Name: "UK", Values: []float64{1, 2, 3}}}},
+			wantErr: `duplicate series "UK"`,
+		},
+		{
+			name:    "values not matching the categories",
+			chart:   domain.Chart{AssetBase: base, Categories: months, Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2}}}},
+			wantErr: "has 2 values for 3 categories",
+		},
+		{
+			name:    "bar chart by x",
+			chart:   domain.Chart{AssetBase: base, Kind: domain.ChartBar, Series: []domain.ChartSeries{{Name: "UK", X: []float64{1}, Values: []float64{1}}}},
+			wantErr: "bar charts place values by category",
+		},
+		{
+			name:    "scatter chart by category",
+			chart:   domain.Chart{AssetBase: base, Kind: domain.ChartScatter, Categories: months, Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, 2, 3}}}},
+			wantErr: "scatter charts need an x value",
+		},
+		{
+			name: "pie chart with two series",
+			chart: domain.Chart{AssetBase: base, Kind: domain.ChartPie, Categories: []string{"A"},
+				Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1}}, {Name: "US", Values: []float64{1}}}},
+			wantErr: "exactly one series",
+		},
+		{
+			name:    "pie chart with a negative value",
+			chart:   domain.Chart{AssetBase: base, Kind: domain.ChartPie, Categories: []string{"A", "B"}, Series: []domain.ChartSeries{{Name: "UK", Values: []float64{1, -1}}}},
+			wantErr: "cannot be negative",
+		},
+		{
+			name:    "pie chart from data",
+			chart:   domain.Chart{AssetBase: base, Kind: domain.ChartPie, Data: [][]float64{{1, 2}}},
+			wantErr: "pie charts need a series",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.chart.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+// ErrAssetExists is returned when importing an asset whose id is taken without replacing existing assets
+var ErrAssetExists = NewError(KindConflict, "asset already exists")
+
+// AssetImportMode decides what importing an asset whose id is already taken does
+type AssetImportMode string
+
+const (
+	// AssetImportInsert rejects the asset with ErrAssetExists
+	AssetImportInsert AssetImportMode = "insert"
+	// AssetImportUpsert replaces the stored asset, which must be of the same type
+	AssetImportUpsert AssetImportMode = "upsert"
+)
+
+// ParseAssetImportMode validates an import mode name
+func ParseAssetImportMode(s string) (AssetImportMode, bool) {
+	switch mode := AssetImportMode(s); mode {
+	case AssetImportInsert, AssetImportUpsert:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
+// AssetImportOutcome is what importing one asset did
+type AssetImportOutcome string
+
+const (
+	AssetImportCreated AssetImportOutcome = "created"
+	AssetImportUpdated AssetImportOutcome = "updated"
+)
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// AssetSortField is the field a listing of assets is ordered by
+type AssetSortField string
+
+const (
+	AssetSortCreatedAt AssetSortField = "created_at"
+	AssetSortUpdatedAt AssetSortField = "updated_at"
+	AssetSortTitle     AssetSortField = "title"
+)
+
+// ParseAssetSortField validates a sort field name
+func ParseAssetSortField(s string) (AssetSortField, bool) {
+	switch field := AssetSortField(s); field {
+	case AssetSortCreatedAt, AssetSortUpdatedAt, AssetSortTitle:
+		return field, true
+	default:
+		return "", false
+	}
+}
+
+// AssetQuery selects a page of the asset catalogue. Zero-valued filters do not constrain the result,
+// any audience filter restricts it to audiences, and ranges are inclusive. Audience filters are matched against
+// the definition of each audience, as described by MatchesAudience.
+type AssetQuery struct {
+	Type        *AssetType
+	Title       string // case-insensitive substring
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+
+	Gender         string
+	BirthCountry   string
+	AgeGroup       string
+	HoursSocialMin *float64
+	HoursSocialMax *float64
+
+	SortBy     AssetSortField
+	Descending bool
+	Limit      int
+	Cursor     string // NextCursor of the previous page, or "" for the first page
+}
+
+// AssetPage is one page of the asset catalogue.
+// NextCursor is an opaque token for the following page and is empty on the last page.
+type AssetPage struct {
+	Assets     []Asset
+	NextCursor string
+}
+
+// HasAudienceFilters reports whether the query filters on the attributes of audiences
+func (q AssetQuery) HasAudienceFilters() bool {
+	return q.Gender != "" || q.BirthCountry != "" || q.AgeGroup != "" ||
+		q.HoursSocialMin != nil || q.HoursSocialMax != nil
+}
+
+// MatchesAudience reports whether the audience passes every audience filter of the query. An audience passes a
+// filter when its definition has a condition on the filtered attribute and admits respondents whose attribute
+// is within the filter, whatever their other attributes. So an audience of women aged 30 to 40 passes
+// gender=female and age_group=25-34, but neither gender=male nor birth_country=GR. An audience recording an
+// average of hours on social media within the hours range also passes that filter.
+func (q AssetQuery) MatchesAudience(a *Audience) bool {
+	definition := a.Definition()
+	if definition == nil {
+		// Without conditions, only the average hours can pass a filter
+		definition = &AudienceCriterion{}
+	}
+
+	if q.Gender != "" && !definition.admitsValue(AudienceFieldGender, q.Gender) {
+		return false
+	}
+	if q.BirthCountry != "" {
+		country, _ := NormalizeCountry(q.BirthCountry)
+		if !definition.admitsValue(AudienceFieldBirthCountry, country) {
+			return false
+		}
+	}
+	if q.AgeGroup != "" {
+		min, max, ok := ageGroupRange(q.AgeGroup)
+		if !ok || !definition.admitsRange(AudienceFieldAge, min, max) {
+			return false
+		}
+	}
+	if q.HoursSocialMin != nil || q.HoursSocialMax != nil {
+		min, max := 0.0, AudienceFieldHoursSocial.maximum()
+		if q.HoursSocialMin != nil {
+			min = *q.HoursSocialMin
+		}
+		if q.HoursSocialMax != nil {
+			max = *q.HoursSocialMax
+		}
+		average := a.AverageHoursSocial > 0 && a.AverageHoursSocial >= min && a.AverageHoursSocial <= max
+		if !average && !definition.admitsRange(AudienceFieldHoursSocial, min, max) {
+			return false
+		}
+	}
+	return true
+}
+
+// admitsValue reports whether the criterion has a condition on a gender or birth country field and holds for
+// the value of it
+func (c *AudienceCriterion) admitsValue(field AudienceField, value string) bool {
+	return c.constrains(field) && c.holdsFor(field, value, 0)
+}
+
+// admitsRange reports whether the criterion has a condition on a numeric field and holds for some value of it
+// from min to max. The criterion only changes its outcome at the numbers its conditions compare with, so it
+// is tried at the ends of the range, at those numbers and between each two of them.
+func (c *AudienceCriterion) admitsRange(field AudienceField, min, max float64) bool {
+	if !c.constrains(field) || min > max {
+		return false
+	}
+	max = math.Min(max, field.maximum())
+
+	candidates := []float64{min, max}
+	for _, threshold := range c.thresholds(field, nil) {
+		if threshold > min && threshold < max {
+			candidates = append(candidates, threshold)
+		}
+	}
+	slices.Sort(candidates)
+	candidates = slices.Compact(candidates)
+	for i := len(candidates) - 1; i > 0; i-- {
+		candidates = append(candidates, (candidates[i-1]+candidates[i])/2)
+	}
+
+	for _, number := range candidates {
+		if c.holdsFor(field, "", number) {
+			return true
+		}
+	}
+	return false
+}
+
+// constrains reports whether the criterion has a condition on the field
+func (c *AudienceCriterion) constrains(field AudienceField) bool {
+	if !c.IsGroup() {
+		return c.Field == field
+	}
+	for i := range c.Criteria {
+		if c.Criteria[i].constrains(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// thresholds appends the numbers the conditions of the criterion on the field compare with
+func (c *AudienceCriterion) thresholds(field AudienceField, thresholds []float64) []float64 {
+	if c.IsGroup() {
+		for i := range c.Criteria {
+			thresholds = c.Criteria[i].thresholds(field, thresholds)
+		}
+		return thresholds
+	}
+	if c.Field != field {
+		return thresholds
+	}
+	for _, number := range []*float64{c.Value, c.Min, c.Max} {
+		if number != nil {
+			thresholds = append(thresholds, *number)
+		}
+	}
+	return thresholds
+}
+
+// holdsFor reports whether the criterion holds for a respondent with the value or number for the field, taking
+// every condition on another field to hold
+func (c *AudienceCriterion) holdsFor(field AudienceField, value string, number float64) bool {
+	switch c.Operator {
+	case AudienceAnd:
+		for i := range c.Criteria {
+			if !c.Criteria[i].holdsFor(field, value, number) {
+				return false
+			}
+		}
+		return true
+	case AudienceOr:
+		for i := range c.Criteria {
+			if c.Criteria[i].holdsFor(field, value, number) {
+				return true
+			}
+		}
+		return false
+	}
+	if c.Field != field {
+		return true
+	}
+
+	switch c.Operator {
+	case AudienceIn:
+		return slices.Contains(c.Values, value)
+	case AudienceNotIn:
+		return !slices.Contains(c.Values, value)
+	case AudienceBetween:
+		return c.Min != nil && c.Max != nil && number >= *c.Min && number <= *c.Max
+	}
+	if c.Value == nil {
+		return false
+	}
+	switch c.Operator {
+	case AudienceEq:
+		return number == *c.Value
+	case AudienceNe:
+		return number != *c.Value
+	case AudienceLt:
+		return number < *c.Value
+	case AudienceLte:
+		return number <= *c.Value
+	case AudienceGt:
+		return number > *c.Value
+	case AudienceGte:
+		return number >= *c.Value
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetQuery_MatchesAudience(t *testing.T) {
+	base := domain.AssetBase{ID: "aud-1", Type: domain.AssetTypeAudience, Title: "Audience"}
+	country := func(values ...string) domain.AudienceCriterion {
+		return domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: values}
+	}
+	// Women aged 30 to 40 born in Greece or Cyprus, or anyone spending more than 5 hours a day on social media
+	criteria := group(domain.AudienceOr,
+		group(domain.AudienceAnd, gender("female"), ageBetween(30, 40), country("GR", "CY")),
+		hoursSocial(domain.AudienceGt, 5))
+	audience := &domain.Audience{AssetBase: base, Criteria: &criteria}
+
+	tests := []struct {
+		name     string
+		audience *domain.Audience
+		query    domain.AssetQuery
+		want     bool
+	}{
+		{"no audience filters", audience, domain.AssetQuery{}, true},
+		{"gender in the definition", audience, domain.AssetQuery{Gender: "female"}, true},
+		{"gender only admitted through another field", audience, domain.AssetQuery{Gender: "male"}, true},
+		{"country in a list", audience, domain.AssetQuery{BirthCountry: "cy"}, true},
+		{"overlapping age group", audience, domain.AssetQuery{AgeGroup: "25-34"}, true},
+		{"open ended age group", audience, domain.AssetQuery{AgeGroup: "65+"}, true},
+		{"hours above the threshold", audience, domain.AssetQuery{HoursSocialMin: number(6)}, true},
+		{"inverted hours range", audience, domain.AssetQuery{HoursSocialMin: number(8), HoursSocialMax: number(6)}, false},
+		{"every filter together", audience, domain.AssetQuery{Gender: "female", BirthCountry: "GR", AgeGroup: "35-44"}, true},
+		{
+			name:     "gender excluded",
+			audience: &domain.Audience{AssetBase: base, Criteria: &domain.AudienceCriterion{Operator: domain.AudienceNotIn, Field: domain.AudienceFieldGender, Values: []string{"male"}}},
+			query:    domain.AssetQuery{Gender: "male"},
+			want:     false,
+		},
+		{
+			name:     "hours at a strict threshold",
+			audience: &domain.Audience{AssetBase: base, Criteria: ptr(hoursSocial(domain.AudienceGt, 5))},
+			query:    domain.AssetQuery{HoursSocialMin: number(5), HoursSocialMax: number(5)},
+			want:     false,
+		},
+		{
+			name:     "hours between two strict thresholds",
+			audience: &domain.Audience{AssetBase: base, Criteria: ptr(group(domain.AudienceAnd, hoursSocial(domain.AudienceGt, 2), hoursSocial(domain.AudienceLt, 3)))},
+			query:    domain.AssetQuery{HoursSocialMin: number(1), HoursSocialMax: number(4)},
+			want:     true,
+		},
+		{
+			name:     "attribute without a condition",
+			audience: &domain.Audience{AssetBase: base, Criteria: ptr(ageBetween(18, 24))},
+			query:    domain.AssetQuery{Gender: "female"},
+			want:     false,
+		},
+		{
+			name:     "age group outside every range",
+			audience: &domain.Audience{AssetBase: base, Criteria: ptr(group(domain.AudienceAnd, ageBetween(18, 24), gender("male")))},
+			query:    domain.AssetQuery{AgeGroup: "25-34"},
+			want:     false,
+		},
+		{
+			name:     "ages narrowed by two conditions",
+			audience: &domain.Audience{AssetBase: base, Criteria: ptr(group(domain.AudienceAnd, ageBetween(18, 40), ageBetween(35, 60)))},
+			query:    domain.AssetQuery{AgeGroup: "25-34"},
+			want:     false,
+		},
+		{
+			name:     "audience without a definition",
+			audience: &domain.Audience{AssetBase: base},
+			query:    domain.AssetQuery{Gender: "female"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.query.MatchesAudience(tt.audience)
+
+			// Assert
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func ptr(criterion domain.AudienceCriterion) *domain.AudienceCriterion {
+	return &criterion
+}
@@ -0,0 +1,13 @@
+package domain
+
+// AssetSearchHit is an asset matching a full-text search, with its relevance score
+type AssetSearchHit struct {
+	AssetID string
+	Score   float64
+}
+
+// AssetSearchResult is a matching asset with its relevance score; higher scores are more relevant
+type AssetSearchResult struct {
+	Asset Asset
+	Score float64
+}
@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxAudienceCriteriaDepth is how deep groups of audience criteria may be nested, counting the outermost one
+const MaxAudienceCriteriaDepth = 4
+
+// Gender is the gender of a respondent
+type Gender string
+
+const (
+	GenderMale      Gender = "male"
+	GenderFemale    Gender = "female"
+	GenderNonBinary Gender = "non-binary"
+	GenderOther     Gender = "other"
+)
+
+// ParseGender validates a gender name
+func ParseGender(s string) (Gender, bool) {
+	switch gender := Gender(s); gender {
+	case GenderMale, GenderFemale, GenderNonBinary, GenderOther:
+		return gender, true
+	default:
+		return "", false
+	}
+}
+
+// AudienceField is a respondent attribute audience criteria test
+type AudienceField string
+
+const (
+	AudienceFieldGender       AudienceField = "gender"
+	AudienceFieldBirthCountry AudienceField = "birth_country"
+	AudienceFieldAge          AudienceField = "age"
+	AudienceFieldHoursSocial  AudienceField = "hours_social"
+	AudienceFieldPurchases    AudienceField = "purchases_last_month"
+)
+
+// maximum is the largest value a numeric field can hold
+func (f AudienceField) maximum() float64 {
+	switch f {
+	case AudienceFieldAge:
+		return 150
+	case AudienceFieldHoursSocial:
+		return 24
+	default:
+		return 1e6
+	}
+}
+
+// AudienceOperator combines audience criteria, or compares a respondent attribute
+type AudienceOperator string
+
+const (
+	// AudienceAnd and AudienceOr combine criteria
+	AudienceAnd AudienceOperator = "and"
+	AudienceOr  AudienceOperator = "or"
+
+	// AudienceIn and AudienceNotIn compare a gender or birth country with a list of them
+	AudienceIn    AudienceOperator = "in"
+	AudienceNotIn AudienceOperator = "not_in"
+
+	// The other operators compare a number with one value, or with a range for AudienceBetween
+	AudienceEq      AudienceOperator = "eq"
+	AudienceNe      AudienceOperator = "ne"
+	AudienceLt      AudienceOperator = "lt"
+	AudienceLte     AudienceOperator = "lte"
+	AudienceGt      AudienceOperator = "gt"
+	AudienceGte     AudienceOperator = "gte"
+	AudienceBetween AudienceOperator = "between"
+)
+
+// AudienceCriterion is a node of the criteria defining an audience. A group combines its Criteria with And or Or;
+// any other node is a condition on one respondent Field. In and NotIn compare genders and birth countries with
+// Values; the numeric operators compare age, hours on social media a day or purchases last month with Value, or
+// with the inclusive range from Min to Max for Between.
+type AudienceCriterion struct {
+	Operator AudienceOperator
+	Criteria []AudienceCriterion
+
+	Field  AudienceField
+	Values []string
+	Value  *float64
+	Min    *float64
+	Max    *float64
+}
+
+// IsGroup reports whether the criterion combines other criteria
+func (c *AudienceCriterion) IsGroup() bool {
+	return c.Operator == AudienceAnd || c.Operator == AudienceOr
+}
+
+// Validate checks the criterion and every criterion it groups
+func (c *AudienceCriterion) Validate() error {
+	return c.validate("criteria", 1)
+}
+
+// validate checks a criterion at the given path of the criteria, naming the path in errors
+func (c *AudienceCriterion) validate(path string, depth int) error {
+	if c.IsGroup() {
+		if depth > MaxAudienceCriteriaDepth {
+			return fmt.Errorf("%s: groups cannot be nested more than %d deep", path, MaxAudienceCriteriaDepth)
+		}
+		if len(c.Criteria) == 0 {
+			return fmt.Errorf("%s: %s groups need at least one criterion", path, c.Operator)
+		}
+		if c.Field != "" || len(c.Values) > 0 || c.Value != nil || c.Min != nil || c.Max != nil {
+			return fmt.Errorf("%s: groups take criteria, not a field or values", path)
+		}
+		for i := range c.Criteria {
+			if err := c.Criteria[i].validate(fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(c.Criteria) > 0 {
+		return fmt.Errorf("%s: only and and or groups take criteria", path)
+	}
+	switch c.Field {
+	case AudienceFieldGender, AudienceFieldBirthCountry:
+		return c.validateList(path)
+	case AudienceFieldAge, AudienceFieldHoursSocial, AudienceFieldPurchases:
+		return c.validateNumber(path)
+	case "":
+		return fmt.Errorf("%s: needs a field, or and or or with criteria", path)
+	default:
+		return fmt.Errorf("%s: unknown field %q", path, c.Field)
+	}
+}
+
+// validateList checks a condition on a gender or birth country
+func (c *AudienceCriterion) validateList(path string) error {
+	if c.Operator != AudienceIn && c.Operator != AudienceNotIn {
+		return fmt.Errorf("%s: %s is compared with in or not_in", path, c.Field)
+	}
+	if c.Value != nil || c.Min != nil || c.Max != nil {
+		return fmt.Errorf("%s: %s is compared with values, not numbers", path, c.Field)
+	}
+	if len(c.Values) == 0 {
+		return fmt.Errorf("%s: %s needs at least one value", path, c.Operator)
+	}
+
+	for i, value := range c.Values {
+		if slices.Contains(c.Values[:i], value) {
+			return fmt.Errorf("%s: duplicate value %q", path, value)
+		}
+		if c.Field == AudienceFieldGender {
+			if _, ok := ParseGender(value); !ok {
+				return fmt.Errorf("%s: invalid gender %q: must be male, female, non-binary or other", path, value)
+			}
+		} else if !IsISOCountry(value) {
+			return fmt.Errorf("%s: invalid birth country %q: must be an upper case ISO 3166-1 alpha-2 code", path, value)
+		}
+	}
+	return nil
+}
+
+// validateNumber checks a condition on age, hours on social media or purchases
+func (c *AudienceCriterion) validateNumber(path string) error {
+	if len(c.Values) > 0 {
+		return fmt.Errorf("%s: %s is compared with numbers, not values", path, c.Field)
+	}
+
+	var numbers []float64
+	switch c.Operator {
+	case AudienceEq, AudienceNe, AudienceLt, AudienceLte, AudienceGt, AudienceGte:
+		if c.Value == nil || c.Min != nil || c.Max != nil {
+			return fmt.Errorf("%s: %s needs a value and no min or max", path, c.Operator)
+		}
+		numbers = []float64{*c.Value}
+	case AudienceBetween:
+		if c.Min == nil || c.Max == nil || c.Value != nil {
+			return fmt.Errorf("%s: between needs a min and a max and no value", path)
+		}
+		if *c.Min > *c.Max {
+			return fmt.Errorf("%s: min cannot be greater than max", path)
+		}
+		numbers = []float64{*c.Min, *c.Max}
+	default:
+		return fmt.Errorf("%s: %s is compared with eq, ne, lt, lte, gt, gte or between", path, c.Field)
+	}
+
+	for _, number := range numbers {
+		if number < 0 || number > c.Field.maximum() {
+			return fmt.Errorf("%s: %s must be between 0 and %s", path, c.Field, formatNumber(c.Field.maximum()))
+		}
+	}
+	return nil
+}
+
+// Summary describes the criterion in words, such as
+// "gender is male, aged 24 to 35 and spending more than 3 hours a day on social media"
+func (c *AudienceCriterion) Summary() string {
+	if !c.IsGroup() {
+		return c.describeCondition()
+	}
+
+	parts := make([]string, len(c.Criteria))
+	for i := range c.Criteria {
+		parts[i] = c.Criteria[i].Summary()
+		if c.Criteria[i].IsGroup() && len(c.Criteria[i].Criteria) > 1 {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return joinList(parts, string(c.Operator))
+}
+
+// describeCondition describes a condition on one field in words
+func (c *AudienceCriterion) describeCondition() string {
+	switch c.Field {
+	case AudienceFieldGender:
+		if c.Operator == AudienceNotIn {
+			if len(c.Values) == 1 {
+				return "gender is not " + c.Values[0]
+			}
+			return "gender is none of " + joinList(c.Values, "and")
+		}
+		return "gender is " + joinList(c.Values, "or")
+	case AudienceFieldBirthCountry:
+		if c.Operator == AudienceNotIn {
+			return "born outside " + joinList(c.Values, "and")
+		}
+		return "born in " + joinList(c.Values, "or")
+	case AudienceFieldAge:
+		return "aged " + c.describeComparison("under", "over", "", "")
+	case AudienceFieldHoursSocial:
+		return "spending " + c.describeComparison("less than", "more than", "hour", "hours") + " a day on social media"
+	case AudienceFieldPurchases:
+		return "with " + c.describeComparison("fewer than", "more than", "purchase", "purchases") + " last month"
+	default:
+		return ""
+	}
+}
+
+// describeComparison describes a numeric comparison, followed by its unit when it has one
+func (c *AudienceCriterion) describeComparison(below, above, unit, units string) string {
+	if c.Operator == AudienceBetween && c.Min != nil && c.Max != nil {
+		return strings.TrimSpace(formatNumber(*c.Min) + " to " + formatNumber(*c.Max) + " " + units)
+	}
+
+	if c.Value == nil {
+		return units
+	}
+	value := formatNumber(*c.Value)
+	if *c.Value == 1 {
+		units = unit
+	}
+	var phrase string
+	switch c.Operator {
+	case AudienceEq:
+		phrase = value
+	case AudienceNe:
+		phrase = "other than " + value
+	case AudienceLt:
+		phrase = below + " " + value
+	case AudienceLte:
+		phrase = "at most " + value
+	case AudienceGt:
+		phrase = above + " " + value
+	case AudienceGte:
+		phrase = "at least " + value
+	}
+	return strings.TrimSpace(phrase + " " + units)
+}
+
+// joinList joins items as in "a, b and c", with the given conjunction
+func joinList(items []string, conjunction string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
+
+// formatNumber formats a number of criteria without trailing zeros
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,206 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func number(f float64) *float64 {
+	return &f
+}
+
+func gender(values ...string) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: values}
+}
+
+func ageBetween(min, max float64) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: domain.AudienceBetween, Field: domain.AudienceFieldAge, Min: number(min), Max: number(max)}
+}
+
+func hoursSocial(op domain.AudienceOperator, value float64) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: op, Field: domain.AudienceFieldHoursSocial, Value: number(value)}
+}
+
+func group(op domain.AudienceOperator, criteria ...domain.AudienceCriterion) domain.AudienceCriterion {
+	return domain.AudienceCriterion{Operator: op, Criteria: criteria}
+}
+
+func TestAudienceCriterion_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		criterion domain.AudienceCriterion
+		wantErr   string
+	}{
+		{
+			name:      "males 24 to 35 spending more than 3 hours on social",
+			criterion: group(domain.AudienceAnd, gender("male"), ageBetween(24, 35), hoursSocial(domain.AudienceGt, 3)),
+		},
+		{
+			name: "nested or group of countries",
+			criterion: group(domain.AudienceOr,
+				domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"GB", "IE"}},
+				group(domain.AudienceAnd, gender("female", "non-binary"), hoursSocial(domain.AudienceLte, 1))),
+		},
+		{
+			name:      "single condition",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceEq, Field: domain.AudienceFieldPurchases, Value: number(0)},
+		},
+		{
+			name:      "empty group",
+			criterion: group(domain.AudienceAnd),
+			wantErr:   "criteria: and groups need at least one criterion",
+		},
+		{
+			name:      "group with a field",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceOr, Field: domain.AudienceFieldAge, Criteria: []domain.AudienceCriterion{gender("male")}},
+			wantErr:   "groups take criteria, not a field",
+		},
+		{
+			name:      "groups nested too deep",
+			criterion: group(domain.AudienceAnd, group(domain.AudienceOr, group(domain.AudienceAnd, group(domain.AudienceOr, group(domain.AudienceAnd, gender("male")))))),
+			wantErr:   "criteria[0][0][0][0]: groups cannot be nested more than 4 deep",
+		},
+		{
+			name:      "unknown gender, with its path",
+			criterion: group(domain.AudienceAnd, ageBetween(18, 24), gender("robot")),
+			wantErr:   `criteria[1]: invalid gender "robot"`,
+		},
+		{
+			name:      "country that is not an ISO code",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"Canada"}},
+			wantErr:   `invalid birth country "Canada"`,
+		},
+		{
+			name:      "lower case country",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceNotIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"gb"}},
+			wantErr:   `invalid birth country "gb"`,
+		},
+		{
+			name:      "duplicate value",
+			criterion: gender("male", "male"),
+			wantErr:   `duplicate value "male"`,
+		},
+		{
+			name:      "no values",
+			criterion: gender(),
+			wantErr:   "in needs at least one value",
+		},
+		{
+			name:      "gender compared as a number",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceGt, Field: domain.AudienceFieldGender, Value: number(1)},
+			wantErr:   "gender is compared with in or not_in",
+		},
+		{
+			name:      "age compared with values",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldAge, Values: []string{"30"}},
+			wantErr:   "age is compared with numbers, not values",
+		},
+		{
+			name:      "comparison without a value",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceGte, Field: domain.AudienceFieldAge},
+			wantErr:   "gte needs a value",
+		},
+		{
+			name:      "between without a max",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceBetween, Field: domain.AudienceFieldAge, Min: number(18)},
+			wantErr:   "between needs a min and a max",
+		},
+		{
+			name:      "inverted range",
+			criterion: ageBetween(35, 24),
+			wantErr:   "min cannot be greater than max",
+		},
+		{
+			name:      "more hours than a day has",
+			criterion: hoursSocial(domain.AudienceGt, 25),
+			wantErr:   "hours_social must be between 0 and 24",
+		},
+		{
+			name:      "negative purchases",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceLt, Field: domain.AudienceFieldPurchases, Value: number(-1)},
+			wantErr:   "purchases_last_month must be between 0",
+		},
+		{
+			name:      "missing field",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceEq, Value: number(1)},
+			wantErr:   "needs a field",
+		},
+		{
+			name:      "condition with criteria",
+			criterion: domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldGender, Values: []string{"male"}, Criteria: []domain.AudienceCriterion{gender("male")}},
+			wantErr:   "only and and or groups take criteria",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.criterion.Validate()
+
+			// Assert
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAudience_Summary(t *testing.T) {
+	base := domain.AssetBase{ID: "aud-1", Type: domain.AssetTypeAudience, Title: "Audience"}
+	withCriteria := func(criterion domain.AudienceCriterion) *domain.Audience {
+		return &domain.Audience{AssetBase: base, Criteria: &criterion}
+	}
+
+	tests := []struct {
+		name     string
+		audience *domain.Audience
+		want     string
+	}{
+		{
+			name:     "and group",
+			audience: withCriteria(group(domain.AudienceAnd, gender("male"), ageBetween(24, 35), hoursSocial(domain.AudienceGt, 3))),
+			want:     "Gender is male, aged 24 to 35 and spending more than 3 hours a day on social media",
+		},
+		{
+			name: "nested or group",
+			audience: withCriteria(group(domain.AudienceAnd,
+				group(domain.AudienceOr, gender("female", "non-binary"), domain.AudienceCriterion{Operator: domain.AudienceIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"GB", "IE"}}),
+				domain.AudienceCriterion{Operator: domain.AudienceGte, Field: domain.AudienceFieldPurchases, Value: number(1)})),
+			want: "(Gender is female or non-binary or born in GB or IE) and with at least 1 purchase last month",
+		},
+		{
+			name: "exclusions",
+			audience: withCriteria(group(domain.AudienceAnd,
+				domain.AudienceCriterion{Operator: domain.AudienceNotIn, Field: domain.AudienceFieldGender, Values: []string{"male", "female"}},
+				domain.AudienceCriterion{Operator: domain.AudienceNotIn, Field: domain.AudienceFieldBirthCountry, Values: []string{"US"}},
+				domain.AudienceCriterion{Operator: domain.AudienceLt, Field: domain.AudienceFieldAge, Value: number(18)})),
+			want: "Gender is none of male and female, born outside US and aged under 18",
+		},
+		{
+			name:     "single condition",
+			audience: withCriteria(hoursSocial(domain.AudienceLte, 1)),
+			want:     "Spending at most 1 hour a day on social media",
+		},
+		{
+			name:     "no definition",
+			audience: &domain.Audience{AssetBase: base},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := tt.audience.Summary()
+
+			// Assert
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "fmt"
+
+// MaxAudienceMatchProfiles is how many respondent profiles one request can match against the audiences
+const MaxAudienceMatchProfiles = 1000
+
+// ErrInvalidProfile is returned for a respondent profile with an attribute out of range
+var ErrInvalidProfile = NewError(KindValidation, "invalid respondent profile")
+
+// RespondentProfile is what is known of a respondent. Empty and nil attributes are unknown: no condition on an
+// unknown attribute holds, not even not_in or ne.
+type RespondentProfile struct {
+	Gender             Gender
+	BirthCountry       string
+	Age                *int
+	HoursSocial        *float64
+	PurchasesLastMonth *int
+}
+
+// Validate checks the known attributes of the profile
+func (p RespondentProfile) Validate() error {
+	if p.Gender != "" {
+		if _, ok := ParseGender(string(p.Gender)); !ok {
+			return fmt.Errorf("invalid gender %q: must be male, female, non-binary or other", p.Gender)
+		}
+	}
+	if p.BirthCountry != "" && !IsISOCountry(p.BirthCountry) {
+		return fmt.Errorf("invalid birth country %q: must be an upper case ISO 3166-1 alpha-2 code", p.BirthCountry)
+	}
+	for _, field := range []AudienceField{AudienceFieldAge, AudienceFieldHoursSocial, AudienceFieldPurchases} {
+		if value, ok := p.Number(field); ok && (value < 0 || value > field.maximum()) {
+			return fmt.Errorf("%s must be between 0 and %s", field, formatNumber(field.maximum()))
+		}
+	}
+	return nil
+}
+
+// Number returns the value of a numeric attribute of the profile, and whether it is known
+func (p RespondentProfile) Number(field AudienceField) (float64, bool) {
+	switch {
+	case field == AudienceFieldAge && p.Age != nil:
+		return float64(*p.Age), true
+	case field == AudienceFieldHoursSocial && p.HoursSocial != nil:
+		return *p.HoursSocial, true
+	case field == AudienceFieldPurchases && p.PurchasesLastMonth != nil:
+		return float64(*p.PurchasesLastMonth), true
+	default:
+		return 0, false
+	}
+}
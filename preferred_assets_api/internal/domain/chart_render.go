@@ -0,0 +1,44 @@
+package domain
+
+// ErrNotAChart is returned when rendering an asset that is not a chart
+var ErrNotAChart = NewError(KindUnprocessable, "asset is not a chart")
+
+// Sizes in pixels a chart may be rendered at, and is rendered at unless asked otherwise
+const (
+	MinChartRenderSize = 100
+	MaxChartRenderSize = 1200
+
+	DefaultChartRenderWidth  = 640
+	DefaultChartRenderHeight = 400
+)
+
+// ChartFormat is the image format a chart is rendered to
+type ChartFormat string
+
+const (
+	ChartSVG ChartFormat = "svg"
+	ChartPNG ChartFormat = "png"
+)
+
+// ParseChartFormat validates a chart image format name
+func ParseChartFormat(s string) (ChartFormat, bool) {
+	switch format := ChartFormat(s); format {
+	case ChartSVG, ChartPNG:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+// ChartRenderOptions describes the image a chart is rendered to. An empty Kind draws the chart as its own kind.
+type ChartRenderOptions struct {
+	Format ChartFormat
+	Kind   ChartKind
+	Width  int
+	Height int
+}
+
+// DefaultChartRenderOptions draws a chart as its own kind in SVG at the default size
+func DefaultChartRenderOptions() ChartRenderOptions {
+	return ChartRenderOptions{Format: ChartSVG, Width: DefaultChartRenderWidth, Height: DefaultChartRenderHeight}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const MaxCollectionNameLength = 100
+
+var (
+	ErrCollectionNotFound     = NewError(KindNotFound, "collection not found")
+	ErrCollectionNameTaken    = NewError(KindConflict, "collection name already in use")
+	ErrInvalidCollection      = NewError(KindValidation, "invalid collection")
+	ErrInvalidCollectionOrder = NewError(KindValidation, "order must list every item of the collection exactly once")
+	ErrNotAFavourite          = NewError(KindUnprocessable, "asset is not one of the user's favourites")
+)
+
+// Collection is a named folder a user files favourites into.
+// A favourite can be in any number of collections, and each collection keeps its own item order.
+type Collection struct {
+	ID        string
+	UserID    string
+	Name      string
+	AssetIDs  []string // items in display order
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64
+
+	// Favourites are the items with their assets, in display order.
+	// They are only loaded when a single collection is read.
+	Favourites []Favourite
+}
+
+// Validate performs domain validation for the Collection type.
+func (c *Collection) Validate() error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCollection)
+	}
+	if len([]rune(name)) > MaxCollectionNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidCollection, MaxCollectionNameLength)
+	}
+	return nil
+}
+
+// HasAsset reports whether the asset is an item of the collection
+func (c *Collection) HasAsset(assetID string) bool {
+	for _, id := range c.AssetIDs {
+		if id == assetID {
+			return true
+		}
+	}
+	return false
+}
+
+// Reorder replaces the item order, which must list every current item exactly once
+func (c *Collection) Reorder(assetIDs []string) error {
+	if len(assetIDs) != len(c.AssetIDs) {
+		return ErrInvalidCollectionOrder
+	}
+	seen := make(map[string]bool, len(assetIDs))
+	for _, id := range assetIDs {
+		if seen[id] || !c.HasAsset(id) {
+			return ErrInvalidCollectionOrder
+		}
+		seen[id] = true
+	}
+	c.AssetIDs = append([]string{}, assetIDs...)
+	return nil
+}
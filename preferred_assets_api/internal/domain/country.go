@@ -0,0 +1,107 @@
+package domain
+
+import "strings"
+
+// isoCountries holds the ISO 3166-1 alpha-2 codes of every country audiences can be defined by
+var isoCountries = map[string]bool{
+	"AD": true, "AE": true, "AF": true, "AG": true, "AI": true, "AL": true, "AM": true, "AO": true, "AQ": true, "AR": true, "AS": true, "AT": true, "AU": true, "AW": true, "AX": true, "AZ": true,
+	"BA": true, "BB": true, "BD": true, "BE": true, "BF": true, "BG": true, "BH": true, "BI": true, "BJ": true, "BL": true, "BM": true, "BN": true, "BO": true, "BQ": true, "BR": true, "BS": true,
+	"BT": true, "BV": true, "BW": true, "BY": true, "BZ": true, "CA": true, "CC": true, "CD": true, "CF": true, "CG": true, "CH": true, "CI": true, "CK": true, "CL": true, "CM": true, "CN": true,
+	"CO": true, "CR": true, "CU": true, "CV": true, "CW": true, "CX": true, "CY": true, "CZ": true, "DE": true, "DJ": true, "DK": true, "DM": true, "DO": true, "DZ": true, "EC": true, "EE": true,
+	"EG": true, "EH": true, "ER": true, "ES": true, "ET": true, "FI": true, "FJ": true, "FK": true, "FM": true, "FO": true, "FR": true, "GA": true, "GB": true, "GD": true, "GE": true, "GF": true,
+	"GG": true, "GH": true, "GI": true, "GL": true, "GM": true, "GN": true, "GP": true, "GQ": true, "GR": true, "GS": true, "GT": true, "GU": true, "GW": true, "GY": true, "HK": true, "HM": true,
+	"HN": true, "HR": true, "HT": true, "HU": true, "ID": true, "IE": true, "IL": true, "IM": true, "IN": true, "IO": true, "IQ": true, "IR": true, "IS": true, "IT": true, "JE": true, "JM": true,
+	"JO": true, "JP": true, "KE": true, "KG": true, "KH": true, "KI": true, "KM": true, "KN": true, "KP": true, "KR": true, "KW": true, "KY": true, "KZ": true, "LA": true, "LB": true, "LC": true,
+	"LI": true, "LK": true, "LR": true, "LS": true, "LT": true, "LU": true, "LV": true, "LY": true, "MA": true, "MC": true, "MD": true, "ME": true, "MF": true, "MG": true, "MH": true, "MK": true,
+	"ML": true, "MM": true, "MN": true, "MO": true, "MP": true, "MQ": true, "MR": true, "MS": true, "MT": true, "MU": true, "MV": true, "MW": true, "MX": true, "MY": true, "MZ": true, "NA": true,
+	"NC": true, "NE": true, "NF": true, "NG": true, "NI": true, "NL": true, "NO": true, "NP": true, "NR": true, "NU": true, "NZ": true, "OM": true, "PA": true, "PE": true, "PF": true, "PG": true,
+	"PH": true, "PK": true, "PL": true, "PM": true, "PN": true, "PR": true, "PS": true, "PT": true, "PW": true, "PY": true, "QA": true, "RE": true, "RO": true, "RS": true, "RU": true, "RW": true,
+	"SA": true, "SB": true, "SC": true, "SD": true, "SE": true, "SG": true, "SH": true, "SI": true, "SJ": true, "SK": true, "SL": true, "SM": true, "SN": true, "SO": true, "SR": true, "SS": true,
+	"ST": true, "SV": true, "SX": true, "SY": true, "SZ": true, "TC": true, "TD": true, "TF": true, "TG": true, "TH": true, "TJ": true, "TK": true, "TL": true, "TM": true, "TN": true, "TO": true,
+	"TR": true, "TT": true, "TV": true, "TW": true, "TZ": true, "UA": true, "UG": true, "UM": true, "US": true, "UY": true, "UZ": true, "VA": true, "VC": true, "VE": true, "VG": true, "VI": true,
+	"VN": true, "VU": true, "WF": true, "WS": true, "YE": true, "YT": true, "ZA": true, "ZM": true, "ZW": true,
+}
+
+// countryAliases maps codes in common use that are not ISO 3166-1 alpha-2 codes to the ISO code of their country
+var countryAliases = map[string]string{
+	"UK": "GB", "USA": "US", "UAE": "AE",
+}
+
+// countryNames maps the English names of countries in lower case, including common short and former names, to
+// their ISO code
+var countryNames = map[string]string{
+	"andorra": "AD", "united arab emirates": "AE", "afghanistan": "AF", "antigua and barbuda": "AG", "anguilla": "AI",
+	"albania": "AL", "armenia": "AM", "angola": "AO", "antarctica": "AQ", "argentina": "AR", "american samoa": "AS",
+	"austria": "AT", "australia": "AU", "aruba": "AW", "aland islands": "AX", "åland islands": "AX", "azerbaijan": "AZ",
+	"bosnia and herzegovina": "BA", "barbados": "BB", "bangladesh": "BD", "belgium": "BE", "burkina faso": "BF",
+	"bulgaria": "BG", "bahrain": "BH", "burundi": "BI", "benin": "BJ", "saint barthelemy": "BL",
+	"saint barthélemy": "BL", "bermuda": "BM", "brunei": "BN", "bolivia": "BO", "caribbean netherlands": "BQ",
+	"bonaire, sint eustatius and saba": "BQ", "brazil": "BR", "bahamas": "BS", "the bahamas": "BS", "bhutan": "BT",
+	"bouvet island": "BV", "botswana": "BW", "belarus": "BY", "belize": "BZ", "canada": "CA", "cocos islands": "CC",
+	"cocos (keeling) islands": "CC", "democratic republic of the congo": "CD", "dr congo": "CD", "congo-kinshasa": "CD",
+	"central african republic": "CF", "republic of the congo": "CG", "congo": "CG", "congo-brazzaville": "CG",
+	"switzerland": "CH", "ivory coast": "CI", "cote d'ivoire": "CI", "côte d'ivoire": "CI", "cook islands": "CK",
+	"chile": "CL", "cameroon": "CM", "china": "CN", "colombia": "CO", "costa rica": "CR", "cuba": "CU",
+	"cape verde": "CV", "cabo verde": "CV", "curacao": "CW", "curaçao": "CW", "christmas island": "CX", "cyprus": "CY",
+	"czechia": "CZ", "czech republic": "CZ", "germany": "DE", "djibouti": "DJ", "denmark": "DK", "dominica": "DM",
+	"dominican republic": "DO", "algeria": "DZ", "ecuador": "EC", "estonia": "EE", "egypt": "EG",
+	"western sahara": "EH", "eritrea": "ER", "spain": "ES", "ethiopia": "ET", "finland": "FI", "fiji": "FJ",
+	"falkland islands": "FK", "micronesia": "FM", "faroe islands": "FO", "france": "FR", "gabon": "GA",
+	"united kingdom": "GB", "great britain": "GB", "britain": "GB", "england": "GB", "scotland": "GB", "wales": "GB",
+	"northern ireland": "GB", "grenada": "GD", "georgia": "GE", "french guiana": "GF", "guernsey": "GG", "ghana": "GH",
+	"gibraltar": "GI", "greenland": "GL", "gambia": "GM", "the gambia": "GM", "guinea": "GN", "guadeloupe": "GP",
+	"equatorial guinea": "GQ", "greece": "GR", "south georgia and the south sandwich islands": "GS", "guatemala": "GT",
+	"guam": "GU", "guinea-bissau": "GW", "guyana": "GY", "hong kong": "HK", "heard island and mcdonald islands": "HM",
+	"honduras": "HN", "croatia": "HR", "haiti": "HT", "hungary": "HU", "indonesia": "ID", "ireland": "IE",
+	"israel": "IL", "isle of man": "IM", "india": "IN", "british indian ocean territory": "IO", "iraq": "IQ",
+	"iran": "IR", "iceland": "IS", "italy": "IT", "jersey": "JE", "jamaica": "JM", "jordan": "JO", "japan": "JP",
+	"kenya": "KE", "kyrgyzstan": "KG", "cambodia": "KH", "kiribati": "KI", "comoros": "KM",
+	"saint kitts and nevis": "KN", "north korea": "KP", "south korea": "KR", "korea": "KR", "kuwait": "KW",
+	"cayman islands": "KY", "kazakhstan": "KZ", "laos": "LA", "lebanon": "LB", "saint lucia": "LC",
+	"liechtenstein": "LI", "sri lanka": "LK", "liberia": "LR", "lesotho": "LS", "lithuania": "LT", "luxembourg": "LU",
+	"latvia": "LV", "libya": "LY", "morocco": "MA", "monaco": "MC", "moldova": "MD", "montenegro": "ME",
+	"saint martin": "MF", "madagascar": "MG", "marshall islands": "MH", "north macedonia": "MK", "macedonia": "MK",
+	"mali": "ML", "myanmar": "MM", "burma": "MM", "mongolia": "MN", "macao": "MO", "macau": "MO",
+	"northern mariana islands": "MP", "martinique": "MQ", "mauritania": "MR", "montserrat": "MS", "malta": "MT",
+	"mauritius": "MU", "maldives": "MV", "malawi": "MW", "mexico": "MX", "malaysia": "MY", "mozambique": "MZ",
+	"namibia": "NA", "new caledonia": "NC", "niger": "NE", "norfolk island": "NF", "nigeria": "NG", "nicaragua": "NI",
+	"netherlands": "NL", "the netherlands": "NL", "holland": "NL", "norway": "NO", "nepal": "NP", "nauru": "NR",
+	"niue": "NU", "new zealand": "NZ", "oman": "OM", "panama": "PA", "peru": "PE", "french polynesia": "PF",
+	"papua new guinea": "PG", "philippines": "PH", "the philippines": "PH", "pakistan": "PK", "poland": "PL",
+	"saint pierre and miquelon": "PM", "pitcairn islands": "PN", "pitcairn": "PN", "puerto rico": "PR",
+	"palestine": "PS", "portugal": "PT", "palau": "PW", "paraguay": "PY", "qatar": "QA", "reunion": "RE",
+	"réunion": "RE", "romania": "RO", "serbia": "RS", "russia": "RU", "russian federation": "RU", "rwanda": "RW",
+	"saudi arabia": "SA", "solomon islands": "SB", "seychelles": "SC", "sudan": "SD", "sweden": "SE", "singapore": "SG",
+	"saint helena": "SH", "slovenia": "SI", "svalbard and jan mayen": "SJ", "slovakia": "SK", "sierra leone": "SL",
+	"san marino": "SM", "senegal": "SN", "somalia": "SO", "suriname": "SR", "south sudan": "SS",
+	"sao tome and principe": "ST", "são tomé and príncipe": "ST", "el salvador": "SV", "sint maarten": "SX",
+	"syria": "SY", "eswatini": "SZ", "swaziland": "SZ", "turks and caicos islands": "TC", "chad": "TD",
+	"french southern territories": "TF", "togo": "TG", "thailand": "TH", "tajikistan": "TJ", "tokelau": "TK",
+	"timor-leste": "TL", "east timor": "TL", "turkmenistan": "TM", "tunisia": "TN", "tonga": "TO", "turkey": "TR",
+	"türkiye": "TR", "trinidad and tobago": "TT", "tuvalu": "TV", "taiwan": "TW", "tanzania": "TZ", "ukraine": "UA",
+	"uganda": "UG", "united states minor outlying islands": "UM", "united states": "US",
+	"united states of america": "US", "america": "US", "uruguay": "UY", "uzbekistan": "UZ", "vatican city": "VA",
+	"holy see": "VA", "saint vincent and the grenadines": "VC", "venezuela": "VE", "british virgin islands": "VG",
+	"us virgin islands": "VI", "u.s. virgin islands": "VI", "vietnam": "VN", "viet nam": "VN", "vanuatu": "VU",
+	"wallis and futuna": "WF", "samoa": "WS", "yemen": "YE", "mayotte": "YT", "south africa": "ZA", "zambia": "ZM",
+	"zimbabwe": "ZW",
+}
+
+// NormalizeCountry turns a country code in any case, one of its common aliases or the English name of its country
+// into its ISO 3166-1 alpha-2 code
+func NormalizeCountry(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	if iso, ok := countryNames[strings.ToLower(code)]; ok {
+		return iso, true
+	}
+	code = strings.ToUpper(code)
+	if alias, ok := countryAliases[code]; ok {
+		code = alias
+	}
+	return code, IsISOCountry(code)
+}
+
+// IsISOCountry reports whether code is an ISO 3166-1 alpha-2 country code, in upper case
+func IsISOCountry(code string) bool {
+	return isoCountries[code]
+}
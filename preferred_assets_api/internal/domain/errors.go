@@ -0,0 +1,51 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies domain errors, so adapters can translate them without knowing every sentinel
+type ErrorKind string
+
+const (
+	// KindNotFound means the addressed resource does not exist
+	KindNotFound ErrorKind = "not_found"
+	// KindConflict means the request clashes with the current state, e.g. a name already in use
+	KindConflict ErrorKind = "conflict"
+	// KindValidation means the request itself is invalid
+	KindValidation ErrorKind = "validation"
+	// KindUnprocessable means the request is valid but the resource's state does not allow it
+	KindUnprocessable ErrorKind = "unprocessable"
+	// KindPreconditionFailed means the resource changed since the caller read it
+	KindPreconditionFailed ErrorKind = "precondition_failed"
+	// KindUnauthorized means the caller's credentials were rejected
+	KindUnauthorized ErrorKind = "unauthorized"
+	// KindForbidden means the caller may not perform the request
+	KindForbidden ErrorKind = "forbidden"
+)
+
+// ErrVersionConflict is returned when a resource was changed since the caller read its version
+var ErrVersionConflict = NewError(KindPreconditionFailed, "version conflict")
+
+// Error is a domain error of a given kind. Sentinels are *Error values, so errors.Is keeps working
+// when they are wrapped with more detail.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+}
+
+// NewError returns a domain error of the given kind
+func NewError(kind ErrorKind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// KindOf returns the kind of the first domain error in err's chain, or false if there is none
+func KindOf(err error) (ErrorKind, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind, true
+	}
+	return "", false
+}
@@ -2,20 +2,117 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+const (
+	MaxFavouriteNoteLength  = 2000
+	MaxFavouriteTitleLength = 200
+	MaxFavouriteTags        = 20
+	MaxFavouriteTagLength   = 50
+)
+
+var (
+	ErrInvalidCursor     = NewError(KindValidation, "invalid cursor")
+	ErrFavouriteNotFound = NewError(KindNotFound, "favourite not found")
+	ErrInvalidFavourite  = NewError(KindValidation, "invalid favourite")
+	ErrFavouriteExists   = NewError(KindConflict, "asset already favourited")
+	// ErrUnknownUser and ErrUnknownAsset reject a favourite whose user or asset does not exist; ErrUnknownUser also
+	// rejects a collection of a user that does not exist
+	ErrUnknownUser  = NewError(KindUnprocessable, "user does not exist")
+	ErrUnknownAsset = NewError(KindUnprocessable, "asset does not exist")
+)
+
 type Favourite struct {
 	UserID    string
 	AssetID   string
 	CreatedAt time.Time
 	AssetType AssetType
 
+	// Note, CustomTitle and Tags are private to the user and never change the asset itself
+	Note        string
+	CustomTitle string
+	Tags        []string
+
 	Audience *Audience
 	Chart    *Chart
 	Insight  *Insight
 }
 
+// FavouriteTombstone tells a user that an asset they had favourited was deleted, and when
+type FavouriteTombstone struct {
+	UserID       string
+	AssetID      string
+	AssetType    AssetType
+	AssetTitle   string
+	FavouritedAt time.Time
+	RemovedAt    time.Time
+}
+
+// FavouritePage is one page of a user's favourites.
+// NextCursor is an opaque token for the following page and is empty on the last page.
+type FavouritePage struct {
+	Favourites []Favourite
+	NextCursor string
+}
+
+// FavouritePatch changes the user's overrides of a favourite. Nil fields are left unchanged;
+// an empty string clears Note or CustomTitle and an empty, non-nil slice clears Tags.
+type FavouritePatch struct {
+	Note        *string
+	CustomTitle *string
+	Tags        []string
+}
+
+// Apply copies the set fields of the patch onto the favourite, normalising the tags
+func (p FavouritePatch) Apply(f *Favourite) {
+	if p.Note != nil {
+		f.Note = strings.TrimSpace(*p.Note)
+	}
+	if p.CustomTitle != nil {
+		f.CustomTitle = strings.TrimSpace(*p.CustomTitle)
+	}
+	if p.Tags != nil {
+		f.Tags = NormaliseTags(p.Tags)
+	}
+}
+
+// Validate performs domain validation of the user's overrides of a favourite.
+func (f *Favourite) Validate() error {
+	if len([]rune(f.Note)) > MaxFavouriteNoteLength {
+		return fmt.Errorf("%w: note must be at most %d characters", ErrInvalidFavourite, MaxFavouriteNoteLength)
+	}
+	if len([]rune(f.CustomTitle)) > MaxFavouriteTitleLength {
+		return fmt.Errorf("%w: custom title must be at most %d characters", ErrInvalidFavourite, MaxFavouriteTitleLength)
+	}
+	if len(f.Tags) > MaxFavouriteTags {
+		return fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidFavourite, MaxFavouriteTags)
+	}
+	for _, tag := range f.Tags {
+		if len([]rune(tag)) > MaxFavouriteTagLength {
+			return fmt.Errorf("%w: tag %q must be at most %d characters", ErrInvalidFavourite, tag, MaxFavouriteTagLength)
+		}
+	}
+	return nil
+}
+
+// NormaliseTags trims and lowercases the tags, dropping blanks and duplicates while keeping their order
+func NormaliseTags(tags []string) []string {
+	normalised := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalised = append(normalised, tag)
+	}
+	return normalised
+}
+
 func (f *Favourite) GetAsset() Asset {
 	switch f.AssetType {
 	case AssetTypeAudience:
@@ -0,0 +1,44 @@
+package domain
+
+// MaxFavouriteBatchSize caps the operations of one favourites batch
+const MaxFavouriteBatchSize = 500
+
+// FavouriteAction is what a batch operation does with a favourite
+type FavouriteAction string
+
+const (
+	FavouriteActionAdd    FavouriteAction = "add"
+	FavouriteActionRemove FavouriteAction = "remove"
+)
+
+// FavouriteOperation adds or removes one favourite of a batch
+type FavouriteOperation struct {
+	Action  FavouriteAction
+	AssetID string
+}
+
+// FavouriteOutcome is what a batch operation did
+type FavouriteOutcome string
+
+const (
+	FavouriteAdded          FavouriteOutcome = "added"
+	FavouriteAlreadyPresent FavouriteOutcome = "already_present"
+	FavouriteRemoved        FavouriteOutcome = "removed"
+	FavouriteNotPresent     FavouriteOutcome = "not_present"
+	FavouriteAssetMissing   FavouriteOutcome = "asset_missing"
+	// FavouriteSkipped marks the operations of an all-or-nothing batch that was not applied
+	FavouriteSkipped FavouriteOutcome = "skipped"
+)
+
+// FavouriteOperationResult reports the outcome of one operation of a batch
+type FavouriteOperationResult struct {
+	FavouriteOperation
+	Outcome FavouriteOutcome
+}
+
+// FavouriteBatchResult lists the outcome of every operation of a batch, in request order.
+// Applied is false when an all-or-nothing batch was rejected and nothing was changed.
+type FavouriteBatchResult struct {
+	Applied bool
+	Results []FavouriteOperationResult
+}
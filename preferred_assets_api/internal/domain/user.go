@@ -2,11 +2,18 @@ package domain
 
 import "time"
 
+var (
+	ErrUserNotFound     = NewError(KindNotFound, "user not found")
+	ErrUserSubjectTaken = NewError(KindConflict, "subject already linked to another user")
+)
+
 type User struct {
 	Id        string
 	Name      string
 	Email     string
 	Password  string // hashed
+	Subject   string // identity provider subject (sub) or username the user signs in with
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64
 }
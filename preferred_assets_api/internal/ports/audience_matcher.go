@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+// AudienceMatcher finds the audiences whose definitions respondent profiles meet
+type AudienceMatcher interface {
+	// Index adds the definition of an audience, replacing any earlier version of it.
+	// Other assets, and audiences without a definition, are dropped from the matcher.
+	Index(asset domain.Asset)
+	// Remove drops the audience from the matcher
+	Remove(id string)
+	// Match returns the ids of the audiences the profile belongs to, in ascending order
+	Match(profile domain.RespondentProfile) []string
+}
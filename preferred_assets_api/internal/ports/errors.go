@@ -0,0 +1,27 @@
+package ports
+
+import "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+// The errors every repository adapter must return, whatever its storage. They are the domain errors,
+// so services can pass them on as is and the HTTP layer maps them by kind.
+
+// ErrUserNotFound is returned by user repositories when no user matches
+var ErrUserNotFound = domain.ErrUserNotFound
+
+// ErrAssetNotFound is returned by asset repositories for an unknown asset id
+var ErrAssetNotFound = domain.ErrAssetNotFound
+
+// ErrAssetExists is returned by asset repositories when saving an asset whose id is taken
+var ErrAssetExists = domain.ErrAssetExists
+
+// ErrCollectionNotFound is returned by collection repositories for an unknown collection id
+var ErrCollectionNotFound = domain.ErrCollectionNotFound
+
+// ErrFavouriteNotFound is returned by favourite repositories when the user has not favourited the asset
+var ErrFavouriteNotFound = domain.ErrFavouriteNotFound
+
+// ErrFavouriteExists is returned by favourite repositories when the user has already favourited the asset
+var ErrFavouriteExists = domain.ErrFavouriteExists
+
+// ErrAPIKeyNotFound is returned by API key repositories for an unknown key id or hash
+var ErrAPIKeyNotFound = domain.ErrAPIKeyNotFound
@@ -22,20 +22,116 @@ type UserHandler interface {
 
 	// Get handles HTTP GET /users/{id}/favourites requests
 	GetFavourites(w http.ResponseWriter, r *http.Request)
+
+	// ExportFavourites handles HTTP GET /users/{id}/favourites/export requests
+	ExportFavourites(w http.ResponseWriter, r *http.Request)
 }
 
 type FavouriteHandler interface {
 	// Create handles HTTP POST /favourites requests
 	Create(w http.ResponseWriter, r *http.Request)
 
+	// Batch handles HTTP POST /favourites:batch requests
+	Batch(w http.ResponseWriter, r *http.Request)
+
 	// Delete handles HTTP DELETE /favourites/{id} requests
 	Delete(w http.ResponseWriter, r *http.Request)
+
+	// Update handles HTTP PATCH /favourites/{userId}/assets/{assetId} requests
+	Update(w http.ResponseWriter, r *http.Request)
+
+	// ListRemoved handles HTTP GET /users/{id}/favourites/removed requests
+	ListRemoved(w http.ResponseWriter, r *http.Request)
+
+	// DismissRemoved handles HTTP DELETE /users/{id}/favourites/removed/{assetId} requests
+	DismissRemoved(w http.ResponseWriter, r *http.Request)
+}
+
+type MeHandler interface {
+	// ListFavourites handles HTTP GET /me/favourites requests
+	ListFavourites(w http.ResponseWriter, r *http.Request)
+
+	// AddFavourite handles HTTP POST /me/favourites requests
+	AddFavourite(w http.ResponseWriter, r *http.Request)
+
+	// RemoveFavourite handles HTTP DELETE /me/favourites/{assetId} requests
+	RemoveFavourite(w http.ResponseWriter, r *http.Request)
+
+	// UpdateFavourite handles HTTP PATCH /me/favourites/{assetId} requests
+	UpdateFavourite(w http.ResponseWriter, r *http.Request)
+
+	// Permissions handles HTTP GET /me/permissions requests
+	Permissions(w http.ResponseWriter, r *http.Request)
 }
 
 type AssetHandler interface {
 	// Create handles HTTP POST /assets requests
 	Create(w http.ResponseWriter, r *http.Request)
 
+	// Import handles HTTP POST /assets:import requests
+	Import(w http.ResponseWriter, r *http.Request)
+
+	// List handles HTTP GET /assets requests
+	List(w http.ResponseWriter, r *http.Request)
+
+	// Search handles HTTP GET /assets/search requests
+	Search(w http.ResponseWriter, r *http.Request)
+
+	// Get handles HTTP GET /assets/{id} requests
+	Get(w http.ResponseWriter, r *http.Request)
+
+	// Render handles HTTP GET /assets/{id}/render requests
+	Render(w http.ResponseWriter, r *http.Request)
+
+	// Update handles HTTP PUT /assets/{id} requests
+	Update(w http.ResponseWriter, r *http.Request)
+
+	// Patch handles HTTP PATCH /assets/{id} requests with a JSON merge patch body
+	Patch(w http.ResponseWriter, r *http.Request)
+
 	// Delete handles HTTP DELETE /assets/{id} requests
 	Delete(w http.ResponseWriter, r *http.Request)
+
+	// MatchAudiences handles HTTP POST /audiences:match requests
+	MatchAudiences(w http.ResponseWriter, r *http.Request)
+}
+
+type CollectionHandler interface {
+	// Create handles HTTP POST /users/{id}/collections requests
+	Create(w http.ResponseWriter, r *http.Request)
+
+	// List handles HTTP GET /users/{id}/collections requests
+	List(w http.ResponseWriter, r *http.Request)
+
+	// Get handles HTTP GET /users/{id}/collections/{cid} requests
+	Get(w http.ResponseWriter, r *http.Request)
+
+	// Rename handles HTTP PATCH /users/{id}/collections/{cid} requests
+	Rename(w http.ResponseWriter, r *http.Request)
+
+	// Delete handles HTTP DELETE /users/{id}/collections/{cid} requests
+	Delete(w http.ResponseWriter, r *http.Request)
+
+	// AddItem handles HTTP PUT /users/{id}/collections/{cid}/items/{assetId} requests
+	AddItem(w http.ResponseWriter, r *http.Request)
+
+	// RemoveItem handles HTTP DELETE /users/{id}/collections/{cid}/items/{assetId} requests
+	RemoveItem(w http.ResponseWriter, r *http.Request)
+
+	// Reorder handles HTTP PUT /users/{id}/collections/{cid}/order requests
+	Reorder(w http.ResponseWriter, r *http.Request)
+
+	// AddToCollections handles HTTP POST /users/{id}/favourites/{assetId}/collections requests
+	AddToCollections(w http.ResponseWriter, r *http.Request)
+}
+
+type APIKeyHandler interface {
+	// Create handles HTTP POST /api-keys requests
+	Create(w http.ResponseWriter, r *http.Request)
+
+	// List handles HTTP GET /api-keys requests
+	List(w http.ResponseWriter, r *http.Request)
+
+	// Revoke handles HTTP DELETE /api-keys/{id} requests
+	Revoke(w http.ResponseWriter, r *http.Request)
 }
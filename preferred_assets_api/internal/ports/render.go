@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"io"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+)
+
+// ChartRenderer draws charts as images
+type ChartRenderer interface {
+	// Render returns the image of the chart described by the options. The image may be shared between callers
+	// and must not be modified.
+	Render(chart *domain.Chart, options domain.ChartRenderOptions) ([]byte, error)
+}
+
+// FavouritesReportWriter starts printable reports of a user's favourites
+type FavouritesReportWriter interface {
+	// NewReport writes the start of the report of the user's favourites to w
+	NewReport(w io.Writer, userID string, generatedAt time.Time) (FavouritesReport, error)
+}
+
+// FavouritesReport is a report being written one favourite at a time
+type FavouritesReport interface {
+	Add(f domain.Favourite) error
+	// Close ends the report; it is not written in full until then
+	Close() error
+}
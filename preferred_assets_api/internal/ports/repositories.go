@@ -1,26 +1,48 @@
 package ports
 
 import (
+	"time"
+
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/adapters/repositories/entities"
 )
 
 type UserRepository interface {
 	GetByID(id string) (entities.UserEntity, error)
+	// GetBySubject returns the user linked to the identity provider subject, or ErrUserNotFound
+	GetBySubject(subject string) (entities.UserEntity, error)
 	Save(user entities.UserEntity) error
 	GetAll() ([]entities.UserEntity, error)
+	// Delete removes the user together with their favourites, tombstones and collections
 	Delete(id string) error
-	Update(user entities.UserEntity) error
+	// Update stores the user and returns the version it was stored at
+	Update(user entities.UserEntity) (int64, error)
+	// CompareAndSwap updates the user only if its stored version equals expectedVersion, otherwise it returns ErrVersionConflict.
+	// It returns the version the user was stored at.
+	CompareAndSwap(user entities.UserEntity, expectedVersion int64) (int64, error)
+	// CompareAndDelete deletes the user, as Delete does, only if its stored version equals expectedVersion, otherwise it
+	// returns ErrVersionConflict
+	CompareAndDelete(id string, expectedVersion int64) error
 	GetFavouritesByID(id string) ([]entities.FavouriteEntity, error)
+	GetFavouritesPageByID(id string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error)
 }
 
 type AssetRepository interface {
+	// Save stores a new asset at version 1, returning ErrAssetExists if its id is taken
 	Save(asset entities.AssetEntity) (entities.AssetEntity, error)
 	GetByID(id string) (entities.AssetEntity, error)
 	GetByIDs(ids []string) ([]entities.AssetEntity, error)
 	GetAll() ([]entities.AssetEntity, error)
 	GetByType(assetType entities.AssetType) ([]entities.AssetEntity, error)
-	Update(asset entities.AssetEntity) error
+	// Query returns one page of the assets matching the query, in the query ordering
+	Query(query entities.AssetQuery) (entities.AssetPage, error)
+	// Update stores the asset and returns the version it was stored at
+	Update(asset entities.AssetEntity) (int64, error)
 	Delete(id string) error
+	// CompareAndSwap updates the asset only if its stored version equals expectedVersion, otherwise it returns ErrVersionConflict.
+	// It returns the version the asset was stored at.
+	CompareAndSwap(asset entities.AssetEntity, expectedVersion int64) (int64, error)
+	// CompareAndDelete deletes the asset only if its stored version equals expectedVersion, otherwise it returns ErrVersionConflict
+	CompareAndDelete(id string, expectedVersion int64) error
 	Exists(id string) (bool, error)
 }
 
@@ -28,5 +50,53 @@ type FavouriteRepository interface {
 	Add(f entities.FavouriteEntity) error
 	Delete(userID, assetID string) error
 	GetByUserID(userID string) ([]entities.FavouriteEntity, error)
+	GetPageByUserID(userID string, after *entities.FavouriteCursor, limit int) (entities.FavouritePage, error)
 	Exists(userID, assetID string) (bool, error)
+	// Get returns ErrFavouriteNotFound if the user has not favourited the asset
+	Get(userID, assetID string) (entities.FavouriteEntity, error)
+	// Update replaces the user's overrides of an existing favourite, returning ErrFavouriteNotFound if there is none
+	Update(f entities.FavouriteEntity) error
+	// ApplyChanges applies the changes in order, all under a single lock or transaction. It reports for each change
+	// whether it took effect: false for adding a favourite the user already has or removing one they do not.
+	ApplyChanges(changes []entities.FavouriteChange) ([]bool, error)
+	// DeleteByAssetID removes every user's favourite of the asset and returns the removed favourites.
+	// Unless tombstone is nil, a copy of it is recorded for each of those users.
+	DeleteByAssetID(assetID string, tombstone *entities.FavouriteTombstoneEntity) ([]entities.FavouriteEntity, error)
+	// DeleteByUserID removes every favourite and tombstone of the user
+	DeleteByUserID(userID string) error
+	// GetTombstonesByUserID returns the user's tombstones, most recently removed first
+	GetTombstonesByUserID(userID string) ([]entities.FavouriteTombstoneEntity, error)
+	// DeleteTombstone dismisses a tombstone; dismissing one that does not exist is not an error
+	DeleteTombstone(userID, assetID string) error
+}
+
+type CollectionRepository interface {
+	// Save stores a new collection at version 1
+	Save(collection entities.CollectionEntity) error
+	// GetByID returns ErrCollectionNotFound for an unknown id
+	GetByID(id string) (entities.CollectionEntity, error)
+	// GetByUserID returns the user's collections, oldest first
+	GetByUserID(userID string) ([]entities.CollectionEntity, error)
+	// Update replaces the name, items and update time of the collection, or returns ErrCollectionNotFound.
+	// It returns the version the collection was stored at.
+	Update(collection entities.CollectionEntity) (int64, error)
+	// CompareAndSwap updates the collection only if its stored version equals expectedVersion, otherwise it returns
+	// ErrVersionConflict. It returns the version the collection was stored at.
+	CompareAndSwap(collection entities.CollectionEntity, expectedVersion int64) (int64, error)
+	// Delete removes the collection, or returns ErrCollectionNotFound
+	Delete(id string) error
+	// RemoveAsset takes the asset out of every collection of the user, bumping the version of those it was in
+	RemoveAsset(userID, assetID string) error
+	// DeleteByUserID removes every collection of the user
+	DeleteByUserID(userID string) error
+}
+
+type APIKeyRepository interface {
+	Save(key entities.APIKeyEntity) error
+	// GetByHash returns the key whose secret hashes to hash, or ErrAPIKeyNotFound
+	GetByHash(hash string) (entities.APIKeyEntity, error)
+	// GetAll returns every key, revoked ones included, oldest first
+	GetAll() ([]entities.APIKeyEntity, error)
+	// Revoke marks the key revoked at the given time, or returns ErrAPIKeyNotFound
+	Revoke(id string, at time.Time) error
 }
@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+// AssetSearchIndex is a full-text index over the searchable text of assets
+type AssetSearchIndex interface {
+	// Index adds the asset to the index, replacing any earlier version of it
+	Index(asset domain.Asset)
+	// Remove drops the asset from the index
+	Remove(id string)
+	// Search returns up to limit hits for the query, most relevant first.
+	// A nil within searches every asset, otherwise only the assets whose ids it contains.
+	Search(query string, limit int, within map[string]bool) []domain.AssetSearchHit
+}
@@ -6,17 +6,68 @@ type UserService interface {
 	CreateUser(user domain.User) error
 	GetUserByID(id string) (*domain.User, error)
 	GetAllUsers() ([]domain.User, error)
-	UpdateUser(user domain.User) error
-	DeleteUser(id string) error
+	// UpdateUser stores the user and returns the version it was stored at
+	UpdateUser(user domain.User, expectedVersion int64) (int64, error)
+	DeleteUser(id string, expectedVersion int64) error
 	GetFavouritesByUser(id string) ([]domain.Favourite, error)
+	GetFavouritesPageByUser(id string, limit int, cursor string) (domain.FavouritePage, error)
+	// ResolveUserID returns the id of the user linked to the identity provider subject.
+	// It returns domain.ErrUserNotFound if the subject is not linked.
+	ResolveUserID(subject string) (string, error)
+	// ProvisionUser returns the id of the user linked to the subject, creating a user linked to the subject,
+	// and named after the username, the first time it is seen
+	ProvisionUser(subject string, username string, email string) (string, error)
 }
 
 type AssetService interface {
 	CreateAsset(asset domain.Asset) (domain.Asset, error)
-	DeleteAsset(id string) error
+	GetAsset(id string) (domain.Asset, error)
+	ListAssets(query domain.AssetQuery) (domain.AssetPage, error)
+	// SearchAssets runs a full-text search, restricted to the favourites of favouritesOf when it is not empty
+	SearchAssets(query string, favouritesOf string, limit int) ([]domain.AssetSearchResult, error)
+	UpdateAsset(asset domain.Asset, expectedVersion int64) (domain.Asset, error)
+	DeleteAsset(id string, expectedVersion int64) error
+	// ImportAsset validates and stores one asset of a bulk import; mode decides what happens when its id is taken
+	ImportAsset(asset domain.Asset, mode domain.AssetImportMode) (domain.AssetImportOutcome, error)
+	// RenderChart draws the chart asset as an image, failing with domain.ErrNotAChart for other assets
+	RenderChart(id string, options domain.ChartRenderOptions) ([]byte, error)
+	// MatchAudiences returns the ids of the audiences each profile belongs to, in the order of the profiles
+	MatchAudiences(profiles []domain.RespondentProfile) ([][]string, error)
 }
 
 type FavouriteService interface {
 	CreateFavourite(favourite domain.Favourite) error
 	DeleteFavourite(userId string, assetId string) error
+	// ApplyFavouriteBatch adds and removes many of the user's favourites at once and reports each operation.
+	// With allOrNothing a batch adding a missing asset changes nothing.
+	ApplyFavouriteBatch(userId string, operations []domain.FavouriteOperation, allOrNothing bool) (domain.FavouriteBatchResult, error)
+	// UpdateFavourite changes the user's private overrides of a favourite and returns it with its asset
+	UpdateFavourite(userId string, assetId string, patch domain.FavouritePatch) (domain.Favourite, error)
+	// GetRemovedFavourites returns the tombstones of the user's favourites whose assets were deleted
+	GetRemovedFavourites(userId string) ([]domain.FavouriteTombstone, error)
+	DismissRemovedFavourite(userId string, assetId string) error
+}
+
+type CollectionService interface {
+	// CreateCollection returns domain.ErrUnknownUser if the user does not exist
+	CreateCollection(userID string, name string) (domain.Collection, error)
+	GetCollections(userID string) ([]domain.Collection, error)
+	// GetCollection returns the collection with its favourites and their assets, in display order
+	GetCollection(userID string, collectionID string) (domain.Collection, error)
+	RenameCollection(userID string, collectionID string, name string) (domain.Collection, error)
+	DeleteCollection(userID string, collectionID string) error
+	// AddFavourite files one of the user's favourites into each of the collections
+	AddFavourite(userID string, assetID string, collectionIDs []string) error
+	RemoveFavourite(userID string, collectionID string, assetID string) error
+	ReorderCollection(userID string, collectionID string, assetIDs []string) (domain.Collection, error)
+}
+
+type APIKeyService interface {
+	// CreateAPIKey issues a key acting with the roles, restricted to the users when userIDs is not empty.
+	// The returned key in clear is not stored and cannot be retrieved again.
+	CreateAPIKey(name string, roles []string, userIDs []string) (domain.APIKey, string, error)
+	ListAPIKeys() ([]domain.APIKey, error)
+	RevokeAPIKey(id string) error
+	// Authenticate returns the key matching the clear key, or domain.ErrInvalidAPIKey if it is unknown or revoked
+	Authenticate(plain string) (domain.APIKey, error)
 }
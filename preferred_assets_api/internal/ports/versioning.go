@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/internal/domain"
+
+// AnyVersion passed as the expected version makes a write unconditional
+const AnyVersion int64 = 0
+
+// ErrVersionConflict is returned by the compare-and-swap repository methods when the stored version
+// is no longer the version the caller read. It is the domain error, so services can pass it on as is.
+var ErrVersionConflict = domain.ErrVersionConflict
@@ -2,20 +2,38 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/cmd/api/config"
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/golang-jwt/jwt"
 )
 
+var _ TokenVerifier = (*KeycloakClient)(nil)
+
+// discoveryBackoff is how long a failed OIDC discovery is reported to callers before it is attempted again
+const discoveryBackoff = 5 * time.Second
+
+// KeycloakClient verifies tokens against the keys the realm publishes through OIDC discovery.
+// If the realm cannot be reached at startup, discovery is retried on a later verification, at most once
+// per discoveryBackoff.
 type KeycloakClient struct {
-	config   *config.KeycloakConfig
-	client   *http.Client
-	verifier *oidc.IDTokenVerifier
+	config *config.KeycloakConfig
+	client *http.Client
+
+	// mu guards the fields below; it is never held during discovery
+	mu        sync.Mutex
+	verifier  *oidc.IDTokenVerifier
+	discovery chan struct{} // closed when the discovery in progress ends, nil when none is
+	failure   error
+	failedAt  time.Time
 }
 
 type CustomClaims struct {
@@ -28,29 +46,92 @@ type CustomClaims struct {
 	Email         string `json:"email"`
 	EmailVerified bool   `json:"email_verified"`
 	PreferredName string `json:"preferred_username"`
+	// Audience shadows the single-valued aud of jwt.StandardClaims, since Keycloak sends a list when a token has several audiences
+	Audience        Audience `json:"aud,omitempty"`
+	AuthorizedParty string   `json:"azp,omitempty"`
 	jwt.StandardClaims
 }
 
+// Audience is the aud claim of a token, which is either a single string or a list of them
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or a list of strings: %w", err)
+	}
+	*a = list
+	return nil
+}
+
+// IssuedTo reports whether the token was issued to the client, naming it as its audience or authorized party
+func (c *CustomClaims) IssuedTo(clientID string) bool {
+	return clientID != "" && (c.AuthorizedParty == clientID || slices.Contains(c.Audience, clientID))
+}
+
 func NewKeycloakClient(cfg *config.KeycloakConfig) (*KeycloakClient, error) {
-	ctx := context.Background()
+	kc := &KeycloakClient{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+	if _, err := kc.getVerifier(); err != nil {
+		log.Printf("Keycloak discovery failed, retrying on a later request: %v", err)
+	}
+	return kc, nil
+}
 
-	URL := getKeycloakURL(cfg)
-	log.Printf("%s", URL+"/realms/"+cfg.Realm)
-	provider, err := oidc.NewProvider(ctx, URL+"/realms/"+cfg.Realm)
+// getVerifier returns the realm's token verifier, running OIDC discovery if it has not succeeded yet.
+// Concurrent callers share one discovery, and a failed one is not retried until discoveryBackoff has passed.
+func (kc *KeycloakClient) getVerifier() (*oidc.IDTokenVerifier, error) {
+	kc.mu.Lock()
+	if kc.verifier != nil {
+		defer kc.mu.Unlock()
+		return kc.verifier, nil
+	}
+	if done := kc.discovery; done != nil {
+		kc.mu.Unlock()
+		<-done
+		kc.mu.Lock()
+		defer kc.mu.Unlock()
+		return kc.verifier, kc.failure
+	}
+	if kc.failure != nil && time.Since(kc.failedAt) < discoveryBackoff {
+		defer kc.mu.Unlock()
+		return nil, kc.failure
+	}
+	done := make(chan struct{})
+	kc.discovery = done
+	kc.mu.Unlock()
+
+	verifier, err := kc.discover()
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	kc.verifier, kc.failure, kc.failedAt = verifier, err, time.Now()
+	kc.discovery = nil
+	close(done)
+	return verifier, err
+}
+
+// discover fetches the realm's OIDC configuration and builds a verifier for its keys
+func (kc *KeycloakClient) discover() (*oidc.IDTokenVerifier, error) {
+	URL := getKeycloakURL(kc.config)
+	log.Printf("%s", URL+"/realms/"+kc.config.Realm)
+	ctx := oidc.ClientContext(context.Background(), kc.client)
+	provider, err := oidc.NewProvider(ctx, URL+"/realms/"+kc.config.Realm)
 	if err != nil {
 		return nil, err
 	}
 
-	verifier := provider.Verifier(&oidc.Config{
-		ClientID:          cfg.ClientID,
+	return provider.Verifier(&oidc.Config{
+		ClientID:          kc.config.ClientID,
 		SkipClientIDCheck: true,
-	})
-
-	return &KeycloakClient{
-		config:   cfg,
-		client:   &http.Client{Timeout: cfg.Timeout},
-		verifier: verifier,
-	}, nil
+	}), nil
 }
 
 /*
@@ -123,10 +204,11 @@ func (kc *KeycloakClient) VerifyToken(tokenString string) (*CustomClaims, error)
 	if kc == nil {
 		return nil, fmt.Errorf("keycloak client is not initialized")
 	}
-	if kc.verifier == nil {
-		return nil, fmt.Errorf("verifier is not initialized - Keycloak client was not properly created")
+	verifier, err := kc.getVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("keycloak is not reachable: %v", err)
 	}
-	idToken, err := kc.verifier.Verify(ctx, tokenString)
+	idToken, err := verifier.Verify(ctx, tokenString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify token: %v", err)
 	}
@@ -140,12 +222,7 @@ func (kc *KeycloakClient) VerifyToken(tokenString string) (*CustomClaims, error)
 }
 
 func (kc *KeycloakClient) GetUserRoles(claims *CustomClaims) []string {
-	var roles []string
-	roles = append(roles, claims.RealmAccess.Roles...)
-	if clientRoles, exists := claims.ResourceAccess[kc.config.ClientID]; exists {
-		roles = append(roles, clientRoles.Roles...)
-	}
-	return roles
+	return rolesFor(claims, kc.config.ClientID)
 }
 
 func getKeycloakURL(cfg *config.KeycloakConfig) string {
@@ -0,0 +1,44 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/cmd/api/config"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeycloakClient_DiscoveryBacksOff(t *testing.T) {
+	// Arrange
+	var discoveries atomic.Int32
+	realm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		discoveries.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer realm.Close()
+	client, err := auth.NewKeycloakClient(&config.KeycloakConfig{ExternalURL: realm.URL, Realm: "test", Timeout: time.Second})
+	require.NoError(t, err)
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.VerifyToken("any-token")
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	for _, err := range errs {
+		require.ErrorContains(t, err, "keycloak is not reachable")
+	}
+	require.Equal(t, int32(1), discoveries.Load(), "expected the failed startup discovery not to be retried yet")
+}
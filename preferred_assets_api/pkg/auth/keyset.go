@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt"
+)
+
+var _ TokenVerifier = (*KeySetVerifier)(nil)
+
+// KeySetVerifier verifies tokens with locally configured keys, without contacting the identity provider.
+// It accepts HMAC keys, and RSA and ECDSA public keys; each key only verifies tokens signed with its own algorithm family.
+// Tokens must expire and must be issued to the client, naming it as their audience or authorized party.
+type KeySetVerifier struct {
+	// keys holds the verification keys by key id; a single key without an id verifies tokens without a kid
+	keys     map[string]any
+	issuer   string
+	clientID string
+}
+
+// NewJWKSVerifier builds a verifier from a JSON Web Key Set, such as a saved copy of the realm's certs endpoint.
+// Tokens must name their key with kid unless the set holds a single key. An empty issuer disables the issuer check.
+func NewJWKSVerifier(jwks []byte, issuer string, clientID string) (*KeySetVerifier, error) {
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(jwks, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		if !k.IsPublic() {
+			if public := k.Public(); public.Valid() {
+				k = public
+			}
+		}
+		keys[k.KeyID] = k.Key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS holds no signing keys")
+	}
+
+	return &KeySetVerifier{keys: keys, issuer: issuer, clientID: clientID}, nil
+}
+
+// NewStaticKeyVerifier builds a verifier from a single key: a PEM encoded RSA or ECDSA public key, or otherwise an HMAC secret.
+// An empty issuer disables the issuer check.
+func NewStaticKeyVerifier(key []byte, issuer string, clientID string) (*KeySetVerifier, error) {
+	if len(key) == 0 {
+		return nil, errors.New("static key is empty")
+	}
+
+	var verificationKey any = key
+	if bytes.HasPrefix(bytes.TrimSpace(key), []byte("-----BEGIN")) {
+		if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(key); err == nil {
+			verificationKey = rsaKey
+		} else if ecKey, err := jwt.ParseECPublicKeyFromPEM(key); err == nil {
+			verificationKey = ecKey
+		} else {
+			return nil, errors.New("static key is not an RSA or ECDSA public key")
+		}
+	}
+
+	return &KeySetVerifier{keys: map[string]any{"": verificationKey}, issuer: issuer, clientID: clientID}, nil
+}
+
+func (v *KeySetVerifier) VerifyToken(tokenString string) (*CustomClaims, error) {
+	var claims CustomClaims
+	if _, err := jwt.ParseWithClaims(tokenString, &claims, v.keyFor); err != nil {
+		return nil, fmt.Errorf("failed to verify token: %v", err)
+	}
+	// StandardClaims.Valid accepts tokens without an expiry
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("failed to verify token: missing expiry")
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return nil, fmt.Errorf("failed to verify token: unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.IssuedTo(v.clientID) {
+		return nil, fmt.Errorf("failed to verify token: not issued to client %q", v.clientID)
+	}
+	return &claims, nil
+}
+
+func (v *KeySetVerifier) GetUserRoles(claims *CustomClaims) []string {
+	return rolesFor(claims, v.clientID)
+}
+
+// keyFor picks the key named by the token's kid and refuses it for an algorithm of another family,
+// so that a public key can never be used as an HMAC secret
+func (v *KeySetVerifier) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.keys[kid]
+	if !ok && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			key, ok = only, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if _, isSecret := key.([]byte); isSecret {
+			return key, nil
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, isRSA := key.(*rsa.PublicKey); isRSA {
+			return key, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if _, isEC := key.(*ecdsa.PublicKey); isEC {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("signing key %q does not accept algorithm %s", kid, token.Method.Alg())
+}
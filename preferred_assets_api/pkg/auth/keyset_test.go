@@ -0,0 +1,211 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/cmd/api/config"
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "http://localhost:8090/realms/preferred-assets-realm"
+	testClientID = "preferred-assets-api"
+)
+
+func testClaims() auth.CustomClaims {
+	claims := auth.CustomClaims{
+		PreferredName:   "user",
+		AuthorizedParty: testClientID,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   "bea950c6-ae5f-442d-babc-1ab6db7c6c7b",
+			Issuer:    testIssuer,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	claims.RealmAccess.Roles = []string{"Users"}
+	return claims
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, claims auth.CustomClaims, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func publicKeyPEM(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestStaticKeyVerifier_HMAC(t *testing.T) {
+	// Arrange
+	secret := []byte("a-test-secret-of-reasonable-length")
+	verifier, err := auth.NewStaticKeyVerifier(secret, testIssuer, testClientID)
+	require.NoError(t, err)
+
+	expired := testClaims()
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	otherIssuer := testClaims()
+	otherIssuer.Issuer = "http://evil.example.com"
+	neverExpires := testClaims()
+	neverExpires.ExpiresAt = 0
+	otherClient := testClaims()
+	otherClient.AuthorizedParty = "another-client"
+	otherClient.Audience = auth.Audience{"account"}
+	audience := testClaims()
+	audience.AuthorizedParty = "another-client"
+	audience.Audience = auth.Audience{"account", testClientID}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid token", token: sign(t, jwt.SigningMethodHS256, "", testClaims(), secret)},
+		{name: "wrong secret", token: sign(t, jwt.SigningMethodHS256, "", testClaims(), []byte("another-secret")), wantErr: true},
+		{name: "expired token", token: sign(t, jwt.SigningMethodHS256, "", expired, secret), wantErr: true},
+		{name: "other issuer", token: sign(t, jwt.SigningMethodHS256, "", otherIssuer, secret), wantErr: true},
+		{name: "token without expiry", token: sign(t, jwt.SigningMethodHS256, "", neverExpires, secret), wantErr: true},
+		{name: "token issued to another client", token: sign(t, jwt.SigningMethodHS256, "", otherClient, secret), wantErr: true},
+		{name: "client among the audience", token: sign(t, jwt.SigningMethodHS256, "", audience, secret)},
+		{name: "not a token", token: "not-a-token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			claims, err := verifier.VerifyToken(tt.token)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "bea950c6-ae5f-442d-babc-1ab6db7c6c7b", claims.Subject)
+			require.Equal(t, []string{"Users"}, verifier.GetUserRoles(claims))
+		})
+	}
+}
+
+func TestStaticKeyVerifier_RSA(t *testing.T) {
+	// Arrange
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemKey := publicKeyPEM(t, key)
+	verifier, err := auth.NewStaticKeyVerifier(pemKey, "", testClientID)
+	require.NoError(t, err)
+
+	// Act
+	claims, err := verifier.VerifyToken(sign(t, jwt.SigningMethodRS256, "any-kid", testClaims(), key))
+	// A token signed with HS256 using the public key as the secret must not verify
+	_, confusionErr := verifier.VerifyToken(sign(t, jwt.SigningMethodHS256, "", testClaims(), pemKey))
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "user", claims.PreferredName)
+	require.Error(t, confusionErr)
+}
+
+func TestJWKSVerifier(t *testing.T) {
+	// Arrange
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	unknown, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &first.PublicKey, KeyID: "first", Algorithm: "RS256", Use: "sig"},
+		{Key: &second.PublicKey, KeyID: "second", Algorithm: "RS256", Use: "sig"},
+	}})
+	require.NoError(t, err)
+	verifier, err := auth.NewJWKSVerifier(jwks, testIssuer, testClientID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "first key", token: sign(t, jwt.SigningMethodRS256, "first", testClaims(), first)},
+		{name: "second key", token: sign(t, jwt.SigningMethodRS256, "second", testClaims(), second)},
+		{name: "kid of another key", token: sign(t, jwt.SigningMethodRS256, "second", testClaims(), first), wantErr: true},
+		{name: "unknown kid", token: sign(t, jwt.SigningMethodRS256, "unknown", testClaims(), unknown), wantErr: true},
+		{name: "no kid", token: sign(t, jwt.SigningMethodRS256, "", testClaims(), first), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := verifier.VerifyToken(tt.token)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewTokenVerifier(t *testing.T) {
+	// Arrange
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "k1", Use: "sig"}}})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	jwksPath := filepath.Join(dir, "certs.json")
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0o600))
+	pemPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(pemPath, publicKeyPEM(t, key), 0o600))
+
+	tests := []struct {
+		name    string
+		auth    config.AuthConfig
+		wantErr bool
+	}{
+		{name: "jwks file", auth: config.AuthConfig{Mode: auth.VerifierJWKS, JWKSPath: jwksPath}},
+		{name: "static key file", auth: config.AuthConfig{Mode: auth.VerifierStatic, StaticKeyPath: pemPath}},
+		{name: "missing jwks file", auth: config.AuthConfig{Mode: auth.VerifierJWKS, JWKSPath: filepath.Join(dir, "missing.json")}, wantErr: true},
+		{name: "empty static key", auth: config.AuthConfig{Mode: auth.VerifierStatic}, wantErr: true},
+		{name: "unknown mode", auth: config.AuthConfig{Mode: "magic"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			verifier, err := auth.NewTokenVerifier(&config.Config{Auth: tt.auth, Keycloak: config.KeycloakConfig{ClientID: testClientID}})
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = verifier.VerifyToken(sign(t, jwt.SigningMethodRS256, "k1", testClaims(), key))
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Permissions checked by the API's routes
+const (
+	PermissionAssetsRead      = "assets:read"
+	PermissionAssetsWrite     = "assets:write"
+	PermissionFavouritesRead  = "favourites:read"
+	PermissionFavouritesWrite = "favourites:write"
+	// PermissionUsersAdmin manages users and reaches every user's favourites and collections
+	PermissionUsersAdmin   = "users:admin"
+	PermissionAPIKeysAdmin = "api-keys:admin"
+)
+
+// AllPermissions lists every permission the API knows about
+var AllPermissions = []string{
+	PermissionAssetsRead,
+	PermissionAssetsWrite,
+	PermissionFavouritesRead,
+	PermissionFavouritesWrite,
+	PermissionUsersAdmin,
+	PermissionAPIKeysAdmin,
+}
+
+// RolePermissions maps role names to the permissions they grant. Realm and client roles share one namespace,
+// as the verifiers return both in the caller's roles.
+type RolePermissions map[string][]string
+
+// DefaultRolePermissions grants the realm's two roles what they could do before permissions were configurable
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		"Administrators": slices.Clone(AllPermissions),
+		"Users":          {PermissionAssetsRead, PermissionFavouritesRead, PermissionFavouritesWrite},
+	}
+}
+
+// LoadRolePermissions reads the role mapping from a JSON object of role names to permission lists,
+// or returns the defaults when path is empty. Unknown permissions are rejected to catch typos early.
+func LoadRolePermissions(path string) (RolePermissions, error) {
+	if path == "" {
+		return DefaultRolePermissions(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read permissions file: %w", err)
+	}
+	var mapping RolePermissions
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse permissions file: %w", err)
+	}
+	for role, permissions := range mapping {
+		for _, permission := range permissions {
+			if !slices.Contains(AllPermissions, permission) {
+				return nil, fmt.Errorf("unknown permission %q for role %q", permission, role)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// PermissionsFor returns the sorted permissions granted by any of the roles
+func (m RolePermissions) PermissionsFor(roles []string) []string {
+	permissions := []string{}
+	for _, role := range roles {
+		permissions = append(permissions, m[role]...)
+	}
+	slices.Sort(permissions)
+	return slices.Compact(permissions)
+}
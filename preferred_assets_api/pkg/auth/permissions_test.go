@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRolePermissions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		roles   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "defaults for users", roles: []string{"Users"}, want: []string{"assets:read", "favourites:read", "favourites:write"}},
+		{name: "defaults for administrators", roles: []string{"Administrators", "Users"}, want: []string{"api-keys:admin", "assets:read", "assets:write", "favourites:read", "favourites:write", "users:admin"}},
+		{
+			name:  "realm and client roles from a file",
+			path:  write("permissions.json", `{"Users": ["assets:read"], "editor": ["assets:read", "assets:write"]}`),
+			roles: []string{"Users", "editor"},
+			want:  []string{"assets:read", "assets:write"},
+		},
+		{name: "unmapped role", path: write("empty.json", `{}`), roles: []string{"Users"}, want: []string{}},
+		{name: "unknown permission", path: write("typo.json", `{"Users": ["asset:read"]}`), wantErr: true},
+		{name: "malformed file", path: write("malformed.json", `["Users"]`), wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			mapping, err := auth.LoadRolePermissions(tt.path)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, mapping.PermissionsFor(tt.roles))
+		})
+	}
+}
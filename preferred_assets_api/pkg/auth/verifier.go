@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MichailidouNatalia/GWI-Engineering-Challenge/preferred_assets_api/cmd/api/config"
+)
+
+// Token verifier modes selectable through config.AuthConfig
+const (
+	VerifierOIDC   = "oidc"
+	VerifierJWKS   = "jwks"
+	VerifierStatic = "static"
+)
+
+// TokenVerifier verifies bearer tokens and reads the caller's roles from their claims
+type TokenVerifier interface {
+	VerifyToken(tokenString string) (*CustomClaims, error)
+	GetUserRoles(claims *CustomClaims) []string
+}
+
+// NewTokenVerifier builds the verifier selected by the configuration.
+// Only the OIDC mode needs the identity provider; the JWKS and static modes verify tokens offline.
+func NewTokenVerifier(cfg *config.Config) (TokenVerifier, error) {
+	switch cfg.Auth.Mode {
+	case VerifierOIDC, "":
+		return NewKeycloakClient(&cfg.Keycloak)
+
+	case VerifierJWKS:
+		data, err := os.ReadFile(cfg.Auth.JWKSPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return NewJWKSVerifier(data, cfg.Auth.Issuer, cfg.Keycloak.ClientID)
+
+	case VerifierStatic:
+		key := []byte(cfg.Auth.StaticKey)
+		if cfg.Auth.StaticKeyPath != "" {
+			data, err := os.ReadFile(cfg.Auth.StaticKeyPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read static key: %w", err)
+			}
+			key = data
+		}
+		return NewStaticKeyVerifier(key, cfg.Auth.Issuer, cfg.Keycloak.ClientID)
+
+	default:
+		return nil, fmt.Errorf("unknown token verifier: %s", cfg.Auth.Mode)
+	}
+}
+
+// rolesFor collects the realm roles and the roles of the client from the claims
+func rolesFor(claims *CustomClaims, clientID string) []string {
+	var roles []string
+	roles = append(roles, claims.RealmAccess.Roles...)
+	if clientRoles, exists := claims.ResourceAccess[clientID]; exists {
+		roles = append(roles, clientRoles.Roles...)
+	}
+	return roles
+}